@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+)
+
+// ciReportFile is the artifact name within ci.artifact_dir, kept fixed so
+// pipelines can upload it without configuration
+const ciReportFile = "report.json"
+
+// reportCI writes the CI artifact, the JSON report in ci.artifact_dir
+func reportCI(cfg *config.Config, result *models.AnalysisResult) {
+	if cfg.CI.ArtifactDir != "" {
+		jsonCfg := *cfg
+		jsonCfg.Output.Format = "json"
+		report := analyzer.NewReportGeneratorWithConfig(&jsonCfg).Generate(result)
+
+		artifactPath := filepath.Join(cfg.CI.ArtifactDir, ciReportFile)
+		if err := writeReportToFile(report, artifactPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write CI artifact: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/dashboard"
+	"gophercheck/internal/history"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	dashboardAddrFlag    string
+	dashboardHistoryFlag string
+)
+
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard [files or directories]",
+	Short: "Serve an interactive dashboard of the analysis",
+	Long: `Analyze the code and serve a local web UI for exploring the results: a
+sortable, filterable issue table, a file tree heatmap colored by per-file
+score, a treemap of lines of code and penalty density, per-rule trends from
+the history file, and the flagged source with links to open it in an editor. "Re-analyze" in the UI runs the analysis again
+and appends a snapshot to the history file.
+
+Examples:
+	gophercheck dashboard .                         # http://localhost:7878
+	gophercheck dashboard --addr :9000 ./...        # Another port
+	gophercheck dashboard --history "" .            # Keep no history`,
+	Run: runDashboard,
+}
+
+func init() {
+	dashboardCmd.Flags().StringVar(&dashboardAddrFlag, "addr", "localhost:7878", "Address to serve the dashboard on")
+	dashboardCmd.Flags().StringVar(&dashboardHistoryFlag, "history", history.DefaultFile, "History file of past scores (empty to keep none)")
+	rootCmd.AddCommand(dashboardCmd)
+}
+
+func runDashboard(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	if len(args) == 1 && args[0] == "-" {
+		fmt.Fprintln(os.Stderr, "The dashboard can't analyze stdin")
+		os.Exit(1)
+	}
+
+	server := dashboard.NewServer(cfg, dashboardAnalyzer(cfg, args), dashboardHistoryFlag, dashboardAddrFlag)
+	if err := server.Refresh(); err != nil {
+		fmt.Fprintf(os.Stderr, "Analysis failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	listener, err := net.Listen("tcp", dashboardAddrFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Serving dashboard on http://%s (Ctrl+C to stop)\n", listener.Addr())
+	if err := http.Serve(listener, server.Handler()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// dashboardAnalyzer analyzes args afresh on every call, so files added or
+// removed since the last refresh are picked up
+func dashboardAnalyzer(cfg *config.Config, args []string) dashboard.AnalyzeFunc {
+	return func() (*dashboard.Analysis, error) {
+		analyzerEngine, goFiles, err := prepareAnalysis(cfg, args)
+		if err != nil {
+			return nil, err
+		}
+		if len(goFiles) == 0 {
+			return nil, fmt.Errorf("no Go files found to analyze")
+		}
+
+		treemap, result, err := analyzerEngine.AnalyzeTreemap(goFiles)
+		if err != nil {
+			return nil, err
+		}
+
+		paths := make(map[string]string, len(goFiles))
+		for _, file := range goFiles {
+			paths[analyzerEngine.ReportedPath(file)] = file
+		}
+		return &dashboard.Analysis{Result: result, Treemap: treemap, Paths: paths}, nil
+	}
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/fix"
+	"gophercheck/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	fixDiffFlag       bool
+	fixLikelySafeFlag bool
+	fixVerifyFlag     bool
+)
+
+var fixCmd = &cobra.Command{
+	Use:   "fix [files or directories]",
+	Short: "Apply suggested fixes",
+	Long: `Run the analysis and apply the suggested fixes attached to mechanically
+fixable findings, such as rewriting string concatenation in a loop to use a
+strings.Builder. Files are only rewritten when a fix applies cleanly.
+
+Each fix is classified by the detector that suggests it: safe fixes preserve
+behavior in every case the detector checked, likely-safe fixes preserve it
+unless the code relies on something the detector can't see, and unsafe fixes
+need review. Only safe fixes are applied unless --include-likely-safe is set;
+unsafe fixes are never applied, but their diffs are shown in reports.
+
+With --verify, fixes are applied one package at a time, and the package is
+built and tested with go build and go test after each. If it fails, the
+package's fixes are retried one by one and any fix that breaks compilation
+or tests is rolled back. The fixes kept and rolled back are listed at the
+end.
+
+Examples:
+	gophercheck fix .           # Rewrite files in place
+	gophercheck fix --diff .    # Print the changes as a unified diff instead
+	gophercheck fix --include-likely-safe .   # Also apply likely-safe fixes
+	gophercheck fix --verify ./...            # Keep only fixes that build and pass tests
+	cat main.go | gophercheck fix -   # Print the fixed source to stdout`,
+	Run: runFix,
+}
+
+func init() {
+	fixCmd.Flags().BoolVar(&fixDiffFlag, "diff", false, "Print a unified diff instead of rewriting files")
+	fixCmd.Flags().BoolVar(&fixLikelySafeFlag, "include-likely-safe", false, "Also apply fixes classified as likely safe")
+	fixCmd.Flags().BoolVar(&fixVerifyFlag, "verify", false, "Build and test each package after fixing it, rolling back fixes that break it")
+	rootCmd.AddCommand(fixCmd)
+}
+
+func runFix(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	fromStdin := len(args) == 1 && args[0] == "-"
+	if fixVerifyFlag && (fixDiffFlag || fromStdin) {
+		fmt.Fprintln(os.Stderr, "--verify rewrites files on disk and can't be combined with --diff or stdin")
+		os.Exit(1)
+	}
+
+	analyzerEngine, goFiles, err := prepareAnalysis(cfg, args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(goFiles) == 0 {
+		fmt.Fprintln(os.Stderr, "No Go files found to analyze")
+		os.Exit(1)
+	}
+
+	result, err := analyzerEngine.AnalyzeFiles(goFiles)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Analysis failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	minimum := models.FixSafe
+	if fixLikelySafeFlag {
+		minimum = models.FixLikelySafe
+	}
+	fixesByFile, skipped := groupFixes(result.Issues, minimum)
+	files := make([]string, 0, len(fixesByFile))
+	for file := range fixesByFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	if fixVerifyFlag {
+		if err := verifyFixes(analyzerEngine, files, fixesByFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		reportSkippedFixes(skipped, minimum)
+		return
+	}
+
+	total := 0
+	for _, file := range files {
+		applied, err := applyFileFixes(analyzerEngine, file, fixesByFile[file], fromStdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+			os.Exit(1)
+		}
+		total += applied
+	}
+
+	switch {
+	case fixDiffFlag:
+		fmt.Fprintf(os.Stderr, "%d fix(es) available in %d file(s)\n", total, len(files))
+	case !fromStdin:
+		fmt.Fprintf(os.Stderr, "Applied %d fix(es) in %d file(s)\n", total, len(files))
+	}
+	reportSkippedFixes(skipped, minimum)
+}
+
+func reportSkippedFixes(skipped int, minimum models.FixSafety) {
+	if skipped == 0 {
+		return
+	}
+	hint := ""
+	if !fixLikelySafeFlag {
+		hint = " (use --include-likely-safe to apply likely-safe fixes)"
+	}
+	fmt.Fprintf(os.Stderr, "Skipped %d fix(es) below %s%s\n", skipped, minimum, hint)
+}
+
+// groupFixes collects the issues with suggested fixes by reported file,
+// keeping those at least as safe as minimum. It also returns the number of
+// fixes left out.
+func groupFixes(issues []models.Issue, minimum models.FixSafety) (map[string][]models.Issue, int) {
+	fixesByFile := make(map[string][]models.Issue)
+	skipped := 0
+	for _, issue := range issues {
+		if issue.SuggestedFix == nil || len(issue.SuggestedFix.Edits) == 0 {
+			continue
+		}
+		if !issue.SuggestedFix.Safety.AtLeast(minimum) {
+			skipped++
+			continue
+		}
+		fixesByFile[issue.File] = append(fixesByFile[issue.File], issue)
+	}
+	return fixesByFile, skipped
+}
+
+// verifyFixes applies the fixes package by package with fix.VerifyPackage
+// and lists the fixes kept and rolled back
+func verifyFixes(analyzerEngine *analyzer.Analyzer, files []string, fixesByFile map[string][]models.Issue) error {
+	byPackage := make(map[string][]fix.Candidate)
+	var dirs []string
+	for _, file := range files {
+		path := analyzerEngine.ResolvePath(file)
+		dir := filepath.Dir(path)
+		if _, ok := byPackage[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		for _, issue := range fixesByFile[file] {
+			byPackage[dir] = append(byPackage[dir], fix.Candidate{Path: path, Issue: issue})
+		}
+	}
+	sort.Strings(dirs)
+
+	kept, total := 0, 0
+	for _, dir := range dirs {
+		outcomes, err := fix.VerifyPackage(dir, byPackage[dir], fix.GoCheck)
+		if err != nil {
+			return fmt.Errorf("%s: %w", dir, err)
+		}
+		for _, outcome := range outcomes {
+			total++
+			location := fmt.Sprintf("%s:%d", outcome.Issue.File, outcome.Issue.Line)
+			if outcome.Kept {
+				kept++
+				fmt.Printf("kept        %s  %s\n", location, outcome.Issue.SuggestedFix.Description)
+				continue
+			}
+			fmt.Printf("rolled back %s  %s: %s\n", location, outcome.Issue.SuggestedFix.Description, outcome.Reason)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Kept %d of %d fix(es) in %d package(s)\n", kept, total, len(dirs))
+	return nil
+}
+
+// applyFileFixes applies the fixes for one reported file and either prints a
+// diff, prints the fixed source (for stdin), or rewrites the file
+func applyFileFixes(analyzerEngine *analyzer.Analyzer, file string, issues []models.Issue, fromStdin bool) (int, error) {
+	fixes := make([]*models.SuggestedFix, 0, len(issues))
+	for _, issue := range issues {
+		fixes = append(fixes, issue.SuggestedFix)
+	}
+
+	path := analyzerEngine.ResolvePath(file)
+	src, err := analyzerEngine.ReadSource(path)
+	if err != nil {
+		return 0, err
+	}
+
+	fixed, applied, err := fix.ApplyFixes(src, fixes)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case fixDiffFlag:
+		fmt.Print(fix.UnifiedDiff(file, src, fixed))
+		return applied, nil
+	case fromStdin:
+		_, err = os.Stdout.Write(fixed)
+		return applied, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return applied, os.WriteFile(path, fixed, info.Mode().Perm())
+}
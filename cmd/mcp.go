@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/config"
+	"gophercheck/internal/mcp"
+	"gophercheck/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve the analysis as MCP tools for AI coding assistants",
+	Long: `Serve the analysis over the Model Context Protocol: JSON-RPC 2.0 messages,
+one per line, on stdin and stdout. AI coding assistants start it as a stdio
+server and call its tools:
+
+  analyze_file    Analyze a Go file, or unsaved contents passed as source,
+                  and list its findings with ids
+  explain_issue   Explain a finding by id, or a rule by name
+  suggest_fix     The suggestion for a finding, with a diff when the fix
+                  is mechanical
+
+Files are analyzed with the configuration of the working directory, as
+gophercheck would analyze them on their own. Findings are kept for the
+session, so later calls can refer to their ids.
+
+Examples:
+	gophercheck mcp                        # Started by the assistant
+	gophercheck mcp --config ci.yaml       # With a particular configuration`,
+	Args: cobra.NoArgs,
+	Run:  runMCP,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+func runMCP(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+	levels, _ := models.NewSeverityLevels(cfg.Output.SeverityLevels) // Checked by loadConfigOrExit
+	server := mcp.NewServer(func(path string, src []byte) (*models.AnalysisResult, error) {
+		return analyzeForMCP(cfg, path, src)
+	}, version, cfg.Output.DocsBaseURL, levels)
+	if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "MCP server failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// analyzeForMCP analyzes one Go file, from src when it is not nil. Each call
+// starts afresh, so edits made between calls are always seen.
+func analyzeForMCP(cfg *config.Config, path string, src []byte) (*models.AnalysisResult, error) {
+	if !strings.HasSuffix(path, ".go") {
+		return nil, fmt.Errorf("%s is not a Go file", path)
+	}
+	analyzerEngine := analyzer.NewAnalyzerWithConfig(cfg)
+	if src != nil {
+		analyzerEngine.AddSource(path, src)
+	} else if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	return analyzerEngine.AnalyzeFiles([]string{path})
+}
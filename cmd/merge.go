@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/history"
+	"gophercheck/internal/models"
+	"gophercheck/internal/workspace"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	mergeFormatFlag  string
+	mergePartialFlag bool
+	mergeHistoryFlag string
+	mergeUploadFlag  string
+	mergeNotifyFlag  bool
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge [JSON results]",
+	Short: "Combine shard results into one report",
+	Long: `Combine the JSON results of sharded runs (gophercheck --shard i/n -f json)
+into one result, with issue counts and the score computed over every shard as
+if the packages had been analyzed together. The score uses the current
+configuration, which should match the one the shards ran with.
+
+Results of all n shards are required unless --allow-partial is set. Findings
+that span packages, such as import cycles, are only found within a shard.
+
+Examples:
+	gophercheck merge shard-*.json                  # Combined JSON result
+	gophercheck merge -f console shard-*.json       # Combined console report
+	gophercheck merge --allow-partial shard-1.json  # Merge whatever shards finished
+	gophercheck merge --history .gophercheck/history.jsonl shard-*.json   # Also record the score
+	gophercheck merge --notify -f console shard-*.json   # Post the nightly summary to Slack/Teams`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runMerge,
+}
+
+func init() {
+	mergeCmd.Flags().StringVarP(&mergeFormatFlag, "format", "f", "json", "Output format (console, json, jsonl, csv, quickfix, sarif, sonar, html)")
+	mergeCmd.Flags().BoolVar(&mergePartialFlag, "allow-partial", false, "Merge even if some shards are missing")
+	mergeCmd.Flags().StringVar(&mergeUploadFlag, "upload", "", "POST the combined JSON result to this HTTPS endpoint (see output.upload)")
+	mergeCmd.Flags().BoolVar(&mergeNotifyFlag, "notify", false, "Post a summary of the combined result to Slack/Teams (see notifications)")
+	mergeCmd.Flags().StringVar(&mergeHistoryFlag, "history", "", "Append the combined score to this history file (as the dashboard keeps)")
+	rootCmd.AddCommand(mergeCmd)
+}
+
+func runMerge(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+	cfg.Output.Format = mergeFormatFlag
+	if mergeUploadFlag != "" {
+		cfg.Output.Upload.URL = mergeUploadFlag
+	}
+	if mergeNotifyFlag {
+		cfg.Notifications.Enabled = true
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid options: %v\n", err)
+		os.Exit(1)
+	}
+
+	var results []*models.AnalysisResult
+	var shards []string
+	for _, path := range args {
+		result, err := loadResult(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, result)
+		shards = append(shards, result.Shard)
+	}
+
+	missing, err := checkShards(args, shards)
+	switch {
+	case err != nil:
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	case len(missing) > 0 && !mergePartialFlag:
+		fmt.Fprintf(os.Stderr, "Missing shard(s) %s (use --allow-partial to merge anyway)\n", strings.Join(missing, ", "))
+		os.Exit(1)
+	case len(missing) > 0:
+		fmt.Fprintf(os.Stderr, "Warning: missing shard(s) %s\n", strings.Join(missing, ", "))
+	}
+
+	merged := models.MergeResults(cfg, results...)
+	if mergeHistoryFlag != "" {
+		if err := history.Append(mergeHistoryFlag, history.NewSnapshot(merged)); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to record history: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	fmt.Print(analyzer.NewReportGeneratorWithConfig(cfg).Generate(merged))
+	if cfg.Output.Upload.URL != "" {
+		uploadResult(cfg, merged)
+	}
+	if cfg.Notifications.Enabled {
+		notifyResult(cfg, merged)
+	}
+}
+
+// loadResult reads a JSON result written by gophercheck -f json
+func loadResult(path string) (*models.AnalysisResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var result models.AnalysisResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("%s is not a JSON report: %w", path, err)
+	}
+	if major(result.SchemaVersion) != major(models.SchemaVersion) {
+		return nil, fmt.Errorf("%s has schema version %s, incompatible with %s", path, result.SchemaVersion, models.SchemaVersion)
+	}
+	return &result, nil
+}
+
+func major(version string) string {
+	major, _, _ := strings.Cut(version, ".")
+	return major
+}
+
+// checkShards verifies that the results are shards of one split with none
+// repeated, and returns the shards that are missing. A result without a shard
+// is a whole analysis and can only be "merged" on its own.
+func checkShards(paths, specs []string) ([]string, error) {
+	seen := make(map[int]string)
+	count := 0
+	for i, spec := range specs {
+		if spec == "" {
+			if len(specs) > 1 {
+				return nil, fmt.Errorf("%s is not a shard result (run with --shard i/n)", paths[i])
+			}
+			return nil, nil
+		}
+		shard, err := workspace.ParseShard(spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", paths[i], err)
+		}
+		if count != 0 && shard.Count != count {
+			return nil, fmt.Errorf("%s is shard %s, but other results split into %d shards", paths[i], spec, count)
+		}
+		count = shard.Count
+		if previous, ok := seen[shard.Index]; ok {
+			return nil, fmt.Errorf("%s and %s are both shard %s", previous, paths[i], spec)
+		}
+		seen[shard.Index] = paths[i]
+	}
+
+	var missing []string
+	for index := 1; index <= count; index++ {
+		if _, ok := seen[index]; !ok {
+			missing = append(missing, fmt.Sprintf("%d/%d", index, count))
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	metricsFormatFlag string
+	metricsSortFlag   string
+	metricsTopFlag    int
+	metricsPkgFlag    bool
+)
+
+// metricsSortKeys are the columns --sort-by accepts
+var metricsSortKeys = map[string]func(models.FunctionMetrics) int{
+	"loc":        func(m models.FunctionMetrics) int { return m.LOC },
+	"cyclomatic": func(m models.FunctionMetrics) int { return m.Cyclomatic },
+	"cognitive":  func(m models.FunctionMetrics) int { return m.Cognitive },
+	"nesting":    func(m models.FunctionMetrics) int { return m.Nesting },
+	"params":     func(m models.FunctionMetrics) int { return m.Params },
+	"issues":     func(m models.FunctionMetrics) int { return m.Issues },
+}
+
+// packageSortKeys are the columns --sort-by accepts with --packages
+var packageSortKeys = map[string]func(models.PackageMetrics) float64{
+	"fan_in":      func(m models.PackageMetrics) float64 { return float64(m.FanIn) },
+	"fan_out":     func(m models.PackageMetrics) float64 { return float64(m.FanOut) },
+	"instability": func(m models.PackageMetrics) float64 { return m.Instability },
+}
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics [files or directories]",
+	Short: "Print size and complexity metrics for every function",
+	Long: `Run the analysis and print one row per function with its lines of code,
+cyclomatic and cognitive complexity, nesting depth, parameter count, and the
+number of issues reported inside it, whether or not any threshold is crossed.
+With --packages, print one row per package instead with its fan-in (analyzed
+packages importing it), fan-out (analyzed packages it imports), and
+instability, fan-out / (fan-in + fan-out), marking hub packages.
+
+Examples:
+	gophercheck metrics .                                # Table in file order
+	gophercheck metrics --sort-by cognitive --top 10 .   # Ten hardest functions
+	gophercheck metrics --format csv . > metrics.csv     # For spreadsheets
+	gophercheck metrics --packages --sort-by fan_in .    # Most imported packages`,
+	Run: runMetrics,
+}
+
+func init() {
+	metricsCmd.Flags().StringVar(&metricsFormatFlag, "format", "console", "Output format (console, csv, json)")
+	metricsCmd.Flags().StringVar(&metricsSortFlag, "sort-by", "", "Sort descending by loc, cyclomatic, cognitive, nesting, params, or issues (default file order)")
+	metricsCmd.Flags().IntVar(&metricsTopFlag, "top", 0, "Only print the first N functions (or packages)")
+	metricsCmd.Flags().BoolVar(&metricsPkgFlag, "packages", false, "Print package coupling instead: fan-in, fan-out, and instability (sort by fan_in, fan_out, or instability)")
+	rootCmd.AddCommand(metricsCmd)
+}
+
+func runMetrics(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+
+	if !slices.Contains(analyzer.MetricsFormats, metricsFormatFlag) {
+		fmt.Fprintf(os.Stderr, "Invalid format: %s (valid: %v)\n", metricsFormatFlag, analyzer.MetricsFormats)
+		os.Exit(1)
+	}
+	sortKey, ok := metricsSortKeys[metricsSortFlag]
+	packageSortKey, packageOK := packageSortKeys[metricsSortFlag]
+	if metricsPkgFlag && metricsSortFlag != "" && !packageOK {
+		fmt.Fprintf(os.Stderr, "Invalid sort column: %s (valid with --packages: fan_in, fan_out, instability)\n", metricsSortFlag)
+		os.Exit(1)
+	}
+	if !metricsPkgFlag && metricsSortFlag != "" && !ok {
+		fmt.Fprintf(os.Stderr, "Invalid sort column: %s (valid: loc, cyclomatic, cognitive, nesting, params, issues)\n", metricsSortFlag)
+		os.Exit(1)
+	}
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	analyzerEngine, goFiles, err := prepareAnalysis(cfg, args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(goFiles) == 0 {
+		fmt.Fprintln(os.Stderr, "No Go files found to analyze")
+		os.Exit(1)
+	}
+
+	functions, _, err := analyzerEngine.AnalyzeMetrics(goFiles)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Analysis failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	if metricsPkgFlag {
+		printPackageMetrics(analyzerEngine.PackageMetrics(), packageSortKey)
+		return
+	}
+
+	if sortKey != nil {
+		slices.SortStableFunc(functions, func(a, b models.FunctionMetrics) int {
+			return cmp.Compare(sortKey(b), sortKey(a))
+		})
+	}
+	if metricsTopFlag > 0 && len(functions) > metricsTopFlag {
+		functions = functions[:metricsTopFlag]
+	}
+
+	output, err := analyzer.FormatMetrics(functions, metricsFormatFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to format metrics: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(output)
+}
+
+// printPackageMetrics prints package coupling, sorted descending by sortKey
+// when set, else by package path
+func printPackageMetrics(packages []models.PackageMetrics, sortKey func(models.PackageMetrics) float64) {
+	if sortKey != nil {
+		slices.SortStableFunc(packages, func(a, b models.PackageMetrics) int {
+			return cmp.Compare(sortKey(b), sortKey(a))
+		})
+	}
+	if metricsTopFlag > 0 && len(packages) > metricsTopFlag {
+		packages = packages[:metricsTopFlag]
+	}
+
+	output, err := analyzer.FormatPackageMetrics(packages, metricsFormatFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to format metrics: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(output)
+}
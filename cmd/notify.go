@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+	"gophercheck/internal/notify"
+)
+
+// notifyResult posts a summary of the run to the configured Slack and Teams
+// webhooks and records it for the next run to compare against. Like uploads,
+// a failed notification is reported but doesn't fail the run.
+func notifyResult(cfg *config.Config, result *models.AnalysisResult) {
+	settings := cfg.Notifications
+	notifier := notify.NewNotifier(settings, os.Getenv)
+	if !notifier.Webhooks() {
+		fmt.Fprintf(os.Stderr, "Warning: notifications are enabled but neither $%s nor $%s is set\n", settings.SlackWebhookEnv, settings.TeamsWebhookEnv)
+		return
+	}
+
+	previous, err := notify.LoadState(settings.StateFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring the last notified run: %v\n", err)
+	}
+	summary := notify.Summarize(projectName(cfg), result, previous, notifier.MinSeverity())
+	if reason := notifier.Withhold(summary, time.Now()); reason != "" {
+		// The state stays, so the next notification covers this run too
+		fmt.Fprintf(os.Stderr, "Notification held back: %s\n", reason)
+		return
+	}
+	if err := notifier.Send(summary); err != nil {
+		// Keep the old state so the next run still reports what this one missed
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	if err := notify.SaveState(settings.StateFile, notify.NewState(result)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record the notified run: %v\n", err)
+	}
+	fmt.Fprintln(os.Stderr, "Sent run summary notification")
+}
+
+// projectName is the configured project name, or else the name of the
+// current directory
+func projectName(cfg *config.Config) string {
+	if cfg.ProjectName != "" {
+		return cfg.ProjectName
+	}
+	if dir, err := os.Getwd(); err == nil {
+		return filepath.Base(dir)
+	}
+	return "project"
+}
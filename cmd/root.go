@@ -1,17 +1,28 @@
 package cmd
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"os/signal"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"gophercheck/internal/analyzer"
 	"gophercheck/internal/config"
+	"gophercheck/internal/history"
+	"gophercheck/internal/models"
+	"gophercheck/internal/notify"
 	"gophercheck/internal/watcher"
+	"gophercheck/internal/workspace"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -21,13 +32,42 @@ var (
 	formatFlag         string
 	watchFlag          bool
 	configFlag         string
+	policyFlag         string
 	generateConfigFlag bool
 	verboseFlag        bool
+	sortByFlag         string
+	maxEffortFlag      string
+	includeTestsFlag   bool
+	maxIssuesFlag      int
+	maxPerRuleFlag     int
+	stopAtMaxFlag      bool
+	funcFlag           string
+	onlyFunctionFlag   []string
+	onlyTypeFlag       []string
+	ciFlag             bool
+	stdinFilenameFlag  string
+	metricsFileFlag    string
+	quickfixFileFlag   string
+	cycleGraphFlag     string
+	summaryFileFlag    string
+	minCategoryFlag    map[string]int
+	groupByFlag        string
+	newSinceFlag       string
+	baselineFlag       string
+	shardFlag          string
+	uploadFlag         string
+	notifyFlag         bool
+	lowMemoryFlag      bool
+	timeBudgetFlag     string
+	historyFlag        string
+
+	ciProvider string          // Detected CI provider when the CI profile is active
+	shard      workspace.Shard // Part of the packages to analyze, from --shard
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "gophercheck [files or directories]",
+	Use:   "gophercheck [files, directories, or packages]",
 	Short: "A Go performance analyzer that detects optimization opportunities",
 	Long: `gophercheck is a static analysis tool that scans Go code for common
 performance issues and provides actionable optimization suggestions.
@@ -35,12 +75,30 @@ performance issues and provides actionable optimization suggestions.
 Examples:
 	gophercheck .                            # Analyze current directory
 	gophercheck main.go utils.go             # Analyze specific files
+	gophercheck ./...                        # Analyze packages matching a pattern
+	cat main.go | gophercheck -              # Analyze a single file read from stdin
+	gophercheck --func ProcessItems .        # Only report issues in one function
 	gophercheck --format=json .              # Output results in JSON format
 	gophercheck --config .gophercheck.yml .  # Use custom config
+	gophercheck --policy org-policy.yml .    # Enforce an organization policy bundle
 	gophercheck --watch .                    # Watch mode - analyze on file changes
 	gophercheck --watch --verbose .          # Watch mode with detailed output
-	gophercheck --generate-config            # Generate sample config file`,
-	Run: runAnalysis,
+	gophercheck --generate-config            # Generate sample config file
+	gophercheck --shard 2/4 -f json ./...    # Analyze the second of four package shards
+	gophercheck --low-memory -f jsonl ./...  # Huge monorepos: bounded memory, issues streamed
+	gophercheck --upload https://health.example.com/api/results ./...   # Also send the result to a collector
+	gophercheck --notify ./...               # Post a summary to Slack/Teams (see notifications)
+	gophercheck --summary-file s.txt ./...   # Write the final summary line to a file
+	gophercheck --time-budget 30s ./...      # Pre-commit hooks: analyze what fits in 30s, densest files first
+
+Every analysis ends with a summary line on stderr for scripts to parse
+(--summary-file writes it to a file instead).
+
+In CI (CI, GITHUB_ACTIONS, or GITLAB_CI set) colors and emoji are turned off
+and the JSON report is written to the ci.artifact_dir directory. Configure or
+disable this in the ci config section.`,
+	Args: cobra.ArbitraryArgs, // Paths, not subcommand names
+	Run:  runAnalysis,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -52,11 +110,37 @@ func Execute() {
 }
 
 func init() {
-	rootCmd.Flags().StringVarP(&formatFlag, "format", "f", "console", "Output format (console, json)")
+	rootCmd.Flags().StringVarP(&formatFlag, "format", "f", "console", "Output format (console, json, jsonl, csv, quickfix, sarif, sonar, html)")
+	rootCmd.Flags().StringVar(&quickfixFileFlag, "quickfix-file", "", "In watch mode, keep an editor quickfix/problem-matcher errors file at this path")
+	rootCmd.Flags().StringVar(&metricsFileFlag, "metrics-file", "", "With --format=csv, also write per-function metrics as CSV to this file")
+	rootCmd.Flags().StringVar(&cycleGraphFlag, "cycle-graph", "", "Also write the import cycles found as a Graphviz DOT graph to this file")
+	rootCmd.Flags().StringVar(&summaryFileFlag, "summary-file", "", "Write the final summary line to this file instead of stderr")
+	rootCmd.Flags().StringToIntVar(&minCategoryFlag, "min-category-score", nil, "Exit with status 1 if a category's score is below this minimum, e.g. memory=70")
 	rootCmd.Flags().BoolVarP(&watchFlag, "watch", "w", false, "Watch mode for development")
-	rootCmd.Flags().StringVarP(&configFlag, "config", "c", "", "Path to configuration file")
+	rootCmd.PersistentFlags().StringVarP(&configFlag, "config", "c", "", "Path to configuration file")
+	rootCmd.PersistentFlags().StringVar(&policyFlag, "policy", "", "Organization policy bundle the configuration can't weaken (overrides policy.path)")
 	rootCmd.Flags().BoolVar(&generateConfigFlag, "generate-config", false, "Generate sample configuration file")
 	rootCmd.Flags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show detailed output with suggestions")
+	rootCmd.Flags().StringVar(&sortByFlag, "sort-by", "", "Issue ordering (severity, impact)")
+	rootCmd.Flags().StringVar(&groupByFlag, "group-by", "", "Group issues by owner (owner)")
+	rootCmd.Flags().StringVar(&newSinceFlag, "new-since", "", "Report issues introduced since this git ref apart from pre-existing ones")
+	rootCmd.Flags().StringVar(&baselineFlag, "baseline", "", "Report issues missing from this earlier JSON report apart from pre-existing ones")
+	rootCmd.Flags().StringVar(&maxEffortFlag, "max-effort", "", "Only report issues up to this fix effort (trivial, small, large)")
+	rootCmd.Flags().BoolVar(&includeTestsFlag, "include-tests", false, "Also analyze _test.go files using the relaxed test profile")
+	rootCmd.Flags().IntVar(&maxIssuesFlag, "max-issues", 0, "Report at most this many issues (0 = unlimited)")
+	rootCmd.Flags().IntVar(&maxPerRuleFlag, "max-issues-per-rule", 0, "Report at most this many issues per rule (0 = unlimited)")
+	rootCmd.Flags().BoolVar(&stopAtMaxFlag, "stop-at-max-issues", false, "Stop analyzing files once --max-issues issues are found")
+	rootCmd.Flags().BoolVar(&ciFlag, "ci", false, "Use the CI profile even when no CI environment is detected")
+	rootCmd.Flags().StringVar(&uploadFlag, "upload", "", "POST the JSON result to this HTTPS endpoint (token from $GOPHERCHECK_UPLOAD_TOKEN)")
+	rootCmd.Flags().BoolVar(&notifyFlag, "notify", false, "Post a run summary to the Slack/Teams webhooks in $GOPHERCHECK_SLACK_WEBHOOK/$GOPHERCHECK_TEAMS_WEBHOOK")
+	rootCmd.Flags().BoolVar(&lowMemoryFlag, "low-memory", false, "Analyze one package at a time, releasing its syntax trees before the next (for very large trees)")
+	rootCmd.Flags().StringVar(&timeBudgetFlag, "time-budget", "", "Stop analyzing after this long, e.g. 30s, files densest in past issues first (the result is partial)")
+	rootCmd.Flags().StringVar(&historyFlag, "history", "", "Append a snapshot of complete results to this history file, which --time-budget also ranks files by (default "+history.DefaultFile+")")
+	rootCmd.Flags().StringVar(&shardFlag, "shard", "", "Only analyze shard i/n of the packages, e.g. 2/4 (combine results with gophercheck merge)")
+	rootCmd.PersistentFlags().StringVar(&funcFlag, "func", "", "Only report issues in the named function (Func, Method, or Type.Method); short for --only-function with one name")
+	rootCmd.PersistentFlags().StringSliceVar(&onlyFunctionFlag, "only-function", nil, "Only report issues in functions matching these globs (Func, Method, or Type.Method, e.g. Handler*)")
+	rootCmd.PersistentFlags().StringSliceVar(&onlyTypeFlag, "only-type", nil, "Only report issues in types matching these globs and their methods (e.g. *Handler)")
+	rootCmd.PersistentFlags().StringVar(&stdinFilenameFlag, "stdin-filename", "<stdin>", "File name to report for source read from stdin (-)")
 }
 
 func runAnalysis(cmd *cobra.Command, args []string) {
@@ -65,16 +149,119 @@ func runAnalysis(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	cfg, err := config.LoadConfig(configFlag)
-	if err != nil {
-		color.Red("Error loading configuration: %v\n", err)
-		os.Exit(1)
+	cfg := loadConfigOrExit()
+
+	if ciFlag {
+		cfg.CI.Detect = "always"
+	}
+	if ciProvider = cfg.CIProvider(os.Getenv); ciProvider != "" {
+		cfg.ApplyCIProfile()
+		color.NoColor = !cfg.Output.Colors
 	}
 
 	if formatFlag != "" {
 		cfg.Output.Format = formatFlag
 	}
 
+	if metricsFileFlag != "" {
+		cfg.Output.MetricsFile = metricsFileFlag
+	}
+
+	if quickfixFileFlag != "" {
+		cfg.Output.QuickfixFile = quickfixFileFlag
+	}
+
+	if cycleGraphFlag != "" {
+		cfg.Output.CycleGraphFile = cycleGraphFlag
+	}
+
+	if summaryFileFlag != "" {
+		cfg.Output.SummaryFile = summaryFileFlag
+	}
+
+	if sortByFlag != "" {
+		cfg.Output.SortBy = sortByFlag
+	}
+
+	if groupByFlag != "" {
+		cfg.Output.GroupBy = groupByFlag
+	}
+	if cfg.Output.GroupBy == "owner" && !cfg.Ownership.CodeOwners && !cfg.Ownership.Blame {
+		cfg.Ownership.CodeOwners = true // Grouping needs owners to group by
+	}
+
+	if newSinceFlag != "" {
+		cfg.Output.NewSince = newSinceFlag
+	}
+
+	if baselineFlag != "" {
+		cfg.Output.Baseline = baselineFlag
+	}
+
+	if maxEffortFlag != "" {
+		cfg.Output.MaxFixEffort = maxEffortFlag
+	}
+
+	if includeTestsFlag {
+		cfg.Files.IncludeTests = true
+	}
+
+	if maxIssuesFlag > 0 {
+		cfg.Output.MaxIssues = maxIssuesFlag
+	}
+
+	if maxPerRuleFlag > 0 {
+		cfg.Output.MaxIssuesPerRule = maxPerRuleFlag
+	}
+
+	if stopAtMaxFlag {
+		cfg.Output.StopAtMaxIssues = true
+	}
+
+	if uploadFlag != "" {
+		cfg.Output.Upload.URL = uploadFlag
+	}
+
+	if notifyFlag {
+		cfg.Notifications.Enabled = true
+	}
+
+	if lowMemoryFlag {
+		cfg.Analysis.LowMemory = true
+	}
+
+	if timeBudgetFlag != "" {
+		cfg.Analysis.TimeBudget = timeBudgetFlag
+		if watchFlag {
+			color.Red("Invalid options: --time-budget can't be used with --watch\n")
+			os.Exit(1)
+		}
+	}
+
+	for category, minimum := range minCategoryFlag {
+		if cfg.Analysis.MinCategoryScores == nil {
+			cfg.Analysis.MinCategoryScores = make(map[string]int)
+		}
+		cfg.Analysis.MinCategoryScores[category] = minimum
+	}
+
+	if err := cfg.Validate(); err != nil {
+		color.Red("Invalid options: %v\n", err)
+		os.Exit(1)
+	}
+
+	if shardFlag != "" {
+		var err error
+		if shard, err = workspace.ParseShard(shardFlag); err != nil {
+			color.Red("Invalid options: %v\n", err)
+			os.Exit(1)
+		}
+		if watchFlag {
+			color.Red("Invalid options: --shard can't be used with --watch\n")
+			os.Exit(1)
+		}
+	}
+
 	verboseFlag, _ := cmd.Flags().GetBool("verbose")
 	if verboseFlag {
 		cfg.Output.Verbose = true
@@ -121,14 +308,53 @@ func runWatchMode(cfg *config.Config, paths []string) {
 	defer fileWatcher.Close()
 
 	analyzerEngine := analyzer.NewAnalyzerWithConfig(cfg)
+	if err := analyzerEngine.SetSymbolFilter(symbolFunctions(), onlyTypeFlag); err != nil {
+		color.Red("%v\n", err)
+		os.Exit(1)
+	}
 	reportGen := analyzer.NewReportGeneratorWithConfig(cfg)
+	store := analyzer.NewResultStore(cfg)
+
+	if cfg.Notifications.Enabled && !notify.NewNotifier(cfg.Notifications, os.Getenv).Webhooks() {
+		color.Yellow("⚠️  Not notifying: neither $%s nor $%s is set\n", cfg.Notifications.SlackWebhookEnv, cfg.Notifications.TeamsWebhookEnv)
+		cfg.Notifications.Enabled = false
+	}
 
 	color.Cyan("🔍 Running initial analysis...\n")
-	runInitialAnalysis(cfg, validPaths, analyzerEngine, reportGen)
+	runInitialAnalysis(cfg, validPaths, analyzerEngine, reportGen, store)
 
+	// Changes and watcher errors arrive on different goroutines; the console
+	// lock keeps their output apart
+	var console sync.Mutex
+	degradedShown := false
 	changeHandler := func(changedFiles []string) error {
-		return handleFileChanges(changedFiles, cfg, analyzerEngine, reportGen)
+		console.Lock()
+		defer console.Unlock()
+		if reason, since := fileWatcher.Degraded(); reason != "" {
+			printDegradedBanner(reason, since)
+		} else if degradedShown {
+			color.Green("✅ Watcher recovered; analyzing everything again\n")
+			degradedShown = false
+		}
+		if err := handleFileChanges(changedFiles, cfg, validPaths, analyzerEngine, reportGen, store); err != nil {
+			return err
+		}
+		if cfg.Notifications.Enabled {
+			notifyResult(cfg, store.Result())
+		}
+		return nil
 	}
+	go func() {
+		for werr := range fileWatcher.Errors() {
+			console.Lock()
+			printWatchError(werr, cfg.Output.Verbose)
+			if reason, since := fileWatcher.Degraded(); reason != "" {
+				printDegradedBanner(reason, since)
+				degradedShown = true
+			}
+			console.Unlock()
+		}
+	}()
 
 	if err := fileWatcher.Watch(validPaths, changeHandler); err != nil {
 		color.Red("Failed to start file watcher: %v\n", err)
@@ -154,26 +380,194 @@ func runWatchMode(cfg *config.Config, paths []string) {
 	color.Yellow("\n🛑 Stopping watch mode...\n")
 }
 
-func runSingleAnalysis(cfg *config.Config, args []string) {
+// printWatchError prints a problem the watcher ran into; stack traces of
+// panics only with --verbose
+func printWatchError(werr watcher.WatchError, verbose bool) {
+	timestamp := werr.Time.Format("15:04:05")
+	if werr.Fatal {
+		color.Red("❌ [%s] Watcher failed (%s): %v\n", timestamp, werr.Kind, werr.Err)
+	} else {
+		color.Yellow("⚠️  [%s] Watcher error (%s): %v\n", timestamp, werr.Kind, werr.Err)
+	}
+	if werr.Path != "" {
+		color.White("   path: %s\n", werr.Path)
+	}
+	if werr.Stack != "" && verbose {
+		color.White("%s\n", werr.Stack)
+	}
+}
+
+// printDegradedBanner says that watch results may be stale until the watcher
+// has caught up
+func printDegradedBanner(reason string, since time.Time) {
+	color.New(color.FgBlack, color.BgYellow).Printf(" WATCHER DEGRADED since %s ", since.Format("15:04:05"))
+	fmt.Println()
+	color.Yellow("   %s\n", reason)
+}
+
+// loadConfigOrExit loads the configuration selected by --config and enforces
+// its policy, or the one selected by --policy
+func loadConfigOrExit() *config.Config {
+	cfg, err := config.LoadConfig(configFlag)
+	if err != nil {
+		color.Red("Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if policyFlag != "" {
+		cfg.Policy = config.PolicyConfig{Path: policyFlag}
+	}
+	if err := cfg.ApplyPolicy(); err != nil {
+		color.Red("Error loading policy: %v\n", err)
+		os.Exit(1)
+	}
+	for _, warning := range cfg.RuleWarnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	if _, err := models.NewSeverityLevels(cfg.Output.SeverityLevels); err != nil {
+		color.Red("Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
+	return cfg
+}
+
+// collectAllGoFiles gathers Go files from every path argument, reporting
+// (but skipping) paths that cannot be read. Package patterns such as ./...
+// are resolved like go build does. Files left out by exclude_packages are
+// returned as skipped.
+func collectAllGoFiles(cfg *config.Config, paths []string) ([]string, []models.SkippedFile) {
 	var goFiles []string
-	for _, arg := range args {
-		files, err := collectGoFiles(arg)
+	var patterns []string
+	for _, path := range paths {
+		if workspace.IsPackagePattern(path) {
+			patterns = append(patterns, path)
+			continue
+		}
+		files, err := collectGoFiles(path, cfg.Files)
 		if err != nil {
-			color.Red("Error collecting files from %s: %v\n", arg, err)
+			color.Red("Error collecting files from %s: %v\n", path, err)
 			continue
 		}
 		goFiles = append(goFiles, files...)
 	}
 
+	if len(patterns) > 0 {
+		files, err := workspace.LoadPatternFiles(patterns, cfg.Files.IncludeTests)
+		if err != nil {
+			color.Red("Error loading packages %s: %v\n", strings.Join(patterns, " "), err)
+		}
+		// Package patterns are relative to the working directory, and so
+		// are the include and exclude globs for the files they match
+		globs := workspace.NewFileGlobs(cfg.Files.Include, cfg.Files.Exclude)
+		cwd, _ := os.Getwd()
+		for _, file := range files {
+			if globs.Keep(cwd, file) {
+				goFiles = append(goFiles, file)
+			}
+		}
+	}
+
+	filter := workspace.NewPackageFilter(cfg.Files.ExcludePackages)
+	var kept []string
+	var skipped []models.SkippedFile
+	for _, filename := range goFiles {
+		if pattern, excluded := filter.ExcludedBy(filename); excluded {
+			skipped = append(skipped, models.SkippedFile{
+				File:   filename,
+				Kind:   models.SkipExcluded,
+				Reason: fmt.Sprintf("matches exclude_packages pattern %q", pattern),
+			})
+			continue
+		}
+		kept = append(kept, filename)
+	}
+	return shard.Filter(kept), skipped
+}
+
+// symbolFunctions returns the function patterns of --only-function, with
+// --func's name added
+func symbolFunctions() []string {
+	if funcFlag == "" {
+		return onlyFunctionFlag
+	}
+	return append(slices.Clone(onlyFunctionFlag), funcFlag)
+}
+
+// prepareAnalysis creates an analyzer for the command-line arguments and
+// returns the files it should analyze. A lone "-" reads one file from stdin.
+func prepareAnalysis(cfg *config.Config, args []string) (*analyzer.Analyzer, []string, error) {
+	analyzerEngine := analyzer.NewAnalyzerWithConfig(cfg)
+	if err := analyzerEngine.SetSymbolFilter(symbolFunctions(), onlyTypeFlag); err != nil {
+		return nil, nil, err
+	}
+
+	if len(args) == 1 && args[0] == "-" {
+		src, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading stdin: %w", err)
+		}
+		analyzerEngine.AddSource(stdinFilenameFlag, src)
+		return analyzerEngine, []string{stdinFilenameFlag}, nil
+	}
+
+	goFiles, skipped := collectAllGoFiles(cfg, args)
+	analyzerEngine.AddSkipped(skipped...)
+	if cfg.Analysis.TimeBudget != "" {
+		analyzerEngine.SetPreviousIssues(previousIssueCounts())
+	}
+	return analyzerEngine, goFiles, nil
+}
+
+// previousIssueCounts returns the issues per file of the latest snapshot in
+// the history file, for a time-budgeted analysis to start with the densest
+// files. Without history the files are analyzed in the order collected.
+func previousIssueCounts() map[string]int {
+	path := historyFlag
+	if path == "" {
+		path = history.DefaultFile
+	}
+	snapshots, err := history.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: not ranking files by history: %v\n", err)
+		return nil
+	}
+	return history.IssuesByFile(snapshots)
+}
+
+// recordHistory appends the result to the --history file. Partial results
+// are left out so the history keeps describing the whole codebase.
+func recordHistory(result *models.AnalysisResult) {
+	if historyFlag == "" || result.Partial {
+		return
+	}
+	if err := history.Append(historyFlag, history.NewSnapshot(result)); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to record history: %v\n", err)
+	}
+}
+
+func runSingleAnalysis(cfg *config.Config, args []string) {
+	analyzerEngine, goFiles, err := prepareAnalysis(cfg, args)
+	if err != nil {
+		color.Red("%v\n", err)
+		os.Exit(1)
+	}
+
 	if len(goFiles) == 0 {
 		color.Yellow("⚠️  No Go files found to analyze\n")
 		return
 	}
 
-	analyzerEngine := analyzer.NewAnalyzerWithConfig(cfg)
 	reportGen := analyzer.NewReportGeneratorWithConfig(cfg)
 
-	if cfg.Output.Verbose {
+	if cfg.Output.Format == "jsonl" {
+		runStreamingAnalysis(cfg, goFiles, analyzerEngine)
+		return
+	}
+
+	if isMachineFormat(cfg.Output.Format) {
+		// Keep stdout parseable
+	} else if ciProvider != "" && !cfg.Output.Colors {
+		fmt.Printf("Analyzing %d Go files (%s profile)...\n\n", len(goFiles), ciProvider)
+	} else if cfg.Output.Verbose {
 		color.Cyan("🔍 Analyzing %d Go files with %d detectors...\n", len(goFiles), analyzerEngine.GetDetectorCount())
 		if configFlag != "" {
 			color.Cyan("📋 Using configuration: %s\n", configFlag)
@@ -183,11 +577,22 @@ func runSingleAnalysis(cfg *config.Config, args []string) {
 		color.Cyan("🔍 Analyzing %d Go files...\n\n", len(goFiles))
 	}
 
-	result, err := analyzerEngine.AnalyzeFiles(goFiles)
+	previous, since, expired, err := previousIssues(cfg, goFiles)
+	if err != nil {
+		color.Red("%v\n", err)
+		os.Exit(1)
+	}
+
+	result, err := analyzeWithMetrics(cfg, goFiles, analyzerEngine)
 	if err != nil {
 		color.Red("Analysis failed: %v\n", err)
 		return
 	}
+	if since != "" {
+		result.ClassifyAges(previous, since)
+		result.BaselineExpired = expired
+	}
+	result.Shard = shard.String()
 
 	report := reportGen.Generate(result)
 
@@ -201,22 +606,189 @@ func runSingleAnalysis(cfg *config.Config, args []string) {
 		fmt.Print(report)
 	}
 
+	if cfg.Output.CycleGraphFile != "" {
+		writeCycleGraph(cfg, result.Issues)
+	}
+	recordHistory(result)
+
+	if ciProvider != "" {
+		reportCI(cfg, result)
+	}
+	if cfg.Output.Upload.URL != "" {
+		uploadResult(cfg, result)
+	}
+	if cfg.Notifications.Enabled {
+		if result.Shard != "" {
+			fmt.Fprintln(os.Stderr, "Not notifying for a single shard (use gophercheck merge --notify)")
+		} else {
+			notifyResult(cfg, result)
+		}
+	}
+	reportSummary(cfg, result)
+	exitOnCategoryFailures(result)
+
 	if !cfg.Output.Colors && result.PerformanceScore < cfg.Analysis.ScoreThresholds.Fair {
 		os.Exit(1)
 	}
 }
 
-func runInitialAnalysis(cfg *config.Config, paths []string, analyzerEngine *analyzer.Analyzer, reportGen *analyzer.ReportGenerator) {
-	var goFiles []string
-	for _, path := range paths {
-		files, err := collectGoFiles(path)
+// previousIssues returns the issues to classify new ones against, per
+// new_since or baseline, and what they come from; since is empty when
+// neither is set. A baseline older than baseline_ttl has expired: there are
+// no previous issues, so every issue is new again.
+func previousIssues(cfg *config.Config, goFiles []string) (previous []models.Issue, since string, expired bool, err error) {
+	switch {
+	case cfg.Output.NewSince != "":
+		previous, err = analyzer.IssuesAtRef(cfg, goFiles, cfg.Output.NewSince)
+		return previous, cfg.Output.NewSince, false, err
+	case cfg.Output.Baseline != "":
+		if baselineExpired(cfg.Output) {
+			return nil, cfg.Output.Baseline, true, nil
+		}
+		previous, err = analyzer.LoadBaseline(cfg.Output.Baseline)
+		return previous, cfg.Output.Baseline, false, err
+	}
+	return nil, "", false, nil
+}
+
+// baselineExpired reports whether the baseline is older than baseline_ttl,
+// warning on stderr when it is
+func baselineExpired(output config.OutputConfig) bool {
+	maxAge := output.BaselineMaxAge()
+	if maxAge == 0 {
+		return false
+	}
+	updated, err := analyzer.BaselineDate(output.Baseline)
+	if err != nil || time.Since(updated) <= maxAge {
+		return false // A missing baseline fails when it's loaded
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  Baseline %s was last updated %s, more than %s ago; its issues are reported as new until it is regenerated\n",
+		output.Baseline, updated.Format("2006-01-02"), output.BaselineTTL)
+	return true
+}
+
+// analyzeWithMetrics analyzes goFiles and, for the csv format with a metrics
+// file configured, writes the per-function metrics beside the issue report
+func analyzeWithMetrics(cfg *config.Config, goFiles []string, analyzerEngine *analyzer.Analyzer) (*models.AnalysisResult, error) {
+	if cfg.Output.Format != "csv" || cfg.Output.MetricsFile == "" {
+		return analyzerEngine.AnalyzeFiles(goFiles)
+	}
+
+	functions, result, err := analyzerEngine.AnalyzeMetrics(goFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics strings.Builder
+	if err := analyzer.WriteMetricsCSV(&metrics, functions); err != nil {
+		return nil, err
+	}
+	if err := writeReportToFile(metrics.String(), cfg.Output.MetricsFile); err != nil {
+		color.Red("Failed to write metrics to file: %v\n", err)
+	} else if cfg.Output.OutputFile != "" {
+		// Stdout carries the issue rows otherwise
+		color.Green("📄 Metrics saved to: %s\n", cfg.Output.MetricsFile)
+	}
+	return result, nil
+}
+
+// runStreamingAnalysis writes one JSON issue per line as soon as each file has
+// been analyzed, so large runs can be piped without buffering the full result
+func runStreamingAnalysis(cfg *config.Config, goFiles []string, analyzerEngine *analyzer.Analyzer) {
+	previous, since, _, err := previousIssues(cfg, goFiles)
+	if err != nil {
+		color.Red("%v\n", err)
+		os.Exit(1)
+	}
+	var classifier *models.AgeClassifier
+	if since != "" {
+		classifier = models.NewAgeClassifier(previous)
+	}
+
+	var out io.Writer = os.Stdout
+	if cfg.Output.OutputFile != "" {
+		if err := os.MkdirAll(filepath.Dir(cfg.Output.OutputFile), 0755); err != nil {
+			color.Red("Failed to write report to file: %v\n", err)
+			os.Exit(1)
+		}
+		file, err := os.Create(cfg.Output.OutputFile)
 		if err != nil {
-			color.Red("Error collecting files from %s: %v\n", path, err)
-			continue
+			color.Red("Failed to write report to file: %v\n", err)
+			os.Exit(1)
 		}
-		goFiles = append(goFiles, files...)
+		defer file.Close()
+		out = file
 	}
 
+	writer := bufio.NewWriter(out)
+	encoder := json.NewEncoder(writer)
+	issues := make(chan models.Issue, 64)
+	done := make(chan struct{})
+	limiter := analyzer.NewIssueLimiter(cfg)
+	var cycles []models.Issue // For the cycle graph
+
+	go func() {
+		defer close(done)
+		for issue := range issues {
+			if classifier != nil {
+				classifier.Classify(&issue)
+			}
+			if !limiter.Allow(issue) {
+				continue
+			}
+			if len(issue.Cycle) > 0 {
+				cycles = append(cycles, issue)
+			}
+			if err := encoder.Encode(issue); err != nil {
+				color.Red("Failed to encode issue: %v\n", err)
+			}
+			writer.Flush() // Consumers see each issue immediately
+		}
+	}()
+
+	result, err := analyzerEngine.AnalyzeFilesStream(goFiles, issues)
+	<-done
+	if err != nil {
+		color.Red("Analysis failed: %v\n", err)
+		return
+	}
+	if limiter.Omitted > 0 {
+		fmt.Fprintf(os.Stderr, "%d more issues omitted by --max-issues limits\n", limiter.Omitted)
+	}
+	if cfg.Output.CycleGraphFile != "" {
+		writeCycleGraph(cfg, cycles)
+	}
+	fmt.Fprintf(os.Stderr, "Scope: %s\n", result.Scope)
+	if result.Partial {
+		fmt.Fprintf(os.Stderr, "Partial result: the time budget of %s ran out\n", result.TimeBudget)
+	}
+	for _, skipped := range result.SkippedFiles {
+		fmt.Fprintf(os.Stderr, "Skipped %s: %s\n", skipped.File, skipped.Reason)
+	}
+	for _, failure := range result.DetectorFailures {
+		fmt.Fprintf(os.Stderr, "Detector %s failed on %s: %s\n", failure.Rule, failure.File, failure.Panic)
+	}
+	for _, slow := range result.SlowRules {
+		fmt.Fprintf(os.Stderr, "Rule %s took %gms on %s (budget %gms)\n", slow.Rule, slow.DurationMS, slow.File, slow.BudgetMS)
+	}
+	// With streaming, the streamed issues are the CI artifact; the result
+	// holds only totals
+	reportSummary(cfg, result)
+	exitOnCategoryFailures(result)
+
+	if !cfg.Output.Colors && result.PerformanceScore < cfg.Analysis.ScoreThresholds.Fair {
+		os.Exit(1)
+	}
+}
+
+// isMachineFormat reports whether stdout must contain only the report itself
+func isMachineFormat(format string) bool {
+	return format == "json" || format == "jsonl" || format == "csv" || format == "quickfix" || format == "sarif" || format == "sonar" || format == "html"
+}
+
+func runInitialAnalysis(cfg *config.Config, paths []string, analyzerEngine *analyzer.Analyzer, reportGen *analyzer.ReportGenerator, store *analyzer.ResultStore) {
+	goFiles, _ := collectAllGoFiles(cfg, paths)
+
 	if len(goFiles) == 0 {
 		color.Yellow("⚠️  No Go files found to analyze\n")
 		return
@@ -226,11 +798,14 @@ func runInitialAnalysis(cfg *config.Config, paths []string, analyzerEngine *anal
 		color.White("📋 Found %d Go files\n", len(goFiles))
 	}
 
-	result, err := analyzerEngine.AnalyzeFiles(goFiles)
+	// Parsed files and type info are kept for the incremental runs that follow
+	result, err := analyzerEngine.AnalyzeIncremental(goFiles, goFiles)
 	if err != nil {
 		color.Red("Initial analysis failed: %v\n", err)
 		return
 	}
+	store.Update(result)
+	updateQuickfixFile(cfg, store)
 
 	report := reportGen.Generate(result)
 	fmt.Print(report)
@@ -238,7 +813,7 @@ func runInitialAnalysis(cfg *config.Config, paths []string, analyzerEngine *anal
 	color.White("═══════════════════════════════════════\n\n")
 }
 
-func handleFileChanges(changedFiles []string, cfg *config.Config, analyzerEngine *analyzer.Analyzer, reportGen *analyzer.ReportGenerator) error {
+func handleFileChanges(changedFiles []string, cfg *config.Config, paths []string, analyzerEngine *analyzer.Analyzer, reportGen *analyzer.ReportGenerator, store *analyzer.ResultStore) error {
 	if len(changedFiles) == 0 {
 		return nil
 	}
@@ -256,23 +831,33 @@ func handleFileChanges(changedFiles []string, cfg *config.Config, analyzerEngine
 		}
 	}
 
-	existingFiles := make([]string, 0, len(changedFiles))
-	for _, file := range changedFiles {
-		if stat, err := os.Stat(file); err == nil {
-			if !stat.IsDir() && strings.HasSuffix(file, ".go") {
-				if strings.HasSuffix(file, "_test.go") {
-					if cfg.Files.IncludeTests {
-						existingFiles = append(existingFiles, file)
-					}
-				} else {
-					existingFiles = append(existingFiles, file)
-				}
+	before := store.Result()
+
+	// Files deleted or renamed, alone or with their directory, are no longer
+	// collected; their issues go with them
+	watched, _ := collectAllGoFiles(cfg, paths)
+	byReported := make(map[string]string, len(watched))
+	for _, file := range watched {
+		byReported[analyzerEngine.ReportedPath(file)] = file
+	}
+	removed := store.Retain(slices.Collect(maps.Keys(byReported)))
+	if len(removed) > 0 {
+		color.Cyan("🗑️  Dropped the issues of %d removed files\n", len(removed))
+		if cfg.Output.Verbose {
+			for _, file := range removed {
+				color.White("   - %s\n", file)
 			}
 		}
 	}
 
+	existingFiles := changedWatchedFiles(changedFiles, removed, byReported, analyzerEngine, store)
 	if len(existingFiles) == 0 {
-		color.Yellow("⚠️  No valid Go files to analyze\n\n")
+		if len(removed) == 0 {
+			color.Yellow("⚠️  No valid Go files to analyze\n")
+		}
+		updateQuickfixFile(cfg, store)
+		printCodebaseSummary(before, store.Result())
+		color.White("─────────────────────────────────────────\n\n")
 		return nil
 	}
 
@@ -280,24 +865,213 @@ func handleFileChanges(changedFiles []string, cfg *config.Config, analyzerEngine
 		color.White("   → Analyzing %d Go files\n", len(existingFiles))
 	}
 
-	result, err := analyzerEngine.AnalyzeFiles(existingFiles)
+	// Unchanged files are reused from earlier runs as context
+	result, err := analyzerEngine.AnalyzeIncremental(watched, existingFiles)
 	if err != nil {
 		color.Red("Analysis failed: %v\n", err)
 		color.Yellow("Continuing to watch for changes...\n\n")
 		return nil
 	}
 
-	if result.TotalIssues > 0 {
+	// An edit confined to one function gets a focused before/after comparison
+	focus, reported := "", ""
+	if len(existingFiles) == 1 {
+		if edited := analyzerEngine.EditedFunctions(existingFiles[0]); len(edited) == 1 {
+			focus, reported = edited[0], analyzerEngine.ReportedPath(existingFiles[0])
+		}
+	}
+	var previous []models.Issue
+	if focus != "" {
+		previous = store.FunctionIssues(reported, focus)
+	}
+
+	store.Update(result)
+	updateQuickfixFile(cfg, store)
+
+	switch {
+	case focus != "":
+		printFunctionChange(focus, previous, store.FunctionIssues(reported, focus))
+	case result.TotalIssues > 0:
 		report := reportGen.Generate(result)
 		fmt.Print(report)
-	} else {
-		color.Green("✅ No issues found in changed files (Score: %d/100)\n", result.PerformanceScore)
+	default:
+		color.Green("✅ No issues found in changed files\n")
 	}
+	printCodebaseSummary(before, store.Result())
 
 	color.White("─────────────────────────────────────────\n\n")
 	return nil
 }
 
+// changedWatchedFiles returns the watched files to analyze again after a
+// change: the changed files still watched, those in directories created or
+// moved in, and, once files were removed, the rest of their packages and the
+// files with import cycle or hub findings, which the removal may resolve.
+// byReported maps the reported path of every watched file to it.
+func changedWatchedFiles(changedFiles, removed []string, byReported map[string]string, analyzerEngine *analyzer.Analyzer, store *analyzer.ResultStore) []string {
+	targets := make(map[string]bool)
+	for _, changed := range changedFiles {
+		reported := analyzerEngine.ReportedPath(changed)
+		if file, ok := byReported[reported]; ok {
+			targets[file] = true
+			continue
+		}
+		if stat, err := os.Stat(changed); err == nil && stat.IsDir() {
+			// A directory, or a watched root resynced by the watcher, maybe "."
+			for inside, file := range byReported {
+				if reported == "." || strings.HasPrefix(filepath.ToSlash(inside), filepath.ToSlash(reported)+"/") {
+					targets[file] = true
+				}
+			}
+		}
+	}
+
+	if len(removed) > 0 {
+		removedDirs := make(map[string]bool)
+		for _, file := range removed {
+			removedDirs[path.Dir(filepath.ToSlash(file))] = true
+		}
+		for reported, file := range byReported {
+			graphIssue := slices.ContainsFunc(store.Issues(reported), func(issue models.Issue) bool {
+				return issue.Type == models.IssueImportCycle || issue.Type == models.IssueHubPackage
+			})
+			if graphIssue || removedDirs[path.Dir(filepath.ToSlash(reported))] {
+				targets[file] = true
+			}
+		}
+	}
+
+	files := slices.Collect(maps.Keys(targets))
+	slices.Sort(files)
+	return files
+}
+
+// updateQuickfixFile rewrites the configured quickfix file with every issue in
+// the watched codebase. The file is replaced in one step so editors never read
+// it half-written.
+func updateQuickfixFile(cfg *config.Config, store *analyzer.ResultStore) {
+	if cfg.Output.QuickfixFile == "" {
+		return
+	}
+
+	quickfixCfg := *cfg
+	quickfixCfg.Output.Format = "quickfix"
+	report := analyzer.NewReportGeneratorWithConfig(&quickfixCfg).Generate(store.Result())
+
+	tmp := cfg.Output.QuickfixFile + ".tmp"
+	if err := writeReportToFile(report, tmp); err != nil {
+		color.Red("Failed to write quickfix file: %v\n", err)
+		return
+	}
+	if err := os.Rename(tmp, cfg.Output.QuickfixFile); err != nil {
+		color.Red("Failed to write quickfix file: %v\n", err)
+	}
+}
+
+// printFunctionChange reports how the findings in one edited function changed,
+// matching issues by fingerprint
+func printFunctionChange(function string, before, after []models.Issue) {
+	added, removed := analyzer.DiffIssues(before, after)
+	if len(added) == 0 && len(removed) == 0 {
+		color.White("🎯 %s(): no change in findings (%d issues)\n", function, len(after))
+		return
+	}
+
+	var changes []string
+	if len(removed) > 0 {
+		changes = append(changes, "removed "+severityCounts(removed))
+	}
+	if len(added) > 0 {
+		changes = append(changes, "added "+severityCounts(added))
+	}
+	color.Cyan("🎯 %s(): you %s\n", function, strings.Join(changes, ", "))
+
+	for _, issue := range removed {
+		color.Green("   - %-8s %s:%d %s\n", issue.Severity, filepath.Base(issue.File), issue.Line, issue.Message)
+	}
+	for _, issue := range added {
+		color.Red("   + %-8s %s:%d %s\n", issue.Severity, filepath.Base(issue.File), issue.Line, issue.Message)
+	}
+}
+
+// severityCounts formats issue counts by severity, e.g. "1 HIGH, 2 LOW"
+func severityCounts(issues []models.Issue) string {
+	counts := make(map[models.Severity]int)
+	for _, issue := range issues {
+		counts[issue.Severity]++
+	}
+	var parts []string
+	for severity := models.SeverityCritical; severity >= models.SeverityLow; severity-- {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printCodebaseSummary prints the score and issue counts of the whole watched
+// codebase, comparable to a full run, with the change since the last report
+func printCodebaseSummary(before, after *models.AnalysisResult) {
+	line := fmt.Sprintf("📦 Codebase: score %d/100%s, grade %s · %d issues%s in %d files",
+		after.PerformanceScore, signedDelta(after.PerformanceScore-before.PerformanceScore), after.Grade,
+		after.TotalIssues, signedDelta(after.TotalIssues-before.TotalIssues), len(after.Files))
+
+	var counts []string
+	for _, severity := range []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"} {
+		if n := after.IssuesBySeverity[severity]; n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, severity))
+		}
+	}
+	if len(counts) > 0 {
+		line += ": " + strings.Join(counts, ", ")
+	}
+	color.Cyan("%s\n", line)
+}
+
+// signedDelta formats a change as " (+2)" or " (-1)", or nothing when zero
+func signedDelta(delta int) string {
+	if delta == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%+d)", delta)
+}
+
+// writeCycleGraph writes the import cycles among issues as a DOT graph to
+// the configured file
+func writeCycleGraph(cfg *config.Config, issues []models.Issue) {
+	if err := writeReportToFile(models.CycleGraph(issues), cfg.Output.CycleGraphFile); err != nil {
+		color.Red("Failed to write cycle graph to file: %v\n", err)
+	} else if !isMachineFormat(cfg.Output.Format) {
+		color.Green("📄 Cycle graph saved to: %s\n", cfg.Output.CycleGraphFile)
+	}
+}
+
+// reportSummary prints the summary line, the last line of every analysis, or
+// writes it to output.summary_file
+func reportSummary(cfg *config.Config, result *models.AnalysisResult) {
+	line := analyzer.SummaryLine(result)
+	switch {
+	case cfg.Output.SummaryFile != "":
+		if err := writeReportToFile(line+"\n", cfg.Output.SummaryFile); err != nil {
+			color.Red("Failed to write summary line to file: %v\n", err)
+		}
+	case cfg.Output.SummaryLine:
+		fmt.Fprintln(os.Stderr, line)
+	}
+}
+
+// exitOnCategoryFailures exits with status 1 when a category's sub-score is
+// below its analysis.min_category_scores minimum
+func exitOnCategoryFailures(result *models.AnalysisResult) {
+	failures := result.CategoryFailures()
+	for _, failure := range failures {
+		fmt.Fprintf(os.Stderr, "Failed: %s\n", failure)
+	}
+	if len(failures) > 0 {
+		os.Exit(1)
+	}
+}
+
 func writeReportToFile(report, filePath string) error {
 	dir := filepath.Dir(filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -318,11 +1092,15 @@ func generateConfig() {
 	color.Cyan("🚀 Run 'gophercheck --config=%s .' to use it\n", configPath)
 }
 
-// collectGoFiles recursively finds all .go files in the given path
-func collectGoFiles(path string) ([]string, error) {
+// collectGoFiles recursively finds all .go files in the given path, including
+// _test.go files only when files.IncludeTests is set, descending into
+// symlinked directories only when files.FollowSymlinks is set, and into
+// vendor directories only when files.IgnoreVendor is unset or path is inside one
+func collectGoFiles(path string, files config.FilesConfig) ([]string, error) {
 	var goFiles []string
+	globs := workspace.NewFileGlobs(files.Include, files.Exclude)
 
-	err := filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+	err := workspace.Walk(path, files.FollowSymlinks, func(filePath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -330,16 +1108,22 @@ func collectGoFiles(path string) ([]string, error) {
 		// Skip vendor, .git, and other common directories
 		if info.IsDir() {
 			name := info.Name()
-			if name == "vendor" || name == ".git" || name == "node_modules" {
+			if name == ".git" || name == "node_modules" || workspace.SkipVendorDir(filePath, path, files.IgnoreVendor) || globs.SkipDir(path, filePath) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// Only include .go files, but exclude _test.go files for now
-		if strings.HasSuffix(filePath, ".go") && !strings.HasSuffix(filePath, "_test.go") {
-			goFiles = append(goFiles, filePath)
+		if !strings.HasSuffix(filePath, ".go") {
+			return nil
+		}
+		if strings.HasSuffix(filePath, "_test.go") && !files.IncludeTests {
+			return nil
+		}
+		if !globs.Keep(path, filePath) {
+			return nil
 		}
+		goFiles = append(goFiles, filePath)
 
 		return nil
 	})
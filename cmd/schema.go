@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"fmt"
+
+	"gophercheck/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema for --format=json output",
+	Long: fmt.Sprintf(`Print the JSON Schema describing the JSON report format.
+
+The current result format is schema_version %s. Within a major version
+fields are only added, never renamed or removed.`, models.SchemaVersion),
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(string(models.ResultSchema))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
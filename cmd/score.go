@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	scoreGradeFlag     bool
+	scoreFailUnderFlag int
+	scoreCategoryFlag  string
+	scoreBadgeFlag     bool
+)
+
+var scoreCmd = &cobra.Command{
+	Use:   "score [files or directories]",
+	Short: "Print only the performance score",
+	Long: `Run the analysis and print only the numeric performance score, for badges,
+Makefiles, and shell scripts.
+
+Examples:
+	gophercheck score .                      # e.g. "82"
+	gophercheck score --grade .              # e.g. "82 B"
+	gophercheck score --fail-under 75 .      # Exit 1 if the score is below 75
+	gophercheck score --category memory .    # The memory category's sub-score
+	gophercheck score --badge . > badge.json # shields.io endpoint badge
+
+Category minimums in analysis.min_category_scores also apply.`,
+	Run: runScore,
+}
+
+func init() {
+	scoreCmd.Flags().BoolVar(&scoreGradeFlag, "grade", false, "Also print the letter grade")
+	scoreCmd.Flags().IntVar(&scoreFailUnderFlag, "fail-under", 0, "Exit with status 1 if the score is below this value")
+	scoreCmd.Flags().StringVar(&scoreCategoryFlag, "category", "", "Use this category's sub-score (performance, complexity, memory, quality)")
+	scoreCmd.Flags().BoolVar(&scoreBadgeFlag, "badge", false, "Print the score as shields.io endpoint badge JSON")
+	rootCmd.AddCommand(scoreCmd)
+}
+
+func runScore(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+
+	if scoreCategoryFlag != "" && !slices.Contains(cfg.Analysis.EnabledCategories, scoreCategoryFlag) {
+		fmt.Fprintf(os.Stderr, "Unknown or disabled category %s (enabled: %s)\n", scoreCategoryFlag, strings.Join(cfg.Analysis.EnabledCategories, ", "))
+		os.Exit(1)
+	}
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	analyzerEngine, goFiles, err := prepareAnalysis(cfg, args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(goFiles) == 0 {
+		fmt.Fprintln(os.Stderr, "No Go files found to analyze")
+		os.Exit(1)
+	}
+
+	result, err := analyzerEngine.AnalyzeFiles(goFiles)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Analysis failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !result.Graded() {
+		fmt.Fprintln(os.Stderr, "No files analyzed, nothing to score")
+		os.Exit(1)
+	}
+
+	score, grade := result.PerformanceScore, result.Grade
+	if scoreCategoryFlag != "" {
+		score = result.CategoryScores[scoreCategoryFlag]
+		grade = models.GradeFor(score, cfg.Analysis.GradingScale)
+	}
+
+	switch {
+	case scoreBadgeFlag:
+		fmt.Println(scoreBadge(cfg, scoreCategoryFlag, score, grade))
+	case scoreGradeFlag:
+		fmt.Printf("%d %s\n", score, grade)
+	default:
+		fmt.Println(score)
+	}
+
+	exitOnCategoryFailures(result)
+	if score < scoreFailUnderFlag {
+		os.Exit(1)
+	}
+}
+
+// scoreBadge renders a score as shields.io endpoint JSON, colored by the
+// score thresholds, e.g. https://img.shields.io/endpoint?url=<badge.json url>
+func scoreBadge(cfg *config.Config, category string, score int, grade string) string {
+	label := "gophercheck"
+	if category != "" {
+		label += " " + category
+	}
+
+	thresholds := cfg.Analysis.ScoreThresholds
+	color := "red"
+	switch {
+	case score >= thresholds.Excellent:
+		color = "brightgreen"
+	case score >= thresholds.Good:
+		color = "green"
+	case score >= thresholds.Fair:
+		color = "yellow"
+	}
+
+	badge, _ := json.Marshal(struct {
+		SchemaVersion int    `json:"schemaVersion"`
+		Label         string `json:"label"`
+		Message       string `json:"message"`
+		Color         string `json:"color"`
+	}{1, label, fmt.Sprintf("%d (%s)", score, grade), color})
+	return string(badge)
+}
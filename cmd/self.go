@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/models"
+	"gophercheck/internal/workspace"
+
+	"github.com/spf13/cobra"
+)
+
+// selfModulePath is the module path the self gate looks for when locating
+// gophercheck's own source tree
+const selfModulePath = "gophercheck"
+
+// selfPinFile holds the pinned strict-profile rule densities, at the module root
+const selfPinFile = ".gophercheck-self.json"
+
+var selfUpdateFlag bool
+
+var selfCmd = &cobra.Command{
+	Use:   "self",
+	Short: "Gate gophercheck's own source against its pinned issue densities",
+	Long: `Analyze gophercheck's own source tree with the strict profile and compare each
+rule's issues per 1000 lines of code against the densities pinned in
+.gophercheck-self.json at the module root. Exits 1 when any rule fires more
+densely than pinned, or a detector fails, so it can be used as a release
+gate. Densities, unlike the score, keep telling regressions apart however
+many issues the tree has. Run from anywhere inside the gophercheck checkout.
+
+The strict profile lowers the complexity and function length thresholds,
+keeps lower-confidence findings, and doesn't downgrade rarely-run code.
+
+Examples:
+	gophercheck self            # Check against the pin
+	gophercheck self --update   # Pin the current densities after an improvement`,
+	Args: cobra.NoArgs,
+	Run:  runSelf,
+}
+
+func init() {
+	selfCmd.Flags().BoolVar(&selfUpdateFlag, "update", false, "Write the current results as the new pin")
+	rootCmd.AddCommand(selfCmd)
+}
+
+// selfPin is the pinned outcome of the self analysis: how densely each rule
+// fires, and the lines of code that was measured over
+type selfPin struct {
+	LOC   int                `json:"loc"`
+	Rules map[string]float64 `json:"issues_per_kloc"`
+}
+
+func newSelfPin(densities []models.RuleDensity, loc int) selfPin {
+	pin := selfPin{LOC: loc, Rules: make(map[string]float64, len(densities))}
+	for _, density := range densities {
+		pin.Rules[density.Rule] = density.PerKLOC
+	}
+	return pin
+}
+
+func runSelf(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+	cfg.ApplyStrictProfile()
+
+	root, err := selfModuleRoot()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	// Package patterns resolve against the working directory, and ./...
+	// leaves out testdata's deliberately bad code
+	if err := os.Chdir(root); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	analyzerEngine, goFiles, err := prepareAnalysis(cfg, []string{"./..."})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(goFiles) == 0 {
+		fmt.Fprintln(os.Stderr, "No Go files found to analyze")
+		os.Exit(1)
+	}
+
+	_, result, err := analyzerEngine.AnalyzeMetrics(goFiles)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Analysis failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(analyzer.SummaryLine(result))
+
+	current := newSelfPin(analyzerEngine.RuleDensities(result))
+	pinPath := filepath.Join(root, selfPinFile)
+	if selfUpdateFlag {
+		if err := writeSelfPin(pinPath, current); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", selfPinFile, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Pinned the densities of %d rules over %d lines in %s\n", len(current.Rules), current.LOC, selfPinFile)
+		return
+	}
+
+	pinned, err := readSelfPin(pinPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v (run with --update to create it)\n", selfPinFile, err)
+		os.Exit(1)
+	}
+
+	regressions := compareSelfPin(pinned, current)
+	for _, failure := range result.DetectorFailures {
+		regressions = append(regressions, fmt.Sprintf("detector %s panicked on %s: %s", failure.Rule, failure.File, failure.Panic))
+	}
+	if len(regressions) > 0 {
+		for _, regression := range regressions {
+			fmt.Fprintf(os.Stderr, "Regression: %s\n", regression)
+		}
+		os.Exit(1)
+	}
+	if !maps.Equal(current.Rules, pinned.Rules) {
+		fmt.Println("Results improved on the pin; run gophercheck self --update to ratchet it")
+	}
+}
+
+// selfModuleRoot walks up from the working directory to the gophercheck
+// module root
+func selfModuleRoot() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	module := workspace.NewResolver().ModuleFor(filepath.Join(wd, "self.go"))
+	if module == nil || module.Path != selfModulePath {
+		return "", fmt.Errorf("not inside the %s source tree", selfModulePath)
+	}
+	return module.Dir, nil
+}
+
+// compareSelfPin lists the rules that fire more densely in current than
+// pinned, a rule missing from the pin counting as pinned at 0
+func compareSelfPin(pinned, current selfPin) []string {
+	var regressions []string
+	for _, rule := range slices.Sorted(maps.Keys(current.Rules)) {
+		if density := current.Rules[rule]; density > pinned.Rules[rule] {
+			regressions = append(regressions, fmt.Sprintf("%s: %.2f issues per KLOC, %.2f pinned", rule, density, pinned.Rules[rule]))
+		}
+	}
+	return regressions
+}
+
+func readSelfPin(path string) (selfPin, error) {
+	var pin selfPin
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return pin, err
+	}
+	if err := json.Unmarshal(data, &pin); err != nil {
+		return pin, err
+	}
+	if pin.Rules == nil {
+		return pin, fmt.Errorf("it pins a score, not issues_per_kloc")
+	}
+	return pin, nil
+}
+
+func writeSelfPin(path string, pin selfPin) error {
+	data, err := json.MarshalIndent(pin, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"gophercheck/internal/analyzer"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsFormatFlag string
+	statsTopFlag    int
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [files or directories]",
+	Short: "Print an overview of the codebase",
+	Long: `Run the analysis and summarize the codebase instead of listing issues: file,
+package, and function counts, lines of code, the distribution of function
+length and complexity, the largest functions, and issues per rule relative to
+the code size.
+
+Examples:
+	gophercheck stats .                  # Console overview
+	gophercheck stats --top 20 .         # List the 20 largest functions
+	gophercheck stats --format json .    # For dashboards`,
+	Run: runStats,
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsFormatFlag, "format", "console", "Output format (console, json)")
+	statsCmd.Flags().IntVar(&statsTopFlag, "top", 10, "Number of largest functions to list")
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+
+	if !slices.Contains(analyzer.StatsFormats, statsFormatFlag) {
+		fmt.Fprintf(os.Stderr, "Invalid format: %s (valid: %v)\n", statsFormatFlag, analyzer.StatsFormats)
+		os.Exit(1)
+	}
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	analyzerEngine, goFiles, err := prepareAnalysis(cfg, args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(goFiles) == 0 {
+		fmt.Fprintln(os.Stderr, "No Go files found to analyze")
+		os.Exit(1)
+	}
+
+	stats, err := analyzerEngine.AnalyzeStats(goFiles, max(statsTopFlag, 0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Analysis failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	output, err := analyzer.FormatStats(stats, statsFormatFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to format stats: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(output)
+}
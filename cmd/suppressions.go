@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	suppressionsFormatFlag   string
+	suppressionsBaselineFlag string
+)
+
+var suppressionsCmd = &cobra.Command{
+	Use:   "suppressions [files or directories]",
+	Short: "List the findings silenced by directives and the baseline",
+	Long: `Audit the exceptions a codebase has accumulated: every //gophercheck:ignore
+directive, with its rules, reason, until date, and how many findings it
+covers, and every current issue the baseline accepts as pre-existing. Each
+comes with the author and age of its line, from git blame, so teams can
+review what has been silenced and for how long.
+
+The baseline is output.baseline from the configuration, or --baseline.
+
+Examples:
+	gophercheck suppressions .                           # Inline directives
+	gophercheck suppressions --baseline report.json .    # And baseline entries
+	gophercheck suppressions --format json .             # For scripts`,
+	Run: runSuppressions,
+}
+
+func init() {
+	suppressionsCmd.Flags().StringVar(&suppressionsFormatFlag, "format", "console", "Output format (console, json)")
+	suppressionsCmd.Flags().StringVar(&suppressionsBaselineFlag, "baseline", "", "Earlier JSON report whose accepted issues to list")
+	rootCmd.AddCommand(suppressionsCmd)
+}
+
+func runSuppressions(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+	if suppressionsBaselineFlag != "" {
+		cfg.Output.Baseline = suppressionsBaselineFlag
+	}
+
+	if !slices.Contains(analyzer.SuppressionAuditFormats, suppressionsFormatFlag) {
+		fmt.Fprintf(os.Stderr, "Invalid format: %s (valid: %v)\n", suppressionsFormatFlag, analyzer.SuppressionAuditFormats)
+		os.Exit(1)
+	}
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	analyzerEngine, goFiles, err := prepareAnalysis(cfg, args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(goFiles) == 0 {
+		fmt.Fprintln(os.Stderr, "No Go files found to analyze")
+		os.Exit(1)
+	}
+
+	var previous []models.Issue
+	expired := cfg.Output.Baseline != "" && baselineExpired(cfg.Output)
+	if cfg.Output.Baseline != "" && !expired {
+		if previous, err = analyzer.LoadBaseline(cfg.Output.Baseline); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	audit, err := analyzerEngine.AuditSuppressions(goFiles, cfg.Output.Baseline, previous)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Analysis failed: %v\n", err)
+		os.Exit(1)
+	}
+	audit.BaselineExpired = expired
+
+	output, err := analyzer.FormatSuppressionAudit(audit, suppressionsFormatFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to format suppressions: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(output)
+}
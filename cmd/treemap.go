@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"gophercheck/internal/analyzer"
+
+	"github.com/spf13/cobra"
+)
+
+var treemapFormatFlag string
+
+var treemapCmd = &cobra.Command{
+	Use:   "treemap [files or directories]",
+	Short: "Export a treemap of where issues concentrate",
+	Long: `Run the analysis and export the codebase as a treemap: one rectangle per
+file, sized by lines of code and colored by penalty density (score penalty per
+100 lines), nested in rectangles for their directories. Hotspots show up as
+large red areas.
+
+Examples:
+	gophercheck treemap . > treemap.svg              # SVG image
+	gophercheck treemap --format json . > tree.json  # For other visualizations`,
+	Run: runTreemap,
+}
+
+func init() {
+	treemapCmd.Flags().StringVar(&treemapFormatFlag, "format", "svg", "Output format (json, svg)")
+	rootCmd.AddCommand(treemapCmd)
+}
+
+func runTreemap(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+
+	if !slices.Contains(analyzer.TreemapFormats, treemapFormatFlag) {
+		fmt.Fprintf(os.Stderr, "Invalid format: %s (valid: %v)\n", treemapFormatFlag, analyzer.TreemapFormats)
+		os.Exit(1)
+	}
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	analyzerEngine, goFiles, err := prepareAnalysis(cfg, args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(goFiles) == 0 {
+		fmt.Fprintln(os.Stderr, "No Go files found to analyze")
+		os.Exit(1)
+	}
+
+	root, _, err := analyzerEngine.AnalyzeTreemap(goFiles)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Analysis failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	output, err := analyzer.FormatTreemap(root, treemapFormatFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to format treemap: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(output)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+	"gophercheck/internal/upload"
+)
+
+// uploadResult POSTs the JSON result to output.upload.url, whatever --format
+// printed. A failed upload is reported but doesn't fail the run, so an
+// unavailable collector doesn't block builds; Ctrl-C stops its retries.
+func uploadResult(cfg *config.Config, result *models.AnalysisResult) {
+	jsonCfg := *cfg
+	jsonCfg.Output.Format = "json"
+	report := analyzer.NewReportGeneratorWithConfig(&jsonCfg).Generate(result)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := upload.NewSink(cfg.Output.Upload, os.Getenv).Send(ctx, []byte(report)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Uploaded results to %s\n", cfg.Output.Upload.URL)
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X gophercheck/cmd.version=v1.2.0 -X gophercheck/cmd.commit=$(git rev-parse HEAD) -X gophercheck/cmd.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// When unset, commit and date fall back to the VCS info embedded by go build.
+var (
+	version   = "dev"
+	commit    = ""
+	buildDate = ""
+)
+
+var versionJSONFlag bool
+
+// buildInfo is the output of `gophercheck version --json`
+type buildInfo struct {
+	Version       string                  `json:"version"`
+	Commit        string                  `json:"commit"`
+	BuildDate     string                  `json:"build_date"`
+	GoVersion     string                  `json:"go_version"`
+	Platform      string                  `json:"platform"`
+	SchemaVersion string                  `json:"schema_version"`
+	Detectors     []analyzer.DetectorInfo `json:"detectors"`
+
+	// Retired rule ids that configs may still name, with their replacements
+	DeprecatedRules []config.RuleDeprecation `json:"deprecated_rules,omitempty"`
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version, build information, and built-in detectors",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		info := currentBuildInfo()
+
+		if versionJSONFlag {
+			data, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				fmt.Printf("Error generating version info: %v\n", err)
+				return
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		fmt.Printf("gophercheck %s\n", info.Version)
+		fmt.Printf("  commit:         %s\n", info.Commit)
+		fmt.Printf("  built:          %s\n", info.BuildDate)
+		fmt.Printf("  go:             %s (%s)\n", info.GoVersion, info.Platform)
+		fmt.Printf("  schema version: %s\n", info.SchemaVersion)
+		fmt.Println("  detectors:")
+		for _, detector := range info.Detectors {
+			fmt.Printf("    %-22s %s\n", detector.Rule, detector.Version)
+		}
+		if len(info.DeprecatedRules) > 0 {
+			fmt.Println("  deprecated rules:")
+			for _, deprecation := range info.DeprecatedRules {
+				replacement := strings.Join(deprecation.ReplacedBy, ", ")
+				if replacement == "" {
+					replacement = "(retired)"
+				}
+				fmt.Printf("    %-22s -> %s since %s\n", deprecation.Rule, replacement, deprecation.Since)
+			}
+		}
+	},
+}
+
+func init() {
+	versionCmd.Flags().BoolVar(&versionJSONFlag, "json", false, "Print build information as JSON")
+	rootCmd.AddCommand(versionCmd)
+}
+
+func currentBuildInfo() buildInfo {
+	info := buildInfo{
+		Version:       version,
+		Commit:        commit,
+		BuildDate:     buildDate,
+		GoVersion:     runtime.Version(),
+		Platform:      runtime.GOOS + "/" + runtime.GOARCH,
+		SchemaVersion: models.SchemaVersion,
+		Detectors:     analyzer.BuiltinDetectors(),
+
+		DeprecatedRules: config.DeprecatedRules,
+	}
+
+	if embedded, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "dev" && embedded.Main.Version != "" && embedded.Main.Version != "(devel)" {
+			info.Version = embedded.Main.Version
+		}
+		for _, setting := range embedded.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = setting.Value
+			case setting.Key == "vcs.modified" && setting.Value == "true" && info.Commit != "":
+				info.Commit += "-dirty"
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = "unknown"
+	}
+	return info
+}
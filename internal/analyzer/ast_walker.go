@@ -1,131 +1,359 @@
 package analyzer
 
 import (
+	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/importer"
 	"go/parser"
+	"go/scanner"
 	"go/token"
 	"go/types"
+	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"gophercheck/internal/analyzer/detectors"
 	"gophercheck/internal/config"
 	"gophercheck/internal/context"
+	"gophercheck/internal/fix"
 	"gophercheck/internal/models"
+	"gophercheck/internal/ownership"
+	"gophercheck/internal/workspace"
 )
 
 type Analyzer struct {
 	fileSet   *token.FileSet
 	detectors []Detector
+	rules     []string           // Config rule name of each detector, by index
+	fixSafety []models.FixSafety // Default safety of each detector's fixes, by index
 	config    *config.Config
 	context   *context.AnalysisContext
-}
+	paths     *pathNormalizer
+	modules   *workspace.Resolver
+	owners    *ownership.Annotator // Nil unless ownership annotations are enabled
+
+	sources  map[string][]byte    // In-memory file contents, by filename
+	symbols  symbolFilter         // Only report issues in these functions and types when set
+	skipped  []models.SkippedFile // Files left out before analysis, see AddSkipped
+	previous map[string]int       // Issues per file in an earlier run, see SetPreviousIssues
+	deadline time.Time            // When analysis.time_budget runs out, zero without one
+
+	collectMetrics bool                     // Measure every function while analyzing
+	functions      []models.FunctionMetrics // Function metrics collected by the last analysis
+	fileLOC        map[string]int           // Lines of code per file, collected with functions
+
+	failures []models.DetectorFailure // Detector panics since the last finished result
+	clocks   map[string]*ruleClock    // Time per rule since the last finished result
+	slow     []models.SlowRule        // Runs over analysis.rule_budget since the last finished result
+	disabled []bool                   // Detectors turned off by analysis.rule_budget, by index
 
-type Detector interface {
-	Name() string
-	Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue
+	suppressed int                  // Findings silenced by //gophercheck:ignore since the last finished result
+	active     int                  // Directives in effect since the last finished result
+	expired    []models.Suppression // Expired directives since the last finished result
+
+	collectSuppressions bool                 // Keep every directive while analyzing
+	suppressions        []models.Suppression // Directives collected by the last analysis
+
+	cache  map[string]*cachedFile // Files parsed by AnalyzeIncremental, by filename
+	edited map[string][]string    // Functions edited since the previous AnalyzeIncremental, by filename
 }
 
+type Detector = detectors.Detector
+
 func NewAnalyzer() *Analyzer {
 	return NewAnalyzerWithConfig(config.DefaultConfig())
 }
 
 func NewAnalyzerWithConfig(cfg *config.Config) *Analyzer {
+	modules := workspace.NewResolver()
 	analyzer := &Analyzer{
 		fileSet: token.NewFileSet(),
 		config:  cfg,
+		paths:   newPathNormalizer(cfg.Output.PathMode, modules),
+		modules: modules,
+		owners:  ownership.NewAnnotator(cfg.Ownership),
+		sources: make(map[string][]byte),
 		context: &context.AnalysisContext{
-			TypeInfo: &types.Info{
-				Types:      make(map[ast.Expr]types.TypeAndValue),
-				Defs:       make(map[*ast.Ident]types.Object),
-				Uses:       make(map[*ast.Ident]types.Object),
-				Selections: make(map[*ast.SelectorExpr]*types.Selection),
-				Scopes:     make(map[ast.Node]*types.Scope),
-			},
-			CallGraph:   make(map[string]*context.CallInfo),
-			LoopContext: make(map[ast.Node]*context.LoopInfo),
-			DataSizes:   make(map[string]*context.DataSizeInfo),
+			TypeInfo:     newTypeInfo(),
+			CallGraph:    make(map[string]*context.CallInfo),
+			LoopContext:  make(map[ast.Node]*context.LoopInfo),
+			DataSizes:    make(map[string]*context.DataSizeInfo),
+			PackagePaths: make(map[string]string),
+			ModulePaths:  make(map[string]string),
+			GoVersions:   make(map[string]string),
+			Methods:      make(map[string][]*ast.FuncDecl),
+			Imports:      make(map[string][]context.Import),
 		},
 	}
-	// Initialize detectors based on configuration
-	analyzer.detectors = []Detector{}
-
-	// Only add detectors that are enabled in config
-	if cfg.IsRuleEnabled("nested_loops") {
-		detector := detectors.NewNestedLoopDetectorWithConfig(cfg)
-		analyzer.detectors = append(analyzer.detectors, detector)
+	// Only add registered detectors that are enabled in config
+	for _, registration := range detectors.Registered() {
+		if cfg.IsRuleEnabled(registration.Rule) {
+			analyzer.addDetector(registration.Rule, registration.FixSafety, registration.New(cfg))
+		}
 	}
 
-	if cfg.IsRuleEnabled("string_concat") {
-		detector := detectors.NewStringConcatDetectorWithConfig(cfg)
-		analyzer.detectors = append(analyzer.detectors, detector)
-	}
+	return analyzer
+}
 
-	if cfg.IsRuleEnabled("cyclomatic_complexity") {
-		detector := detectors.NewComplexityDetectorWithConfig(cfg)
-		analyzer.detectors = append(analyzer.detectors, detector)
+func newTypeInfo() *types.Info {
+	return &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Scopes:     make(map[ast.Node]*types.Scope),
 	}
+}
 
-	if cfg.IsRuleEnabled("memory_allocation") {
-		detector := detectors.NewMemoryAllocDetectorWithConfig(cfg)
-		analyzer.detectors = append(analyzer.detectors, detector)
-	}
+func (a *Analyzer) addDetector(rule string, fixSafety models.FixSafety, detector Detector) {
+	a.detectors = append(a.detectors, detector)
+	a.rules = append(a.rules, rule)
+	a.fixSafety = append(a.fixSafety, fixSafety)
+}
 
-	if cfg.IsRuleEnabled("slice_growth") {
-		detector := detectors.NewSliceGrowthDetectorWithConfig(cfg)
-		analyzer.detectors = append(analyzer.detectors, detector)
-	}
+// AddSource registers in-memory contents for filename, which is then analyzed
+// from memory instead of disk (e.g. code read from stdin or an editor buffer)
+func (a *Analyzer) AddSource(filename string, src []byte) {
+	a.sources[filename] = src
+}
 
-	if cfg.IsRuleEnabled("data_structure") {
-		detector := detectors.NewDataStructureDetectorWithConfig(cfg)
-		analyzer.detectors = append(analyzer.detectors, detector)
-	}
+// AddSkipped records files left out before analysis, e.g. excluded while
+// collecting them, to be listed in the result with the files skipped during it
+func (a *Analyzer) AddSkipped(skipped ...models.SkippedFile) {
+	a.skipped = append(a.skipped, skipped...)
+}
 
-	if cfg.IsRuleEnabled("function_length") {
-		detector := detectors.NewFunctionLengthDetectorWithConfig(cfg)
-		analyzer.detectors = append(analyzer.detectors, detector)
-	}
+func (a *Analyzer) AnalyzeFiles(filenames []string) (*models.AnalysisResult, error) {
+	return a.analyze(filenames, func(result *models.AnalysisResult, issue models.Issue) {
+		result.AddIssue(issue)
+	})
+}
 
-	if cfg.IsRuleEnabled("import_cycles") {
-		detector := detectors.NewImportCycleDetectorWithConfig(cfg)
-		analyzer.detectors = append(analyzer.detectors, detector)
-	}
+// AnalyzeFilesStream sends each issue to out as soon as its file has been
+// analyzed instead of accumulating them. The returned result carries the
+// totals and score but no Issues. out is closed when analysis finishes.
+func (a *Analyzer) AnalyzeFilesStream(filenames []string, out chan<- models.Issue) (*models.AnalysisResult, error) {
+	defer close(out)
 
-	return analyzer
+	return a.analyze(filenames, func(result *models.AnalysisResult, issue models.Issue) {
+		result.RecordIssue(issue)
+		out <- issue
+	})
 }
 
-func (a *Analyzer) AnalyzeFiles(filenames []string) (*models.AnalysisResult, error) {
+// analyze runs every detector over filenames and passes each surviving issue
+// to emit, file by file, together with the result being built. With
+// analysis.low_memory the files are analyzed a package at a time, and each
+// package's syntax trees and type info are released before the next one.
+func (a *Analyzer) analyze(filenames []string, emit func(*models.AnalysisResult, models.Issue)) (*models.AnalysisResult, error) {
 	startTime := time.Now()
-	var result *models.AnalysisResult
-	if a.config != nil {
-		result = models.NewAnalysisResultWithConfig(a.config)
-	} else {
-		result = models.NewAnalysisResult()
+	result := a.newResult()
+	result.SkippedFiles = append(result.SkippedFiles, a.skipped...)
+
+	batches := [][]string{filenames}
+	lowMemory := a.config != nil && a.config.Analysis.LowMemory
+	budget := a.timeBudget()
+	if budget > 0 {
+		a.deadline = startTime.Add(budget)
+		defer func() { a.deadline = time.Time{} }()
+		result.TimeBudget = a.config.Analysis.TimeBudget
+		filenames = a.densestFirst(filenames)
+	}
+	if lowMemory || budget > 0 {
+		batches = packageBatches(filenames)
 	}
 
+	a.scanImports(filenames)
+
+	imports := a.newImporter() // Shared so dependencies are only type-checked once
+	stopAt := NewIssueLimiter(a.config)
+	for i, batch := range batches {
+		// The first package is started however tight the budget, so a
+		// partial result covers at least one file
+		if i > 0 && a.overBudget(result, slices.Concat(batches[i:]...)) {
+			break
+		}
+		files := a.analyzeBatch(batch, imports, result, stopAt, emit)
+		if lowMemory {
+			a.release(files)
+		}
+		if result.StoppedEarly {
+			break
+		}
+	}
+	a.finishResult(result, startTime)
+	return result, nil
+}
+
+// analyzeBatch parses and analyzes a set of files, adding them to result, and
+// returns their syntax trees
+func (a *Analyzer) analyzeBatch(filenames []string, imports types.Importer, result *models.AnalysisResult, stopAt *IssueLimiter, emit func(*models.AnalysisResult, models.Issue)) []*ast.File {
 	files := make([]*ast.File, 0, len(filenames))
+	parsed := make([]string, 0, len(filenames)) // Names of files, in step with files
 	for _, filename := range filenames {
-		file, err := parser.ParseFile(a.fileSet, filename, nil, parser.ParseComments)
+		if skipped, ok := a.oversized(filename); ok {
+			result.SkippedFiles = append(result.SkippedFiles, skipped)
+			continue
+		}
+
+		var src any
+		if content, ok := a.sources[filename]; ok {
+			src = content
+		}
+		file, err := parser.ParseFile(a.fileSet, filename, src, parser.ParseComments)
 		if err != nil {
-			continue // Skip files with parse errors
+			result.SkippedFiles = append(result.SkippedFiles, parseErrorSkip(filename, err))
+			continue
 		}
 		files = append(files, file)
-		result.Files = append(result.Files, filename)
+		parsed = append(parsed, filename)
+		a.resolveModule(filename)
 	}
 
-	a.buildTypeInfo(files)
+	a.buildTypeInfo(files, parsed, imports)
 
 	a.buildAnalysisContext(files)
 
 	for i, file := range files {
-		filename := result.Files[i]
-		issues := a.analyzeFileWithContext(file, filename)
-		for _, issue := range issues {
-			result.AddIssue(issue)
+		filename := parsed[i]
+		// Generated files are still type-checked, as their package uses them
+		if skipped, ok := a.generated(file, filename); ok {
+			result.SkippedFiles = append(result.SkippedFiles, skipped)
+			continue
+		}
+		if a.config != nil && a.config.Output.StopAtMaxIssues && stopAt.Full() {
+			result.StoppedEarly = true
+			break
+		}
+		// A package started is worth at least one file: parsing and type
+		// checking it are paid for
+		if i > 0 && a.overBudget(result, parsed[i:]) {
+			break
+		}
+
+		result.Files = append(result.Files, filename)
+		if module := a.modules.ModuleFor(filename); module != nil {
+			result.AddModuleFile(module.Path, a.paths.NormalizeDir(module.Dir))
+		}
+		for _, issue := range a.reportableIssues(file, filename) {
+			stopAt.Allow(issue)
+			emit(result, issue)
+		}
+
+		if a.collectMetrics {
+			a.functions = append(a.functions, a.fileMetrics(file, a.paths.Normalize(filename))...)
+			a.fileLOC[a.paths.Normalize(filename)] = codeLines(a.fileSet, file)
+		}
+	}
+	return files
+}
+
+// packageBatches splits filenames into one batch per directory, in order of
+// first appearance. A directory's in-package and external test files share a
+// batch; buildTypeInfo checks them as separate packages.
+func packageBatches(filenames []string) [][]string {
+	var batches [][]string
+	index := make(map[string]int)
+	for _, filename := range filenames {
+		dir := filepath.Dir(filename)
+		i, ok := index[dir]
+		if !ok {
+			i = len(batches)
+			index[dir] = i
+			batches = append(batches, nil)
+		}
+		batches[i] = append(batches[i], filename)
+	}
+	return batches
+}
+
+// release drops the syntax trees of analyzed files and everything derived
+// from them, so memory use is bounded by the largest package rather than the
+// whole tree. Calls are then only counted within a package when estimating
+// how often functions run.
+func (a *Analyzer) release(files []*ast.File) {
+	a.context.TypeInfo = newTypeInfo()
+	a.context.CallGraph = make(map[string]*context.CallInfo)
+	a.context.LoopContext = make(map[ast.Node]*context.LoopInfo)
+	a.context.DataSizes = make(map[string]*context.DataSizeInfo)
+	a.context.Methods = make(map[string][]*ast.FuncDecl)
+	for _, file := range files {
+		if tokenFile := a.fileSet.File(file.Pos()); tokenFile != nil {
+			a.fileSet.RemoveFile(tokenFile)
 		}
 	}
+}
+
+// resolveModule records the import path, module path, and Go version of
+// filename in the analysis context and returns its module, or nil outside a
+// module. Outside a module, a file under GOPATH still gets its import path.
+func (a *Analyzer) resolveModule(filename string) *workspace.Module {
+	if importPath := a.modules.ImportPath(filename); importPath != "" {
+		a.context.PackagePaths[filename] = importPath
+	}
+	module := a.modules.ModuleFor(filename)
+	if module == nil {
+		return nil
+	}
+	a.context.ModulePaths[filename] = module.Path
+	if module.GoVersion != "" {
+		a.context.GoVersions[filename] = module.GoVersion
+	}
+	return module
+}
+
+func (a *Analyzer) newResult() *models.AnalysisResult {
+	if a.config != nil {
+		return models.NewAnalysisResultWithConfig(a.config)
+	}
+	return models.NewAnalysisResult()
+}
+
+// oversized reports a file larger than Files.MaxFileSize, which is skipped
+// rather than parsed: huge generated files would dominate the runtime
+func (a *Analyzer) oversized(filename string) (models.SkippedFile, bool) {
+	if a.config == nil || a.config.Files.MaxFileSize <= 0 {
+		return models.SkippedFile{}, false
+	}
+
+	size := a.fileSize(filename)
+	limit := int64(a.config.Files.MaxFileSize) * 1024
+	if size <= limit {
+		return models.SkippedFile{}, false
+	}
+	return models.SkippedFile{
+		File:   filename,
+		Kind:   models.SkipTooLarge,
+		Reason: fmt.Sprintf("larger than max_file_size (%d KB > %d KB)", (size+1023)/1024, a.config.Files.MaxFileSize),
+		Size:   size,
+	}, true
+}
+
+// finishResult normalizes the analyzed file names, puts the result in its
+// canonical order, and computes the score
+func (a *Analyzer) finishResult(result *models.AnalysisResult, startTime time.Time) {
+	for i, filename := range result.Files {
+		result.Files[i] = a.paths.Normalize(filename)
+	}
+	for i := range result.SkippedFiles {
+		result.SkippedFiles[i].File = a.paths.Normalize(result.SkippedFiles[i].File)
+	}
+	result.DetectorFailures = append(result.DetectorFailures, a.failures...)
+	a.failures = nil
+	result.SuppressedIssues += a.suppressed
+	result.ActiveSuppressions += a.active
+	result.ExpiredSuppressions = append(result.ExpiredSuppressions, a.expired...)
+	a.suppressed, a.active, a.expired = 0, 0, nil
+	a.addTimings(result)
+	if a.config != nil {
+		result.PolicyRejections = a.config.PolicyRejections
+	}
+	result.Canonicalize()
+	result.UpdateScope()
 
 	result.AnalysisDuration = time.Since(startTime).String()
 	if a.config != nil {
@@ -133,8 +361,145 @@ func (a *Analyzer) AnalyzeFiles(filenames []string) (*models.AnalysisResult, err
 	} else {
 		result.CalculateScore()
 	}
+}
 
-	return result, nil
+// statementOf returns a function finding the innermost statement of file that
+// contains an issue's position. Issues without a column, or outside any
+// statement, get their line instead.
+func (a *Analyzer) statementOf(file *ast.File) func(models.Issue) models.StatementSpan {
+	tokenFile := a.fileSet.File(file.Pos())
+	var statements []ast.Stmt
+	ast.Inspect(file, func(n ast.Node) bool {
+		if stmt, ok := n.(ast.Stmt); ok {
+			if _, block := stmt.(*ast.BlockStmt); !block {
+				statements = append(statements, stmt)
+			}
+		}
+		return true
+	})
+
+	return func(issue models.Issue) models.StatementSpan {
+		if tokenFile == nil || issue.Line < 1 || issue.Line > tokenFile.LineCount() {
+			return models.StatementSpan{Start: -issue.Line}
+		}
+		lineStart := tokenFile.LineStart(issue.Line)
+		if issue.Column > 0 {
+			pos := lineStart + token.Pos(issue.Column-1)
+			var innermost ast.Stmt
+			for _, stmt := range statements {
+				if stmt.Pos() <= pos && pos < stmt.End() && (innermost == nil || stmt.Pos() >= innermost.Pos()) {
+					innermost = stmt
+				}
+			}
+			if innermost != nil {
+				return models.StatementSpan{Start: tokenFile.Offset(innermost.Pos()), End: tokenFile.Offset(innermost.End())}
+			}
+		}
+		start := tokenFile.Offset(lineStart)
+		return models.StatementSpan{Start: start, End: start}
+	}
+}
+
+// reportableIssues runs the detectors over one file and returns the issues
+// that pass the confidence and symbol filters and aren't
+// suppressed, merged and normalized for reporting
+func (a *Analyzer) reportableIssues(file *ast.File, filename string) []models.Issue {
+	var issues []models.Issue
+	var symbolLines []lineRange
+	if a.symbols.active() {
+		symbolLines = a.symbolLines(file)
+	}
+	suppressions := a.fileSuppressions(file, filename)
+	silenced := 0
+	for _, issue := range a.analyzeFileWithContext(file, filename) {
+		if !a.meetsConfidence(issue) {
+			continue
+		}
+		if a.symbols.active() && !inRanges(issue.Line, symbolLines) {
+			continue
+		}
+		if suppressed(suppressions, issue) {
+			silenced++
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	a.recordSuppressions(suppressions, silenced, symbolLines)
+
+	// Duplicates share a file, so merging per file is equivalent to merging globally
+	if a.config != nil && a.config.Analysis.MergeDuplicates {
+		issues = models.MergeDuplicateIssues(issues, a.statementOf(file))
+	}
+	// Only the issues reported count as repeats
+	a.escalateRepeats(issues)
+	a.renderFixDiffs(filename, issues)
+	a.owners.Annotate(filename, issues)
+
+	module := a.modules.ModuleFor(filename)
+	for i := range issues {
+		if module != nil {
+			issues[i].Module = module.Path
+		}
+		if a.config != nil {
+			issues[i].DocsURL = issues[i].Type.DocsURL(a.config.Output.DocsBaseURL)
+		}
+		a.paths.normalizeIssue(&issues[i])
+	}
+	return issues
+}
+
+// renderFixDiffs fills in the unified diff and positioned edits of each
+// suggested fix in a file, dropping fixes whose edits can't be applied
+func (a *Analyzer) renderFixDiffs(filename string, issues []models.Issue) {
+	var src []byte
+	for i := range issues {
+		suggested := issues[i].SuggestedFix
+		if suggested == nil {
+			continue
+		}
+		if src == nil {
+			var err error
+			if src, err = a.ReadSource(filename); err != nil {
+				return
+			}
+		}
+
+		fixed, err := fix.Preview(src, suggested)
+		if err != nil {
+			issues[i].SuggestedFix = nil
+			continue
+		}
+		suggested.Diff = fix.UnifiedDiff(a.paths.Normalize(filename), src, fixed)
+		suggested.TextEdits = fix.RangeEdits(src, suggested.Edits)
+	}
+}
+
+// ReadSource returns the contents of a file being analyzed, preferring source
+// added with AddSource
+func (a *Analyzer) ReadSource(filename string) ([]byte, error) {
+	if src, ok := a.sources[filename]; ok {
+		return src, nil
+	}
+	return os.ReadFile(filename)
+}
+
+// meetsConfidence reports whether an issue clears the configured min_confidence
+func (a *Analyzer) meetsConfidence(issue models.Issue) bool {
+	if a.config == nil {
+		return true
+	}
+	return issue.Confidence >= a.config.Analysis.MinConfidence
+}
+
+// ResolvePath maps a reported (normalized) file path back to the path that
+// was read from disk
+func (a *Analyzer) ResolvePath(reported string) string {
+	return a.paths.Original(reported)
+}
+
+// ReportedPath returns the path under which issues in filename are reported
+func (a *Analyzer) ReportedPath(filename string) string {
+	return a.paths.Normalize(filename)
 }
 
 func (a *Analyzer) GetConfig() *config.Config {
@@ -201,14 +566,41 @@ func (v *ASTVisitor) GetCodeSnippet(pos token.Pos, node ast.Node) string {
 	return position.String()
 }
 
-func (a *Analyzer) buildTypeInfo(files []*ast.File) {
+// buildTypeInfo type-checks each package separately under its module import
+// path, so files from different packages or modules don't pollute each
+// other's scopes. All packages share one types.Info.
+func (a *Analyzer) buildTypeInfo(files []*ast.File, filenames []string, imports types.Importer) {
 	typesConfig := &types.Config{
-		Importer: importer.ForCompiler(a.fileSet, "source", nil),
+		Importer: imports,
 		Error: func(err error) {
 		},
 	}
 
-	typesConfig.Check("", a.fileSet, files, a.context.TypeInfo)
+	type packageKey struct{ dir, name string }
+	var order []packageKey
+	packages := make(map[packageKey][]*ast.File)
+	for i, file := range files {
+		key := packageKey{filepath.Dir(filenames[i]), file.Name.Name}
+		if _, seen := packages[key]; !seen {
+			order = append(order, key)
+		}
+		packages[key] = append(packages[key], file)
+	}
+
+	for _, key := range order {
+		pkgFiles := packages[key]
+		importPath := a.context.PackagePaths[a.fileSet.Position(pkgFiles[0].Pos()).Filename]
+		if strings.HasSuffix(key.name, "_test") && importPath != "" {
+			importPath += "_test"
+		}
+		typesConfig.Check(importPath, a.fileSet, pkgFiles, a.context.TypeInfo)
+	}
+}
+
+// newImporter returns an importer that type-checks dependencies from source
+// and caches them, so it must not outlive changes to the analyzed code
+func (a *Analyzer) newImporter() types.Importer {
+	return importer.ForCompiler(a.fileSet, "source", nil)
 }
 
 func (a *Analyzer) buildAnalysisContext(files []*ast.File) {
@@ -216,6 +608,69 @@ func (a *Analyzer) buildAnalysisContext(files []*ast.File) {
 		a.analyzeCallPatterns(file)
 		a.analyzeLoopPatterns(file)
 		a.analyzeDataSizes(file)
+		a.analyzeMethods(file)
+	}
+}
+
+// analyzeMethods records the methods declared in file by receiver type, so
+// a type's methods can be looked at together wherever they are declared
+func (a *Analyzer) analyzeMethods(file *ast.File) {
+	dir := filepath.Dir(a.fileSet.Position(file.Package).Filename)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if receiver := detectors.ReceiverType(fn); receiver != "" {
+			key := context.TypeKey(dir, file.Name.Name, receiver)
+			a.context.Methods[key] = append(a.context.Methods[key], fn)
+		}
+	}
+}
+
+// scanImports records the imports of every file in the analysis context,
+// parsing only their import declarations, so import graph detectors see all
+// packages whichever batch they run in
+func (a *Analyzer) scanImports(filenames []string) {
+	a.context.Imports = make(map[string][]context.Import)
+	fset := token.NewFileSet() // Kept apart: the full parse comes later
+	for _, filename := range filenames {
+		if _, ok := a.oversized(filename); ok {
+			continue
+		}
+		var src any
+		if content, ok := a.sources[filename]; ok {
+			src = content
+		}
+		file, err := parser.ParseFile(fset, filename, src, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		a.resolveModule(filename)
+		a.analyzeImports(fset, file, filename)
+	}
+}
+
+// analyzeImports records the imports of file under its package
+func (a *Analyzer) analyzeImports(fset *token.FileSet, file *ast.File, filename string) {
+	pkg := a.context.PackagePath(filename)
+	if strings.HasSuffix(file.Name.Name, "_test") {
+		pkg += "_test" // External test package
+	}
+	if _, ok := a.context.Imports[pkg]; !ok {
+		a.context.Imports[pkg] = nil // Analyzed, even without imports
+	}
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		a.context.Imports[pkg] = append(a.context.Imports[pkg], context.Import{
+			Path:     importPath,
+			File:     filename,
+			Line:     fset.Position(spec.Pos()).Line,
+			Vendored: a.modules.Vendored(filename, importPath),
+		})
 	}
 }
 
@@ -232,7 +687,8 @@ func (a *Analyzer) analyzeCallPatterns(file *ast.File) {
 				}
 			}
 		case *ast.CallExpr:
-			if ident, ok := node.Fun.(*ast.Ident); ok {
+			// Calls to generic functions may name the type arguments: Map[int](xs)
+			if ident, ok := detectors.Uninstantiate(node.Fun).(*ast.Ident); ok {
 				if callInfo, exists := a.context.CallGraph[ident.Name]; exists {
 					callInfo.CallSites = append(callInfo.CallSites, node)
 				}
@@ -244,27 +700,35 @@ func (a *Analyzer) analyzeCallPatterns(file *ast.File) {
 
 func (a *Analyzer) analyzeLoopPatterns(file *ast.File) {
 	loopDepth := 0
+	benchmarks := a.config != nil && a.config.Tests.TestFunctions.AllowBenchmarkLoopAllocs &&
+		strings.HasSuffix(a.fileSet.Position(file.Package).Filename, "_test.go")
+	inBenchmark := false
 
 	ast.Inspect(file, func(n ast.Node) bool {
 		switch node := n.(type) {
+		case *ast.FuncDecl:
+			inBenchmark = benchmarks && node.Recv == nil && strings.HasPrefix(node.Name.Name, "Benchmark")
+
 		case *ast.ForStmt:
 			loopDepth++
 			a.context.LoopContext[node] = &context.LoopInfo{
-				LoopNode:     node,
-				BoundType:    a.analyzeLoopBounds(node),
-				EstimatedMax: a.estimateLoopMax(node),
-				IsInnerLoop:  loopDepth > 1,
-				HasEarlyExit: a.hasEarlyExit(node),
+				LoopNode:        node,
+				BoundType:       a.analyzeLoopBounds(node),
+				EstimatedMax:    a.estimateLoopMax(node),
+				IsInnerLoop:     loopDepth > 1,
+				HasEarlyExit:    a.hasEarlyExit(node),
+				IsBenchmarkLoop: inBenchmark && a.isBenchmarkLoop(node),
 			}
 
 		case *ast.RangeStmt:
 			loopDepth++
 			a.context.LoopContext[node] = &context.LoopInfo{
-				LoopNode:     node,
-				BoundType:    context.BoundLinear, // Range is always linear
-				EstimatedMax: a.estimateRangeMax(node),
-				IsInnerLoop:  loopDepth > 1,
-				HasEarlyExit: a.hasEarlyExit(node),
+				LoopNode:        node,
+				BoundType:       context.BoundLinear, // Range is always linear
+				EstimatedMax:    a.estimateRangeMax(node),
+				IsInnerLoop:     loopDepth > 1,
+				HasEarlyExit:    a.hasEarlyExit(node),
+				IsBenchmarkLoop: inBenchmark && a.isBenchmarkLoop(node),
 			}
 		}
 		return true
@@ -303,20 +767,204 @@ func (a *Analyzer) analyzeDataSizes(file *ast.File) {
 }
 
 func (a *Analyzer) analyzeFileWithContext(file *ast.File, filename string) []models.Issue {
+	isTest := strings.HasSuffix(filename, "_test.go")
+
 	var allIssues []models.Issue
-	for _, detector := range a.detectors {
-		// This will have compiler errors until we fix the detectors
-		issues := detector.Detect(file, a.fileSet, filename, a.context)
+	for i, detector := range a.detectors {
+		if isTest && a.config != nil && !a.config.IsRuleEnabledForTests(a.rules[i]) {
+			continue
+		}
+		if a.ruleDisabled(i) {
+			continue
+		}
+		start := time.Now()
+		issues := a.runDetector(i, detector, file, filename)
+		a.timeRule(i, filename, time.Since(start))
+		a.classifyFixes(i, issues)
 		allIssues = append(allIssues, issues...)
 	}
+	a.attributeFunctions(file, allIssues)
+
+	if isTest && a.config != nil {
+		allIssues = a.filterTestFunctionIssues(file, allIssues)
+		for i := range allIssues {
+			allIssues[i].Severity = allIssues[i].Severity.Downgrade(a.config.Tests.DowngradeSeverity)
+		}
+	}
+	a.adjustForFrequency(file, allIssues)
+	a.applySeverityFloors(allIssues)
 	return allIssues
 }
 
+// applySeverityFloors raises issues to the severity_floors of their type,
+// after every other adjustment so a policy's floors can't be lowered
+func (a *Analyzer) applySeverityFloors(issues []models.Issue) {
+	if a.config == nil {
+		return
+	}
+	for i := range issues {
+		if floor, ok := models.ParseSeverity(a.config.Analysis.SeverityFloors[string(issues[i].Type)]); ok {
+			issues[i].Severity = max(issues[i].Severity, floor)
+		}
+	}
+}
+
+// generated reports a file with a "Code generated ... DO NOT EDIT." header,
+// which is skipped under files.skip_generated: nobody edits it by hand
+func (a *Analyzer) generated(file *ast.File, filename string) (models.SkippedFile, bool) {
+	if a.config == nil || !a.config.Files.SkipGenerated || !ast.IsGenerated(file) {
+		return models.SkippedFile{}, false
+	}
+	return models.SkippedFile{
+		File:   filename,
+		Kind:   models.SkipGenerated,
+		Reason: "generated code (skip_generated)",
+	}, true
+}
+
+// parseErrorSkip records a file that doesn't parse, with its first error
+func parseErrorSkip(filename string, err error) models.SkippedFile {
+	reason := "parse error: " + err.Error()
+	if list, ok := err.(scanner.ErrorList); ok && len(list) > 0 {
+		reason = fmt.Sprintf("parse error at line %d: %s", list[0].Pos.Line, list[0].Msg)
+		if len(list) > 1 {
+			reason += fmt.Sprintf(" (and %d more)", len(list)-1)
+		}
+	}
+	return models.SkippedFile{File: filename, Kind: models.SkipParseError, Reason: reason}
+}
+
+// attributeFunctions names each issue after the innermost function or
+// function literal containing it, so issues in goroutine bodies and handler
+// closures aren't reported against the function that happens to declare them
+func (a *Analyzer) attributeFunctions(file *ast.File, issues []models.Issue) {
+	scopes := detectors.FuncScopes(file)
+	for i := range issues {
+		if scope, ok := detectors.InnermostScope(a.fileSet, scopes, issues[i].Line, issues[i].Column); ok {
+			issues[i].Function = scope.Name
+		}
+	}
+}
+
+// runDetector runs one detector over a file. A panic in the detector is
+// recorded as a detector failure rather than ending the analysis.
+func (a *Analyzer) runDetector(index int, detector Detector, file *ast.File, filename string) (issues []models.Issue) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			a.failures = append(a.failures, models.DetectorFailure{
+				Rule:  a.rules[index],
+				File:  a.paths.Normalize(filename),
+				Panic: fmt.Sprint(recovered),
+			})
+			issues = nil
+		}
+	}()
+	return detector.Detect(file, a.fileSet, filename, a.context)
+}
+
+// classifyFixes gives each suggested fix without a safety level the default
+// of the detector that suggested it, or unsafe when the detector has none
+func (a *Analyzer) classifyFixes(index int, issues []models.Issue) {
+	for i := range issues {
+		fix := issues[i].SuggestedFix
+		if fix == nil || fix.Safety != "" {
+			continue
+		}
+		fix.Safety = a.fixSafety[index]
+		if fix.Safety == "" {
+			fix.Safety = models.FixUnsafe
+		}
+	}
+}
+
+// frequencyAdjustment changes the issues of a function by how often it runs
+type frequencyAdjustment struct {
+	levels     int     // Severity levels to raise (positive) or lower (negative)
+	confidence float64 // Added to the confidence
+}
+
+// adjustForFrequency raises issues in functions marked //gophercheck:hotpath
+// and lowers issues in functions that run rarely: those marked
+// //gophercheck:coldpath and, with rare_code_downgrade, those estimated to be
+// one-time initialization or error handling. Methods sharing a name are only
+// adjusted if they agree.
+func (a *Analyzer) adjustForFrequency(file *ast.File, issues []models.Issue) {
+	adjustments := make(map[string]frequencyAdjustment)
+	seen := make(map[string]bool)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		name := detectors.DeclaredName(fn)
+		adjustment := a.frequencyAdjustment(fn)
+		if seen[name] && adjustments[name] != adjustment {
+			adjustment = frequencyAdjustment{}
+		}
+		adjustments[name] = adjustment
+		seen[name] = true
+	}
+
+	for i := range issues {
+		adjustment := adjustments[issues[i].DeclaredFunction()]
+		if adjustment.levels > 0 {
+			issues[i].Severity = issues[i].Severity.Upgrade(adjustment.levels)
+		} else if adjustment.levels < 0 {
+			issues[i].Severity = issues[i].Severity.Downgrade(-adjustment.levels)
+		}
+		if adjustment.confidence != 0 {
+			issues[i].Confidence = min(max(issues[i].Confidence+adjustment.confidence, 0), 1)
+		}
+	}
+}
+
+func (a *Analyzer) frequencyAdjustment(fn *ast.FuncDecl) frequencyAdjustment {
+	rareLevels := 0
+	if a.config != nil {
+		rareLevels = a.config.Analysis.RareCodeDowngrade
+	}
+
+	if frequency, ok := frequencyDirective(fn); ok {
+		// The developer knows best: adjust even with rare_code_downgrade off
+		switch frequency {
+		case context.FrequencyHigh:
+			return frequencyAdjustment{levels: 1, confidence: 0.1}
+		case context.FrequencyRare:
+			return frequencyAdjustment{levels: -max(rareLevels, 1), confidence: -0.1}
+		}
+	}
+	if rareLevels > 0 && a.estimateFrequency(fn) == context.FrequencyRare {
+		return frequencyAdjustment{levels: -rareLevels}
+	}
+	return frequencyAdjustment{}
+}
+
+// frequencyDirective reads a //gophercheck:hotpath or //gophercheck:coldpath
+// line from the doc comment of fn
+func frequencyDirective(fn *ast.FuncDecl) (context.FrequencyEstimate, bool) {
+	if fn.Doc == nil {
+		return context.FrequencyUnknown, false
+	}
+	for _, comment := range fn.Doc.List {
+		switch strings.TrimSpace(comment.Text) {
+		case "//gophercheck:hotpath":
+			return context.FrequencyHigh, true
+		case "//gophercheck:coldpath":
+			return context.FrequencyRare, true
+		}
+	}
+	return context.FrequencyUnknown, false
+}
+
 func (a *Analyzer) estimateFrequency(fn *ast.FuncDecl) context.FrequencyEstimate {
 	if fn.Name == nil {
 		return context.FrequencyUnknown
 	}
 
+	if frequency, ok := frequencyDirective(fn); ok {
+		return frequency
+	}
+
 	name := strings.ToLower(fn.Name.Name)
 
 	if strings.Contains(name, "error") || strings.Contains(name, "panic") {
@@ -327,6 +975,10 @@ func (a *Analyzer) estimateFrequency(fn *ast.FuncDecl) context.FrequencyEstimate
 		return context.FrequencyRare
 	}
 
+	if isTestHelper(fn) {
+		return context.FrequencyRare
+	}
+
 	if strings.Contains(name, "process") || strings.Contains(name, "handle") ||
 		strings.Contains(name, "loop") || strings.Contains(name, "iterate") {
 		return context.FrequencyHigh
@@ -335,6 +987,28 @@ func (a *Analyzer) estimateFrequency(fn *ast.FuncDecl) context.FrequencyEstimate
 	return context.FrequencyModerate
 }
 
+// isTestHelper reports whether fn marks itself as a test helper with
+// t.Helper(), b.Helper(), or f.Helper()
+func isTestHelper(fn *ast.FuncDecl) bool {
+	if fn.Body == nil {
+		return false
+	}
+	for _, stmt := range fn.Body.List {
+		expr, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+		call, ok := expr.X.(*ast.CallExpr)
+		if !ok || len(call.Args) != 0 {
+			continue
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Helper" {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *Analyzer) analyzeLoopBounds(loop *ast.ForStmt) context.LoopBoundType {
 	if loop.Cond == nil {
 		return context.BoundUnknown
@@ -360,7 +1034,11 @@ func (a *Analyzer) estimateLoopMax(loop *ast.ForStmt) int {
 
 	switch cond := loop.Cond.(type) {
 	case *ast.BinaryExpr:
-		if val := a.extractConstantInt(cond.Y); val > 0 {
+		val := a.extractConstantInt(cond.Y)
+		if val > 0 && cond.Op == token.LEQ {
+			return val + 1
+		}
+		if val > 0 {
 			return val
 		}
 	}
@@ -369,6 +1047,9 @@ func (a *Analyzer) estimateLoopMax(loop *ast.ForStmt) int {
 }
 
 func (a *Analyzer) estimateRangeMax(loop *ast.RangeStmt) int {
+	if count := a.extractConstantInt(loop.X); count >= 0 {
+		return count // range over an integer
+	}
 	if ident, ok := loop.X.(*ast.Ident); ok {
 		if sizeInfo, exists := a.context.DataSizes[ident.Name]; exists {
 			return sizeInfo.EstimatedLen
@@ -419,23 +1100,20 @@ func (a *Analyzer) isMakeCall(call *ast.CallExpr) bool {
 	return false
 }
 
+// extractConstantInt returns the value of an integer constant expression:
+// a literal or, with type info, a named or computed constant. It returns -1
+// for anything else.
 func (a *Analyzer) extractConstantInt(expr ast.Expr) int {
+	if a.context.TypeInfo != nil {
+		if tv, ok := a.context.TypeInfo.Types[expr]; ok && tv.Value != nil {
+			if value, exact := constant.Int64Val(constant.ToInt(tv.Value)); exact && value >= 0 {
+				return int(value)
+			}
+		}
+	}
 	if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.INT {
-		switch lit.Value {
-		case "0":
-			return 0
-		case "1":
-			return 1
-		case "2":
-			return 2
-		case "5":
-			return 5
-		case "10":
-			return 10
-		case "100":
-			return 100
-		case "1000":
-			return 1000
+		if value, err := strconv.ParseInt(lit.Value, 0, 0); err == nil {
+			return int(value)
 		}
 	}
 	return -1
@@ -0,0 +1,33 @@
+package analyzer_test
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"gophercheck/internal/analyzer"
+)
+
+// TestBenchmarkLoopsOnlyInBenchmarks requires the b.N / b.Loop() exemption to
+// cover only the Benchmark functions of test files: loops of the same shape
+// in production code and in test helpers stay hot loops
+func TestBenchmarkLoopsOnlyInBenchmarks(t *testing.T) {
+	want := []string{"Render", "concatParts"}
+
+	files := packageFiles(t, filepath.Join(testdataRoot, "benchmarks"))
+	result, err := analyzer.NewAnalyzerWithConfig(testConfig()).AnalyzeFiles(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, issue := range result.Issues {
+		if issue.Type == "string_concatenation" {
+			got = append(got, issue.Function)
+		}
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("string_concatenation in %v, want %v", got, want)
+	}
+}
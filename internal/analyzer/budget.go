@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"gophercheck/internal/models"
+)
+
+// SetPreviousIssues records how many issues each file had in an earlier run,
+// by reported path, so that under analysis.time_budget the files densest in
+// issues are analyzed first
+func (a *Analyzer) SetPreviousIssues(counts map[string]int) {
+	a.previous = counts
+}
+
+func (a *Analyzer) timeBudget() time.Duration {
+	if a.config == nil {
+		return 0
+	}
+	return a.config.Analysis.Budget()
+}
+
+// densestFirst orders filenames by their previous issues per KB of source,
+// highest first. Files without previous issues keep their order after them.
+func (a *Analyzer) densestFirst(filenames []string) []string {
+	density := make(map[string]float64)
+	for _, filename := range filenames {
+		if count := a.previous[a.paths.Normalize(filename)]; count > 0 {
+			kb := max(float64(a.fileSize(filename))/1024, 1)
+			density[filename] = float64(count) / kb
+		}
+	}
+	if len(density) == 0 {
+		return filenames
+	}
+
+	ordered := slices.Clone(filenames)
+	slices.SortStableFunc(ordered, func(x, y string) int {
+		return cmp.Compare(density[y], density[x])
+	})
+	return ordered
+}
+
+// overBudget reports whether the time budget has run out. Once it has, the
+// result is marked partial and filenames are recorded as skipped.
+func (a *Analyzer) overBudget(result *models.AnalysisResult, filenames []string) bool {
+	if a.deadline.IsZero() || time.Now().Before(a.deadline) {
+		return false
+	}
+	result.Partial = true
+	for _, filename := range filenames {
+		result.SkippedFiles = append(result.SkippedFiles, models.SkippedFile{
+			File:   filename,
+			Kind:   models.SkipTimeBudget,
+			Reason: fmt.Sprintf("not reached within time_budget (%s)", result.TimeBudget),
+		})
+	}
+	return true
+}
+
+// fileSize returns the size of a file in bytes, in memory or on disk
+func (a *Analyzer) fileSize(filename string) int64 {
+	if src, ok := a.sources[filename]; ok {
+		return int64(len(src))
+	}
+	if info, err := os.Stat(filename); err == nil {
+		return info.Size()
+	}
+	return 0
+}
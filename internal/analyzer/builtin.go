@@ -0,0 +1,36 @@
+package analyzer
+
+import (
+	"gophercheck/internal/analyzer/detectors"
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+)
+
+// DetectorInfo describes a built-in detector for version and build reports
+type DetectorInfo struct {
+	Rule     string `json:"rule"`     // Config rule name
+	Name     string `json:"name"`     // Display name
+	Category string `json:"category"` // Config rules section
+	Version  string `json:"version"`  // Bumped whenever the detector's findings change
+
+	FixSafety models.FixSafety `json:"fix_safety,omitempty"` // Default safety of the detector's suggested fixes, if it suggests any
+}
+
+// BuiltinDetectors returns the rule, name, category, and version of every
+// registered detector, enabled or not
+func BuiltinDetectors() []DetectorInfo {
+	cfg := config.DefaultConfig()
+	registered := detectors.Registered()
+	infos := make([]DetectorInfo, 0, len(registered))
+	for _, registration := range registered {
+		infos = append(infos, DetectorInfo{
+			Rule:     registration.Rule,
+			Name:     registration.New(cfg).Name(),
+			Category: registration.Category,
+			Version:  registration.Version,
+
+			FixSafety: registration.FixSafety,
+		})
+	}
+	return infos
+}
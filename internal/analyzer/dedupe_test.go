@@ -0,0 +1,44 @@
+package analyzer_test
+
+import (
+	"slices"
+	"testing"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/models"
+)
+
+// TestDuplicatesMergePerStatement requires merged duplicates to stay apart
+// when two statements share a line, each keeping the other rules that
+// flagged it
+func TestDuplicatesMergePerStatement(t *testing.T) {
+	engine := analyzer.NewAnalyzerWithConfig(testConfig())
+	engine.AddSource("split.go", []byte(`package p
+
+func Split(values []int) ([]int, []int) {
+	var evens, odds []int
+	for _, v := range values {
+		evens = append(evens, v); odds = append(odds, v+1)
+	}
+	return evens, odds
+}
+`))
+	result, err := engine.AnalyzeFiles([]string{"split.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var columns []int
+	for _, issue := range result.Issues {
+		if issue.Line != 6 {
+			continue
+		}
+		if issue.Type != models.IssueSliceGrowth || !slices.Equal(issue.RelatedTypes, []models.IssueType{models.IssueMemoryAlloc}) {
+			t.Errorf("column %d: %s related to %v, want slice_growth related to memory_allocation", issue.Column, issue.Type, issue.RelatedTypes)
+		}
+		columns = append(columns, issue.Column)
+	}
+	if len(columns) != 2 {
+		t.Errorf("line 6 has findings at columns %v, want one per append", columns)
+	}
+}
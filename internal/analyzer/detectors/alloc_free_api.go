@@ -0,0 +1,224 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strconv"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// AllocFreeAPIDetector finds calls in loops and high-frequency functions to
+// APIs that allocate where an allocation-free alternative exists, e.g.
+// time.Time.Format where AppendFormat writes into a reused buffer. The
+// mapping comes from the alloc_free_api alternatives table in the config.
+type AllocFreeAPIDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*AllocFreeAPIDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "alloc_free_api",
+		Category: "memory",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewAllocFreeAPIDetectorWithConfig(cfg) },
+	})
+}
+
+func NewAllocFreeAPIDetector() *AllocFreeAPIDetector {
+	return &AllocFreeAPIDetector{}
+}
+
+func NewAllocFreeAPIDetectorWithConfig(cfg *config.Config) *AllocFreeAPIDetector {
+	return &AllocFreeAPIDetector{
+		config: cfg,
+	}
+}
+
+func (d *AllocFreeAPIDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *AllocFreeAPIDetector) Name() string {
+	return "Allocation-Free API Detector"
+}
+
+func (d *AllocFreeAPIDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &allocFreeAPIVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		detector: d,
+		context:  ctx,
+		settings: d.settings(),
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+// settings returns the rule config, or the defaults without one
+func (d *AllocFreeAPIDetector) settings() config.AllocFreeAPIConfig {
+	if d.config != nil && d.config.Rules.Memory.AllocFreeAPI.Enabled {
+		return d.config.Rules.Memory.AllocFreeAPI
+	}
+	return config.DefaultConfig().Rules.Memory.AllocFreeAPI
+}
+
+type allocFreeAPIVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	hotFunc     bool // currentFunc is estimated to run at high frequency
+	loopDepth   int
+	detector    *AllocFreeAPIDetector
+	context     *context.AnalysisContext
+	settings    config.AllocFreeAPIConfig
+}
+
+func (v *allocFreeAPIVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Name != nil {
+			v.currentFunc = n.Name.Name
+			v.hotFunc = v.isHotFunction(n.Name.Name)
+		}
+		return v
+
+	case *ast.ForStmt, *ast.RangeStmt:
+		if isBenchmarkTimingLoop(v.context, n) {
+			for _, stmt := range getLoopBody(n) {
+				ast.Walk(v, stmt)
+			}
+			return nil
+		}
+
+		v.loopDepth++
+		for _, stmt := range getLoopBody(n) {
+			ast.Walk(v, stmt)
+		}
+		v.loopDepth--
+		return nil
+
+	case *ast.CallExpr:
+		if v.loopDepth > 0 || (v.hotFunc && v.settings.FlagHotFunctions) {
+			v.checkCall(n)
+		}
+		return v
+
+	default:
+		return v
+	}
+}
+
+func (v *allocFreeAPIVisitor) isHotFunction(name string) bool {
+	if v.context == nil {
+		return false
+	}
+	info, ok := v.context.CallGraph[name]
+	return ok && info.Frequency == context.FrequencyHigh
+}
+
+// checkCall reports call if an alternative without argument_of matches it,
+// and each argument of call that an alternative naming call in its
+// argument_of matches
+func (v *allocFreeAPIVisitor) checkCall(call *ast.CallExpr) {
+	for _, alt := range v.settings.Alternatives {
+		if len(alt.ArgumentOf) == 0 && v.matches(call, alt) {
+			v.createIssue(call, alt)
+			break
+		}
+	}
+
+	for _, arg := range call.Args {
+		inner, ok := arg.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		for _, alt := range v.settings.Alternatives {
+			if len(alt.ArgumentOf) > 0 && v.matches(inner, alt) && v.isCallTo(call, alt.ArgumentOf) {
+				v.createIssue(inner, alt)
+				break
+			}
+		}
+	}
+}
+
+// matches reports whether call is a call to alt.Call with one of its
+// formats, if it lists any
+func (v *allocFreeAPIVisitor) matches(call *ast.CallExpr, alt config.APIAlternative) bool {
+	if !v.isCallTo(call, []string{alt.Call}) {
+		return false
+	}
+	if len(alt.Formats) == 0 {
+		return true
+	}
+	if len(call.Args) == 0 {
+		return false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return false
+	}
+	format, err := strconv.Unquote(lit.Value)
+	return err == nil && slices.Contains(alt.Formats, format) && len(call.Args) == 2
+}
+
+// isCallTo reports whether call calls a function or method in qualified
+func (v *allocFreeAPIVisitor) isCallTo(call *ast.CallExpr, qualified []string) bool {
+	if _, ok := matchCall(v.context, call, qualified); ok {
+		return true
+	}
+	_, ok := matchMethod(v.context, call, qualified)
+	return ok
+}
+
+func (v *allocFreeAPIVisitor) createIssue(call *ast.CallExpr, alt config.APIAlternative) {
+	position := v.fset.Position(call.Pos())
+
+	where := "on every loop iteration"
+	severity := models.SeverityLow
+	switch {
+	case v.loopDepth >= 2:
+		severity = models.SeverityMedium
+	case v.loopDepth == 0:
+		where = fmt.Sprintf("in high-frequency function '%s'", v.currentFunc)
+	}
+
+	issue := models.Issue{
+		Type:        models.IssueAllocFreeAPI,
+		Severity:    severity,
+		File:        v.filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Function:    v.currentFunc,
+		Message:     fmt.Sprintf("%s allocates %s - an allocation-free alternative exists", shortCallName(alt.Call), where),
+		Suggestion:  suggestions.Render("alloc_free_api.replace", suggestions.Data{Type: shortCallName(alt.Call), Source: alt.Replacement, Var: alt.Note}),
+		Complexity:  "An allocation per call",
+		CodeSnippet: position.String(),
+		Confidence:  0.7, // The result may be kept, which the alternative also has to allocate for
+		Impact:      "Allocation-free calls",
+		FixEffort:   models.EffortSmall,
+	}
+	v.issues = append(v.issues, issue)
+}
+
+// shortCallName drops the import path from a qualified call:
+// "regexp.Regexp.FindAllString" stays, "example.com/x/log.Logger.Printf"
+// becomes "log.Logger.Printf"
+func shortCallName(qualified string) string {
+	for i := len(qualified) - 1; i >= 0; i-- {
+		if qualified[i] == '/' {
+			return qualified[i+1:]
+		}
+	}
+	return qualified
+}
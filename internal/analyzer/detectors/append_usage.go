@@ -0,0 +1,299 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// AppendUsageDetector finds append calls whose result is dropped, aliases
+// another slice's backing array, or grows the slice by copying it onto itself
+type AppendUsageDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*AppendUsageDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "append_usage",
+		Category: "memory",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewAppendUsageDetectorWithConfig(cfg) },
+	})
+}
+
+func NewAppendUsageDetector() *AppendUsageDetector {
+	return &AppendUsageDetector{}
+}
+
+func NewAppendUsageDetectorWithConfig(cfg *config.Config) *AppendUsageDetector {
+	return &AppendUsageDetector{
+		config: cfg,
+	}
+}
+
+func (d *AppendUsageDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *AppendUsageDetector) Name() string {
+	return "Append Usage Detector"
+}
+
+func (d *AppendUsageDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &appendUsageVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		detector: d,
+		context:  ctx,
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+type appendUsageVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	loopDepth   int
+	detector    *AppendUsageDetector
+	context     *context.AnalysisContext
+}
+
+func (v *appendUsageVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Name != nil {
+			v.currentFunc = n.Name.Name
+		}
+		return v
+
+	case *ast.ForStmt, *ast.RangeStmt:
+		if isBenchmarkTimingLoop(v.context, n) {
+			// Measured benchmark code: visit the body as if it were not in a loop
+			for _, stmt := range getLoopBody(n) {
+				ast.Walk(v, stmt)
+			}
+			return nil
+		}
+
+		v.loopDepth++
+		for _, stmt := range getLoopBody(n) {
+			ast.Walk(v, stmt)
+		}
+		v.loopDepth--
+		return nil
+
+	case *ast.ExprStmt:
+		if call, ok := n.X.(*ast.CallExpr); ok && v.isAppendCall(call) && v.settings().DetectDiscarded {
+			v.createDiscardedIssue(call)
+		}
+		return v
+
+	case *ast.AssignStmt:
+		v.checkAssignment(n)
+		return v
+
+	default:
+		return v
+	}
+}
+
+// settings returns the rule config, or everything enabled without one
+func (v *appendUsageVisitor) settings() config.AppendUsageConfig {
+	if v.detector.config != nil && v.detector.config.Rules.Memory.AppendUsage.Enabled {
+		return v.detector.config.Rules.Memory.AppendUsage
+	}
+	return config.AppendUsageConfig{
+		Enabled:          true,
+		DetectDiscarded:  true,
+		DetectAliasing:   true,
+		DetectSelfAppend: true,
+	}
+}
+
+func (v *appendUsageVisitor) checkAssignment(assign *ast.AssignStmt) {
+	if assign.Tok != token.ASSIGN && assign.Tok != token.DEFINE {
+		return
+	}
+	if len(assign.Lhs) != len(assign.Rhs) {
+		return
+	}
+
+	settings := v.settings()
+	for i, rhs := range assign.Rhs {
+		call, ok := rhs.(*ast.CallExpr)
+		if !ok || !v.isAppendCall(call) || len(call.Args) == 0 {
+			continue
+		}
+		lhs := assign.Lhs[i]
+
+		if ident, ok := lhs.(*ast.Ident); ok && ident.Name == "_" {
+			if settings.DetectDiscarded {
+				v.createDiscardedIssue(call)
+			}
+			continue
+		}
+
+		if call.Ellipsis.IsValid() && sameExpr(call.Args[len(call.Args)-1], lhs) {
+			if !settings.DetectSelfAppend {
+				continue
+			}
+			if sameExpr(call.Args[0], lhs) {
+				v.createSelfAppendIssue(assign, lhs)
+			} else if v.loopDepth > 0 {
+				v.createPrependIssue(assign, lhs)
+			}
+			continue
+		}
+
+		if settings.DetectAliasing && v.mayAlias(call.Args[0], lhs) {
+			v.createAliasingIssue(assign, lhs, call.Args[0])
+		}
+	}
+}
+
+// isAppendCall reports whether call is to the builtin append, and not to a
+// user-defined function that shadows it
+func (v *appendUsageVisitor) isAppendCall(call *ast.CallExpr) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok || ident.Name != "append" {
+		return false
+	}
+	if v.context != nil && v.context.TypeInfo != nil {
+		if obj, ok := v.context.TypeInfo.Uses[ident]; ok {
+			_, builtin := obj.(*types.Builtin)
+			return builtin
+		}
+	}
+	return true
+}
+
+// mayAlias reports whether appending to src and storing the result in dst can
+// leave both sharing a backing array. Fresh slices (literals, calls, nil),
+// three-index slices, and the s[:0] reuse idiom are not reported.
+func (v *appendUsageVisitor) mayAlias(src, dst ast.Expr) bool {
+	base := src
+	if slice, ok := src.(*ast.SliceExpr); ok {
+		if slice.Slice3 || (slice.Low == nil && isZeroLiteral(slice.High)) {
+			return false
+		}
+		base = slice.X
+	}
+
+	switch b := base.(type) {
+	case *ast.Ident:
+		if b.Name == "nil" {
+			return false
+		}
+	case *ast.SelectorExpr:
+	default:
+		return false
+	}
+
+	if sameExpr(base, dst) {
+		return false
+	}
+
+	// Without type info, only trust the shape of the expression
+	if v.context != nil && v.context.TypeInfo != nil {
+		if tv, ok := v.context.TypeInfo.Types[base]; ok && tv.Type != nil {
+			_, isSlice := tv.Type.Underlying().(*types.Slice)
+			return isSlice
+		}
+	}
+	return true
+}
+
+func isZeroLiteral(expr ast.Expr) bool {
+	lit, ok := expr.(*ast.BasicLit)
+	return ok && lit.Kind == token.INT && lit.Value == "0"
+}
+
+// sameExpr reports whether two expressions spell the same variable or field
+func sameExpr(a, b ast.Expr) bool {
+	return types.ExprString(a) == types.ExprString(b)
+}
+
+func (v *appendUsageVisitor) createDiscardedIssue(call *ast.CallExpr) {
+	name := types.ExprString(call.Args[0])
+	v.addIssue(call, models.Issue{
+		Severity:   models.SeverityHigh,
+		Message:    fmt.Sprintf("Result of append to '%s' is discarded - the appended elements are lost", name),
+		Suggestion: suggestions.Render("append_misuse.discarded", suggestions.Data{Var: name}),
+		Complexity: "Lost update",
+		Confidence: 0.9,
+		Impact:     "Appended elements are kept",
+		FixEffort:  models.EffortTrivial,
+	})
+}
+
+func (v *appendUsageVisitor) createSelfAppendIssue(assign *ast.AssignStmt, lhs ast.Expr) {
+	name := types.ExprString(lhs)
+	issue := models.Issue{
+		Severity:   models.SeverityMedium,
+		Message:    fmt.Sprintf("Slice '%s' is appended to itself - its length doubles", name),
+		Suggestion: suggestions.Render("append_misuse.self_append", suggestions.Data{Var: name}),
+		Complexity: "O(n) copy per append",
+		Confidence: 0.85,
+		Impact:     "Avoids repeated doubling",
+		FixEffort:  models.EffortSmall,
+	}
+	if v.loopDepth > 0 {
+		issue.Severity = models.SeverityHigh
+		issue.Message = fmt.Sprintf("Slice '%s' is appended to itself in a loop - it grows exponentially", name)
+		issue.Complexity = "O(2^n) growth"
+	}
+	v.addIssue(assign, issue)
+}
+
+func (v *appendUsageVisitor) createPrependIssue(assign *ast.AssignStmt, lhs ast.Expr) {
+	name := types.ExprString(lhs)
+	v.addIssue(assign, models.Issue{
+		Severity:   models.SeverityHigh,
+		Message:    fmt.Sprintf("Prepending to slice '%s' in a loop copies it on every iteration", name),
+		Suggestion: suggestions.Render("append_misuse.prepend_in_loop", suggestions.Data{Var: name}),
+		Complexity: "O(n²) due to copying",
+		Confidence: 0.85,
+		Impact:     "O(n²)→O(n)",
+		FixEffort:  models.EffortSmall,
+	})
+}
+
+func (v *appendUsageVisitor) createAliasingIssue(assign *ast.AssignStmt, lhs, src ast.Expr) {
+	name := types.ExprString(lhs)
+	source := types.ExprString(src)
+	v.addIssue(assign, models.Issue{
+		Severity:   models.SeverityMedium,
+		Message:    fmt.Sprintf("'%s' is appended from '%s' and may share its backing array", name, source),
+		Suggestion: suggestions.Render("append_misuse.aliasing", suggestions.Data{Var: name, Source: source}),
+		Complexity: "Shared backing array",
+		Confidence: 0.6, // Harmless when src has no spare capacity or isn't reused
+		Impact:     "Avoids silently overwritten elements",
+		FixEffort:  models.EffortTrivial,
+	})
+}
+
+// addIssue fills in the type and location of issue and records it
+func (v *appendUsageVisitor) addIssue(node ast.Node, issue models.Issue) {
+	position := v.fset.Position(node.Pos())
+
+	issue.Type = models.IssueAppendMisuse
+	issue.File = v.filename
+	issue.Line = position.Line
+	issue.Column = position.Column
+	issue.Function = v.currentFunc
+	issue.CodeSnippet = position.String()
+
+	v.issues = append(v.issues, issue)
+}
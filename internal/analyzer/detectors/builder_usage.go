@@ -0,0 +1,822 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// builderTypes are the buffer types checked, by import path
+var builderTypes = map[string]string{
+	"strings": "Builder",
+	"bytes":   "Buffer",
+}
+
+// builderWrites are the methods that append to a builder
+var builderWrites = map[string]bool{
+	"WriteString": true, "WriteByte": true, "WriteRune": true, "Write": true,
+}
+
+// builderReads are the methods that leave a builder as it is, so calling
+// them on a copy is safe
+var builderReads = map[string]bool{
+	"String": true, "Len": true, "Cap": true, "Available": true,
+}
+
+// stringBuilderMethods are the bytes.Buffer methods strings.Builder also has
+var stringBuilderMethods = map[string]bool{
+	"WriteString": true, "WriteByte": true, "WriteRune": true, "Write": true,
+	"String": true, "Len": true, "Cap": true, "Grow": true, "Reset": true,
+}
+
+// BuilderUsageDetector finds strings.Builder and bytes.Buffer code that does
+// the right thing but could do it better: writes in loops without Grow,
+// builders copied by value, fmt.Fprintf for plain strings, and the type that
+// copies less for how the result is used
+type BuilderUsageDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*BuilderUsageDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "builder_usage",
+		Category: "performance",
+		Version:  "1.2.0",
+		New:      func(cfg *config.Config) Detector { return NewBuilderUsageDetectorWithConfig(cfg) },
+	})
+}
+
+func NewBuilderUsageDetector() *BuilderUsageDetector {
+	return &BuilderUsageDetector{}
+}
+
+func NewBuilderUsageDetectorWithConfig(cfg *config.Config) *BuilderUsageDetector {
+	return &BuilderUsageDetector{
+		config: cfg,
+	}
+}
+
+func (d *BuilderUsageDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *BuilderUsageDetector) Name() string {
+	return "Builder Usage Detector"
+}
+
+func (d *BuilderUsageDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &builderUsageVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		detector: d,
+		context:  ctx,
+		settings: d.settings(),
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+// settings returns the rule config, or the defaults without one
+func (d *BuilderUsageDetector) settings() config.BuilderUsageConfig {
+	if d.config != nil && d.config.Rules.Performance.BuilderUsage.Enabled {
+		return d.config.Rules.Performance.BuilderUsage
+	}
+	return config.DefaultConfig().Rules.Performance.BuilderUsage
+}
+
+// builderVar is a builder variable declared in the current function
+type builderVar struct {
+	typeName string    // "strings.Builder" or "bytes.Buffer"
+	declared token.Pos // Zero for parameters, which the caller may have grown
+	sized    bool      // Grow is called, or the Buffer starts from a given slice
+	reported bool
+}
+
+type builderUsageVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	loops       []ast.Node          // Enclosing loops, innermost last
+	builders    map[any]*builderVar // Builders of the current function, by varKey
+	detector    *BuilderUsageDetector
+	context     *context.AnalysisContext
+	settings    config.BuilderUsageConfig
+}
+
+func (v *builderUsageVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Name != nil {
+			v.currentFunc = n.Name.Name
+		}
+		v.builders = make(map[any]*builderVar)
+		v.collectBuilders(n)
+		if v.settings.DetectCopies {
+			v.checkSignature(n.Type, n.Body)
+		}
+		if v.settings.SuggestType && n.Body != nil {
+			v.checkBuilderTypes(n.Body)
+		}
+		return v
+
+	case *ast.FuncLit:
+		if v.settings.DetectCopies {
+			v.checkSignature(n.Type, n.Body)
+		}
+		return v
+
+	case *ast.ForStmt, *ast.RangeStmt:
+		if isBenchmarkTimingLoop(v.context, n) {
+			for _, stmt := range getLoopBody(n) {
+				ast.Walk(v, stmt)
+			}
+			return nil
+		}
+
+		v.loops = append(v.loops, n)
+		for _, stmt := range getLoopBody(n) {
+			ast.Walk(v, stmt)
+		}
+		v.loops = v.loops[:len(v.loops)-1]
+		return nil
+
+	case *ast.AssignStmt:
+		if v.settings.DetectCopies {
+			for i, rhs := range n.Rhs {
+				if i < len(n.Lhs) && isBlank(n.Lhs[i]) {
+					continue
+				}
+				v.checkCopy(rhs, "assigned")
+			}
+		}
+		return v
+
+	case *ast.ValueSpec:
+		if v.settings.DetectCopies {
+			for _, value := range n.Values {
+				v.checkCopy(value, "assigned")
+			}
+		}
+		return v
+
+	case *ast.ReturnStmt:
+		if v.settings.DetectCopies {
+			for _, result := range n.Results {
+				v.checkCopy(result, "returned")
+			}
+		}
+		return v
+
+	case *ast.CallExpr:
+		if v.settings.DetectCopies {
+			for _, arg := range n.Args {
+				v.checkCopy(arg, "passed")
+			}
+		}
+		if v.settings.DetectFprintf {
+			v.checkFprintf(n)
+		}
+		if v.settings.SuggestGrow && len(v.loops) > 0 {
+			v.checkGrow(n)
+		}
+		return v
+
+	default:
+		return v
+	}
+}
+
+// collectBuilders records the builders a function declares or receives, and
+// which of them are sized up front
+func (v *builderUsageVisitor) collectBuilders(fn *ast.FuncDecl) {
+	for _, list := range []*ast.FieldList{fn.Recv, fn.Type.Params} {
+		if list == nil {
+			continue
+		}
+		for _, field := range list.List {
+			star, ok := field.Type.(*ast.StarExpr)
+			if !ok {
+				continue
+			}
+			if typeName := v.builderTypeExpr(star.X); typeName != "" {
+				for _, name := range field.Names {
+					v.builders[varKey(v.context, name)] = &builderVar{typeName: typeName}
+				}
+			}
+		}
+	}
+	if fn.Body == nil {
+		return
+	}
+
+	declare := func(name *ast.Ident, typeName string, sized bool) {
+		if typeName != "" && name.Name != "_" {
+			v.builders[varKey(v.context, name)] = &builderVar{typeName: typeName, declared: name.Pos(), sized: sized}
+		}
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.ValueSpec:
+			for i, name := range stmt.Names {
+				if i < len(stmt.Values) {
+					typeName, sized := v.builderConstructor(stmt.Values[i])
+					declare(name, typeName, sized)
+				} else if stmt.Type != nil {
+					declare(name, v.builderTypeExpr(stmt.Type), false)
+				}
+			}
+		case *ast.AssignStmt:
+			if stmt.Tok != token.DEFINE || len(stmt.Lhs) != len(stmt.Rhs) {
+				return true
+			}
+			for i, lhs := range stmt.Lhs {
+				if name, ok := lhs.(*ast.Ident); ok {
+					typeName, sized := v.builderConstructor(stmt.Rhs[i])
+					declare(name, typeName, sized)
+				}
+			}
+		case *ast.CallExpr:
+			// sb.Grow(n) anywhere in the function counts, wherever it is
+			if sel, ok := stmt.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Grow" {
+				if ident, ok := sel.X.(*ast.Ident); ok {
+					if builder, ok := v.builders[varKey(v.context, ident)]; ok {
+						builder.sized = true
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+// builderUses is how a function uses one of its local builders
+type builderUses struct {
+	total      int             // References, besides the declaration
+	compatible int             // References strings.Builder also supports
+	strings    int             // String() calls
+	converted  []*ast.CallExpr // []byte(b.String()) conversions
+	ident      *ast.Ident      // The declared name
+}
+
+// checkBuilderTypes reports local builders of the type that copies more for
+// how they are used: a bytes.Buffer only ever turned into a string, whose
+// String() copies the bytes where strings.Builder's doesn't, and a
+// strings.Builder only ever turned into []byte, which copies the string
+// where bytes.Buffer's Bytes() wouldn't
+func (v *builderUsageVisitor) checkBuilderTypes(body *ast.BlockStmt) {
+	uses := make(map[*builderVar]*builderUses)
+	for _, builder := range v.builders {
+		if builder.declared != token.NoPos {
+			uses[builder] = &builderUses{}
+		}
+	}
+	if len(uses) == 0 {
+		return
+	}
+	usesOf := func(expr ast.Expr) *builderUses {
+		if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			expr = unary.X
+		}
+		ident, ok := expr.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		return uses[v.builders[varKey(v.context, ident)]]
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.Ident:
+			builder := v.builders[varKey(v.context, node)]
+			if u := uses[builder]; u != nil {
+				if node.Pos() == builder.declared {
+					u.ident = node
+				} else {
+					u.total++
+				}
+			}
+		case *ast.CallExpr:
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok {
+				if u := usesOf(sel.X); u != nil && stringBuilderMethods[sel.Sel.Name] {
+					u.compatible++
+					if sel.Sel.Name == "String" {
+						u.strings++
+					}
+				}
+			}
+			if pkg, name, ok := packageFunc(v.context, node); ok && pkg == "fmt" && strings.HasPrefix(name, "Fprint") && len(node.Args) > 0 {
+				if u := usesOf(node.Args[0]); u != nil {
+					u.compatible++
+				}
+			}
+			if call, ok := byteSliceConversion(node); ok {
+				if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "String" {
+					if u := usesOf(sel.X); u != nil {
+						u.converted = append(u.converted, node)
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	for builder, u := range uses {
+		if u.ident == nil || u.strings == 0 {
+			continue
+		}
+		switch builder.typeName {
+		case "bytes.Buffer":
+			if u.compatible == u.total && len(u.converted) == 0 {
+				v.reportBuilderType(u.ident, u.ident.Name, "bytes.Buffer", "strings.Builder",
+					fmt.Sprintf("bytes.Buffer %s is only used to build a string - strings.Builder's String() returns it without copying", u.ident.Name))
+			}
+		case "strings.Builder":
+			if len(u.converted) == u.strings {
+				v.reportBuilderType(u.converted[0], u.ident.Name, "strings.Builder", "bytes.Buffer",
+					fmt.Sprintf("strings.Builder %s is only turned into []byte - the conversion copies the whole string, where bytes.Buffer's Bytes() doesn't", u.ident.Name))
+			}
+		}
+	}
+}
+
+// byteSliceConversion returns the converted expression of []byte(call)
+func byteSliceConversion(call *ast.CallExpr) (*ast.CallExpr, bool) {
+	arrayType, ok := call.Fun.(*ast.ArrayType)
+	if !ok || arrayType.Len != nil || len(call.Args) != 1 || !isIdentNamed(arrayType.Elt, "byte") {
+		return nil, false
+	}
+	inner, ok := call.Args[0].(*ast.CallExpr)
+	return inner, ok
+}
+
+func (v *builderUsageVisitor) reportBuilderType(node ast.Node, name, typeName, replacement, message string) {
+	v.addIssue(node, models.Issue{
+		Severity:   models.SeverityLow,
+		Message:    message,
+		Suggestion: suggestions.Render("builder_misuse.type", suggestions.Data{Var: name, Type: replacement, Source: typeName}),
+		Complexity: "One extra copy of the built output",
+		Confidence: 0.8,
+		Impact:     "Saves a copy of the output",
+		FixEffort:  models.EffortTrivial,
+	})
+}
+
+// builderConstructor returns the builder type an expression creates, and
+// whether it starts out sized: strings.Builder{}, &bytes.Buffer{},
+// new(strings.Builder), bytes.NewBuffer(buf)
+func (v *builderUsageVisitor) builderConstructor(expr ast.Expr) (string, bool) {
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		return v.builderTypeExpr(e.Type), false
+	case *ast.CallExpr:
+		if fun, ok := e.Fun.(*ast.Ident); ok && fun.Name == "new" && len(e.Args) == 1 {
+			return v.builderTypeExpr(e.Args[0]), false
+		}
+		pkg, name, ok := packageFunc(v.context, e)
+		if !ok || pkg != "bytes" || len(e.Args) != 1 {
+			return "", false
+		}
+		switch name {
+		case "NewBuffer":
+			return "bytes.Buffer", !isNilIdent(e.Args[0])
+		case "NewBufferString":
+			return "bytes.Buffer", true
+		}
+	}
+	return "", false
+}
+
+// builderTypeExpr returns "strings.Builder" or "bytes.Buffer" when a type
+// expression names one of them
+func (v *builderUsageVisitor) builderTypeExpr(expr ast.Expr) string {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	pkgIdent, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	pkg := pkgIdent.Name
+	if v.context != nil && v.context.TypeInfo != nil {
+		pkgName, ok := v.context.TypeInfo.Uses[pkgIdent].(*types.PkgName)
+		if !ok {
+			return ""
+		}
+		pkg = pkgName.Imported().Path()
+	}
+	if builderTypes[pkg] != sel.Sel.Name {
+		return ""
+	}
+	return pkg + "." + sel.Sel.Name
+}
+
+// builderType returns the builder type of an expression, and whether it is a
+// pointer to one
+func (v *builderUsageVisitor) builderType(expr ast.Expr) (string, bool) {
+	if v.context != nil && v.context.TypeInfo != nil {
+		t := v.context.TypeInfo.TypeOf(expr)
+		if t == nil {
+			return "", false
+		}
+		pointer := false
+		if ptr, ok := t.(*types.Pointer); ok {
+			t, pointer = ptr.Elem(), true
+		}
+		named, ok := t.(*types.Named)
+		if !ok || named.Obj().Pkg() == nil {
+			return "", false
+		}
+		pkg := named.Obj().Pkg().Path()
+		if builderTypes[pkg] != named.Obj().Name() {
+			return "", false
+		}
+		return pkg + "." + named.Obj().Name(), pointer
+	}
+
+	// Without type info, only variables declared in view are known
+	pointer := false
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr, pointer = unary.X, true
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	if builder, ok := v.builders[varKey(v.context, ident)]; ok {
+		return builder.typeName, pointer || builder.declared == token.NoPos
+	}
+	return "", false
+}
+
+// checkSignature reports parameters and results declared as builder values.
+// A parameter body only reads costs a copy per call, so it is reported at a
+// lower severity than one written to.
+func (v *builderUsageVisitor) checkSignature(fnType *ast.FuncType, body *ast.BlockStmt) {
+	for _, list := range []*ast.FieldList{fnType.Params, fnType.Results} {
+		if list == nil {
+			continue
+		}
+		for _, field := range list.List {
+			typeName := v.builderTypeExpr(field.Type)
+			if typeName == "" {
+				continue
+			}
+			name := typeName
+			if len(field.Names) > 0 {
+				name = field.Names[0].Name
+			}
+			if list == fnType.Params {
+				written := slices.IndexFunc(field.Names, func(param *ast.Ident) bool {
+					return v.writesTo(body, param)
+				})
+				if written < 0 {
+					v.reportReadOnlyCopy(field.Type, name, typeName)
+					continue
+				}
+				name = field.Names[written].Name
+			}
+			v.reportCopy(field.Type, name, typeName, "declared")
+		}
+	}
+}
+
+// writesTo reports whether body may change the builder param: by calling a
+// method other than builderReads, or by taking its address
+func (v *builderUsageVisitor) writesTo(body *ast.BlockStmt, param *ast.Ident) bool {
+	if body == nil || param.Name == "_" {
+		return false
+	}
+	key := varKey(v.context, param)
+	uses := func(expr ast.Expr) bool {
+		ident, ok := expr.(*ast.Ident)
+		return ok && varKey(v.context, ident) == key
+	}
+
+	written := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			written = uses(n.X) && !builderReads[n.Sel.Name]
+		case *ast.UnaryExpr:
+			written = n.Op == token.AND && uses(n.X)
+		}
+		return !written
+	})
+	return written
+}
+
+// checkCopy reports an existing builder value used where it is copied.
+// Composite literals and calls create a new value, which is fine.
+func (v *builderUsageVisitor) checkCopy(expr ast.Expr, how string) {
+	switch expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr, *ast.StarExpr, *ast.IndexExpr:
+	default:
+		return
+	}
+	if v.context == nil || v.context.TypeInfo == nil {
+		return // Value and pointer variables can't be told apart
+	}
+	typeName, pointer := v.builderType(expr)
+	if typeName == "" || pointer {
+		return
+	}
+	if tv, ok := v.context.TypeInfo.Types[expr]; ok && tv.IsType() {
+		return // new(strings.Builder) and similar name the type, not a value
+	}
+	v.reportCopy(expr, types.ExprString(expr), typeName, how)
+}
+
+func (v *builderUsageVisitor) reportCopy(node ast.Node, name, typeName, how string) {
+	severity := models.SeverityHigh
+	message := fmt.Sprintf("strings.Builder %s is %s by value - writing to a copy of a non-empty Builder panics", name, how)
+	if typeName == "bytes.Buffer" {
+		severity = models.SeverityMedium
+		message = fmt.Sprintf("bytes.Buffer %s is %s by value - the copy shares its backing array, so writes through one corrupt the other", name, how)
+	}
+
+	v.addIssue(node, models.Issue{
+		Severity:   severity,
+		Message:    message,
+		Suggestion: suggestions.Render("builder_misuse.copy", suggestions.Data{Var: name, Type: typeName}),
+		Complexity: "Copies the builder's header, sharing its buffer",
+		Confidence: 0.9,
+		Impact:     "Prevents a runtime panic or corrupted output",
+		FixEffort:  models.EffortTrivial,
+	})
+}
+
+// reportReadOnlyCopy reports a builder parameter only read from, which is
+// safe but copies the builder on every call
+func (v *builderUsageVisitor) reportReadOnlyCopy(node ast.Node, name, typeName string) {
+	v.addIssue(node, models.Issue{
+		Severity:   models.SeverityLow,
+		Message:    fmt.Sprintf("%s %s is declared by value - it is only read, but every call copies it", typeName, name),
+		Suggestion: suggestions.Render("builder_misuse.copy", suggestions.Data{Var: name, Type: typeName}),
+		Complexity: "Copies the builder's header, sharing its buffer",
+		Confidence: 0.9,
+		Impact:     "Avoids a copy per call, and a panic once a write is added",
+		FixEffort:  models.EffortTrivial,
+	})
+}
+
+// checkGrow reports writes in a loop with a known iteration count to a local
+// builder that is never sized, once per builder
+func (v *builderUsageVisitor) checkGrow(call *ast.CallExpr) {
+	var target ast.Expr
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok && builderWrites[sel.Sel.Name] {
+		target = sel.X
+	} else if pkg, name, ok := packageFunc(v.context, call); ok && pkg == "fmt" && strings.HasPrefix(name, "Fprint") && len(call.Args) > 0 {
+		target = call.Args[0]
+		if unary, ok := target.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			target = unary.X
+		}
+	}
+	ident, ok := target.(*ast.Ident)
+	if !ok {
+		return
+	}
+	builder, ok := v.builders[varKey(v.context, ident)]
+	if !ok || builder.sized || builder.reported || builder.declared == token.NoPos {
+		return
+	}
+
+	// The outermost loop the builder lives outside of decides the total
+	var loop ast.Node
+	for _, enclosing := range v.loops {
+		if enclosing.Pos() > builder.declared {
+			loop = enclosing
+			break
+		}
+	}
+	if loop == nil {
+		return // Declared inside the loop, so it starts empty each iteration
+	}
+	count, ok := v.iterationCount(loop)
+	if !ok {
+		return
+	}
+	builder.reported = true
+
+	v.addIssue(call, models.Issue{
+		Severity:   models.SeverityLow,
+		Message:    fmt.Sprintf("%s %s is written in a loop of %s iterations without Grow - its buffer is reallocated as it fills", builder.typeName, ident.Name, count),
+		Suggestion: suggestions.Render("builder_misuse.grow", suggestions.Data{Var: ident.Name, Type: builder.typeName, Source: count}),
+		Complexity: "O(log n) buffer reallocations and copies",
+		Confidence: 0.6, // The written sizes may be too varied to estimate
+		Impact:     "One allocation instead of repeated growth",
+		FixEffort:  models.EffortTrivial,
+	})
+}
+
+// iterationCount returns an expression for how many times a loop runs, when
+// that is known before it starts: len(items) for range, n for i < n
+func (v *builderUsageVisitor) iterationCount(loop ast.Node) (string, bool) {
+	switch l := loop.(type) {
+	case *ast.RangeStmt:
+		if v.context != nil && v.context.TypeInfo != nil {
+			t := v.context.TypeInfo.TypeOf(l.X)
+			if t == nil {
+				return "", false
+			}
+			switch u := t.Underlying().(type) {
+			case *types.Chan, *types.Signature:
+				return "", false
+			case *types.Basic:
+				if u.Info()&types.IsInteger != 0 {
+					return types.ExprString(l.X), true
+				}
+			}
+		} else if _, isCall := l.X.(*ast.CallExpr); isCall {
+			return "", false // May be a channel or iterator
+		}
+		return "len(" + types.ExprString(l.X) + ")", true
+
+	case *ast.ForStmt:
+		if v.context == nil {
+			return "", false
+		}
+		if info, ok := v.context.LoopContext[l]; !ok || info.BoundType == context.BoundUnknown {
+			return "", false
+		}
+		cond, ok := l.Cond.(*ast.BinaryExpr)
+		if !ok || (cond.Op != token.LSS && cond.Op != token.LEQ) {
+			return "", false
+		}
+		return types.ExprString(cond.Y), true
+	}
+	return "", false
+}
+
+// checkFprintf reports fmt.Fprintf into a builder with a format that plain
+// writes can produce, and suggests the equivalent writes
+func (v *builderUsageVisitor) checkFprintf(call *ast.CallExpr) {
+	pkg, name, ok := packageFunc(v.context, call)
+	if !ok || pkg != "fmt" || name != "Fprintf" || len(call.Args) < 2 {
+		return
+	}
+	typeName, pointer := v.builderType(call.Args[0])
+	if typeName == "" || !pointer {
+		return
+	}
+	lit, ok := call.Args[1].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return
+	}
+	format, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return
+	}
+
+	receiver := types.ExprString(call.Args[0])
+	if unary, ok := call.Args[0].(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		receiver = types.ExprString(unary.X)
+	}
+	writes, ok := v.plainWrites(receiver, format, call.Args[2:])
+	if !ok {
+		return
+	}
+
+	severity := models.SeverityLow
+	if len(v.loops) > 0 {
+		severity = models.SeverityMedium
+	}
+	v.addIssue(call, models.Issue{
+		Severity:   severity,
+		Message:    fmt.Sprintf("fmt.Fprintf into %s %s only formats plain values - WriteString avoids parsing the format and boxing arguments", typeName, receiver),
+		Suggestion: suggestions.Render("builder_misuse.fprintf", suggestions.Data{Var: strings.Join(writes, "\n")}),
+		Complexity: "Format parsing and interface conversions per call",
+		Confidence: 0.85,
+		Impact:     "Removes per-call formatting overhead",
+		FixEffort:  models.EffortTrivial,
+	})
+}
+
+// plainWrites translates a format using only %s, %d, %c, and %% into
+// builder writes, e.g. sb.WriteString(strconv.Itoa(n))
+func (v *builderUsageVisitor) plainWrites(receiver, format string, args []ast.Expr) ([]string, bool) {
+	var writes []string
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			writes = append(writes, fmt.Sprintf("%s.WriteString(%s)", receiver, strconv.Quote(literal.String())))
+			literal.Reset()
+		}
+	}
+
+	argIndex := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			literal.WriteByte(format[i])
+			continue
+		}
+		if i+1 >= len(format) {
+			return nil, false
+		}
+		i++
+		verb := format[i]
+		if verb == '%' {
+			literal.WriteByte('%')
+			continue
+		}
+		if argIndex >= len(args) {
+			return nil, false
+		}
+		arg := args[argIndex]
+		argIndex++
+
+		var write string
+		switch verb {
+		case 's':
+			switch v.argKind(arg) {
+			case "string", "":
+				write = fmt.Sprintf("%s.WriteString(%s)", receiver, types.ExprString(arg))
+			case "bytes":
+				write = fmt.Sprintf("%s.Write(%s)", receiver, types.ExprString(arg))
+			default:
+				return nil, false // Stringers and errors need fmt
+			}
+		case 'd':
+			if kind := v.argKind(arg); kind != "int" && kind != "" {
+				return nil, false
+			}
+			write = fmt.Sprintf("%s.WriteString(strconv.Itoa(%s))", receiver, types.ExprString(arg))
+		case 'c':
+			write = fmt.Sprintf("%s.WriteRune(%s)", receiver, types.ExprString(arg))
+		default:
+			return nil, false // Flags, widths, and other verbs need fmt
+		}
+		flush()
+		writes = append(writes, write)
+	}
+	flush()
+
+	return writes, argIndex == len(args)
+}
+
+// argKind classifies an argument as "string", "bytes", "int", or "other",
+// and "" without type info
+func (v *builderUsageVisitor) argKind(arg ast.Expr) string {
+	if v.context == nil || v.context.TypeInfo == nil {
+		return ""
+	}
+	t := v.context.TypeInfo.TypeOf(arg)
+	if t == nil {
+		return ""
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsString != 0:
+			return "string"
+		case u.Kind() == types.Int || u.Kind() == types.UntypedInt:
+			return "int"
+		}
+	case *types.Slice:
+		if elem, ok := u.Elem().(*types.Basic); ok && elem.Kind() == types.Byte {
+			return "bytes"
+		}
+	}
+	return "other"
+}
+
+// addIssue fills in the type and location of issue and records it
+func (v *builderUsageVisitor) addIssue(node ast.Node, issue models.Issue) {
+	position := v.fset.Position(node.Pos())
+
+	issue.Type = models.IssueBuilderMisuse
+	issue.File = v.filename
+	issue.Line = position.Line
+	issue.Column = position.Column
+	issue.Function = v.currentFunc
+	issue.CodeSnippet = position.String()
+
+	v.issues = append(v.issues, issue)
+}
+
+func isBlank(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "_"
+}
+
+func isNilIdent(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "nil"
+}
@@ -0,0 +1,302 @@
+package detectors
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"strings"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// BusyWaitDetector finds select statements with a single case, loops around
+// a select whose default case does nothing, and loops with an empty body.
+// The last two keep a core busy instead of blocking until there is work.
+type BusyWaitDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*BusyWaitDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "busy_wait",
+		Category: "concurrency",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewBusyWaitDetectorWithConfig(cfg) },
+	})
+}
+
+func NewBusyWaitDetector() *BusyWaitDetector {
+	return &BusyWaitDetector{}
+}
+
+func NewBusyWaitDetectorWithConfig(cfg *config.Config) *BusyWaitDetector {
+	return &BusyWaitDetector{
+		config: cfg,
+	}
+}
+
+func (d *BusyWaitDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *BusyWaitDetector) Name() string {
+	return "Busy Wait Detector"
+}
+
+func (d *BusyWaitDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &busyWaitVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		context:  ctx,
+		settings: d.settings(),
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+// settings returns the rule config, or the defaults without one
+func (d *BusyWaitDetector) settings() config.BusyWaitConfig {
+	if d.config != nil && d.config.Rules.Concurrency.BusyWait.Enabled {
+		return d.config.Rules.Concurrency.BusyWait
+	}
+	return config.DefaultConfig().Rules.Concurrency.BusyWait
+}
+
+type busyWaitVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	context     *context.AnalysisContext
+	settings    config.BusyWaitConfig
+}
+
+func (v *busyWaitVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Name != nil {
+			v.currentFunc = n.Name.Name
+		}
+		return v
+
+	case *ast.SelectStmt:
+		if v.settings.DetectSingleCase && len(n.Body.List) == 1 {
+			v.checkSingleCase(n)
+		}
+		return v
+
+	case *ast.ForStmt:
+		if v.settings.DetectSpinLoops && len(n.Body.List) == 0 && n.Post == nil {
+			v.checkSpinLoop(n)
+		}
+		if v.settings.DetectBusySelect {
+			v.checkBusySelect(n.Body)
+		}
+		return v
+
+	case *ast.RangeStmt:
+		if v.settings.DetectBusySelect {
+			v.checkBusySelect(n.Body)
+		}
+		return v
+
+	default:
+		return v
+	}
+}
+
+// checkSingleCase reports a select with one clause: a lone default does
+// nothing, and a lone case is the same as the channel operation on its own
+func (v *busyWaitVisitor) checkSingleCase(sel *ast.SelectStmt) {
+	clause, ok := sel.Body.List[0].(*ast.CommClause)
+	if !ok {
+		return
+	}
+
+	if clause.Comm == nil {
+		v.addIssue(sel, models.IssueSingleCaseSelect, models.Issue{
+			Severity:   models.SeverityLow,
+			Message:    "select with only a default case never waits on anything - the select is redundant",
+			Suggestion: suggestions.Render("single_case_select.default_only", suggestions.Data{}),
+			Complexity: "No channel operation",
+			Confidence: 0.95,
+			Impact:     "Simpler code",
+			FixEffort:  models.EffortTrivial,
+		})
+		return
+	}
+
+	operation := v.fset.Position(clause.Comm.Pos())
+	text := strings.TrimSpace(v.nodeText(clause.Comm))
+	v.addIssue(sel, models.IssueSingleCaseSelect, models.Issue{
+		Severity:   models.SeverityLow,
+		Message:    fmt.Sprintf("select with a single case (%s) blocks exactly like the channel operation on its own", text),
+		Suggestion: suggestions.Render("single_case_select.plain_op", suggestions.Data{Var: text}),
+		Complexity: fmt.Sprintf("One channel operation at line %d", operation.Line),
+		Confidence: 0.9,
+		Impact:     "Simpler code; add a timeout or ctx.Done() case if blocking was not intended",
+		FixEffort:  models.EffortTrivial,
+	})
+}
+
+// checkBusySelect reports a loop whose whole body is a select with an empty
+// default, so the loop polls at full speed. Loops doing other work between
+// polls are left alone.
+func (v *busyWaitVisitor) checkBusySelect(body *ast.BlockStmt) {
+	if len(body.List) != 1 {
+		return
+	}
+	sel, ok := body.List[0].(*ast.SelectStmt)
+	if !ok {
+		return
+	}
+
+	var defaultClause *ast.CommClause
+	for _, clause := range sel.Body.List {
+		comm, ok := clause.(*ast.CommClause)
+		if !ok {
+			continue
+		}
+		if comm.Comm == nil {
+			defaultClause = comm
+		}
+	}
+	if defaultClause == nil || !isEmptyBranch(defaultClause.Body) {
+		return
+	}
+
+	v.addIssue(defaultClause, models.IssueBusyWait, models.Issue{
+		Severity:   models.SeverityHigh,
+		Message:    "Loop around select with an empty default case spins at 100% CPU until a channel is ready",
+		Suggestion: suggestions.Render("busy_wait.select_default", suggestions.Data{}),
+		Complexity: "O(∞) polling iterations while idle",
+		Confidence: 0.85,
+		Impact:     "Frees a CPU core while waiting",
+		FixEffort:  models.EffortTrivial,
+	})
+}
+
+// checkSpinLoop reports for {} and for !done {}, unless the condition itself
+// blocks, e.g. for scanner.Scan() {}
+func (v *busyWaitVisitor) checkSpinLoop(loop *ast.ForStmt) {
+	condition := "for {}"
+	if loop.Cond != nil {
+		if v.waits(loop.Cond) || !v.onlyPolls(loop.Cond) {
+			return
+		}
+		condition = fmt.Sprintf("for %s {}", v.nodeText(loop.Cond))
+	}
+
+	v.addIssue(loop, models.IssueBusyWait, models.Issue{
+		Severity:   models.SeverityHigh,
+		Message:    fmt.Sprintf("Spin loop %s burns a CPU core while waiting", condition),
+		Suggestion: suggestions.Render("busy_wait.spin", suggestions.Data{}),
+		Complexity: "O(∞) iterations while waiting",
+		Confidence: 0.9,
+		Impact:     "Frees a CPU core while waiting",
+		FixEffort:  models.EffortSmall,
+	})
+}
+
+// waits reports whether a statement or expression blocks or backs off:
+// channel operations, sleeps, yields, and Wait calls
+func (v *busyWaitVisitor) waits(node ast.Node) bool {
+	waits := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.FuncLit:
+			return false // Runs elsewhere
+		case *ast.SendStmt, *ast.SelectStmt:
+			waits = true
+		case *ast.UnaryExpr:
+			if e.Op == token.ARROW {
+				waits = true
+			}
+		case *ast.RangeStmt:
+			waits = true // May range over a channel
+		case *ast.CallExpr:
+			name := ""
+			switch fun := e.Fun.(type) {
+			case *ast.SelectorExpr:
+				name = fun.Sel.Name
+			case *ast.Ident:
+				name = fun.Name
+			}
+			lower := strings.ToLower(name)
+			if strings.Contains(lower, "sleep") || strings.Contains(lower, "wait") ||
+				strings.Contains(lower, "backoff") || name == "Gosched" || name == "Lock" {
+				waits = true
+			}
+		}
+		return !waits
+	})
+	return waits
+}
+
+// onlyPolls reports whether a loop condition just reads state: variables,
+// fields, and atomic loads, with no other calls that might do work
+func (v *busyWaitVisitor) onlyPolls(cond ast.Expr) bool {
+	polls := true
+	ast.Inspect(cond, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return polls
+		}
+		if pkg, _, ok := packageFunc(v.context, call); ok && (pkg == "sync/atomic" || pkg == "atomic") {
+			return true
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Load" {
+			return true // atomic.Bool and friends
+		}
+		polls = false
+		return false
+	})
+	return polls
+}
+
+// isEmptyBranch reports a case body that does nothing but continue
+func isEmptyBranch(body []ast.Stmt) bool {
+	for _, stmt := range body {
+		switch s := stmt.(type) {
+		case *ast.EmptyStmt:
+		case *ast.BranchStmt:
+			if s.Tok != token.CONTINUE {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (v *busyWaitVisitor) nodeText(node ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, v.fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// addIssue fills in the type and location of issue and records it
+func (v *busyWaitVisitor) addIssue(node ast.Node, issueType models.IssueType, issue models.Issue) {
+	position := v.fset.Position(node.Pos())
+
+	issue.Type = issueType
+	issue.File = v.filename
+	issue.Line = position.Line
+	issue.Column = position.Column
+	issue.Function = v.currentFunc
+	issue.CodeSnippet = position.String()
+
+	v.issues = append(v.issues, issue)
+}
@@ -0,0 +1,190 @@
+package detectors
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"strings"
+
+	"gophercheck/internal/context"
+)
+
+// packageFunc returns the import path and name of a package-level function
+// called as pkg.Func(...). Type info resolves renamed imports; without it the
+// package name is assumed to match the last element of the import path.
+func packageFunc(ctx *context.AnalysisContext, call *ast.CallExpr) (string, string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", "", false
+	}
+	pkgIdent, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+
+	if ctx != nil && ctx.TypeInfo != nil {
+		if obj, ok := ctx.TypeInfo.Uses[pkgIdent]; ok {
+			pkgName, ok := obj.(*types.PkgName)
+			if !ok {
+				return "", "", false
+			}
+			return pkgName.Imported().Path(), sel.Sel.Name, true
+		}
+	}
+	return pkgIdent.Name, sel.Sel.Name, true
+}
+
+// assignedInLoop returns the names of variables the loop declares or changes,
+// including its own iteration variables. Expressions built only from other
+// names have the same value on every iteration.
+func assignedInLoop(loop ast.Node) map[string]bool {
+	assigned := make(map[string]bool)
+	addTarget := func(expr ast.Expr) {
+		// x, x.f, and x[i] all change what x refers to
+		for {
+			switch e := expr.(type) {
+			case *ast.SelectorExpr:
+				expr = e.X
+				continue
+			case *ast.IndexExpr:
+				expr = e.X
+				continue
+			case *ast.StarExpr:
+				expr = e.X
+				continue
+			case *ast.Ident:
+				assigned[e.Name] = true
+			}
+			return
+		}
+	}
+
+	if rangeStmt, ok := loop.(*ast.RangeStmt); ok {
+		if rangeStmt.Key != nil {
+			addTarget(rangeStmt.Key)
+		}
+		if rangeStmt.Value != nil {
+			addTarget(rangeStmt.Value)
+		}
+	}
+
+	ast.Inspect(loop, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range stmt.Lhs {
+				addTarget(lhs)
+			}
+		case *ast.IncDecStmt:
+			addTarget(stmt.X)
+		case *ast.ValueSpec:
+			for _, name := range stmt.Names {
+				assigned[name.Name] = true
+			}
+		case *ast.UnaryExpr:
+			if stmt.Op == token.AND {
+				addTarget(stmt.X) // Address taken, may be changed through the pointer
+			}
+		}
+		return true
+	})
+	return assigned
+}
+
+// isLoopInvariant reports whether expr is built only from literals, constants,
+// and variables the loop doesn't change. Calls are never invariant, since
+// they may return something different each time.
+func isLoopInvariant(expr ast.Expr, assigned map[string]bool) bool {
+	invariant := true
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.CallExpr, *ast.FuncLit:
+			invariant = false
+		case *ast.SelectorExpr:
+			ast.Inspect(e.X, func(inner ast.Node) bool {
+				if ident, ok := inner.(*ast.Ident); ok && assigned[ident.Name] {
+					invariant = false
+				}
+				return invariant
+			})
+			return false // The field name itself isn't a variable
+		case *ast.Ident:
+			if assigned[e.Name] {
+				invariant = false
+			}
+		}
+		return invariant
+	})
+	return invariant
+}
+
+// matchCall returns the entry of qualified, e.g. "os.ReadFile" or
+// "io/ioutil.ReadAll", that call refers to
+func matchCall(ctx *context.AnalysisContext, call *ast.CallExpr, qualified []string) (string, bool) {
+	pkg, name, ok := packageFunc(ctx, call)
+	if !ok {
+		return "", false
+	}
+	for _, entry := range qualified {
+		dot := strings.LastIndex(entry, ".")
+		if dot < 0 || entry[dot+1:] != name {
+			continue
+		}
+		if entryPkg := entry[:dot]; entryPkg == pkg || importName(entryPkg) == pkg {
+			return entry, true
+		}
+	}
+	return "", false
+}
+
+// matchMethod returns the entry of qualified, e.g. "net/http.Client.Do",
+// naming the method call invokes. Methods are only resolved with type info.
+func matchMethod(ctx *context.AnalysisContext, call *ast.CallExpr, qualified []string) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || ctx == nil || ctx.TypeInfo == nil {
+		return "", false
+	}
+	selection, ok := ctx.TypeInfo.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return "", false
+	}
+	recv := selection.Recv()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	named, ok := recv.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return "", false
+	}
+
+	method := named.Obj().Pkg().Path() + "." + named.Obj().Name() + "." + sel.Sel.Name
+	for _, entry := range qualified {
+		if entry == method {
+			return entry, true
+		}
+	}
+	return "", false
+}
+
+// varKey identifies a variable by its object when type info is available, and
+// by name otherwise
+func varKey(ctx *context.AnalysisContext, ident *ast.Ident) any {
+	if ctx != nil && ctx.TypeInfo != nil {
+		if obj := ctx.TypeInfo.ObjectOf(ident); obj != nil {
+			return obj
+		}
+	}
+	return ident.Name
+}
+
+// importName returns the package name an import path is usually imported
+// as, skipping a major version suffix: "github.com/labstack/echo/v4" is echo
+func importName(pkgPath string) string {
+	name := path.Base(pkgPath)
+	if len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		if dir := path.Dir(pkgPath); dir != "." {
+			return path.Base(dir)
+		}
+	}
+	return name
+}
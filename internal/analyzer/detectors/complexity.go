@@ -1,13 +1,18 @@
 package detectors
 
 import (
+	"cmp"
 	"fmt"
 	"go/ast"
 	"go/token"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"gophercheck/internal/config"
 	"gophercheck/internal/context"
 	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
 )
 
 // ComplexityDetector calculates cyclomatic complexity of functions
@@ -15,6 +20,17 @@ type ComplexityDetector struct {
 	config *config.Config
 }
 
+var _ Detector = (*ComplexityDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "cyclomatic_complexity",
+		Category: "complexity",
+		Version:  "1.2.0",
+		New:      func(cfg *config.Config) Detector { return NewComplexityDetectorWithConfig(cfg) },
+	})
+}
+
 // NewComplexityDetector creates a new complexity detector
 func NewComplexityDetector() *ComplexityDetector {
 	return &ComplexityDetector{}
@@ -43,7 +59,10 @@ func (d *ComplexityDetector) Detect(file *ast.File, fset *token.FileSet, filenam
 		context:  ctx,
 	}
 
-	ast.Walk(detector, file)
+	for _, scope := range FuncScopes(file) {
+		detector.checkFunction(scope)
+	}
+	detector.checkTypes(file)
 	return detector.issues
 }
 
@@ -55,21 +74,115 @@ type complexityVisitor struct {
 	context  *context.AnalysisContext
 }
 
-func (v *complexityVisitor) Visit(node ast.Node) ast.Visitor {
-	if fn, ok := node.(*ast.FuncDecl); ok && fn.Body != nil {
-		complexity := v.calculateComplexity(fn.Body)
-		threshold := 10
-		if v.detector.config != nil && v.detector.config.Rules.Complexity.CyclomaticComplexity.Enabled {
-			threshold = v.detector.config.Rules.Complexity.CyclomaticComplexity.MediumThreshold
+// checkFunction measures one function or function literal. A literal's
+// decisions don't count toward its enclosing function, so goroutine bodies
+// and handler closures are measured on their own.
+func (v *complexityVisitor) checkFunction(scope FuncScope) {
+	complexity := CyclomaticComplexity(scope.Body)
+	threshold := 10
+	if v.detector.config != nil && v.detector.config.Rules.Complexity.CyclomaticComplexity.Enabled {
+		threshold = v.detector.config.Rules.Complexity.CyclomaticComplexity.MediumThreshold
+	}
+	if complexity > threshold {
+		v.createComplexityIssue(scope, complexity)
+	}
+}
+
+// methodComplexity is the cyclomatic complexity of one method
+type methodComplexity struct {
+	name       string
+	complexity int
+}
+
+// checkTypes adds up the complexity of the methods of each type declared in
+// file, wherever in its package they are declared, and flags the types whose
+// methods together are too complex
+func (v *complexityVisitor) checkTypes(file *ast.File) {
+	thresholds, enabled := v.typeThresholds()
+	if !enabled || v.context == nil {
+		return
+	}
+	dir := filepath.Dir(v.fset.Position(file.Package).Filename)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			var methods []methodComplexity
+			total := 0
+			for _, method := range v.context.Methods[context.TypeKey(dir, file.Name.Name, typeSpec.Name.Name)] {
+				if method.Body == nil {
+					continue
+				}
+				complexity := CyclomaticComplexity(method.Body)
+				methods = append(methods, methodComplexity{name: method.Name.Name, complexity: complexity})
+				total += complexity
+			}
+			if total > thresholds.MediumThreshold {
+				v.createTypeIssue(typeSpec, methods, total, thresholds)
+			}
 		}
-		if complexity > threshold {
-			v.createComplexityIssue(fn, complexity)
+	}
+}
+
+// typeThresholds returns the type complexity thresholds, and whether types
+// are checked at all
+func (v *complexityVisitor) typeThresholds() (config.ThresholdConfig, bool) {
+	if v.detector.config == nil {
+		return config.DefaultConfig().Rules.Complexity.TypeComplexity, true
+	}
+	thresholds := v.detector.config.Rules.Complexity.TypeComplexity
+	return thresholds, thresholds.Enabled
+}
+
+func (v *complexityVisitor) createTypeIssue(typeSpec *ast.TypeSpec, methods []methodComplexity, total int, thresholds config.ThresholdConfig) {
+	position := v.fset.Position(typeSpec.Pos())
+
+	severity := models.SeverityMedium
+	switch {
+	case total > thresholds.CriticalThreshold:
+		severity = models.SeverityCritical
+	case total > thresholds.HighThreshold:
+		severity = models.SeverityHigh
+	}
+
+	// The most complex methods are where splitting the type starts
+	slices.SortStableFunc(methods, func(x, y methodComplexity) int {
+		return cmp.Or(cmp.Compare(y.complexity, x.complexity), cmp.Compare(x.name, y.name))
+	})
+	var top []string
+	for i, method := range methods {
+		if i == 3 {
+			break
 		}
+		top = append(top, fmt.Sprintf("%s (%d)", method.name, method.complexity))
+	}
+
+	issue := models.Issue{
+		Type:     models.IssueTypeComplexity,
+		Severity: severity,
+		File:     v.filename,
+		Line:     position.Line,
+		Column:   position.Column,
+		Message: fmt.Sprintf("Type '%s' has a total cyclomatic complexity of %d across %d methods",
+			typeSpec.Name.Name, total, len(methods)),
+		Suggestion: suggestions.Render("type_complexity.split", suggestions.Data{}) +
+			"\n\nMost complex methods: " + strings.Join(top, ", "),
+		Complexity:  fmt.Sprintf("%s: complexity %d in %d methods", typeSpec.Name.Name, total, len(methods)),
+		CodeSnippet: position.String(),
+		Confidence:  1.0, // Computed directly from the AST
+		Impact:      fmt.Sprintf("Complexity %d → %d or less per type", total, thresholds.MediumThreshold),
+		FixEffort:   models.EffortLarge,
 	}
-	return v
+
+	v.issues = append(v.issues, issue)
 }
 
-func (v *complexityVisitor) calculateComplexity(body *ast.BlockStmt) int {
+// CyclomaticComplexity counts the decision points of a function body plus
+// one. Function literals inside the body are not counted.
+func CyclomaticComplexity(body *ast.BlockStmt) int {
 	complexity := 1 // Base complexity
 
 	ast.Inspect(body, func(n ast.Node) bool {
@@ -118,12 +231,9 @@ func (v *complexityVisitor) calculateComplexity(body *ast.BlockStmt) int {
 	return complexity
 }
 
-func (v *complexityVisitor) createComplexityIssue(fn *ast.FuncDecl, complexity int) {
-	position := v.fset.Position(fn.Pos())
-	funcName := "anonymous"
-	if fn.Name != nil {
-		funcName = fn.Name.Name
-	}
+func (v *complexityVisitor) createComplexityIssue(scope FuncScope, complexity int) {
+	position := v.fset.Position(scope.Node.Pos())
+	funcName := scope.Name
 
 	issue := models.Issue{
 		Type:        models.IssueCyclomaticComplex,
@@ -132,15 +242,34 @@ func (v *complexityVisitor) createComplexityIssue(fn *ast.FuncDecl, complexity i
 		Line:        position.Line,
 		Column:      position.Column,
 		Function:    funcName,
-		Message:     fmt.Sprintf("Function '%s' has high cyclomatic complexity: %d", funcName, complexity),
-		Suggestion:  v.generateComplexitySuggestion(complexity),
+		Message:     fmt.Sprintf("%s has high cyclomatic complexity: %d", scope.Describe(), complexity),
+		Suggestion:  nestingSummary(v.fset, scope.Body) + suggestions.Render(v.suggestionID(complexity), suggestions.Data{}),
 		Complexity:  fmt.Sprintf("Complexity: %d", complexity),
 		CodeSnippet: position.String(),
+		Confidence:  1.0, // Computed directly from the AST
+		Impact:      fmt.Sprintf("Complexity %d → %d or less per function", complexity, v.getMediumThreshold()),
+		FixEffort:   v.estimateFixEffort(complexity),
 	}
 
 	v.issues = append(v.issues, issue)
 }
 
+func (v *complexityVisitor) getMediumThreshold() int {
+	if v.detector.config != nil && v.detector.config.Rules.Complexity.CyclomaticComplexity.Enabled {
+		return v.detector.config.Rules.Complexity.CyclomaticComplexity.MediumThreshold
+	}
+	return 10
+}
+
+// estimateFixEffort assumes moderately complex functions can be split locally,
+// while very complex ones usually need a redesign
+func (v *complexityVisitor) estimateFixEffort(complexity int) models.FixEffort {
+	if v.calculateSeverity(complexity) == models.SeverityCritical {
+		return models.EffortLarge
+	}
+	return models.EffortSmall
+}
+
 func (v *complexityVisitor) calculateSeverity(complexity int) models.Severity {
 	mediumThreshold := 10
 	highThreshold := 15
@@ -164,20 +293,14 @@ func (v *complexityVisitor) calculateSeverity(complexity int) models.Severity {
 	}
 }
 
-func (v *complexityVisitor) generateComplexitySuggestion(complexity int) string {
-	suggestions := []string{
-		"Consider breaking this function into smaller, single-purpose functions",
-		"Use early returns to reduce nesting levels",
-		"Extract complex conditional logic into separate functions",
-		"Consider using a state machine or strategy pattern for complex branching",
-		"Use lookup tables or maps instead of long if-else chains",
-	}
-
-	if complexity <= 15 {
-		return suggestions[0] + ". " + suggestions[1]
-	} else if complexity <= 25 {
-		return suggestions[0] + ". " + suggestions[2] + ". " + suggestions[1]
-	} else {
-		return suggestions[3] + ". " + suggestions[0] + ". " + suggestions[4]
+// suggestionID picks the catalog suggestion for a complexity score
+func (v *complexityVisitor) suggestionID(complexity int) string {
+	switch {
+	case complexity <= 15:
+		return "cyclomatic_complexity.moderate"
+	case complexity <= 25:
+		return "cyclomatic_complexity.high"
+	default:
+		return "cyclomatic_complexity.critical"
 	}
 }
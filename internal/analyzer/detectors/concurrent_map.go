@@ -0,0 +1,231 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// ConcurrentMapDetector finds maps written from goroutines started in a loop
+// without a lock
+type ConcurrentMapDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*ConcurrentMapDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "concurrent_map_write",
+		Category: "concurrency",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewConcurrentMapDetectorWithConfig(cfg) },
+	})
+}
+
+func NewConcurrentMapDetector() *ConcurrentMapDetector {
+	return &ConcurrentMapDetector{}
+}
+
+func NewConcurrentMapDetectorWithConfig(cfg *config.Config) *ConcurrentMapDetector {
+	return &ConcurrentMapDetector{
+		config: cfg,
+	}
+}
+
+func (d *ConcurrentMapDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *ConcurrentMapDetector) Name() string {
+	return "Concurrent Map Write Detector"
+}
+
+func (d *ConcurrentMapDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &concurrentMapVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		context:  ctx,
+		reported: make(map[ast.Node]bool),
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+type concurrentMapVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	loops       []ast.Node // Enclosing loops, innermost last
+	context     *context.AnalysisContext
+	reported    map[ast.Node]bool // Goroutine bodies already checked, reported once each
+}
+
+func (v *concurrentMapVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Name != nil {
+			v.currentFunc = n.Name.Name
+		}
+		return v
+
+	case *ast.ForStmt, *ast.RangeStmt:
+		v.loops = append(v.loops, n)
+		for _, stmt := range getLoopBody(n) {
+			ast.Walk(v, stmt)
+		}
+		v.loops = v.loops[:len(v.loops)-1]
+		return nil
+
+	case *ast.CallExpr:
+		if len(v.loops) > 0 {
+			if body := goroutineBody(n); body != nil {
+				v.checkConcurrentWrites(body)
+			}
+		}
+		return v
+
+	case *ast.GoStmt:
+		if len(v.loops) > 0 {
+			if lit, ok := n.Call.Fun.(*ast.FuncLit); ok {
+				v.checkConcurrentWrites(lit.Body)
+			}
+		}
+		return v
+
+	default:
+		return v
+	}
+}
+
+// checkConcurrentWrites reports writes in a goroutine body to maps declared
+// outside the innermost loop, unless the body takes a lock
+func (v *concurrentMapVisitor) checkConcurrentWrites(body *ast.BlockStmt) {
+	loop := v.loops[len(v.loops)-1]
+	if v.reported[body] || callsLock(body) {
+		return
+	}
+	v.reported[body] = true
+
+	// Report each shared map once per goroutine
+	seen := make(map[string]bool)
+	check := func(node ast.Node, mapExpr ast.Expr) {
+		name := types.ExprString(mapExpr)
+		if !seen[name] && v.isSharedMap(mapExpr, loop) {
+			seen[name] = true
+			v.createConcurrentWriteIssue(node, mapExpr)
+		}
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range stmt.Lhs {
+				if index, ok := lhs.(*ast.IndexExpr); ok {
+					check(stmt, index.X)
+				}
+			}
+		case *ast.IncDecStmt:
+			if index, ok := stmt.X.(*ast.IndexExpr); ok {
+				check(stmt, index.X)
+			}
+		case *ast.CallExpr:
+			if mapArg, _, ok := deleteArgs(v.context, stmt); ok {
+				check(stmt, mapArg)
+			}
+		}
+		return true
+	})
+}
+
+func (v *concurrentMapVisitor) createConcurrentWriteIssue(node ast.Node, mapExpr ast.Expr) {
+	name := types.ExprString(mapExpr)
+	v.addIssue(node, models.Issue{
+		Type:       models.IssueConcurrentMap,
+		Severity:   models.SeverityHigh,
+		Message:    fmt.Sprintf("Map '%s' is written from goroutines started in a loop without a lock - concurrent map writes crash the program", name),
+		Suggestion: suggestions.Render("concurrent_map_write.lock", suggestions.Data{Var: name}),
+		Complexity: "Data race",
+		Confidence: 0.7, // Synchronization may happen outside the goroutine
+		Impact:     "Prevents fatal concurrent map writes",
+		FixEffort:  models.EffortSmall,
+	})
+}
+
+// goroutineBody returns the function literal body passed to an errgroup-style
+// Go(func() error { ... }) call
+func goroutineBody(call *ast.CallExpr) *ast.BlockStmt {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Go" || len(call.Args) != 1 {
+		return nil
+	}
+	if lit, ok := call.Args[0].(*ast.FuncLit); ok {
+		return lit.Body
+	}
+	return nil
+}
+
+// callsLock reports whether body calls a Lock method, e.g. mu.Lock()
+func callsLock(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Lock" {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// isSharedMap reports whether expr is a map declared outside loop, and so
+// shared by every goroutine the loop starts
+func (v *concurrentMapVisitor) isSharedMap(expr ast.Expr, loop ast.Node) bool {
+	if !isMapExpr(v.context, expr) {
+		return false
+	}
+
+	root := expr
+	for {
+		sel, ok := root.(*ast.SelectorExpr)
+		if !ok {
+			break
+		}
+		root = sel.X
+	}
+	ident, ok := root.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	obj := v.context.TypeInfo.ObjectOf(ident)
+	if obj == nil {
+		return false
+	}
+	if obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope() {
+		return true // Package-level variable
+	}
+	return obj.Pos() < loop.Pos() || obj.Pos() >= loop.End()
+}
+
+// addIssue fills in the location of issue and records it
+func (v *concurrentMapVisitor) addIssue(node ast.Node, issue models.Issue) {
+	position := v.fset.Position(node.Pos())
+
+	issue.File = v.filename
+	issue.Line = position.Line
+	issue.Column = position.Column
+	issue.Function = v.currentFunc
+	issue.CodeSnippet = position.String()
+
+	v.issues = append(v.issues, issue)
+}
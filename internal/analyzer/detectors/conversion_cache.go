@@ -0,0 +1,670 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// stringConversions are the functions turning a value into a string, with
+// the index of the converted argument
+var stringConversions = map[string]map[string]int{
+	"strconv": {"Itoa": 0, "FormatInt": 0, "FormatUint": 0, "FormatFloat": 0, "Quote": 0, "QuoteRune": 0},
+	"fmt":     {"Sprint": 0, "Sprintf": 1},
+}
+
+// ConversionCacheDetector finds loops converting the same few values to
+// strings over and over: strconv and fmt conversions of loop-invariant
+// values, conversions of values drawn from a small set such as x % 10 or a
+// byte, and enum-to-string switches run on every iteration. Each is
+// suggested a precomputed lookup table, with the table code generated.
+type ConversionCacheDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*ConversionCacheDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "conversion_cache",
+		Category: "performance",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewConversionCacheDetectorWithConfig(cfg) },
+	})
+}
+
+func NewConversionCacheDetector() *ConversionCacheDetector {
+	return &ConversionCacheDetector{}
+}
+
+func NewConversionCacheDetectorWithConfig(cfg *config.Config) *ConversionCacheDetector {
+	return &ConversionCacheDetector{
+		config: cfg,
+	}
+}
+
+func (d *ConversionCacheDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *ConversionCacheDetector) Name() string {
+	return "Conversion Cache Detector"
+}
+
+func (d *ConversionCacheDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &conversionCacheVisitor{
+		fset:      fset,
+		filename:  filename,
+		issues:    make([]models.Issue, 0),
+		detector:  d,
+		context:   ctx,
+		settings:  d.settings(),
+		pkgName:   file.Name.Name,
+		enumFuncs: make(map[string]enumSwitch),
+		reported:  make(map[string]bool),
+	}
+	if detector.settings.DetectEnumSwitches {
+		detector.collectEnumFuncs(file)
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+// settings returns the rule config, or the defaults without one
+func (d *ConversionCacheDetector) settings() config.ConversionCacheConfig {
+	if d.config != nil && d.config.Rules.Performance.ConversionCache.Enabled {
+		return d.config.Rules.Performance.ConversionCache
+	}
+	return config.DefaultConfig().Rules.Performance.ConversionCache
+}
+
+// enumSwitch is a switch mapping constants to string literals
+type enumSwitch struct {
+	name     string // The function it makes up, e.g. Kind.String
+	tag      ast.Expr
+	keys     []ast.Expr // Case values, in source order
+	names    []string   // The quoted literal for each key
+	fallback string     // The default clause's literal, if any
+}
+
+type conversionCacheVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	pkgName     string
+	loops       []ast.Node // Enclosing loops, innermost last
+	detector    *ConversionCacheDetector
+	context     *context.AnalysisContext
+	settings    config.ConversionCacheConfig
+	enumFuncs   map[string]enumSwitch // Functions that are one enum switch, by name
+	reported    map[string]bool       // Enum functions already reported
+}
+
+func (v *conversionCacheVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Name != nil {
+			v.currentFunc = n.Name.Name
+		}
+		return v
+
+	case *ast.ForStmt, *ast.RangeStmt:
+		if isBenchmarkTimingLoop(v.context, n) {
+			for _, stmt := range getLoopBody(n) {
+				ast.Walk(v, stmt)
+			}
+			return nil
+		}
+
+		v.loops = append(v.loops, n)
+		for _, stmt := range getLoopBody(n) {
+			ast.Walk(v, stmt)
+		}
+		v.loops = v.loops[:len(v.loops)-1]
+		return nil
+
+	case *ast.FuncLit:
+		// A closure defined in a loop doesn't necessarily run in it
+		loops := v.loops
+		v.loops = nil
+		ast.Walk(v, n.Body)
+		v.loops = loops
+		return nil
+
+	case *ast.CallExpr:
+		if len(v.loops) > 0 {
+			v.checkConversion(n)
+			v.checkEnumCall(n)
+		}
+		return v
+
+	case *ast.SwitchStmt:
+		if len(v.loops) > 0 && v.settings.DetectEnumSwitches {
+			if sw, ok := v.assignedEnumSwitch(n); ok {
+				v.createEnumIssue(n, sw)
+			}
+		}
+		return v
+
+	default:
+		return v
+	}
+}
+
+// checkConversion reports a string conversion in a loop whose value is the
+// same on every iteration, or drawn from a set small enough to tabulate
+func (v *conversionCacheVisitor) checkConversion(call *ast.CallExpr) {
+	pkg, name, ok := packageFunc(v.context, call)
+	if !ok {
+		return
+	}
+	index, ok := stringConversions[pkg][name]
+	if !ok || index >= len(call.Args) {
+		return
+	}
+	if pkg == "fmt" && !v.singleValueFormat(call, name) {
+		return
+	}
+	funcName := pkg + "." + name
+	value := call.Args[index]
+
+	if v.settings.DetectInvariant && v.argsInvariant(call.Args) {
+		if _, ok := value.(*ast.BasicLit); !ok {
+			v.createHoistIssue(call, funcName)
+		}
+		return
+	}
+	if !v.settings.DetectSmallSets {
+		return
+	}
+	set, size := v.smallSet(value)
+	if size < 2 || size > v.settings.MaxTableSize {
+		return
+	}
+	if pkg == "strconv" && size <= 100 && v.decimalInt(call, name) {
+		return // strconv returns constant strings for 0..99 without allocating
+	}
+	v.createTableIssue(call, funcName, set, size)
+}
+
+// singleValueFormat reports whether an fmt call formats exactly one value
+// with a plain verb, so it is a conversion rather than a message
+func (v *conversionCacheVisitor) singleValueFormat(call *ast.CallExpr, name string) bool {
+	if name == "Sprint" {
+		return len(call.Args) == 1
+	}
+	if len(call.Args) != 2 {
+		return false
+	}
+	format, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || format.Kind != token.STRING {
+		return false
+	}
+	switch format.Value {
+	case `"%d"`, `"%v"`, `"%s"`, `"%x"`, `"%X"`, `"%q"`, `"%t"`:
+		return true
+	}
+	return false
+}
+
+// decimalInt reports whether a strconv call formats an integer in base 10,
+// the case strconv serves from constants for small values
+func (v *conversionCacheVisitor) decimalInt(call *ast.CallExpr, name string) bool {
+	switch name {
+	case "Itoa":
+		return true
+	case "FormatInt", "FormatUint":
+		base, ok := v.constantInt(call.Args[1])
+		return ok && base == 10
+	}
+	return false
+}
+
+// argsInvariant reports whether every argument is the same on each iteration
+// of the innermost loop
+func (v *conversionCacheVisitor) argsInvariant(args []ast.Expr) bool {
+	assigned := assignedInLoop(v.loops[len(v.loops)-1])
+	for _, arg := range args {
+		if !isLoopInvariant(arg, assigned) {
+			return false
+		}
+	}
+	return true
+}
+
+// smallSet returns the expression whose values form a small set, looking
+// through parentheses and type conversions, and the number of values it
+// takes: N for x % N, M+1 for x & M, and 256 for bytes. It returns 0 when the
+// set is unknown.
+func (v *conversionCacheVisitor) smallSet(expr ast.Expr) (ast.Expr, int) {
+	for {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+			continue
+		case *ast.CallExpr:
+			if len(e.Args) == 1 && v.isTypeConversion(e) {
+				expr = e.Args[0]
+				continue
+			}
+		case *ast.BinaryExpr:
+			bound, ok := v.constantInt(e.Y)
+			if ok && bound > 0 && bound <= int64(v.settings.MaxTableSize) {
+				switch e.Op {
+				case token.REM:
+					return e, int(bound)
+				case token.AND:
+					return e, int(bound) + 1
+				}
+			}
+		}
+		break
+	}
+
+	if v.context == nil || v.context.TypeInfo == nil {
+		return nil, 0
+	}
+	if basic, ok := coreTypeOf(v.context.TypeInfo.TypeOf(expr)).(*types.Basic); ok && basic.Kind() == types.Uint8 {
+		return expr, 256
+	}
+	return nil, 0
+}
+
+// isTypeConversion reports whether call converts to a type, e.g. int64(x)
+func (v *conversionCacheVisitor) isTypeConversion(call *ast.CallExpr) bool {
+	if v.context != nil && v.context.TypeInfo != nil {
+		if tv, ok := v.context.TypeInfo.Types[call.Fun]; ok {
+			return tv.IsType()
+		}
+	}
+	ident, ok := call.Fun.(*ast.Ident)
+	return ok && types.Universe.Lookup(ident.Name) != nil && ident.Name != "len" && ident.Name != "cap"
+}
+
+// constantInt returns the value of an integer constant expression
+func (v *conversionCacheVisitor) constantInt(expr ast.Expr) (int64, bool) {
+	if v.context != nil && v.context.TypeInfo != nil {
+		if tv, ok := v.context.TypeInfo.Types[expr]; ok && tv.Value != nil {
+			return constant.Int64Val(constant.ToInt(tv.Value))
+		}
+	}
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(lit.Value, 0, 64)
+	return n, err == nil
+}
+
+// collectEnumFuncs records the functions and methods whose whole body is an
+// enum switch returning a literal from every case
+func (v *conversionCacheVisitor) collectEnumFuncs(file *ast.File) {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || len(fn.Body.List) == 0 || len(fn.Body.List) > 2 {
+			continue
+		}
+		sw, ok := fn.Body.List[0].(*ast.SwitchStmt)
+		if !ok {
+			continue
+		}
+		enum, ok := v.enumSwitchOf(sw, returnedLiteral)
+		if !ok {
+			continue
+		}
+		if len(fn.Body.List) == 2 {
+			ret, ok := fn.Body.List[1].(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != 1 {
+				continue
+			}
+			fallback, ok := ret.Results[0].(*ast.BasicLit)
+			if !ok || fallback.Kind != token.STRING {
+				continue // The fallback formats the value, e.g. "Kind(" + ... + ")"
+			}
+			enum.fallback = fallback.Value
+		}
+		enum.name = fn.Name.Name
+		if fn.Recv != nil && len(fn.Recv.List) == 1 {
+			if recv := rootIdent(Uninstantiate(fn.Recv.List[0].Type)); recv != nil {
+				enum.name = recv.Name + "." + fn.Name.Name
+			}
+		}
+		v.enumFuncs[fn.Name.Name] = enum
+	}
+}
+
+// checkEnumCall reports a call in a loop to a function collected by
+// collectEnumFuncs, once per function
+func (v *conversionCacheVisitor) checkEnumCall(call *ast.CallExpr) {
+	if len(v.enumFuncs) == 0 {
+		return
+	}
+	var name string
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		name = fun.Name
+	case *ast.SelectorExpr:
+		name = fun.Sel.Name
+	default:
+		return
+	}
+	enum, ok := v.enumFuncs[name]
+	if !ok || v.reported[name] {
+		return
+	}
+	v.reported[name] = true
+	v.createEnumIssue(call, enum)
+}
+
+// assignedEnumSwitch matches a switch whose every case assigns a string
+// literal to the same variable
+func (v *conversionCacheVisitor) assignedEnumSwitch(sw *ast.SwitchStmt) (enumSwitch, bool) {
+	var target string
+	return v.enumSwitchOf(sw, func(stmt ast.Stmt) (string, bool) {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return "", false
+		}
+		ident, ok := assign.Lhs[0].(*ast.Ident)
+		if !ok || (target != "" && ident.Name != target) {
+			return "", false
+		}
+		target = ident.Name
+		lit, ok := assign.Rhs[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return "", false
+		}
+		return lit.Value, true
+	})
+}
+
+// enumSwitchOf matches a tagged switch on an integer with at least
+// MinEnumCases constant cases, each clause being a single statement that
+// literal turns into a quoted string. Switches on strings are left alone: a
+// map lookup hashes the string and isn't clearly cheaper.
+func (v *conversionCacheVisitor) enumSwitchOf(sw *ast.SwitchStmt, literal func(ast.Stmt) (string, bool)) (enumSwitch, bool) {
+	if sw.Tag == nil || sw.Init != nil || !v.isIntegerTag(sw.Tag) {
+		return enumSwitch{}, false
+	}
+	enum := enumSwitch{tag: sw.Tag}
+	for _, stmt := range sw.Body.List {
+		clause := stmt.(*ast.CaseClause)
+		if len(clause.Body) != 1 {
+			return enumSwitch{}, false
+		}
+		name, ok := literal(clause.Body[0])
+		if !ok {
+			return enumSwitch{}, false
+		}
+		if clause.List == nil {
+			enum.fallback = name
+			continue
+		}
+		for _, key := range clause.List {
+			if !v.isConstant(key) {
+				return enumSwitch{}, false
+			}
+			enum.keys = append(enum.keys, key)
+			enum.names = append(enum.names, name)
+		}
+	}
+	return enum, len(enum.keys) >= v.settings.MinEnumCases
+}
+
+// isIntegerTag reports whether a switch tag is an integer, assuming it is
+// without type info
+func (v *conversionCacheVisitor) isIntegerTag(tag ast.Expr) bool {
+	if v.context == nil || v.context.TypeInfo == nil {
+		return true
+	}
+	basic, ok := coreTypeOf(v.context.TypeInfo.TypeOf(tag)).(*types.Basic)
+	return ok && basic.Info()&types.IsInteger != 0
+}
+
+// returnedLiteral matches return "literal"
+func returnedLiteral(stmt ast.Stmt) (string, bool) {
+	ret, ok := stmt.(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", false
+	}
+	lit, ok := ret.Results[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	return lit.Value, true
+}
+
+// isConstant reports whether a case value is a constant: known from type
+// info, or without it a literal or a named (possibly qualified) constant
+func (v *conversionCacheVisitor) isConstant(expr ast.Expr) bool {
+	if v.context != nil && v.context.TypeInfo != nil {
+		tv, ok := v.context.TypeInfo.Types[expr]
+		return ok && tv.Value != nil
+	}
+	switch e := expr.(type) {
+	case *ast.BasicLit, *ast.Ident:
+		return true
+	case *ast.SelectorExpr:
+		_, ok := e.X.(*ast.Ident)
+		return ok
+	}
+	return false
+}
+
+// enumTable generates the lookup table replacing an enum switch: an array
+// indexed by the constants when they are small non-negative integers, a map
+// otherwise. It returns the table declaration and the lookup expression.
+func (v *conversionCacheVisitor) enumTable(enum enumSwitch) (string, string) {
+	tag := types.ExprString(enum.tag)
+	base := "value"
+	if ident := rootIdent(enum.tag); ident != nil {
+		base = ident.Name
+	}
+	if v.context != nil && v.context.TypeInfo != nil {
+		if named, ok := v.context.TypeInfo.TypeOf(enum.tag).(*types.Named); ok {
+			base = named.Obj().Name()
+		}
+	}
+	table := lowerFirst(base) + "Names"
+
+	var b strings.Builder
+	if v.smallIntKeys(enum.keys) {
+		fmt.Fprintf(&b, "var %s = [...]string{\n", table)
+	} else {
+		fmt.Fprintf(&b, "var %s = map[%s]string{\n", table, v.typeString(enum.tag))
+	}
+	for i, key := range enum.keys {
+		fmt.Fprintf(&b, "    %s: %s,\n", types.ExprString(key), enum.names[i])
+	}
+	b.WriteString("}")
+
+	fallback := enum.fallback
+	if fallback == "" {
+		fallback = `""`
+	}
+	if v.smallIntKeys(enum.keys) {
+		return b.String(), fmt.Sprintf("name := %s\nif %s >= 0 && int(%s) < len(%s) && %s[%s] != \"\" {\n    name = %s[%s]\n}", fallback, tag, tag, table, table, tag, table, tag)
+	}
+	return b.String(), fmt.Sprintf("name, ok := %s[%s]\nif !ok {\n    name = %s\n}", table, tag, fallback)
+}
+
+// smallIntKeys reports whether every key is an integer constant between 0
+// and MaxTableSize, so the table can be an array. Without type info the
+// values are unknown and a map is suggested.
+func (v *conversionCacheVisitor) smallIntKeys(keys []ast.Expr) bool {
+	if v.context == nil || v.context.TypeInfo == nil {
+		return false
+	}
+	for _, key := range keys {
+		n, ok := v.constantInt(key)
+		if !ok || n < 0 || n >= int64(v.settings.MaxTableSize) {
+			return false
+		}
+	}
+	return true
+}
+
+// valueTable generates a table of the conversion call applied to every value
+// of set, and the expression looking the result up
+func (v *conversionCacheVisitor) valueTable(call *ast.CallExpr, set ast.Expr, size int) (string, string) {
+	base := "value"
+	operand := set
+	if binary, ok := set.(*ast.BinaryExpr); ok {
+		operand = binary.X
+	}
+	if ident := rootIdent(operand); ident != nil {
+		base = ident.Name
+	}
+	table := lowerFirst(base) + "Strings"
+
+	args := make([]string, len(call.Args))
+	for i, arg := range call.Args {
+		args[i] = types.ExprString(arg)
+		if containsNode(arg, set) {
+			args[i] = v.replaceSet(arg, set, "i")
+		}
+	}
+	conversion := fmt.Sprintf("%s(%s)", types.ExprString(call.Fun), strings.Join(args, ", "))
+
+	source := fmt.Sprintf("var %s = func() (t [%d]string) {\n    for i := range t {\n        t[i] = %s\n    }\n    return t\n}()", table, size, conversion)
+	return source, fmt.Sprintf("%s[%s]", table, types.ExprString(set))
+}
+
+// replaceSet writes expr, which wraps set in parentheses and conversions as
+// smallSet unwrapped them, with set replaced by name
+func (v *conversionCacheVisitor) replaceSet(expr, set ast.Expr, name string) string {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		if e.X != set {
+			return v.replaceSet(e.X, set, name)
+		}
+	case *ast.CallExpr:
+		if len(e.Args) == 1 && e.Args[0] != set {
+			return fmt.Sprintf("%s(%s)", types.ExprString(e.Fun), v.replaceSet(e.Args[0], set, name))
+		}
+		if len(e.Args) == 1 {
+			return fmt.Sprintf("%s(%s)", types.ExprString(e.Fun), name)
+		}
+	}
+	if expr == set {
+		return name
+	}
+	return types.ExprString(expr)
+}
+
+// containsNode reports whether target is node or one of its descendants
+func containsNode(node, target ast.Node) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == target {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func (v *conversionCacheVisitor) createHoistIssue(call *ast.CallExpr, funcName string) {
+	position := v.fset.Position(call.Pos())
+	issue := models.Issue{
+		Type:        models.IssueConversionCache,
+		Severity:    models.SeverityMedium,
+		File:        v.filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Function:    v.currentFunc,
+		Message:     fmt.Sprintf("%s converts a value the loop doesn't change on every iteration - convert it once before the loop", funcName),
+		Suggestion:  suggestions.Render("conversion_cache.hoist", suggestions.Data{Type: funcName, Source: types.ExprString(call)}),
+		Complexity:  "One conversion per iteration instead of one",
+		CodeSnippet: position.String(),
+		Confidence:  0.85,
+		Impact:      "Removes a conversion from the loop",
+		FixEffort:   models.EffortTrivial,
+	}
+	v.issues = append(v.issues, issue)
+}
+
+func (v *conversionCacheVisitor) createTableIssue(call *ast.CallExpr, funcName string, set ast.Expr, size int) {
+	position := v.fset.Position(call.Pos())
+	source, lookup := v.valueTable(call, set, size)
+	issue := models.Issue{
+		Type:        models.IssueConversionCache,
+		Severity:    models.SeverityLow,
+		File:        v.filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Function:    v.currentFunc,
+		Message:     fmt.Sprintf("%s converts one of %d values in a loop - precompute them in a lookup table", funcName, size),
+		Suggestion:  suggestions.Render("conversion_cache.table", suggestions.Data{Type: funcName, Source: source, Var: lookup, Iterations: size}),
+		Complexity:  "A conversion per iteration instead of an index",
+		CodeSnippet: position.String(),
+		Confidence:  0.7, // The values may not repeat often enough to pay for the table
+		Impact:      "Allocation-free conversions",
+		FixEffort:   models.EffortSmall,
+	}
+	v.issues = append(v.issues, issue)
+}
+
+func (v *conversionCacheVisitor) createEnumIssue(node ast.Node, enum enumSwitch) {
+	position := v.fset.Position(node.Pos())
+	source, lookup := v.enumTable(enum)
+	what := fmt.Sprintf("switch maps %d constants to strings", len(enum.keys))
+	if enum.name != "" {
+		what = fmt.Sprintf("%s maps %d constants to strings with a switch", enum.name, len(enum.keys))
+	}
+	issue := models.Issue{
+		Type:        models.IssueConversionCache,
+		Severity:    models.SeverityLow,
+		File:        v.filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Function:    v.currentFunc,
+		Message:     fmt.Sprintf("%s on every loop iteration - a lookup table indexes them directly", what),
+		Suggestion:  suggestions.Render("conversion_cache.enum", suggestions.Data{Source: source, Var: lookup, Type: types.ExprString(enum.tag)}),
+		Complexity:  "A chain of comparisons per iteration instead of an index",
+		CodeSnippet: position.String(),
+		Confidence:  0.6, // The compiler may already turn a dense switch into a jump table
+		Impact:      "Constant-time names",
+		FixEffort:   models.EffortSmall,
+	}
+	v.issues = append(v.issues, issue)
+}
+
+// typeString returns the type of expr as written in this package, or "T"
+// without type info
+func (v *conversionCacheVisitor) typeString(expr ast.Expr) string {
+	if v.context == nil || v.context.TypeInfo == nil {
+		return "T"
+	}
+	t := v.context.TypeInfo.TypeOf(expr)
+	if t == nil {
+		return "T"
+	}
+	return types.TypeString(types.Default(t), func(pkg *types.Package) string {
+		if pkg.Name() == v.pkgName {
+			return ""
+		}
+		return pkg.Name()
+	})
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
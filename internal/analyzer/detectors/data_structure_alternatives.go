@@ -0,0 +1,304 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// dataStructureConfig returns the data structure settings, or the defaults
+// when the detector runs without a config
+func (v *dataStructureVisitor) dataStructureConfig() config.DataStructureConfig {
+	if v.detector.config == nil {
+		return config.DefaultConfig().Rules.Performance.DataStructure
+	}
+	return v.detector.config.Rules.Performance.DataStructure
+}
+
+// checkMembershipLoop reports a nested range loop whose body compares the
+// element itself for equality: a membership test that a set answers in O(1).
+// It returns true when it reported, so the generic linear search issue is
+// skipped for the loop.
+func (v *dataStructureVisitor) checkMembershipLoop(rangeStmt *ast.RangeStmt) bool {
+	cfg := v.dataStructureConfig()
+	if !cfg.DetectMembershipSets || v.loopDepth < cfg.MinSearchComplexity {
+		return false
+	}
+	value, ok := rangeStmt.Value.(*ast.Ident)
+	if !ok || value.Name == "_" || rangeStmt.Body == nil {
+		return false
+	}
+
+	if !testsMembership(rangeStmt.Body, value.Name) {
+		return false
+	}
+
+	sliceName := "slice"
+	if ident := rootIdent(rangeStmt.X); ident != nil {
+		sliceName = ident.Name
+	}
+	v.addAlternativeIssue(rangeStmt, models.SeverityMedium, 0.75,
+		fmt.Sprintf("Membership test over '%s' in a nested loop - a map[%s]struct{} set answers it in O(1)", sliceName, v.typeName(value)),
+		"inefficient_data_structure.set", suggestions.Data{Var: sliceName, Type: v.typeName(value)},
+		"O(n) per test → O(1) with a set")
+	return true
+}
+
+// checkMembershipCall reports slices.Contains and slices.Index inside a loop
+// on a slice the loop doesn't build, which rescans it on every iteration
+func (v *dataStructureVisitor) checkMembershipCall(call *ast.CallExpr) {
+	if !v.dataStructureConfig().DetectMembershipSets || len(call.Args) != 2 {
+		return
+	}
+	pkg, name, ok := packageFunc(v.context, call)
+	if !ok || pkg != "slices" || (name != "Contains" && name != "Index") {
+		return
+	}
+	slice := rootIdent(call.Args[0])
+	if slice == nil || declaredIn(v.loops[0], slice.Name) {
+		return
+	}
+
+	elemType := "T"
+	if v.context != nil && v.context.TypeInfo != nil {
+		if sliceType, ok := coreTypeOf(v.context.TypeInfo.TypeOf(call.Args[0])).(*types.Slice); ok {
+			elemType = types.TypeString(sliceType.Elem(), v.qualifier)
+		}
+	}
+	v.addAlternativeIssue(call, models.SeverityMedium, 0.8,
+		fmt.Sprintf("slices.%s on '%s' inside a loop - a map[%s]struct{} set answers it in O(1)", name, slice.Name, elemType),
+		"inefficient_data_structure.set", suggestions.Data{Var: slice.Name, Type: elemType},
+		"O(n) per test → O(1) with a set")
+}
+
+// checkMinMaxScan reports a nested range loop that keeps a running minimum or
+// maximum, recomputing it from scratch for every outer iteration where a heap
+// would hand out the next one in O(log n)
+func (v *dataStructureVisitor) checkMinMaxScan(rangeStmt *ast.RangeStmt) bool {
+	if !v.dataStructureConfig().DetectMinMaxScans || v.loopDepth < 2 || rangeStmt.Body == nil {
+		return false
+	}
+	element := rangeVarNames(rangeStmt)
+	if len(element) == 0 {
+		return false
+	}
+
+	for _, stmt := range rangeStmt.Body.List {
+		ifStmt, ok := stmt.(*ast.IfStmt)
+		if !ok {
+			continue
+		}
+		if !keepsRunningBest(ifStmt, element) {
+			continue
+		}
+
+		sliceName := "items"
+		if ident := rootIdent(rangeStmt.X); ident != nil {
+			sliceName = ident.Name
+		}
+		v.addAlternativeIssue(rangeStmt, models.SeverityMedium, 0.7,
+			fmt.Sprintf("Minimum or maximum of '%s' rescanned in a nested loop - consider a heap (container/heap)", sliceName),
+			"inefficient_data_structure.heap", suggestions.Data{Var: sliceName},
+			"O(n) per extraction → O(log n) with a heap")
+		return true
+	}
+	return false
+}
+
+// checkQueueShift reports x = x[n:] in a loop. The slice keeps its backing
+// array alive and append has to grow a new one once the front is used up, so
+// a ring buffer or deque serves a FIFO queue better.
+func (v *dataStructureVisitor) checkQueueShift(assign *ast.AssignStmt) {
+	if !v.dataStructureConfig().DetectQueueShifts || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return
+	}
+	queue, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return
+	}
+	slice, ok := assign.Rhs[0].(*ast.SliceExpr)
+	if !ok || slice.Low == nil || slice.High != nil || !isIdentNamed(slice.X, queue.Name) {
+		return
+	}
+
+	v.addAlternativeIssue(assign, models.SeverityLow, 0.6,
+		fmt.Sprintf("Queue '%s' is shifted with %s = %s[%s:] in a loop - consider a ring buffer or deque", queue.Name, queue.Name, queue.Name, types.ExprString(slice.Low)),
+		"inefficient_data_structure.deque", suggestions.Data{Var: queue.Name},
+		"Reuses one buffer instead of reallocating")
+}
+
+// checkSortedInsert reports an append followed by a sort of the same slice
+// in one loop body, which re-sorts the whole slice for every element where a
+// binary search and slices.Insert keep it sorted
+func (v *dataStructureVisitor) checkSortedInsert(body []ast.Stmt) {
+	if !v.dataStructureConfig().DetectSortedInserts {
+		return
+	}
+	appended := make(map[string]bool)
+	for _, stmt := range body {
+		if name, ok := appendTarget(stmt); ok {
+			appended[name] = true
+			continue
+		}
+		exprStmt, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+		call, ok := exprStmt.X.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			continue
+		}
+		pkg, name, ok := packageFunc(v.context, call)
+		if !ok || !sortFuncs[pkg][name] {
+			continue
+		}
+		slice := rootIdent(call.Args[0])
+		if slice == nil || !appended[slice.Name] {
+			continue
+		}
+
+		v.addAlternativeIssue(call, models.SeverityMedium, 0.8,
+			fmt.Sprintf("Slice '%s' is re-sorted after every append - insert at the binary search position with slices.Insert", slice.Name),
+			"inefficient_data_structure.sorted_insert", suggestions.Data{Var: slice.Name},
+			"O(n log n) per insert → O(n)")
+		delete(appended, slice.Name)
+	}
+}
+
+func (v *dataStructureVisitor) addAlternativeIssue(node ast.Node, severity models.Severity, confidence float64, message, suggestionID string, data suggestions.Data, impact string) {
+	position := v.fset.Position(node.Pos())
+	v.issues = append(v.issues, models.Issue{
+		Type:        models.IssueInefficinetDS,
+		Severity:    severity,
+		File:        v.filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Function:    v.currentFunc,
+		Message:     message,
+		Suggestion:  suggestions.Render(suggestionID, data),
+		CodeSnippet: position.String(),
+		Confidence:  confidence,
+		Impact:      impact,
+		FixEffort:   models.EffortSmall,
+	})
+}
+
+// typeName returns the type of expr for suggestions, or T without type info.
+// Types of the analyzed package are written unqualified.
+func (v *dataStructureVisitor) typeName(expr ast.Expr) string {
+	if v.context == nil || v.context.TypeInfo == nil {
+		return "T"
+	}
+	t := v.context.TypeInfo.TypeOf(expr)
+	if t == nil {
+		return "T"
+	}
+	return types.TypeString(t, v.qualifier)
+}
+
+func (v *dataStructureVisitor) qualifier(pkg *types.Package) string {
+	if pkg.Name() == v.pkgName {
+		return ""
+	}
+	return pkg.Name()
+}
+
+// coreTypeOf is coreType that tolerates a missing type
+func coreTypeOf(t types.Type) types.Type {
+	if t == nil {
+		return nil
+	}
+	return coreType(t)
+}
+
+// testsMembership reports whether body has an if comparing the element
+// variable itself for equality with something that doesn't depend on it
+func testsMembership(body *ast.BlockStmt, name string) bool {
+	element := map[string]bool{name: true}
+	for _, stmt := range body.List {
+		ifStmt, ok := stmt.(*ast.IfStmt)
+		if !ok {
+			continue
+		}
+		cond, ok := ifStmt.Cond.(*ast.BinaryExpr)
+		if !ok || cond.Op != token.EQL {
+			continue
+		}
+		if isIdentNamed(cond.X, name) && !mentions(cond.Y, element) ||
+			isIdentNamed(cond.Y, name) && !mentions(cond.X, element) {
+			return true
+		}
+	}
+	return false
+}
+
+// keepsRunningBest reports whether ifStmt compares an iteration variable in
+// element with <, >, <= or >= against a variable the if body then assigns, as
+// in if v < best { best = v }. Comparisons joined by && or || count.
+func keepsRunningBest(ifStmt *ast.IfStmt, element map[string]bool) bool {
+	found := false
+	ast.Inspect(ifStmt.Cond, func(n ast.Node) bool {
+		cond, ok := n.(*ast.BinaryExpr)
+		if !ok || found {
+			return !found
+		}
+		if cond.Op != token.LSS && cond.Op != token.GTR && cond.Op != token.LEQ && cond.Op != token.GEQ {
+			return true
+		}
+		var other ast.Expr
+		switch {
+		case mentions(cond.X, element) && !mentions(cond.Y, element):
+			other = cond.Y
+		case mentions(cond.Y, element) && !mentions(cond.X, element):
+			other = cond.X
+		default:
+			return false
+		}
+		ast.Inspect(other, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && assignsTo(ifStmt.Body, ident.Name) {
+				found = true
+			}
+			return !found
+		})
+		return false
+	})
+	return found
+}
+
+// appendTarget returns x for a statement x = append(x, ...)
+func appendTarget(stmt ast.Stmt) (string, bool) {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return "", false
+	}
+	target, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 || !isIdentNamed(call.Fun, "append") || !isIdentNamed(call.Args[0], target.Name) {
+		return "", false
+	}
+	return target.Name, true
+}
+
+// assignsTo reports whether node assigns to the variable name
+func assignsTo(node ast.Node, name string) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok {
+			for _, lhs := range assign.Lhs {
+				if ident := rootIdent(lhs); ident != nil && ident.Name == name {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,153 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+	"gophercheck/internal/workspace"
+	"strconv"
+	"strings"
+)
+
+// DependencyRulesDetector flags imports the dependency_rules config forbids,
+// e.g. internal/models importing internal/analyzer
+type DependencyRulesDetector struct {
+	config *config.Config
+	rules  []dependencyRule
+}
+
+var _ Detector = (*DependencyRulesDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "dependency_rules",
+		Category: "quality",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewDependencyRulesDetectorWithConfig(cfg) },
+	})
+}
+
+// dependencyRule is a config.DependencyRule with its patterns compiled
+type dependencyRule struct {
+	config.DependencyRule
+	from, allow, deny workspace.PackagePatterns
+}
+
+func NewDependencyRulesDetector() *DependencyRulesDetector {
+	return &DependencyRulesDetector{}
+}
+
+func NewDependencyRulesDetectorWithConfig(cfg *config.Config) *DependencyRulesDetector {
+	d := &DependencyRulesDetector{}
+	d.SetConfig(cfg)
+	return d
+}
+
+func (d *DependencyRulesDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+	d.rules = nil
+	if cfg == nil {
+		return
+	}
+	for _, rule := range cfg.Rules.Quality.DependencyRules.Rules {
+		d.rules = append(d.rules, dependencyRule{
+			DependencyRule: rule,
+			from:           workspace.CompilePackagePatterns([]string{rule.From}),
+			allow:          workspace.CompilePackagePatterns(rule.Allow),
+			deny:           workspace.CompilePackagePatterns(rule.Deny),
+		})
+	}
+}
+
+func (d *DependencyRulesDetector) Name() string {
+	return "Dependency Rules Detector"
+}
+
+func (d *DependencyRulesDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	if len(d.rules) == 0 || ctx == nil {
+		return nil
+	}
+
+	modulePath := ctx.ModulePaths[filename]
+	pkg := ctx.PackagePath(filename)
+	rule := d.ruleFor(pkg, modulePath)
+	if rule == nil {
+		return nil
+	}
+
+	var issues []models.Issue
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		relative, internal := relativeToModule(importPath, modulePath)
+
+		var violation string
+		switch {
+		case rule.deny.Match(relative) || rule.deny.Match(importPath):
+			violation = fmt.Sprintf("must not import %s", relative)
+		case internal && len(rule.allow) > 0 && !rule.allow.Match(relative) && !rule.allow.Match(importPath):
+			violation = fmt.Sprintf("may only import %s of its module, not %s", strings.Join(rule.Allow, ", "), relative)
+		default:
+			continue
+		}
+		issues = append(issues, d.createIssue(fset, spec, pkg, modulePath, rule, violation, filename))
+	}
+	return issues
+}
+
+// ruleFor returns the first rule whose From matches a package, or nil
+func (d *DependencyRulesDetector) ruleFor(pkg, modulePath string) *dependencyRule {
+	relative, _ := relativeToModule(pkg, modulePath)
+	for i := range d.rules {
+		if d.rules[i].from.Match(relative) || d.rules[i].from.Match(pkg) {
+			return &d.rules[i]
+		}
+	}
+	return nil
+}
+
+// relativeToModule returns an import path relative to the module root, and
+// whether it is in the module at all; the module's root package is "."
+func relativeToModule(importPath, modulePath string) (string, bool) {
+	if modulePath == "" {
+		return importPath, false
+	}
+	if importPath == modulePath {
+		return ".", true
+	}
+	if rest, ok := strings.CutPrefix(importPath, modulePath+"/"); ok {
+		return rest, true
+	}
+	return importPath, false
+}
+
+func (d *DependencyRulesDetector) createIssue(fset *token.FileSet, spec *ast.ImportSpec, pkg, modulePath string, rule *dependencyRule, violation, filename string) models.Issue {
+	position := fset.Position(spec.Pos())
+	name, _ := relativeToModule(pkg, modulePath)
+
+	message := fmt.Sprintf("Package %s %s (dependency rule for %s)", name, violation, rule.From)
+	if rule.Reason != "" {
+		message += ": " + rule.Reason
+	}
+
+	return models.Issue{
+		Type:        models.IssueDependencyRule,
+		Severity:    models.SeverityHigh,
+		File:        filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Message:     message,
+		Suggestion:  suggestions.Render("dependency_violation.invert", suggestions.Data{}),
+		Complexity:  fmt.Sprintf("Rule: %s", rule.From),
+		CodeSnippet: fmt.Sprintf("import %s", spec.Path.Value),
+		Confidence:  1.0, // Declared, not inferred
+		Impact:      "Keeps layers importing in one direction",
+		FixEffort:   models.EffortLarge,
+	}
+}
@@ -0,0 +1,278 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// ErrorfInLoopDetector finds errors built with fmt.Errorf or errors.New on
+// every loop iteration and then only used on the failure path, so the
+// formatting and allocation are wasted whenever the iteration succeeds
+type ErrorfInLoopDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*ErrorfInLoopDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "errorf_in_loop",
+		Category: "performance",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewErrorfInLoopDetectorWithConfig(cfg) },
+	})
+}
+
+func NewErrorfInLoopDetector() *ErrorfInLoopDetector {
+	return &ErrorfInLoopDetector{}
+}
+
+func NewErrorfInLoopDetectorWithConfig(cfg *config.Config) *ErrorfInLoopDetector {
+	return &ErrorfInLoopDetector{
+		config: cfg,
+	}
+}
+
+func (d *ErrorfInLoopDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *ErrorfInLoopDetector) Name() string {
+	return "Errorf In Loop Detector"
+}
+
+func (d *ErrorfInLoopDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &errorfInLoopVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		detector: d,
+		context:  ctx,
+		settings: d.settings(),
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+// settings returns the rule config, or the defaults without one
+func (d *ErrorfInLoopDetector) settings() config.ErrorfInLoopConfig {
+	if d.config != nil && d.config.Rules.Performance.ErrorfInLoop.Enabled {
+		return d.config.Rules.Performance.ErrorfInLoop
+	}
+	return config.DefaultConfig().Rules.Performance.ErrorfInLoop
+}
+
+type errorfInLoopVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	loops       []ast.Node // Enclosing loops, innermost last
+	detector    *ErrorfInLoopDetector
+	context     *context.AnalysisContext
+	settings    config.ErrorfInLoopConfig
+}
+
+func (v *errorfInLoopVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Name != nil {
+			v.currentFunc = n.Name.Name
+		}
+		return v
+
+	case *ast.ForStmt, *ast.RangeStmt:
+		if isBenchmarkTimingLoop(v.context, n) {
+			for _, stmt := range getLoopBody(n) {
+				ast.Walk(v, stmt)
+			}
+			return nil
+		}
+
+		v.loops = append(v.loops, n)
+		v.checkLoopBody(getLoopBody(n))
+		for _, stmt := range getLoopBody(n) {
+			ast.Walk(v, stmt)
+		}
+		v.loops = v.loops[:len(v.loops)-1]
+		return nil
+
+	default:
+		return v
+	}
+}
+
+// checkLoopBody looks at errors built by statements that run on every
+// iteration, i.e. directly in the loop body rather than inside a branch
+func (v *errorfInLoopVisitor) checkLoopBody(body []ast.Stmt) {
+	for i, stmt := range body {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			continue
+		}
+		call, ok := assign.Rhs[0].(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		funcName, ok := v.errorConstructor(call)
+		if !ok {
+			continue
+		}
+		target, ok := assign.Lhs[0].(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		if target.Name == "_" {
+			v.createIssue(call, funcName, "", "discarded")
+			continue
+		}
+		conditional, unconditional := v.countUses(varKey(v.context, target), body[i+1:])
+		if conditional > 0 && !unconditional {
+			v.createIssue(call, funcName, target.Name, "only used on the failure path")
+		}
+	}
+}
+
+// errorConstructor returns "fmt.Errorf" or "errors.New" for calls building
+// a new error
+func (v *errorfInLoopVisitor) errorConstructor(call *ast.CallExpr) (string, bool) {
+	pkg, name, ok := packageFunc(v.context, call)
+	if !ok {
+		return "", false
+	}
+	switch {
+	case pkg == "fmt" && name == "Errorf":
+		return "fmt.Errorf", true
+	case pkg == "errors" && name == "New" && v.settings.IncludeErrorsNew:
+		return "errors.New", true
+	}
+	return "", false
+}
+
+// countUses counts the uses of a variable in the statements after it is set:
+// those inside branches, and whether any run on every iteration. Comparing
+// against nil doesn't count, since a freshly built error is never nil.
+func (v *errorfInLoopVisitor) countUses(key any, stmts []ast.Stmt) (int, bool) {
+	conditional := 0
+	unconditional := false
+	for _, stmt := range stmts {
+		var always, branches []ast.Node
+		switch s := stmt.(type) {
+		case *ast.IfStmt:
+			always = []ast.Node{s.Init, s.Cond}
+			branches = []ast.Node{s.Body, s.Else}
+		case *ast.SwitchStmt:
+			always = []ast.Node{s.Init, s.Tag}
+			branches = []ast.Node{s.Body}
+		case *ast.TypeSwitchStmt:
+			always = []ast.Node{s.Init, s.Assign}
+			branches = []ast.Node{s.Body}
+		case *ast.SelectStmt:
+			branches = []ast.Node{s.Body}
+		case *ast.ForStmt, *ast.RangeStmt:
+			branches = []ast.Node{s} // May run zero times
+		default:
+			always = []ast.Node{s}
+		}
+
+		for _, node := range always {
+			if v.usesIn(node, key) > 0 {
+				unconditional = true
+			}
+		}
+		for _, node := range branches {
+			conditional += v.usesIn(node, key)
+		}
+	}
+	return conditional, unconditional
+}
+
+// usesIn counts the reads of a variable in node, skipping nil comparisons
+func (v *errorfInLoopVisitor) usesIn(node ast.Node, key any) int {
+	if node == nil {
+		return 0
+	}
+	uses := 0
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.BinaryExpr:
+			if (e.Op == token.EQL || e.Op == token.NEQ) && (isNilIdent(e.X) || isNilIdent(e.Y)) {
+				return false
+			}
+		case *ast.Ident:
+			if varKey(v.context, e) == key {
+				uses++
+			}
+		}
+		return true
+	})
+	return uses
+}
+
+// hasVerbs reports whether an Errorf call formats any arguments; without
+// them the message is constant and a sentinel error fits
+func hasVerbs(call *ast.CallExpr) bool {
+	if len(call.Args) == 0 {
+		return false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return true
+	}
+	format, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return true
+	}
+	return strings.Contains(strings.ReplaceAll(format, "%%", ""), "%")
+}
+
+func (v *errorfInLoopVisitor) createIssue(call *ast.CallExpr, funcName, name, usage string) {
+	position := v.fset.Position(call.Pos())
+
+	severity := models.SeverityMedium
+	if len(v.loops) >= 2 {
+		severity = models.SeverityHigh
+	} else if v.context != nil {
+		info, ok := v.context.LoopContext[v.loops[len(v.loops)-1]]
+		if ok && info.BoundType == context.BoundConstant && info.EstimatedMax > 0 && info.EstimatedMax <= 10 {
+			severity = models.SeverityLow
+		}
+	}
+
+	subject := "the error"
+	if name != "" {
+		subject = name
+	}
+	entry := "errorf_in_loop.lazy"
+	if funcName == "errors.New" || !hasVerbs(call) {
+		entry = "errorf_in_loop.sentinel"
+	}
+
+	issue := models.Issue{
+		Type:        models.IssueErrorfInLoop,
+		Severity:    severity,
+		File:        v.filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Function:    v.currentFunc,
+		Message:     fmt.Sprintf("%s builds an error on every loop iteration, but %s is %s", funcName, subject, usage),
+		Suggestion:  suggestions.Render(entry, suggestions.Data{Var: name, Type: funcName}),
+		Complexity:  "O(n) formatting and allocations for errors that are rarely returned",
+		CodeSnippet: position.String(),
+		Confidence:  0.8,
+		Impact:      "No allocation on iterations that succeed",
+		FixEffort:   models.EffortTrivial,
+	}
+
+	v.issues = append(v.issues, issue)
+}
@@ -0,0 +1,131 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+// FuncScope is a function declaration or function literal with a body
+type FuncScope struct {
+	Node ast.Node // *ast.FuncDecl or *ast.FuncLit
+	Body *ast.BlockStmt
+	Name string
+
+	// Receiver type of methods, and of literals inside them, without pointer
+	// or type parameters, e.g. "Server"
+	Receiver string
+}
+
+// IsLiteral reports whether the scope is a function literal
+func (s FuncScope) IsLiteral() bool {
+	_, ok := s.Node.(*ast.FuncLit)
+	return ok
+}
+
+// Describe names the scope for messages: Function 'Outer' or Function
+// literal 'Outer.func1'
+func (s FuncScope) Describe() string {
+	if s.IsLiteral() {
+		return fmt.Sprintf("Function literal '%s'", s.Name)
+	}
+	return fmt.Sprintf("Function '%s'", s.Name)
+}
+
+// FuncScopes lists the functions and function literals of file in source
+// order. Methods are named Type.Method, and literals the way the Go runtime
+// names closures in stack traces: the literals directly inside Outer are
+// Outer.func1, Outer.func2, ..., a literal inside Outer.func1 is
+// Outer.func1.1, and literals in package-level variables are glob..func1,
+// glob..func2, ...
+func FuncScopes(file *ast.File) []FuncScope {
+	var scopes []FuncScope
+	globals := 0
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Body == nil {
+				continue
+			}
+			name, receiver := DeclaredName(decl), ReceiverType(decl)
+			scopes = append(scopes, FuncScope{Node: decl, Body: decl.Body, Name: name, Receiver: receiver})
+			scopes = appendLiteralScopes(scopes, decl.Body, name+".func", receiver, new(int))
+		case *ast.GenDecl:
+			scopes = appendLiteralScopes(scopes, decl, "glob..func", "", &globals)
+		}
+	}
+	return scopes
+}
+
+// appendLiteralScopes appends the function literals directly inside node,
+// numbering them after prefix, and then recursively the literals nested in
+// each of them
+func appendLiteralScopes(scopes []FuncScope, node ast.Node, prefix, receiver string, count *int) []FuncScope {
+	ast.Inspect(node, func(n ast.Node) bool {
+		lit, ok := n.(*ast.FuncLit)
+		if !ok || n == node {
+			return true
+		}
+		*count++
+		name := fmt.Sprintf("%s%d", prefix, *count)
+		scopes = append(scopes, FuncScope{Node: lit, Body: lit.Body, Name: name, Receiver: receiver})
+		scopes = appendLiteralScopes(scopes, lit, name+".", receiver, new(int))
+		return false
+	})
+	return scopes
+}
+
+// DeclaredName names a function declaration the way issues do: Type.Method
+// for methods and the bare name for functions
+func DeclaredName(fn *ast.FuncDecl) string {
+	if receiver := ReceiverType(fn); receiver != "" {
+		return receiver + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// ReceiverType returns the name of a method's receiver type, without pointer
+// or type parameters, or "" for functions
+func ReceiverType(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	for {
+		switch typ := expr.(type) {
+		case *ast.StarExpr:
+			expr = typ.X
+		case *ast.ParenExpr:
+			expr = typ.X
+		case *ast.IndexExpr:
+			expr = typ.X
+		case *ast.IndexListExpr:
+			expr = typ.X
+		case *ast.Ident:
+			return typ.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// InnermostScope returns the innermost of scopes containing the line and
+// column of a position in fset, which must be sorted as FuncScopes returns
+// them (an enclosing scope before the scopes inside it)
+func InnermostScope(fset *token.FileSet, scopes []FuncScope, line, column int) (FuncScope, bool) {
+	var innermost FuncScope
+	found := false
+	for _, scope := range scopes {
+		start, end := fset.Position(scope.Node.Pos()), fset.Position(scope.Node.End())
+		if before(line, column, start) || !before(line, column, end) {
+			continue
+		}
+		innermost, found = scope, true
+	}
+	return innermost, found
+}
+
+// before reports whether line:column comes before position
+func before(line, column int, position token.Position) bool {
+	return line < position.Line || (line == position.Line && column < position.Column)
+}
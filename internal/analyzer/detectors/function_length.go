@@ -7,6 +7,8 @@ import (
 	"gophercheck/internal/config"
 	"gophercheck/internal/context"
 	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+	"strings"
 )
 
 // FunctionLengthDetector finds overly long functions that should be refactored
@@ -14,6 +16,17 @@ type FunctionLengthDetector struct {
 	config *config.Config
 }
 
+var _ Detector = (*FunctionLengthDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "function_length",
+		Category: "complexity",
+		Version:  "1.2.0",
+		New:      func(cfg *config.Config) Detector { return NewFunctionLengthDetectorWithConfig(cfg) },
+	})
+}
+
 func NewFunctionLengthDetector() *FunctionLengthDetector {
 	return &FunctionLengthDetector{}
 }
@@ -37,11 +50,14 @@ func (d *FunctionLengthDetector) Detect(file *ast.File, fset *token.FileSet, fil
 		fset:     fset,
 		filename: filename,
 		issues:   make([]models.Issue, 0),
+		comments: file.Comments,
 		detector: d,
 		context:  ctx,
 	}
 
-	ast.Walk(detector, file)
+	for _, scope := range FuncScopes(file) {
+		detector.analyzeFunctionLength(scope)
+	}
 	return detector.issues
 }
 
@@ -49,6 +65,7 @@ type functionLengthVisitor struct {
 	fset     *token.FileSet
 	filename string
 	issues   []models.Issue
+	comments []*ast.CommentGroup
 	detector *FunctionLengthDetector
 	context  *context.AnalysisContext
 }
@@ -60,54 +77,179 @@ const (
 	CriticalThreshold = 200 // Critical - definitely too long
 )
 
-func (v *functionLengthVisitor) Visit(node ast.Node) ast.Visitor {
-	if fn, ok := node.(*ast.FuncDecl); ok && fn.Body != nil {
-		v.analyzeFunctionLength(fn)
-	}
-	return v
-}
-
-func (v *functionLengthVisitor) analyzeFunctionLength(fn *ast.FuncDecl) {
-	startPos := v.fset.Position(fn.Pos())
-	endPos := v.fset.Position(fn.End())
+// analyzeFunctionLength measures one function or function literal. A
+// literal's lines (or statements) also count toward the function around it.
+func (v *functionLengthVisitor) analyzeFunctionLength(scope FuncScope) {
+	startPos := v.fset.Position(scope.Node.Pos())
+	endPos := v.fset.Position(scope.Node.End())
 
 	totalLines := endPos.Line - startPos.Line + 1
 
-	// Count actual lines of code (excluding braces, empty lines, etc.)
-	actualLOC := v.countActualLinesOfCode(fn.Body)
+	actualLOC := CountLines(v.fset, scope.Body, v.comments, v.lineCountOptions())
+	length := actualLOC
+	switch v.measure() {
+	case config.LengthMeasureSourceLines:
+		opts := v.lineCountOptions()
+		opts.Brackets = true
+		length = CountLines(v.fset, scope.Body, v.comments, opts)
+	case config.LengthMeasureStatements:
+		length = CountStatements(scope.Body)
+	}
 
-	funcName := v.getFunctionName(fn)
+	funcName := scope.Name
 
 	mediumThreshold := 50
 	if v.detector.config != nil && v.detector.config.Rules.Complexity.FunctionLength.Enabled {
 		mediumThreshold = v.detector.config.Rules.Complexity.FunctionLength.MediumThreshold
 	}
-	if actualLOC >= mediumThreshold {
-		severity := v.calculateSeverity(actualLOC)
-		v.createLengthIssue(fn, funcName, actualLOC, totalLines, severity)
+	if length >= mediumThreshold {
+		severity := v.calculateSeverity(length)
+		v.createLengthIssue(scope, funcName, length, actualLOC, totalLines, severity)
 	}
 }
 
-func (v *functionLengthVisitor) getFunctionName(fn *ast.FuncDecl) string {
-	if fn.Name != nil {
-		return fn.Name.Name
+// measure is the configured function length measure
+func (v *functionLengthVisitor) measure() string {
+	if v.detector.config == nil || v.detector.config.Rules.Complexity.FunctionLength.Measure == "" {
+		return config.LengthMeasureLines
 	}
-	return "anonymous"
+	return v.detector.config.Rules.Complexity.FunctionLength.Measure
 }
 
-func (v *functionLengthVisitor) countActualLinesOfCode(body *ast.BlockStmt) int {
-	linesSeen := make(map[int]bool)
+// unit names what the configured measure counts, e.g. "statements"
+func (v *functionLengthVisitor) unit() string {
+	switch v.measure() {
+	case config.LengthMeasureSourceLines:
+		return "source lines"
+	case config.LengthMeasureStatements:
+		return "statements"
+	default:
+		return "lines of code"
+	}
+}
 
+// CountStatements counts the statements of a function body, including those
+// of nested blocks and function literals. Blocks, labels, and case clauses
+// only group statements and don't count themselves.
+func CountStatements(body *ast.BlockStmt) int {
+	count := 0
 	ast.Inspect(body, func(n ast.Node) bool {
-		if n != nil {
-			pos := v.fset.Position(n.Pos())
-			linesSeen[pos.Line] = true
+		switch n.(type) {
+		case *ast.BlockStmt, *ast.LabeledStmt, *ast.CaseClause, *ast.CommClause, *ast.EmptyStmt:
+		case ast.Stmt:
+			count++
 		}
 		return true
 	})
+	return count
+}
+
+// LineCountOptions selects the lines a count includes besides code
+type LineCountOptions struct {
+	Comments   bool // Lines holding only comments
+	EmptyLines bool // Blank lines
+	Brackets   bool // Lines with only closing brackets
+}
 
-	// Return the count of unique lines
-	return len(linesSeen)
+// LinesOfCode counts the lines of a function body holding code, leaving out
+// blank lines, comments, and lines with only closing brackets
+func LinesOfCode(fset *token.FileSet, body *ast.BlockStmt) int {
+	return CountLines(fset, body, nil, LineCountOptions{})
+}
+
+// CountLines counts the lines of a function body from its opening to its
+// closing brace. A line holds code when a node starts on it or a multi-line
+// literal spans it. Comment lines are found in comments (usually the
+// file's) and, like blank lines and lines with only closing brackets, only
+// count when opts says so.
+func CountLines(fset *token.FileSet, body *ast.BlockStmt, comments []*ast.CommentGroup, opts LineCountOptions) int {
+	tokenFile := fset.File(body.Pos())
+	if tokenFile == nil {
+		return 0
+	}
+	first, last := tokenFile.Line(body.Lbrace), tokenFile.Line(body.Rbrace)
+
+	code := make(map[int]bool)
+	closing := make(map[int]bool)
+	markSpan := func(from, to token.Pos, lines map[int]bool) {
+		for line := tokenFile.Line(from); line <= tokenFile.Line(to); line++ {
+			lines[line] = true
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			return true
+		}
+		code[tokenFile.Line(n.Pos())] = true
+		switch n := n.(type) {
+		case *ast.BasicLit:
+			markSpan(n.Pos(), n.End(), code) // Raw strings may span lines
+		case *ast.BlockStmt:
+			closing[tokenFile.Line(n.Rbrace)] = true
+		case *ast.CompositeLit:
+			closing[tokenFile.Line(n.Rbrace)] = true
+		case *ast.CallExpr:
+			closing[tokenFile.Line(n.Rparen)] = true
+		case *ast.ParenExpr:
+			closing[tokenFile.Line(n.Rparen)] = true
+		case *ast.TypeAssertExpr:
+			closing[tokenFile.Line(n.Rparen)] = true
+		case *ast.IndexExpr:
+			closing[tokenFile.Line(n.Rbrack)] = true
+		case *ast.IndexListExpr:
+			closing[tokenFile.Line(n.Rbrack)] = true
+		case *ast.SliceExpr:
+			closing[tokenFile.Line(n.Rbrack)] = true
+		case *ast.FieldList:
+			if n.Closing.IsValid() {
+				closing[tokenFile.Line(n.Closing)] = true
+			}
+		}
+		return true
+	})
+
+	commentLines := make(map[int]bool)
+	for _, group := range comments {
+		if group.End() < body.Lbrace || group.Pos() > body.Rbrace {
+			continue
+		}
+		for _, comment := range group.List {
+			markSpan(comment.Pos(), comment.End(), commentLines)
+		}
+	}
+
+	count := 0
+	for line := first; line <= last; line++ {
+		switch {
+		case code[line]:
+			count++
+		case commentLines[line]:
+			if opts.Comments {
+				count++
+			}
+		case closing[line]:
+			if opts.Brackets {
+				count++
+			}
+		default:
+			if opts.EmptyLines {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// lineCountOptions reads which lines besides code count toward the length
+func (v *functionLengthVisitor) lineCountOptions() LineCountOptions {
+	if v.detector.config == nil {
+		return LineCountOptions{}
+	}
+	lengthConfig := v.detector.config.Rules.Complexity.FunctionLength
+	return LineCountOptions{
+		Comments:   lengthConfig.CountComments,
+		EmptyLines: lengthConfig.CountEmptyLines,
+	}
 }
 
 func (v *functionLengthVisitor) calculateSeverity(loc int) models.Severity {
@@ -133,8 +275,10 @@ func (v *functionLengthVisitor) calculateSeverity(loc int) models.Severity {
 	}
 }
 
-func (v *functionLengthVisitor) createLengthIssue(fn *ast.FuncDecl, funcName string, actualLOC, totalLines int, severity models.Severity) {
-	position := v.fset.Position(fn.Pos())
+// createLengthIssue reports a function whose length, in the configured
+// measure, reached a threshold; actualLOC is its lines of code either way
+func (v *functionLengthVisitor) createLengthIssue(scope FuncScope, funcName string, length, actualLOC, totalLines int, severity models.Severity) {
+	position := v.fset.Position(scope.Node.Pos())
 
 	issue := models.Issue{
 		Type:        models.IssueFunctionLength,
@@ -143,77 +287,40 @@ func (v *functionLengthVisitor) createLengthIssue(fn *ast.FuncDecl, funcName str
 		Line:        position.Line,
 		Column:      position.Column,
 		Function:    funcName,
-		Message:     v.generateMessage(funcName, actualLOC, totalLines),
-		Suggestion:  v.generateSuggestion(severity, actualLOC),
-		Complexity:  fmt.Sprintf("Function length: %d lines", actualLOC),
+		Message:     v.generateMessage(scope, length, totalLines),
+		Suggestion:  nestingSummary(v.fset, scope.Body) + v.generateSuggestion(severity, actualLOC),
+		Complexity:  fmt.Sprintf("Function length: %d %s", length, strings.TrimSuffix(v.unit(), " of code")),
 		CodeSnippet: position.String(),
+		Confidence:  1.0, // Computed directly from the AST
+		Impact:      "Easier to read, test, and change",
+		FixEffort:   v.estimateFixEffort(severity),
 	}
 
 	v.issues = append(v.issues, issue)
 }
 
-func (v *functionLengthVisitor) generateMessage(funcName string, actualLOC, totalLines int) string {
-	return fmt.Sprintf("Function '%s' is too long (%d lines of code, %d total lines) - consider breaking into smaller functions",
-		funcName, actualLOC, totalLines)
+func (v *functionLengthVisitor) estimateFixEffort(severity models.Severity) models.FixEffort {
+	if severity == models.SeverityMedium {
+		return models.EffortSmall
+	}
+	return models.EffortLarge
 }
 
-func (v *functionLengthVisitor) generateSuggestion(severity models.Severity, loc int) string {
-	baseAdvice := `Long functions are harder to understand, test, and maintain. Consider refactoring using these techniques:
-
-1. **Extract Method**: Move logical blocks into separate functions
-2. **Single Responsibility**: Ensure function does only one thing  
-3. **Reduce Nesting**: Use early returns to flatten conditional logic
-4. **Group Related Code**: Extract helper functions for repeated patterns`
+func (v *functionLengthVisitor) generateMessage(scope FuncScope, length, totalLines int) string {
+	return fmt.Sprintf("%s is too long (%d %s, %d total lines) - consider breaking into smaller functions",
+		scope.Describe(), length, v.unit(), totalLines)
+}
 
+func (v *functionLengthVisitor) generateSuggestion(severity models.Severity, loc int) string {
+	data := suggestions.Data{Lines: loc}
 	switch severity {
 	case models.SeverityMedium:
-		return baseAdvice + `
-
-Target: Break into 2-3 smaller functions of ~20-30 lines each.
-
-Example refactoring:
-// Instead of one 60-line function:
-func ProcessData() { /* 60 lines */ }
-
-// Break into:
-func ProcessData() {
-    data := loadData()
-    validated := validateData(data)
-    return transformData(validated)
-}
-func loadData() { /* 15 lines */ }
-func validateData() { /* 20 lines */ }
-func transformData() { /* 15 lines */ }`
-
+		return suggestions.Render("function_length.medium", data)
 	case models.SeverityHigh:
-		return baseAdvice + fmt.Sprintf(`
-
-PRIORITY: This %d-line function significantly exceeds recommended limits.
-
-Refactoring strategy:
-1. Identify 3-5 main logical sections
-2. Extract each section into a separate function
-3. Use meaningful function names that describe intent
-4. Consider if this indicates a class/struct is needed
-
-Target: Break into 4-6 functions of ~15-25 lines each.`, loc)
-
+		return suggestions.Render("function_length.high", data)
 	case models.SeverityCritical:
-		return baseAdvice + fmt.Sprintf(`
-
-🚨 CRITICAL: This %d-line function is extremely difficult to maintain!
-
-Immediate action required:
-1. **Stop adding features** to this function
-2. **Extract at least 5-8 smaller functions** immediately  
-3. **Consider architectural changes** - may need multiple files/packages
-4. **Add comprehensive tests** before refactoring
-5. **Document the refactoring plan** before starting
-
-This function likely violates Single Responsibility Principle.
-Consider if it needs to be split into multiple types/interfaces.`, loc)
-
+		return suggestions.Render("function_length.critical", data)
 	default:
-		return baseAdvice
+		return suggestions.Render("function_length.base", data)
 	}
 }
@@ -0,0 +1,129 @@
+package detectors
+
+import (
+	"go/ast"
+	"go/types"
+
+	"gophercheck/internal/context"
+)
+
+// Uninstantiate strips explicit type arguments from a reference to a generic
+// function or type: Map[int, string] becomes Map and List[T] becomes List.
+// Without type info a single index can't be told apart from indexing a value,
+// so callers only use it where a function or type is expected, such as the
+// Fun of a call.
+func Uninstantiate(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		return e.X
+	case *ast.IndexListExpr:
+		return e.X
+	}
+	return expr
+}
+
+// isSliceTypeExpr reports whether the type expression expr denotes a slice:
+// []T written out or, with type info, a named slice type, an instantiated
+// generic slice type, or a type parameter constrained to slices
+func isSliceTypeExpr(ctx *context.AnalysisContext, expr ast.Expr) bool {
+	if arrayType, ok := expr.(*ast.ArrayType); ok {
+		return arrayType.Len == nil
+	}
+	_, ok := typeExprCore(ctx, expr).(*types.Slice)
+	return ok
+}
+
+// isMapTypeExpr is isSliceTypeExpr for maps
+func isMapTypeExpr(ctx *context.AnalysisContext, expr ast.Expr) bool {
+	if _, ok := expr.(*ast.MapType); ok {
+		return true
+	}
+	_, ok := typeExprCore(ctx, expr).(*types.Map)
+	return ok
+}
+
+// typeExprCore returns the core type of the type expression expr, or nil
+// without type info
+func typeExprCore(ctx *context.AnalysisContext, expr ast.Expr) types.Type {
+	if ctx == nil || ctx.TypeInfo == nil {
+		return nil
+	}
+	t := ctx.TypeInfo.TypeOf(expr)
+	if t == nil {
+		return nil
+	}
+	return coreType(t)
+}
+
+// coreType returns the underlying type of t. For a type parameter it is the
+// underlying type shared by every term of the constraint, e.g. []E for
+// S ~[]E, and nil when the terms disagree or the constraint has none.
+func coreType(t types.Type) types.Type {
+	param, ok := t.(*types.TypeParam)
+	if !ok {
+		return t.Underlying()
+	}
+	iface, ok := param.Constraint().Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+
+	var core types.Type
+	for _, term := range constraintTerms(iface) {
+		under := term.Underlying()
+		if param, ok := under.(*types.TypeParam); ok {
+			under = coreType(param)
+		}
+		if under == nil || (core != nil && !types.Identical(core, under)) {
+			return nil
+		}
+		core = under
+	}
+	return core
+}
+
+// constraintTerms lists the types of the union terms embedded in a
+// constraint interface, following embedded constraint interfaces
+func constraintTerms(iface *types.Interface) []types.Type {
+	var terms []types.Type
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		switch embedded := iface.EmbeddedType(i).(type) {
+		case *types.Union:
+			for j := 0; j < embedded.Len(); j++ {
+				terms = append(terms, embedded.Term(j).Type())
+			}
+		default:
+			if inner, ok := embedded.Underlying().(*types.Interface); ok {
+				terms = append(terms, constraintTerms(inner)...)
+			} else {
+				terms = append(terms, embedded)
+			}
+		}
+	}
+	return terms
+}
+
+// hasTypeParams reports whether t mentions a type parameter anywhere, so its
+// size or layout depends on the instantiation
+func hasTypeParams(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Named:
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			if hasTypeParams(t.TypeArgs().At(i)) {
+				return true
+			}
+		}
+		return t.TypeParams().Len() > 0 && t.TypeArgs().Len() == 0
+	case *types.Array:
+		return hasTypeParams(t.Elem())
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if hasTypeParams(t.Field(i).Type()) {
+				return true
+			}
+		}
+	}
+	return false
+}
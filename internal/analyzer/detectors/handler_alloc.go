@@ -0,0 +1,289 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// regexpCompilers are the regexp functions that compile a pattern
+var regexpCompilers = map[string]bool{
+	"Compile": true, "MustCompile": true, "CompilePOSIX": true, "MustCompilePOSIX": true,
+}
+
+// templateParsers are the text/template and html/template functions and
+// methods that parse template source
+var templateParsers = map[string]bool{
+	"Parse": true, "ParseFiles": true, "ParseGlob": true, "ParseFS": true,
+}
+
+// HandlerAllocDetector finds setup work that HTTP handlers redo on every
+// request: compiling constant regexps, parsing templates, and building
+// constant lookup maps
+type HandlerAllocDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*HandlerAllocDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "handler_alloc",
+		Category: "performance",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewHandlerAllocDetectorWithConfig(cfg) },
+	})
+}
+
+func NewHandlerAllocDetector() *HandlerAllocDetector {
+	return &HandlerAllocDetector{}
+}
+
+func NewHandlerAllocDetectorWithConfig(cfg *config.Config) *HandlerAllocDetector {
+	return &HandlerAllocDetector{
+		config: cfg,
+	}
+}
+
+func (d *HandlerAllocDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *HandlerAllocDetector) Name() string {
+	return "Handler Allocation Detector"
+}
+
+func (d *HandlerAllocDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &handlerAllocVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		context:  ctx,
+		settings: d.settings(),
+	}
+
+	// Handlers may be declared functions or literals passed to a router
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			detector.currentFunc = fn.Name.Name
+			if fn.Body != nil {
+				detector.checkHandler(fn.Type, fn.Body)
+			}
+		case *ast.FuncLit:
+			detector.checkHandler(fn.Type, fn.Body)
+		}
+		return true
+	})
+	return detector.issues
+}
+
+// settings returns the rule config, or the defaults without one
+func (d *HandlerAllocDetector) settings() config.HandlerAllocConfig {
+	if d.config != nil && d.config.Rules.Performance.HandlerAlloc.Enabled {
+		return d.config.Rules.Performance.HandlerAlloc
+	}
+	return config.DefaultConfig().Rules.Performance.HandlerAlloc
+}
+
+type handlerAllocVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	framework   string // Framework of the handler being checked
+	context     *context.AnalysisContext
+	settings    config.HandlerAllocConfig
+}
+
+// handlerShape returns the framework of a handler signature, or "" when the
+// function isn't a handler this config checks
+func (v *handlerAllocVisitor) handlerShape(fnType *ast.FuncType) string {
+	framework := handlerFramework(v.context, fnType)
+	if framework != "net/http" && !v.settings.IncludeFrameworks {
+		return ""
+	}
+	return framework
+}
+
+func (v *handlerAllocVisitor) checkHandler(fnType *ast.FuncType, body *ast.BlockStmt) {
+	framework := v.handlerShape(fnType)
+	if framework == "" {
+		return
+	}
+	v.framework = framework
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// Nested handlers are checked on their own
+			return v.handlerShape(node.Type) == ""
+		case *ast.CallExpr:
+			if v.settings.DetectRegexp {
+				v.checkRegexp(node)
+			}
+			if v.settings.DetectTemplates {
+				v.checkTemplate(node)
+			}
+		case *ast.CompositeLit:
+			if v.settings.DetectMapLiterals {
+				v.checkMapLiteral(node)
+			}
+		}
+		return true
+	})
+}
+
+// checkRegexp reports regexp compiles of a constant pattern
+func (v *handlerAllocVisitor) checkRegexp(call *ast.CallExpr) {
+	pkg, name, ok := packageFunc(v.context, call)
+	if !ok || pkg != "regexp" || !regexpCompilers[name] || len(call.Args) != 1 {
+		return
+	}
+	if !v.isConstant(call.Args[0]) {
+		return // Patterns built from the request have to be compiled per request
+	}
+
+	v.addIssue(call, models.Issue{
+		Severity:   models.SeverityHigh,
+		Message:    fmt.Sprintf("regexp.%s in %s handler compiles the same pattern on every request", name, v.framework),
+		Suggestion: suggestions.Render("handler_allocation.regexp", suggestions.Data{Var: types.ExprString(call.Args[0]), Type: name}),
+		Complexity: "O(pattern length) compile work and allocations per request",
+		Confidence: 0.9,
+		Impact:     "Compiles once at startup instead of per request",
+		FixEffort:  models.EffortTrivial,
+	})
+}
+
+// checkTemplate reports template parsing: template.ParseFiles(...) and
+// template.New(...).Parse(...)
+func (v *handlerAllocVisitor) checkTemplate(call *ast.CallExpr) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !templateParsers[sel.Sel.Name] {
+		return
+	}
+	if pkg, _, ok := packageFunc(v.context, call); ok {
+		if !isTemplatePackage(pkg) {
+			return
+		}
+	} else if !v.isTemplateValue(sel.X) {
+		return
+	}
+
+	v.addIssue(call, models.Issue{
+		Severity:   models.SeverityHigh,
+		Message:    fmt.Sprintf("Template %s in %s handler parses the template on every request", sel.Sel.Name, v.framework),
+		Suggestion: suggestions.Render("handler_allocation.template", suggestions.Data{Type: sel.Sel.Name}),
+		Complexity: "Full template parse and allocations per request",
+		Confidence: 0.85, // Templates reloaded deliberately in development builds look the same
+		Impact:     "Parses once at startup instead of per request",
+		FixEffort:  models.EffortSmall,
+	})
+}
+
+// isTemplateValue reports whether expr is a *template.Template, judged
+// without type info by the call it comes from, e.g. template.New("x")
+func (v *handlerAllocVisitor) isTemplateValue(expr ast.Expr) bool {
+	if v.context != nil && v.context.TypeInfo != nil {
+		if t := v.context.TypeInfo.TypeOf(expr); t != nil {
+			return isTemplatePackage(receiverPackage(t))
+		}
+	}
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		if pkg, _, ok := packageFunc(v.context, call); ok {
+			return isTemplatePackage(pkg)
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		expr = sel.X // template.New("x").Funcs(m).Parse(...)
+	}
+}
+
+func isTemplatePackage(pkg string) bool {
+	return pkg == "text/template" || pkg == "html/template" || pkg == "template"
+}
+
+// checkMapLiteral reports map literals whose keys and values are all
+// constants, which are the same on every request
+func (v *handlerAllocVisitor) checkMapLiteral(lit *ast.CompositeLit) {
+	if !v.isMapLiteral(lit) || len(lit.Elts) < v.settings.MinMapEntries {
+		return
+	}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok || !v.isConstant(kv.Key) || !v.isConstant(kv.Value) {
+			return
+		}
+	}
+
+	v.addIssue(lit, models.Issue{
+		Severity:   models.SeverityMedium,
+		Message:    fmt.Sprintf("Map literal with %d constant entries in %s handler is rebuilt on every request", len(lit.Elts), v.framework),
+		Suggestion: suggestions.Render("handler_allocation.map", suggestions.Data{Type: types.ExprString(lit.Type)}),
+		Complexity: fmt.Sprintf("%d map inserts and allocations per request", len(lit.Elts)),
+		Confidence: 0.8, // The handler may modify its copy
+		Impact:     "Builds the table once instead of per request",
+		FixEffort:  models.EffortTrivial,
+	})
+}
+
+func (v *handlerAllocVisitor) isMapLiteral(lit *ast.CompositeLit) bool {
+	if _, ok := lit.Type.(*ast.MapType); ok {
+		return true
+	}
+	if lit.Type == nil || v.context == nil || v.context.TypeInfo == nil {
+		return false
+	}
+	t := v.context.TypeInfo.TypeOf(lit)
+	if t == nil {
+		return false
+	}
+	_, ok := t.Underlying().(*types.Map)
+	return ok
+}
+
+// isConstant reports literals and named constants
+func (v *handlerAllocVisitor) isConstant(expr ast.Expr) bool {
+	if v.context != nil && v.context.TypeInfo != nil {
+		if tv, ok := v.context.TypeInfo.Types[expr]; ok {
+			return tv.Value != nil
+		}
+	}
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return true
+	case *ast.UnaryExpr:
+		return e.Op == token.SUB && v.isConstant(e.X)
+	case *ast.BinaryExpr:
+		return v.isConstant(e.X) && v.isConstant(e.Y) // Concatenated pattern pieces
+	case *ast.ParenExpr:
+		return v.isConstant(e.X)
+	}
+	return false
+}
+
+// addIssue fills in the type and location of issue and records it
+func (v *handlerAllocVisitor) addIssue(node ast.Node, issue models.Issue) {
+	position := v.fset.Position(node.Pos())
+
+	issue.Type = models.IssueHandlerAlloc
+	issue.File = v.filename
+	issue.Line = position.Line
+	issue.Column = position.Column
+	issue.Function = v.currentFunc
+	issue.CodeSnippet = position.String()
+
+	v.issues = append(v.issues, issue)
+}
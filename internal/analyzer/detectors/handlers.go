@@ -0,0 +1,93 @@
+package detectors
+
+import (
+	"go/ast"
+	"go/types"
+
+	"gophercheck/internal/context"
+)
+
+// frameworkHandlers are web framework handler shapes taking a single context
+// parameter, e.g. func(c *gin.Context)
+var frameworkHandlers = []struct {
+	framework string
+	pkgPath   string
+	typeName  string
+	pointer   bool
+}{
+	{"gin", "github.com/gin-gonic/gin", "Context", true},
+	{"echo", "github.com/labstack/echo/v4", "Context", false},
+	{"echo", "github.com/labstack/echo", "Context", false},
+	{"fiber", "github.com/gofiber/fiber/v2", "Ctx", true},
+	{"fiber", "github.com/gofiber/fiber/v3", "Ctx", false},
+}
+
+// isHTTPHandler reports whether a function has the net/http handler shape or
+// a framework handler shape
+func isHTTPHandler(ctx *context.AnalysisContext, fnType *ast.FuncType) bool {
+	return handlerFramework(ctx, fnType) != ""
+}
+
+// handlerFramework returns "net/http" for func(http.ResponseWriter,
+// *http.Request), including ServeHTTP methods, the framework name for
+// framework handler shapes, and "" otherwise
+func handlerFramework(ctx *context.AnalysisContext, fnType *ast.FuncType) string {
+	if fnType.Params == nil {
+		return ""
+	}
+
+	var params []ast.Expr
+	for _, field := range fnType.Params.List {
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for range count {
+			params = append(params, field.Type)
+		}
+	}
+
+	switch len(params) {
+	case 1:
+		for _, shape := range frameworkHandlers {
+			param := params[0]
+			if shape.pointer {
+				star, ok := param.(*ast.StarExpr)
+				if !ok {
+					continue
+				}
+				param = star.X
+			}
+			if isQualifiedType(ctx, param, shape.pkgPath, shape.typeName) {
+				return shape.framework
+			}
+		}
+	case 2:
+		request, ok := params[1].(*ast.StarExpr)
+		if ok && isQualifiedType(ctx, params[0], "net/http", "ResponseWriter") &&
+			isQualifiedType(ctx, request.X, "net/http", "Request") {
+			return "net/http"
+		}
+	}
+	return ""
+}
+
+// isQualifiedType reports whether a type expression names pkgPath.name. Type
+// info resolves renamed imports; without it the package name is assumed to
+// match the import path.
+func isQualifiedType(ctx *context.AnalysisContext, expr ast.Expr, pkgPath, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	pkgIdent, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	if ctx != nil && ctx.TypeInfo != nil {
+		if pkgName, ok := ctx.TypeInfo.Uses[pkgIdent].(*types.PkgName); ok {
+			return pkgName.Imported().Path() == pkgPath
+		}
+	}
+	return pkgIdent.Name == importName(pkgPath)
+}
@@ -0,0 +1,368 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// IfChainDetector finds if/else-if chains comparing one value against
+// constants, which read better as a switch. When every branch returns or
+// assigns a constant, a lookup map replaces the chain and is generated in
+// the suggestion.
+type IfChainDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*IfChainDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "if_chain",
+		Category: "complexity",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewIfChainDetectorWithConfig(cfg) },
+	})
+}
+
+func NewIfChainDetector() *IfChainDetector {
+	return &IfChainDetector{}
+}
+
+func NewIfChainDetectorWithConfig(cfg *config.Config) *IfChainDetector {
+	return &IfChainDetector{
+		config: cfg,
+	}
+}
+
+func (d *IfChainDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *IfChainDetector) Name() string {
+	return "If Chain Detector"
+}
+
+func (d *IfChainDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &ifChainVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		detector: d,
+		context:  ctx,
+		settings: d.settings(),
+		pkgName:  file.Name.Name,
+		elseIfs:  make(map[*ast.IfStmt]bool),
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+// settings returns the rule config, or the defaults without one
+func (d *IfChainDetector) settings() config.IfChainConfig {
+	if d.config != nil && d.config.Rules.Complexity.IfChain.Enabled {
+		return d.config.Rules.Complexity.IfChain
+	}
+	return config.DefaultConfig().Rules.Complexity.IfChain
+}
+
+// ifChain is an if/else-if chain comparing subject against constants
+type ifChain struct {
+	subject  ast.Expr
+	cases    [][]ast.Expr     // Constants each branch compares against, in source order
+	bodies   []*ast.BlockStmt // Each branch's body
+	fallback *ast.BlockStmt   // The final else, if any
+}
+
+type ifChainVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	pkgName     string
+	detector    *IfChainDetector
+	context     *context.AnalysisContext
+	settings    config.IfChainConfig
+	elseIfs     map[*ast.IfStmt]bool // Ifs continuing a chain already looked at
+}
+
+func (v *ifChainVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		v.currentFunc = DeclaredName(n)
+	case *ast.IfStmt:
+		if v.elseIfs[n] {
+			return v
+		}
+		for next, ok := n.Else.(*ast.IfStmt); ok; next, ok = next.Else.(*ast.IfStmt) {
+			v.elseIfs[next] = true
+		}
+		if chain, ok := v.chainOf(n); ok && len(chain.cases) >= v.settings.MinBranches {
+			v.createIssue(n, chain)
+		}
+	}
+	return v
+}
+
+// chainOf matches an if statement whose every branch compares the same
+// subject with == against constants (several joined by ||), with no init
+// statements and no constant compared twice
+func (v *ifChainVisitor) chainOf(head *ast.IfStmt) (ifChain, bool) {
+	var chain ifChain
+	seen := make(map[string]bool)
+	for stmt := head; ; {
+		if stmt.Init != nil {
+			return ifChain{}, false
+		}
+		subject, constants, ok := v.comparisons(stmt.Cond)
+		if !ok || (chain.subject != nil && types.ExprString(subject) != types.ExprString(chain.subject)) {
+			return ifChain{}, false
+		}
+		for _, constant := range constants {
+			key := v.constantKey(constant)
+			if seen[key] {
+				return ifChain{}, false // A switch rejects duplicate cases
+			}
+			seen[key] = true
+		}
+		chain.subject = subject
+		chain.cases = append(chain.cases, constants)
+		chain.bodies = append(chain.bodies, stmt.Body)
+
+		switch next := stmt.Else.(type) {
+		case *ast.IfStmt:
+			stmt = next
+		case *ast.BlockStmt:
+			chain.fallback = next
+			return chain, true
+		default:
+			return chain, true
+		}
+	}
+}
+
+// comparisons matches x == c, c == x, and disjunctions of them on the same
+// x, returning x and the constants. x must be a variable or field, which
+// evaluates the same however often it is compared.
+func (v *ifChainVisitor) comparisons(cond ast.Expr) (ast.Expr, []ast.Expr, bool) {
+	binary, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !ok {
+		return nil, nil, false
+	}
+	switch binary.Op {
+	case token.LOR:
+		left, leftConstants, ok := v.comparisons(binary.X)
+		if !ok {
+			return nil, nil, false
+		}
+		right, rightConstants, ok := v.comparisons(binary.Y)
+		if !ok || types.ExprString(left) != types.ExprString(right) {
+			return nil, nil, false
+		}
+		return left, append(leftConstants, rightConstants...), true
+	case token.EQL:
+		subject, constant := binary.X, binary.Y
+		if v.isConstant(subject) {
+			subject, constant = constant, subject
+		}
+		if !v.isConstant(constant) || v.isConstant(subject) || !isPlainVariable(subject) {
+			return nil, nil, false
+		}
+		return subject, []ast.Expr{constant}, true
+	}
+	return nil, nil, false
+}
+
+// isPlainVariable matches x and x.f.g, which have no side effects
+func isPlainVariable(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name != "nil" && e.Name != "true" && e.Name != "false"
+	case *ast.SelectorExpr:
+		return isPlainVariable(e.X)
+	}
+	return false
+}
+
+// isConstant reports whether expr is a constant: known from type info, or
+// without it a literal
+func (v *ifChainVisitor) isConstant(expr ast.Expr) bool {
+	if v.context != nil && v.context.TypeInfo != nil {
+		if tv, ok := v.context.TypeInfo.Types[expr]; ok {
+			return tv.Value != nil
+		}
+	}
+	_, ok := expr.(*ast.BasicLit)
+	return ok
+}
+
+// constantKey identifies a constant's value, so the same value written two
+// ways counts as a duplicate when type info is available
+func (v *ifChainVisitor) constantKey(expr ast.Expr) string {
+	if v.context != nil && v.context.TypeInfo != nil {
+		if tv, ok := v.context.TypeInfo.Types[expr]; ok && tv.Value != nil {
+			return tv.Value.ExactString()
+		}
+	}
+	return types.ExprString(expr)
+}
+
+// branchResult matches a branch body that is a single return of one
+// constant, or a single assignment of a constant, returning the constant
+// and the assigned variable ("" for a return)
+func (v *ifChainVisitor) branchResult(body *ast.BlockStmt) (ast.Expr, string, bool) {
+	if body == nil || len(body.List) != 1 {
+		return nil, "", false
+	}
+	switch stmt := body.List[0].(type) {
+	case *ast.ReturnStmt:
+		if len(stmt.Results) == 1 && v.isConstant(stmt.Results[0]) {
+			return stmt.Results[0], "", true
+		}
+	case *ast.AssignStmt:
+		if stmt.Tok == token.ASSIGN && len(stmt.Lhs) == 1 && len(stmt.Rhs) == 1 &&
+			isPlainVariable(stmt.Lhs[0]) && v.isConstant(stmt.Rhs[0]) {
+			return stmt.Rhs[0], types.ExprString(stmt.Lhs[0]), true
+		}
+	}
+	return nil, "", false
+}
+
+// lookupTable generates a map replacing the chain when every branch returns,
+// or every branch assigns the same variable, a constant. It returns the table
+// declaration and the code looking the subject up.
+func (v *ifChainVisitor) lookupTable(chain ifChain) (string, string, bool) {
+	values := make([]ast.Expr, len(chain.bodies))
+	target := ""
+	for i, body := range chain.bodies {
+		value, assigned, ok := v.branchResult(body)
+		if !ok || (i > 0 && assigned != target) {
+			return "", "", false
+		}
+		values[i], target = value, assigned
+	}
+	var fallback ast.Expr
+	if chain.fallback != nil {
+		value, assigned, ok := v.branchResult(chain.fallback)
+		if !ok || assigned != target {
+			return "", "", false
+		}
+		fallback = value
+	}
+
+	subject := types.ExprString(chain.subject)
+	base := "value"
+	if ident := rootIdent(chain.subject); ident != nil {
+		base = ident.Name
+	}
+	if selector, ok := chain.subject.(*ast.SelectorExpr); ok {
+		base = selector.Sel.Name
+	}
+	if v.context != nil && v.context.TypeInfo != nil {
+		if named, ok := v.context.TypeInfo.TypeOf(chain.subject).(*types.Named); ok {
+			base = named.Obj().Name()
+		}
+	}
+	table := lowerFirst(base) + "Values"
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "var %s = map[%s]%s{\n", table, v.typeString(chain.subject), v.typeString(values[0]))
+	for i, constants := range chain.cases {
+		for _, constant := range constants {
+			fmt.Fprintf(&b, "    %s: %s,\n", types.ExprString(constant), types.ExprString(values[i]))
+		}
+	}
+	b.WriteString("}")
+
+	var lookup string
+	switch {
+	case target == "" && fallback != nil:
+		lookup = fmt.Sprintf("if value, ok := %s[%s]; ok {\n    return value\n}\nreturn %s", table, subject, types.ExprString(fallback))
+	case target == "":
+		lookup = fmt.Sprintf("if value, ok := %s[%s]; ok {\n    return value\n}", table, subject)
+	case fallback != nil:
+		lookup = fmt.Sprintf("if value, ok := %s[%s]; ok {\n    %s = value\n} else {\n    %s = %s\n}", table, subject, target, target, types.ExprString(fallback))
+	default:
+		lookup = fmt.Sprintf("if value, ok := %s[%s]; ok {\n    %s = value\n}", table, subject, target)
+	}
+	return b.String(), lookup, true
+}
+
+// switchStatement generates the switch replacing the chain, with each
+// branch's body referred to by its line
+func (v *ifChainVisitor) switchStatement(chain ifChain) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "switch %s {\n", types.ExprString(chain.subject))
+	for i, constants := range chain.cases {
+		names := make([]string, len(constants))
+		for j, constant := range constants {
+			names[j] = types.ExprString(constant)
+		}
+		fmt.Fprintf(&b, "case %s:\n    // body of the branch at line %d\n", strings.Join(names, ", "), v.fset.Position(chain.bodies[i].Pos()).Line)
+	}
+	if chain.fallback != nil {
+		fmt.Fprintf(&b, "default:\n    // body of the else at line %d\n", v.fset.Position(chain.fallback.Pos()).Line)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func (v *ifChainVisitor) createIssue(head *ast.IfStmt, chain ifChain) {
+	position := v.fset.Position(head.Pos())
+	subject := types.ExprString(chain.subject)
+
+	constants := 0
+	for _, cases := range chain.cases {
+		constants += len(cases)
+	}
+
+	message := fmt.Sprintf("if/else-if chain compares %s against %d constants - a switch states the comparison once", subject, constants)
+	suggestion := suggestions.Render("if_chain.switch", suggestions.Data{Var: subject, Source: v.switchStatement(chain)})
+	if table, lookup, ok := v.lookupTable(chain); ok {
+		message = fmt.Sprintf("if/else-if chain maps %d constants of %s to values - a lookup map replaces it", constants, subject)
+		suggestion = suggestions.Render("if_chain.map", suggestions.Data{Var: lookup, Source: table})
+	}
+
+	issue := models.Issue{
+		Type:        models.IssueIfChain,
+		Severity:    models.SeverityLow,
+		File:        v.filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Function:    v.currentFunc,
+		Message:     message,
+		Suggestion:  suggestion,
+		Complexity:  fmt.Sprintf("%d branches comparing %s in sequence", len(chain.cases), subject),
+		CodeSnippet: position.String(),
+		Confidence:  0.9, // Structural; only the style is up for debate
+		Impact:      "Clearer branching in one place",
+		FixEffort:   models.EffortTrivial,
+	}
+	v.issues = append(v.issues, issue)
+}
+
+// typeString returns the type of expr as written in this package, or "T"
+// without type info
+func (v *ifChainVisitor) typeString(expr ast.Expr) string {
+	if v.context == nil || v.context.TypeInfo == nil {
+		return "T"
+	}
+	t := v.context.TypeInfo.TypeOf(expr)
+	if t == nil {
+		return "T"
+	}
+	return types.TypeString(types.Default(t), func(pkg *types.Package) string {
+		if pkg.Name() == v.pkgName {
+			return ""
+		}
+		return pkg.Name()
+	})
+}
@@ -1,35 +1,45 @@
 package detectors
 
 import (
+	"cmp"
 	"fmt"
 	"go/ast"
 	"go/token"
 	"gophercheck/internal/config"
 	"gophercheck/internal/context"
 	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+	"gophercheck/internal/workspace"
 	"path"
+	"slices"
 	"strings"
 )
 
+// ImportCycleDetector finds cycles in the import graph of the analyzed
+// packages, which the analyzer gathers from every file before detectors run.
+// Each cycle is reported once, at the import that leads out of its first
+// package, with every package's import listed in Issue.Cycle.
 type ImportCycleDetector struct {
-	packages map[string]*packageInfo
-	analyzed map[string]bool
-	config   *config.Config
+	config *config.Config
+}
+
+var _ Detector = (*ImportCycleDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "import_cycles",
+		Category: "quality",
+		Version:  "1.4.0",
+		New:      func(cfg *config.Config) Detector { return NewImportCycleDetectorWithConfig(cfg) },
+	})
 }
 
 func NewImportCycleDetector() *ImportCycleDetector {
-	return &ImportCycleDetector{
-		packages: make(map[string]*packageInfo),
-		analyzed: make(map[string]bool),
-	}
+	return &ImportCycleDetector{}
 }
 
 func NewImportCycleDetectorWithConfig(cfg *config.Config) *ImportCycleDetector {
-	return &ImportCycleDetector{
-		packages: make(map[string]*packageInfo),
-		analyzed: make(map[string]bool),
-		config:   cfg,
-	}
+	return &ImportCycleDetector{config: cfg}
 }
 
 func (d *ImportCycleDetector) SetConfig(cfg *config.Config) {
@@ -40,109 +50,92 @@ func (d *ImportCycleDetector) Name() string {
 	return "Import Cycle Detector"
 }
 
-type packageInfo struct {
-	name     string
-	filePath string
-	imports  []string
-	line     int
+// importEdge is an import from one analyzed package of another
+type importEdge struct {
+	from, to string
+	context.Import
 }
 
 func (d *ImportCycleDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
-	detector := &importCycleVisitor{
-		detector: d,
-		fset:     fset,
-		filename: filename,
-		issues:   make([]models.Issue, 0),
-		context:  ctx,
-	}
-
-	ast.Walk(detector, file)
-
-	// After collecting all package info, analyze for cycles
-	cycles := d.findCycles()
-	for _, cycle := range cycles {
-		detector.createCycleIssue(cycle)
+	if ctx == nil || len(ctx.Imports) == 0 {
+		return nil
 	}
 
-	return detector.issues
-}
-
-type importCycleVisitor struct {
-	detector    *ImportCycleDetector
-	fset        *token.FileSet
-	filename    string
-	issues      []models.Issue
-	packageName string
-	context     *context.AnalysisContext
-}
-
-func (v *importCycleVisitor) Visit(node ast.Node) ast.Visitor {
-	switch n := node.(type) {
-	case *ast.File:
-		if n.Name != nil {
-			v.packageName = n.Name.Name
+	var issues []models.Issue
+	for _, cycle := range findCycles(d.importGraph(ctx)) {
+		// The file holding the first import reports the cycle, so it is
+		// reported once whichever files are analyzed first
+		if cycle[0].File != filename {
+			continue
 		}
-		return v
-
-	case *ast.GenDecl:
-		if n.Tok == token.IMPORT {
-			v.processImports(n)
+		if issue, ok := d.createCycleIssue(cycle, ctx); ok {
+			issues = append(issues, issue)
 		}
-		return nil
-
-	default:
-		return v
 	}
+	return issues
 }
 
-func (v *importCycleVisitor) processImports(decl *ast.GenDecl) {
-	var imports []string
-	importLine := 0
+// importGraph returns the imports between analyzed packages, by importing
+// package, in a stable order
+func (d *ImportCycleDetector) importGraph(ctx *context.AnalysisContext) map[string][]importEdge {
+	ignoreTests := d.config != nil && d.config.Rules.Quality.ImportCycles.Enabled &&
+		d.config.Rules.Quality.ImportCycles.IgnoreTestPackages
 
-	for _, spec := range decl.Specs {
-		if importSpec, ok := spec.(*ast.ImportSpec); ok {
-			if importSpec.Path != nil {
-				importPath := strings.Trim(importSpec.Path.Value, `"`)
-
-				if !v.isThirdPartyOrLocalImport(importPath) {
-					continue
-				}
-
-				imports = append(imports, importPath)
-
-				if importLine == 0 {
-					position := v.fset.Position(importSpec.Pos())
-					importLine = position.Line
-				}
-			}
-		}
+	modules := make(map[string]bool) // Paths of the analyzed modules
+	for _, modulePath := range ctx.ModulePaths {
+		modules[modulePath] = true
 	}
 
-	if len(imports) > 0 {
-		// Extract package name from file path
-		packagePath := v.getPackagePathFromFile(v.filename)
-
-		v.detector.packages[packagePath] = &packageInfo{
-			name:     v.packageName,
-			filePath: v.filename,
-			imports:  imports,
-			line:     importLine,
+	graph := make(map[string][]importEdge, len(ctx.Imports))
+	for pkg, imports := range ctx.Imports {
+		var edges []importEdge
+		for _, imp := range imports {
+			if ignoreTests && strings.HasSuffix(imp.File, "_test.go") {
+				continue
+			}
+			// Vendored code named explicitly is analyzed with its own imports
+			if imp.Vendored && d.ignoreVendor() && !workspace.InVendor(imp.File) {
+				continue
+			}
+			if !d.isThirdPartyOrLocalImport(imp.Path, modules) {
+				continue
+			}
+			to := normalizeImportPath(pkg, imp.Path)
+			if _, analyzed := ctx.Imports[to]; !analyzed || to == pkg {
+				continue // Only analyzed packages can close a cycle
+			}
+			edges = append(edges, importEdge{from: pkg, to: to, Import: imp})
 		}
+		slices.SortFunc(edges, func(x, y importEdge) int {
+			return cmp.Or(cmp.Compare(x.to, y.to), cmp.Compare(x.File, y.File), cmp.Compare(x.Line, y.Line))
+		})
+		graph[pkg] = edges
 	}
+	return graph
 }
 
-func (v *importCycleVisitor) isThirdPartyOrLocalImport(importPath string) bool {
-	if v.detector.config != nil && v.detector.config.Rules.Quality.ImportCycles.Enabled {
-		for _, excluded := range v.detector.config.Rules.Quality.ImportCycles.ExcludePackages {
+// isThirdPartyOrLocalImport reports whether an import can belong to the
+// import graph: it is in one of the analyzed modules, whatever its path looks
+// like, or outside them has the dotted or relative path of a non-standard
+// package. Excluded and vendored packages never can.
+func (d *ImportCycleDetector) isThirdPartyOrLocalImport(importPath string, modules map[string]bool) bool {
+	if d.config != nil && d.config.Rules.Quality.ImportCycles.Enabled {
+		for _, excluded := range d.config.Rules.Quality.ImportCycles.ExcludePackages {
 			if importPath == excluded || strings.HasPrefix(importPath, excluded+"/") {
 				return false
 			}
 		}
+	}
 
-		if v.detector.config.Rules.Quality.ImportCycles.IgnoreVendor {
-			if strings.HasPrefix(importPath, "vendor/") || strings.Contains(importPath, "/vendor/") {
-				return false
-			}
+	if d.ignoreVendor() && (strings.HasPrefix(importPath, "vendor/") || strings.Contains(importPath, "/vendor/")) {
+		return false
+	}
+
+	// A module path such as "gophercheck" has no dot, and may even shadow a
+	// standard package name
+	for prefix := importPath; prefix != "." && prefix != "/"; prefix = path.Dir(prefix) {
+		if modules[prefix] {
+			return true
 		}
 	}
 
@@ -165,157 +158,198 @@ func (v *importCycleVisitor) isThirdPartyOrLocalImport(importPath string) bool {
 	return strings.Contains(importPath, ".") || strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../")
 }
 
-func (v *importCycleVisitor) getPackagePathFromFile(filename string) string {
-	// Extract package path from file path
-	// This is simplified - in a real implementation, you'd use go/build or go/packages
-	dir := path.Dir(filename)
-	if dir == "." {
-		return "main"
+// ignoreVendor reports whether vendored packages stay out of the graph, as
+// files.ignore_vendor keeps vendor directories out of the analyzed files
+func (d *ImportCycleDetector) ignoreVendor() bool {
+	return d.config == nil || d.config.Files.IgnoreVendor
+}
+
+// normalizeImportPath resolves a relative import against the importing
+// package
+func normalizeImportPath(pkg, importPath string) string {
+	if strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
+		return path.Join(strings.TrimSuffix(pkg, "_test"), importPath)
 	}
-	return dir
+	return importPath
 }
 
-func (d *ImportCycleDetector) findCycles() [][]string {
-	var cycles [][]string
-	visited := make(map[string]bool)
-	recStack := make(map[string]bool)
-	path := make([]string, 0)
+// findCycles returns one cycle for every set of packages that import each
+// other (a strongly connected component of the graph): the shortest cycle
+// through the set's first package, starting there. Cycles are ordered by
+// their first package.
+func findCycles(graph map[string][]importEdge) [][]importEdge {
+	packages := make([]string, 0, len(graph))
+	for pkg := range graph {
+		packages = append(packages, pkg)
+	}
+	slices.Sort(packages)
 
-	for packagePath := range d.packages {
-		if !visited[packagePath] {
-			if cycle := d.dfs(packagePath, visited, recStack, path); cycle != nil {
-				cycles = append(cycles, cycle)
-			}
+	var cycles [][]importEdge
+	for _, component := range stronglyConnected(packages, graph) {
+		if len(component) < 2 {
+			continue
+		}
+		if cycle := shortestCycle(slices.Min(component), component, graph); cycle != nil {
+			cycles = append(cycles, cycle)
 		}
 	}
-
+	slices.SortFunc(cycles, func(x, y []importEdge) int {
+		return cmp.Compare(x[0].from, y[0].from)
+	})
 	return cycles
 }
 
-func (d *ImportCycleDetector) dfs(packagePath string, visited, recStack map[string]bool, path []string) []string {
-	visited[packagePath] = true
-	recStack[packagePath] = true
-	path = append(path, packagePath)
+// stronglyConnected splits the packages into strongly connected components
+// with Tarjan's algorithm
+func stronglyConnected(packages []string, graph map[string][]importEdge) [][]string {
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var connect func(pkg string)
+	connect = func(pkg string) {
+		index[pkg] = len(index)
+		lowLink[pkg] = index[pkg]
+		stack = append(stack, pkg)
+		onStack[pkg] = true
+
+		for _, edge := range graph[pkg] {
+			if _, seen := index[edge.to]; !seen {
+				connect(edge.to)
+				lowLink[pkg] = minInt(lowLink[pkg], lowLink[edge.to])
+			} else if onStack[edge.to] {
+				lowLink[pkg] = minInt(lowLink[pkg], index[edge.to])
+			}
+		}
 
-	pkg, exists := d.packages[packagePath]
-	if !exists {
-		recStack[packagePath] = false
-		return nil
+		if lowLink[pkg] != index[pkg] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == pkg {
+				break
+			}
+		}
+		components = append(components, component)
 	}
 
-	for _, importPath := range pkg.imports {
-		// Normalize import path to package path
-		normalizedPath := d.normalizeImportPath(importPath)
+	for _, pkg := range packages {
+		if _, seen := index[pkg]; !seen {
+			connect(pkg)
+		}
+	}
+	return components
+}
+
+// shortestCycle finds the shortest path of imports from start back to
+// itself within a component, by breadth-first search
+func shortestCycle(start string, component []string, graph map[string][]importEdge) []importEdge {
+	inComponent := make(map[string]bool, len(component))
+	for _, pkg := range component {
+		inComponent[pkg] = true
+	}
 
-		if !visited[normalizedPath] {
-			if cycle := d.dfs(normalizedPath, visited, recStack, path); cycle != nil {
-				recStack[packagePath] = false
+	reachedBy := make(map[string]importEdge) // The import each package was first reached by
+	queue := []string{start}
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		for _, edge := range graph[pkg] {
+			if !inComponent[edge.to] {
+				continue
+			}
+			if edge.to == start {
+				cycle := []importEdge{edge}
+				for at := pkg; at != start; at = reachedBy[at].from {
+					cycle = append(cycle, reachedBy[at])
+				}
+				slices.Reverse(cycle)
 				return cycle
 			}
-		} else if recStack[normalizedPath] {
-			// Found a cycle - extract the cycle from the path
-			cycle := d.extractCycle(path, normalizedPath)
-			recStack[packagePath] = false
-			return cycle
+			if _, reached := reachedBy[edge.to]; !reached {
+				reachedBy[edge.to] = edge
+				queue = append(queue, edge.to)
+			}
 		}
 	}
-
-	recStack[packagePath] = false
 	return nil
 }
 
-func (d *ImportCycleDetector) normalizeImportPath(importPath string) string {
-	// Simplified normalization - in a real implementation, this would be more sophisticated
-	if strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
-		return path.Clean(importPath)
-	}
-	return importPath
-}
-
-func (d *ImportCycleDetector) extractCycle(path []string, cycleStart string) []string {
-	// Find where the cycle starts and extract it
-	for i, pkg := range path {
-		if pkg == cycleStart {
-			cycle := make([]string, len(path)-i)
-			copy(cycle, path[i:])
-			cycle = append(cycle, cycleStart) // Complete the cycle
-			return cycle
-		}
+func minInt(a, b int) int {
+	if a < b {
+		return a
 	}
-	return path
+	return b
 }
 
-func (v *importCycleVisitor) createCycleIssue(cycle []string) {
-	if len(cycle) < 2 {
-		return
+func (d *ImportCycleDetector) createCycleIssue(cycle []importEdge, ctx *context.AnalysisContext) (models.Issue, bool) {
+	packages := make([]string, 0, len(cycle)+1)
+	for _, edge := range cycle {
+		packages = append(packages, edge.from)
 	}
+	packages = append(packages, cycle[0].from)
 
 	// Check config settings
-	if v.detector.config != nil && v.detector.config.Rules.Quality.ImportCycles.Enabled {
-		cycleLen := len(cycle) - 1 // Remove duplicate at end
-		maxCycleLength := v.detector.config.Rules.Quality.ImportCycles.MaxCycleLength
-
+	if d.config != nil && d.config.Rules.Quality.ImportCycles.Enabled {
 		// Don't report cycles that are within acceptable limits
-		if cycleLen <= maxCycleLength {
-			return
+		if len(cycle) <= d.config.Rules.Quality.ImportCycles.MaxCycleLength {
+			return models.Issue{}, false
 		}
 
 		// Check if test packages should be ignored
-		if v.detector.config.Rules.Quality.ImportCycles.IgnoreTestPackages {
-			// Skip if any package in cycle appears to be a test package
-			for _, pkg := range cycle {
+		if d.config.Rules.Quality.ImportCycles.IgnoreTestPackages {
+			for _, pkg := range packages {
 				if strings.Contains(pkg, "_test") || strings.Contains(pkg, "/test") {
-					return
+					return models.Issue{}, false
 				}
 			}
 		}
 	}
 
-	// Find the package in our current file that's part of the cycle
-	currentPackage := v.getPackagePathFromFile(v.filename)
-
-	// Check if current file is part of this cycle
-	inCycle := false
-	for _, pkg := range cycle {
-		if pkg == currentPackage {
-			inCycle = true
-			break
+	participants := make([]models.CycleImport, len(cycle))
+	confidence := 0.95
+	for i, edge := range cycle {
+		participants[i] = models.CycleImport{
+			Package: edge.from,
+			Imports: edge.to,
+			File:    edge.File,
+			Line:    edge.Line,
+		}
+		if _, resolved := ctx.PackagePaths[edge.File]; !resolved {
+			confidence = 0.8 // Package path inferred from the file's directory
 		}
 	}
 
-	if !inCycle {
-		return
-	}
-
-	// Get the package info for line number
-	pkgInfo := v.detector.packages[currentPackage]
-	line := 1
-	if pkgInfo != nil {
-		line = pkgInfo.line
-	}
-
-	cycleStr := strings.Join(cycle, " → ")
-
-	issue := models.Issue{
+	first := cycle[0]
+	return models.Issue{
 		Type:        models.IssueImportCycle,
-		Severity:    v.calculateCycleSeverity(len(cycle)),
-		File:        v.filename,
-		Line:        line,
+		Severity:    d.calculateCycleSeverity(len(cycle)),
+		File:        first.File,
+		Line:        first.Line,
 		Column:      1,
 		Function:    "", // Not applicable for import issues
-		Message:     fmt.Sprintf("Import cycle detected: %s", cycleStr),
-		Suggestion:  v.generateCycleSuggestion(cycle),
-		Complexity:  fmt.Sprintf("Cycle length: %d packages", len(cycle)-1),
-		CodeSnippet: fmt.Sprintf("%s:%d", v.filename, line),
-	}
-
-	v.issues = append(v.issues, issue)
+		Message:     fmt.Sprintf("Import cycle detected: %s", strings.Join(packages, " → ")),
+		Suggestion:  d.generateCycleSuggestion(len(cycle)),
+		Complexity:  fmt.Sprintf("Cycle length: %d packages", len(cycle)),
+		CodeSnippet: fmt.Sprintf("%s:%d", first.File, first.Line),
+		Confidence:  confidence,
+		Impact:      "Unblocks compilation and decouples packages",
+		FixEffort:   models.EffortLarge,
+		Cycle:       participants,
+	}, true
 }
 
-func (v *importCycleVisitor) calculateCycleSeverity(cycleLength int) models.Severity {
+func (d *ImportCycleDetector) calculateCycleSeverity(cycleLength int) models.Severity {
 	maxCycleLength := 5 // default
-	if v.detector.config != nil && v.detector.config.Rules.Quality.ImportCycles.Enabled {
-		maxCycleLength = v.detector.config.Rules.Quality.ImportCycles.MaxCycleLength
+	if d.config != nil && d.config.Rules.Quality.ImportCycles.Enabled {
+		maxCycleLength = d.config.Rules.Quality.ImportCycles.MaxCycleLength
 	}
 
 	ratio := float64(cycleLength) / float64(maxCycleLength)
@@ -332,58 +366,15 @@ func (v *importCycleVisitor) calculateCycleSeverity(cycleLength int) models.Seve
 	}
 }
 
-func (v *importCycleVisitor) generateCycleSuggestion(cycle []string) string {
-	cycleLen := len(cycle) - 1 // Remove duplicate at end
+func (d *ImportCycleDetector) generateCycleSuggestion(cycleLen int) string {
+	data := suggestions.Data{CycleLength: cycleLen}
 
-	baseAdvice := `Import cycles prevent compilation and indicate poor package design. Here are strategies to break the cycle:
-
-1. **Dependency Inversion**: Create interfaces to break direct dependencies
-2. **Extract Common Code**: Move shared functionality to a separate package
-3. **Merge Packages**: If packages are tightly coupled, consider combining them
-4. **Remove Unnecessary Dependencies**: Review if all imports are actually needed`
 	switch {
 	case cycleLen == 2:
-		return baseAdvice + `
-
-For 2-package cycles:
-// Instead of:
-// package A imports B
-// package B imports A
-
-// Strategy 1 - Extract interface:
-// package A imports B (interface only)
-// package B imports common
-// package common defines interfaces
-
-// Strategy 2 - Dependency injection:
-// package A defines interface, imports B
-// package B implements interface, no import of A
-// main wires them together`
-
+		return suggestions.Render("import_cycle.two", data)
 	case cycleLen == 3:
-		return baseAdvice + `
-
-For 3-package cycles (A → B → C → A):
-1. **Find the weakest link**: Identify which dependency is least essential
-2. **Extract shared interfaces**: Create a common package for shared contracts
-3. **Use event-driven design**: Replace direct calls with event publishing
-4. **Consider package merging**: If A, B, C are tightly coupled, merge them
-
-Example refactoring:
-// Before: A → B → C → A
-// After:  A → common ← B ← C
-//    common contains interfaces used by all`
-
+		return suggestions.Render("import_cycle.three", data)
 	default:
-		return baseAdvice + fmt.Sprintf(`
-
-Complex %d-package cycle requires architectural review:
-1. **Draw dependency diagram** to visualize the cycle
-2. **Identify core domain concepts** that shouldn't depend on periphery
-3. **Apply Clean Architecture principles** (domain → application → infrastructure)
-4. **Consider microservices** if packages represent different bounded contexts
-5. **Use dependency injection container** to manage complex relationships
-
-This cycle suggests the codebase may need significant restructuring.`, cycleLen)
+		return suggestions.Render("import_cycle.many", data)
 	}
 }
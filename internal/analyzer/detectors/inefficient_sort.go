@@ -0,0 +1,373 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// sortFuncs are the standard library sorts, by import path
+var sortFuncs = map[string]map[string]bool{
+	"sort":   {"Slice": true, "SliceStable": true, "Sort": true, "Stable": true, "Strings": true, "Ints": true, "Float64s": true},
+	"slices": {"Sort": true, "SortFunc": true, "SortStableFunc": true},
+}
+
+// InefficientSortDetector finds hand-rolled O(n²) sorts, recognized as
+// nested loops over one slice that swap its elements, and standard library
+// sorts repeated on the same data inside a loop
+type InefficientSortDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*InefficientSortDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "inefficient_sort",
+		Category: "performance",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewInefficientSortDetectorWithConfig(cfg) },
+	})
+}
+
+func NewInefficientSortDetector() *InefficientSortDetector {
+	return &InefficientSortDetector{}
+}
+
+func NewInefficientSortDetectorWithConfig(cfg *config.Config) *InefficientSortDetector {
+	return &InefficientSortDetector{
+		config: cfg,
+	}
+}
+
+func (d *InefficientSortDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *InefficientSortDetector) Name() string {
+	return "Inefficient Sort Detector"
+}
+
+func (d *InefficientSortDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &inefficientSortVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		detector: d,
+		context:  ctx,
+		settings: d.settings(),
+		reported: make(map[ast.Node]bool),
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+// settings returns the rule config, or the defaults without one
+func (d *InefficientSortDetector) settings() config.InefficientSortConfig {
+	if d.config != nil && d.config.Rules.Performance.InefficientSort.Enabled {
+		return d.config.Rules.Performance.InefficientSort
+	}
+	return config.DefaultConfig().Rules.Performance.InefficientSort
+}
+
+type inefficientSortVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	loops       []ast.Node // Enclosing loops, innermost last
+	reported    map[ast.Node]bool
+	detector    *InefficientSortDetector
+	context     *context.AnalysisContext
+	settings    config.InefficientSortConfig
+}
+
+func (v *inefficientSortVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Name != nil {
+			v.currentFunc = n.Name.Name
+		}
+		return v
+
+	case *ast.ForStmt, *ast.RangeStmt:
+		if isBenchmarkTimingLoop(v.context, n) {
+			for _, stmt := range getLoopBody(n) {
+				ast.Walk(v, stmt)
+			}
+			return nil
+		}
+
+		if v.settings.DetectHandRolled && len(v.loops) > 0 {
+			v.checkHandRolled(v.loops[len(v.loops)-1], n)
+		}
+		v.loops = append(v.loops, n)
+		for _, stmt := range getLoopBody(n) {
+			ast.Walk(v, stmt)
+		}
+		v.loops = v.loops[:len(v.loops)-1]
+		return nil
+
+	case *ast.CallExpr:
+		if v.settings.DetectRepeatedSort && len(v.loops) > 0 {
+			v.checkRepeatedSort(n)
+		}
+		return v
+
+	default:
+		return v
+	}
+}
+
+// checkHandRolled reports an outer loop that swaps elements of a slice the
+// inner loop also walks: bubble, selection, and insertion sorts
+func (v *inefficientSortVisitor) checkHandRolled(outer, inner ast.Node) {
+	if v.reported[outer] {
+		return
+	}
+	var info *context.LoopInfo
+	if v.context != nil {
+		info = v.context.LoopContext[outer]
+	}
+	if info != nil && info.BoundType == context.BoundConstant && info.EstimatedMax > 0 && info.EstimatedMax <= 10 {
+		return
+	}
+
+	slice := findSwap(outer)
+	if slice == "" || !walksSlice(inner, slice) {
+		return
+	}
+	v.reported[outer] = true
+
+	severity := models.SeverityMedium
+	if info != nil && info.EstimatedMax > 1000 {
+		severity = models.SeverityHigh
+	}
+
+	position := v.fset.Position(getNodePosition(inner))
+	v.addIssue(position, models.Issue{
+		Severity:   severity,
+		Message:    fmt.Sprintf("Hand-rolled sort of %s with nested loops and swaps - O(n²) where the standard library sorts in O(n log n)", slice),
+		Suggestion: suggestions.Render("inefficient_sort.hand_rolled", suggestions.Data{Var: slice}),
+		Complexity: "O(n²) comparisons and swaps",
+		Confidence: 0.85,
+		Impact:     "O(n²)→O(n log n)",
+		FixEffort:  models.EffortTrivial,
+	})
+}
+
+// findSwap returns the slice whose elements a loop swaps, either as
+// s[i], s[j] = s[j], s[i] or through a temporary variable
+func findSwap(loop ast.Node) string {
+	slice := ""
+	ast.Inspect(loop, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			if s := parallelSwap(node); s != "" {
+				slice = s
+			}
+		case *ast.BlockStmt:
+			for i := 0; i+2 < len(node.List); i++ {
+				if s := tempSwap(node.List[i : i+3]); s != "" {
+					slice = s
+				}
+			}
+		}
+		return slice == ""
+	})
+	return slice
+}
+
+// parallelSwap matches s[i], s[j] = s[j], s[i]
+func parallelSwap(assign *ast.AssignStmt) string {
+	if assign.Tok != token.ASSIGN || len(assign.Lhs) != 2 || len(assign.Rhs) != 2 {
+		return ""
+	}
+	var index [4]*ast.IndexExpr
+	for i, expr := range []ast.Expr{assign.Lhs[0], assign.Lhs[1], assign.Rhs[0], assign.Rhs[1]} {
+		ie, ok := expr.(*ast.IndexExpr)
+		if !ok {
+			return ""
+		}
+		index[i] = ie
+	}
+	slice := types.ExprString(index[0].X)
+	for _, ie := range index[1:] {
+		if types.ExprString(ie.X) != slice {
+			return ""
+		}
+	}
+	if !sameExpr(index[0], index[3]) || !sameExpr(index[1], index[2]) || sameExpr(index[0], index[1]) {
+		return ""
+	}
+	return slice
+}
+
+// tempSwap matches t := s[i]; s[i] = s[j]; s[j] = t
+func tempSwap(stmts []ast.Stmt) string {
+	var assigns [3]*ast.AssignStmt
+	for i, stmt := range stmts {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return ""
+		}
+		assigns[i] = assign
+	}
+
+	temp, ok := assigns[0].Lhs[0].(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	first, ok := assigns[0].Rhs[0].(*ast.IndexExpr)
+	if !ok {
+		return ""
+	}
+	second, ok := assigns[1].Rhs[0].(*ast.IndexExpr)
+	if !ok || !sameExpr(assigns[1].Lhs[0], first) || !sameExpr(assigns[2].Lhs[0], second) {
+		return ""
+	}
+	if restored, ok := assigns[2].Rhs[0].(*ast.Ident); !ok || restored.Name != temp.Name {
+		return ""
+	}
+	if !sameExpr(first.X, second.X) {
+		return ""
+	}
+	return types.ExprString(first.X)
+}
+
+// walksSlice reports whether a loop ranges over or indexes
+// the named slice
+func walksSlice(loop ast.Node, slice string) bool {
+	if rangeStmt, ok := loop.(*ast.RangeStmt); ok && types.ExprString(rangeStmt.X) == slice {
+		return true
+	}
+	found := false
+	ast.Inspect(loop, func(n ast.Node) bool {
+		if index, ok := n.(*ast.IndexExpr); ok && types.ExprString(index.X) == slice {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// checkRepeatedSort reports a standard library sort inside a loop on data
+// declared outside it, which sorts the same elements again every iteration
+func (v *inefficientSortVisitor) checkRepeatedSort(call *ast.CallExpr) {
+	pkg, name, ok := packageFunc(v.context, call)
+	if !ok || !sortFuncs[pkg][name] || len(call.Args) == 0 {
+		return
+	}
+
+	data := call.Args[0]
+	if conversion, ok := data.(*ast.CallExpr); ok && len(conversion.Args) == 1 {
+		data = conversion.Args[0] // sort.Sort(byName(people))
+	}
+	root := rootIdent(data)
+	if root == nil {
+		return
+	}
+	loop := v.loops[len(v.loops)-1]
+	if declaredIn(loop, root.Name) {
+		return // A fresh slice each iteration
+	}
+
+	funcName := pkg + "." + name
+	text := types.ExprString(data)
+	message := fmt.Sprintf("%s sorts %s on every loop iteration although it doesn't change - sort it once before the loop", funcName, text)
+	entry := "inefficient_sort.hoist"
+	if assignedInLoop(loop)[root.Name] {
+		message = fmt.Sprintf("%s re-sorts %s on every loop iteration as it grows - sort once after the loop", funcName, text)
+		entry = "inefficient_sort.after_loop"
+	}
+
+	severity := models.SeverityMedium
+	if len(v.loops) >= 2 {
+		severity = models.SeverityHigh
+	}
+
+	v.addIssue(v.fset.Position(call.Pos()), models.Issue{
+		Severity:   severity,
+		Message:    message,
+		Suggestion: suggestions.Render(entry, suggestions.Data{Var: text, Type: funcName}),
+		Complexity: "O(n · m log m): a full sort per iteration",
+		Confidence: 0.75, // The loop may exit right after sorting
+		Impact:     "O(n · m log m)→O(m log m)",
+		FixEffort:  models.EffortSmall,
+	})
+}
+
+// rootIdent returns the variable at the base of x, x.f, x[i], or *x
+func rootIdent(expr ast.Expr) *ast.Ident {
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return e
+		case *ast.SelectorExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.UnaryExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
+
+// declaredIn reports whether a loop declares a variable with the given
+// name, including its own iteration variables
+func declaredIn(loop ast.Node, name string) bool {
+	declared := false
+	ast.Inspect(loop, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.RangeStmt:
+			for _, expr := range []ast.Expr{node.Key, node.Value} {
+				if ident, ok := expr.(*ast.Ident); ok && ident.Name == name && node.Tok == token.DEFINE {
+					declared = true
+				}
+			}
+		case *ast.AssignStmt:
+			if node.Tok == token.DEFINE {
+				for _, lhs := range node.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok && ident.Name == name {
+						declared = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for _, ident := range node.Names {
+				if ident.Name == name {
+					declared = true
+				}
+			}
+		}
+		return !declared
+	})
+	return declared
+}
+
+// addIssue fills in the type and location of issue and records it
+func (v *inefficientSortVisitor) addIssue(position token.Position, issue models.Issue) {
+	issue.Type = models.IssueInefficientSort
+	issue.File = v.filename
+	issue.Line = position.Line
+	issue.Column = position.Column
+	issue.Function = v.currentFunc
+	issue.CodeSnippet = position.String()
+
+	v.issues = append(v.issues, issue)
+}
@@ -0,0 +1,285 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// logNames are the function and method names that write a log entry.
+// Fatal and Panic are left out: they stop the program, so they don't repeat.
+var logNames = map[string]bool{
+	"Print": true, "Printf": true, "Println": true,
+	"Log": true, "Logf": true, "LogAttrs": true,
+	"Trace": true, "Tracef": true,
+	"Debug": true, "Debugf": true, "Debugw": true, "DebugContext": true,
+	"Info": true, "Infof": true, "Infow": true, "Infoln": true, "InfoContext": true,
+	"Warn": true, "Warnf": true, "Warnw": true, "Warning": true, "Warningf": true, "WarnContext": true,
+	"Error": true, "Errorf": true, "Errorw": true, "ErrorContext": true,
+	"Msg": true, "Msgf": true, "Send": true, // zerolog events
+}
+
+// fmtLogNames are the fmt functions that print to stdout like a logger
+var fmtLogNames = map[string]bool{"Print": true, "Printf": true, "Println": true}
+
+// LogInLoopDetector finds logging calls in loops and high-frequency functions,
+// where formatting and I/O for every entry dominates the runtime
+type LogInLoopDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*LogInLoopDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "log_in_loop",
+		Category: "performance",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewLogInLoopDetectorWithConfig(cfg) },
+	})
+}
+
+func NewLogInLoopDetector() *LogInLoopDetector {
+	return &LogInLoopDetector{}
+}
+
+func NewLogInLoopDetectorWithConfig(cfg *config.Config) *LogInLoopDetector {
+	return &LogInLoopDetector{
+		config: cfg,
+	}
+}
+
+func (d *LogInLoopDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *LogInLoopDetector) Name() string {
+	return "Log In Loop Detector"
+}
+
+func (d *LogInLoopDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &logInLoopVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		detector: d,
+		context:  ctx,
+		settings: d.settings(),
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+type logInLoopVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	hotFunc     bool // currentFunc is estimated to run at high frequency
+	loopDepth   int  // Loops that may run many times
+	guarded     int  // Inside sampling or error-handling branches
+	detector    *LogInLoopDetector
+	context     *context.AnalysisContext
+	settings    config.LogInLoopConfig
+}
+
+func (v *logInLoopVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Name != nil {
+			v.currentFunc = n.Name.Name
+			v.hotFunc = v.isHotFunction(n.Name.Name)
+		}
+		return v
+
+	case *ast.ForStmt, *ast.RangeStmt:
+		if isBenchmarkTimingLoop(v.context, n) || v.isSmallLoop(n) {
+			for _, stmt := range getLoopBody(n) {
+				ast.Walk(v, stmt)
+			}
+			return nil
+		}
+
+		v.loopDepth++
+		for _, stmt := range getLoopBody(n) {
+			ast.Walk(v, stmt)
+		}
+		v.loopDepth--
+		return nil
+
+	case *ast.IfStmt:
+		if !isGuardCondition(n.Cond) {
+			return v
+		}
+		if n.Init != nil {
+			ast.Walk(v, n.Init)
+		}
+		v.guarded++
+		ast.Walk(v, n.Body)
+		if n.Else != nil {
+			ast.Walk(v, n.Else)
+		}
+		v.guarded--
+		return nil
+
+	case *ast.CallExpr:
+		hot := v.loopDepth > 0 || (v.hotFunc && v.settings.FlagHotFunctions)
+		if v.guarded > 0 || !hot {
+			return v
+		}
+		if name, ok := v.logCall(n); ok {
+			v.createIssue(n, name)
+			return nil // Don't report the rest of a chained call again
+		}
+		return v
+
+	default:
+		return v
+	}
+}
+
+// settings returns the rule config, or the defaults without one
+func (d *LogInLoopDetector) settings() config.LogInLoopConfig {
+	if d.config != nil && d.config.Rules.Performance.LogInLoop.Enabled {
+		return d.config.Rules.Performance.LogInLoop
+	}
+	return config.DefaultConfig().Rules.Performance.LogInLoop
+}
+
+func (v *logInLoopVisitor) isHotFunction(name string) bool {
+	if v.context == nil {
+		return false
+	}
+	info, ok := v.context.CallGraph[name]
+	return ok && info.Frequency == context.FrequencyHigh
+}
+
+// isSmallLoop reports loops with a small constant bound, which aren't hot
+func (v *logInLoopVisitor) isSmallLoop(loop ast.Node) bool {
+	if v.context == nil {
+		return false
+	}
+	info, ok := v.context.LoopContext[loop]
+	return ok && info.BoundType == context.BoundConstant && info.EstimatedMax > 0 && info.EstimatedMax <= 10
+}
+
+// isGuardCondition reports conditions that make logging rare: sampling
+// (i%1000 == 0) and error checks (err != nil)
+func isGuardCondition(cond ast.Expr) bool {
+	guard := false
+	ast.Inspect(cond, func(n ast.Node) bool {
+		binary, ok := n.(*ast.BinaryExpr)
+		if !ok {
+			return true
+		}
+		switch binary.Op {
+		case token.REM:
+			guard = true
+		case token.NEQ:
+			if ident, ok := binary.X.(*ast.Ident); ok && strings.HasSuffix(strings.ToLower(ident.Name), "err") {
+				guard = true
+			}
+		}
+		return !guard
+	})
+	return guard
+}
+
+// logCall returns the display name of a logging call, e.g. "log.Printf" or
+// "logger.Info"
+func (v *logInLoopVisitor) logCall(call *ast.CallExpr) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	name := sel.Sel.Name
+
+	// Package-level functions: log.Printf, slog.Info, fmt.Println
+	if pkg, _, ok := packageFunc(v.context, call); ok && v.isLoggerPackage(pkg) {
+		if pkg == "fmt" {
+			return "fmt." + name, fmtLogNames[name]
+		}
+		return types.ExprString(sel), logNames[name]
+	}
+
+	// Methods on logger types: logger.Info, sugar.Infow, event.Msg
+	if !logNames[name] {
+		return "", false
+	}
+	if v.context != nil && v.context.TypeInfo != nil {
+		if selection, ok := v.context.TypeInfo.Selections[sel]; ok {
+			return types.ExprString(sel), v.isLoggerPackage(receiverPackage(selection.Recv()))
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok {
+			if _, isPkg := v.context.TypeInfo.Uses[ident].(*types.PkgName); isPkg {
+				return "", false // A function in some other package
+			}
+		}
+	}
+	receiver := strings.ToLower(types.ExprString(sel.X))
+	return types.ExprString(sel), strings.Contains(receiver, "log")
+}
+
+// isLoggerPackage reports whether pkg is a configured logger package. pkg
+// is a bare package name when there's no type info, e.g. "slog".
+func (v *logInLoopVisitor) isLoggerPackage(pkg string) bool {
+	return pkg != "" && slices.ContainsFunc(v.settings.LoggerPackages, func(loggerPkg string) bool {
+		return loggerPkg == pkg || importName(loggerPkg) == pkg
+	})
+}
+
+// receiverPackage returns the import path of the package declaring a
+// method's receiver type
+func receiverPackage(recv types.Type) string {
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	named, ok := recv.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	return named.Obj().Pkg().Path()
+}
+
+func (v *logInLoopVisitor) createIssue(call *ast.CallExpr, name string) {
+	position := v.fset.Position(call.Pos())
+
+	severity := models.SeverityMedium
+	message := fmt.Sprintf("%s called inside a loop - logging every iteration dominates hot paths", name)
+	complexity := "O(n) log writes: formatting and I/O per iteration"
+	switch {
+	case v.loopDepth >= 2 || (v.loopDepth == 1 && v.hotFunc):
+		severity = models.SeverityHigh
+	case v.loopDepth == 0:
+		severity = models.SeverityLow
+		message = fmt.Sprintf("%s called in high-frequency function '%s' - every call writes a log entry", name, v.currentFunc)
+		complexity = "1 log write per call"
+	}
+
+	issue := models.Issue{
+		Type:        models.IssueLogInLoop,
+		Severity:    severity,
+		File:        v.filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Function:    v.currentFunc,
+		Message:     message,
+		Suggestion:  suggestions.Render("log_in_loop.sample", suggestions.Data{Var: name}),
+		Complexity:  complexity,
+		CodeSnippet: position.String(),
+		Confidence:  0.75, // The loop may be short or the log level disabled
+		Impact:      "Fewer log writes on the hot path",
+		FixEffort:   models.EffortSmall,
+	}
+
+	v.issues = append(v.issues, issue)
+}
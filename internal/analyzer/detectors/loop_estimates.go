@@ -0,0 +1,44 @@
+package detectors
+
+import (
+	"go/ast"
+
+	"gophercheck/internal/context"
+)
+
+// unknownIterations stands for a loop whose iteration count can't be
+// estimated
+const unknownIterations = -1
+
+// loopIterations returns the estimated number of iterations of loop from the
+// analysis context, or unknownIterations
+func loopIterations(ctx *context.AnalysisContext, loop ast.Node) int {
+	if ctx == nil {
+		return unknownIterations
+	}
+	info, ok := ctx.LoopContext[loop]
+	if !ok || info.EstimatedMax < 0 {
+		return unknownIterations
+	}
+	return info.EstimatedMax
+}
+
+// nestedIterations multiplies the iteration estimates of nested loops into
+// the number of times the innermost body runs. Any unknown loop makes the
+// total unknown.
+func nestedIterations(estimates []int) int {
+	total := 1
+	for _, estimate := range estimates {
+		if estimate == unknownIterations {
+			return unknownIterations
+		}
+		total *= estimate
+	}
+	return total
+}
+
+// meetsEstimate reports whether an estimate reaches threshold, counting an
+// unknown estimate as reaching any threshold
+func meetsEstimate(estimate, threshold int) bool {
+	return estimate == unknownIterations || estimate >= threshold
+}
@@ -0,0 +1,217 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// MapMutationDetector finds maps modified while being ranged over
+type MapMutationDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*MapMutationDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "map_mutation",
+		Category: "quality",
+		Version:  "1.1.0",
+		New:      func(cfg *config.Config) Detector { return NewMapMutationDetectorWithConfig(cfg) },
+	})
+}
+
+func NewMapMutationDetector() *MapMutationDetector {
+	return &MapMutationDetector{}
+}
+
+func NewMapMutationDetectorWithConfig(cfg *config.Config) *MapMutationDetector {
+	return &MapMutationDetector{
+		config: cfg,
+	}
+}
+
+func (d *MapMutationDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *MapMutationDetector) Name() string {
+	return "Map Mutation Detector"
+}
+
+func (d *MapMutationDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &mapMutationVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		detector: d,
+		context:  ctx,
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+// rangedMap is a map being iterated by an enclosing range loop
+type rangedMap struct {
+	expr ast.Expr // The map expression
+	key  ast.Expr // The loop's key variable, if any
+}
+
+type mapMutationVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	ranging     []rangedMap
+	detector    *MapMutationDetector
+	context     *context.AnalysisContext
+}
+
+func (v *mapMutationVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Name != nil {
+			v.currentFunc = n.Name.Name
+		}
+		return v
+
+	case *ast.RangeStmt:
+		pushed := false
+		if isMapExpr(v.context, n.X) && v.settings().DetectRangeMutation {
+			v.ranging = append(v.ranging, rangedMap{expr: n.X, key: n.Key})
+			pushed = true
+		}
+
+		ast.Walk(v, n.Body)
+
+		if pushed {
+			v.ranging = v.ranging[:len(v.ranging)-1]
+		}
+		return nil
+
+	case *ast.AssignStmt:
+		for _, lhs := range n.Lhs {
+			if index, ok := lhs.(*ast.IndexExpr); ok {
+				v.checkRangeMutation(n, index.X, index.Index, false)
+			}
+		}
+		return v
+
+	case *ast.IncDecStmt:
+		if index, ok := n.X.(*ast.IndexExpr); ok {
+			v.checkRangeMutation(n, index.X, index.Index, false)
+		}
+		return v
+
+	case *ast.CallExpr:
+		if mapArg, keyArg, ok := deleteArgs(v.context, n); ok {
+			v.checkRangeMutation(n, mapArg, keyArg, true)
+		}
+		return v
+
+	default:
+		return v
+	}
+}
+
+// settings returns the rule config, or everything enabled without one
+func (v *mapMutationVisitor) settings() config.MapMutationConfig {
+	if v.detector.config != nil && v.detector.config.Rules.Quality.MapMutation.Enabled {
+		return v.detector.config.Rules.Quality.MapMutation
+	}
+	return config.MapMutationConfig{
+		Enabled:             true,
+		DetectRangeMutation: true,
+	}
+}
+
+// checkRangeMutation reports a write or delete on a map that an enclosing
+// loop is ranging over. Touching the current key is well-defined and skipped:
+// updating it changes no iteration order, and deleting it is explicitly safe.
+func (v *mapMutationVisitor) checkRangeMutation(node ast.Node, mapExpr, key ast.Expr, isDelete bool) {
+	for _, ranged := range v.ranging {
+		if !sameExpr(ranged.expr, mapExpr) {
+			continue
+		}
+		if ranged.key != nil && sameExpr(ranged.key, key) {
+			return
+		}
+
+		name := types.ExprString(mapExpr)
+		if isDelete {
+			v.addIssue(node, models.Issue{
+				Type:       models.IssueMapMutation,
+				Severity:   models.SeverityLow,
+				Message:    fmt.Sprintf("Deleting other keys from map '%s' while ranging over it - those entries may or may not be visited", name),
+				Suggestion: suggestions.Render("map_mutation.delete_in_range", suggestions.Data{Var: name}),
+				Complexity: "Nondeterministic iteration",
+				Confidence: 0.7,
+				Impact:     "Deterministic iteration",
+				FixEffort:  models.EffortSmall,
+			})
+		} else {
+			v.addIssue(node, models.Issue{
+				Type:       models.IssueMapMutation,
+				Severity:   models.SeverityMedium,
+				Message:    fmt.Sprintf("Inserting into map '%s' while ranging over it - new entries may or may not be visited", name),
+				Suggestion: suggestions.Render("map_mutation.insert_in_range", suggestions.Data{Var: name}),
+				Complexity: "Nondeterministic iteration",
+				Confidence: 0.75,
+				Impact:     "Deterministic iteration",
+				FixEffort:  models.EffortSmall,
+			})
+		}
+		return
+	}
+}
+
+// deleteArgs returns the map and key of a call to the builtin delete
+func deleteArgs(ctx *context.AnalysisContext, call *ast.CallExpr) (ast.Expr, ast.Expr, bool) {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok || ident.Name != "delete" || len(call.Args) != 2 {
+		return nil, nil, false
+	}
+	if ctx != nil && ctx.TypeInfo != nil {
+		if obj, ok := ctx.TypeInfo.Uses[ident]; ok {
+			if _, builtin := obj.(*types.Builtin); !builtin {
+				return nil, nil, false
+			}
+		}
+	}
+	return call.Args[0], call.Args[1], true
+}
+
+// isMapExpr reports whether type info says expr is a map. Without type info a
+// slice index and a map index look the same, so nothing is reported.
+func isMapExpr(ctx *context.AnalysisContext, expr ast.Expr) bool {
+	if ctx == nil || ctx.TypeInfo == nil {
+		return false
+	}
+	tv, ok := ctx.TypeInfo.Types[expr]
+	if !ok || tv.Type == nil {
+		return false
+	}
+	_, isMap := tv.Type.Underlying().(*types.Map)
+	return isMap
+}
+
+// addIssue fills in the location of issue and records it
+func (v *mapMutationVisitor) addIssue(node ast.Node, issue models.Issue) {
+	position := v.fset.Position(node.Pos())
+
+	issue.File = v.filename
+	issue.Line = position.Line
+	issue.Column = position.Column
+	issue.Function = v.currentFunc
+	issue.CodeSnippet = position.String()
+
+	v.issues = append(v.issues, issue)
+}
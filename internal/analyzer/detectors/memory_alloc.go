@@ -4,16 +4,28 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
 	"gophercheck/internal/config"
 	"gophercheck/internal/context"
 	"gophercheck/internal/models"
-	"strings"
+	"gophercheck/internal/suggestions"
 )
 
 type MemoryAllocDetector struct {
 	config *config.Config
 }
 
+var _ Detector = (*MemoryAllocDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "memory_allocation",
+		Category: "memory",
+		Version:  "1.3.0",
+		New:      func(cfg *config.Config) Detector { return NewMemoryAllocDetectorWithConfig(cfg) },
+	})
+}
+
 func NewMemoryAllocDetector() *MemoryAllocDetector {
 	return &MemoryAllocDetector{}
 }
@@ -37,7 +49,6 @@ func (d *MemoryAllocDetector) Detect(file *ast.File, fset *token.FileSet, filena
 		fset:        fset,
 		filename:    filename,
 		issues:      make([]models.Issue, 0),
-		loopDepth:   0,
 		currentFunc: "",
 		detector:    d,
 		context:     ctx,
@@ -50,11 +61,20 @@ type memoryAllocVisitor struct {
 	fset        *token.FileSet
 	filename    string
 	issues      []models.Issue
-	loopDepth   int
 	currentFunc string
 	inLoop      bool
 	detector    *MemoryAllocDetector
 	context     *context.AnalysisContext
+
+	// Estimated iterations of each enclosing loop, outermost first
+	loopIterations []int
+
+	// Slice variables declared in the current function, and whether they
+	// were made with room to append into
+	slices map[string]bool
+
+	// Slices already reported for appends in the current outermost loop
+	reportedAppends map[string]bool
 }
 
 func (v *memoryAllocVisitor) Visit(node ast.Node) ast.Visitor {
@@ -63,9 +83,21 @@ func (v *memoryAllocVisitor) Visit(node ast.Node) ast.Visitor {
 		if n.Name != nil {
 			v.currentFunc = n.Name.Name
 		}
+		v.slices = make(map[string]bool)
 		return v
 	case *ast.ForStmt, *ast.RangeStmt:
-		v.loopDepth++
+		if isBenchmarkTimingLoop(v.context, n) {
+			// Measured benchmark code: visit the body as if it were not in a loop
+			for _, stmt := range getLoopBody(n) {
+				ast.Walk(v, stmt)
+			}
+			return nil
+		}
+
+		if len(v.loopIterations) == 0 {
+			v.reportedAppends = make(map[string]bool)
+		}
+		v.loopIterations = append(v.loopIterations, loopIterations(v.context, n))
 		oldInLoop := v.inLoop
 		v.inLoop = true
 
@@ -73,7 +105,7 @@ func (v *memoryAllocVisitor) Visit(node ast.Node) ast.Visitor {
 			ast.Walk(v, stmt)
 		}
 
-		v.loopDepth--
+		v.loopIterations = v.loopIterations[:len(v.loopIterations)-1]
 		v.inLoop = oldInLoop
 		return nil
 	case *ast.CallExpr:
@@ -83,10 +115,14 @@ func (v *memoryAllocVisitor) Visit(node ast.Node) ast.Visitor {
 		v.checkInefficientAllocation(n)
 		return v
 	case *ast.AssignStmt:
+		v.trackSliceDefinition(n)
 		if v.inLoop {
 			v.checkAppendWithoutPrealloc(n)
 		}
 		return v
+	case *ast.ValueSpec:
+		v.trackSliceDeclaration(n)
+		return v
 	default:
 		return v
 	}
@@ -104,7 +140,7 @@ func (v *memoryAllocVisitor) checkAllocationInLoop(call *ast.CallExpr) {
 
 	if v.isAllocationCall(call) {
 		allocType := v.getAllocationType(call)
-		v.createIssue(call, fmt.Sprintf("Memory allocation (%s) inside loop", allocType), v.generateLoopAllocationSuggestion(allocType), models.SeverityHigh)
+		v.createIssue(call, fmt.Sprintf("Memory allocation (%s) inside loop", allocType), suggestions.Render("memory_allocation.loop_alloc", suggestions.Data{Type: allocType}), models.SeverityHigh, 0.9)
 	}
 
 }
@@ -122,37 +158,99 @@ func (v *memoryAllocVisitor) checkInefficientAllocation(call *ast.CallExpr) {
 	if v.isMakeSliceWithoutCapacity(call) {
 		v.createIssue(call,
 			"Slice created without capacity hint - may cause multiple reallocations",
-			v.generateCapacitySuggestion(),
-			models.SeverityMedium)
+			suggestions.Render("memory_allocation.slice_capacity", suggestions.Data{}),
+			models.SeverityMedium, 0.7) // Length-only make may be intentional
 	}
 
 	if v.isMakeMapWithoutSize(call) {
 		v.createIssue(call,
 			"Map created without size hint - may cause rehashing",
-			v.generateMapSizeSuggestion(),
-			models.SeverityLow)
+			suggestions.Render("memory_allocation.map_size", suggestions.Data{}),
+			models.SeverityLow, 0.6) // Final size is often unknown
 	}
 }
 
+// checkAppendWithoutPrealloc reports the first append in a loop to a slice
+// the function declared without room to append into, once the enclosing
+// loops are estimated to run at least min_loop_iterations times
 func (v *memoryAllocVisitor) checkAppendWithoutPrealloc(assign *ast.AssignStmt) {
 	minLoopIterations := 5 // default
 	if v.detector.config != nil && v.detector.config.Rules.Memory.Allocation.Enabled {
 		minLoopIterations = v.detector.config.Rules.Memory.Allocation.MinLoopIterations
 	}
 
-	if v.loopDepth < minLoopIterations {
+	if len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok || !v.isAppendCall(call) {
+		return
+	}
+	ident, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return
+	}
+	preallocated, declared := v.slices[ident.Name]
+	if !declared || preallocated || v.reportedAppends[ident.Name] {
+		return
+	}
+	if !meetsEstimate(nestedIterations(v.loopIterations), minLoopIterations) {
+		return
+	}
+
+	v.reportedAppends[ident.Name] = true
+	v.createIssue(assign,
+		"append() in loop without preallocation - causes slice growth",
+		suggestions.Render("memory_allocation.append_in_loop", suggestions.Data{Var: ident.Name}),
+		models.SeverityMedium, 0.7)
+}
+
+// trackSliceDefinition records slices declared with :=
+func (v *memoryAllocVisitor) trackSliceDefinition(assign *ast.AssignStmt) {
+	if assign.Tok != token.DEFINE || len(assign.Lhs) != len(assign.Rhs) {
 		return
 	}
+	for i, lhs := range assign.Lhs {
+		if ident, ok := lhs.(*ast.Ident); ok {
+			v.trackSlice(ident, nil, assign.Rhs[i])
+		}
+	}
+}
 
-	if len(assign.Rhs) == 1 {
-		if call, ok := assign.Rhs[0].(*ast.CallExpr); ok {
-			if v.isAppendCall(call) && v.loopDepth > 0 {
-				v.createIssue(assign,
-					"append() in loop without preallocation - causes slice growth",
-					v.generateAppendSuggestion(),
-					models.SeverityMedium)
-			}
+// trackSliceDeclaration records slices declared with var
+func (v *memoryAllocVisitor) trackSliceDeclaration(spec *ast.ValueSpec) {
+	for i, name := range spec.Names {
+		var value ast.Expr
+		if i < len(spec.Values) {
+			value = spec.Values[i]
 		}
+		v.trackSlice(name, spec.Type, value)
+	}
+}
+
+// trackSlice records whether a new slice variable has room to append into:
+// a make with a capacity or non-zero length does, a nil slice or empty
+// literal doesn't. Other values aren't tracked.
+func (v *memoryAllocVisitor) trackSlice(name *ast.Ident, typ, value ast.Expr) {
+	if v.slices == nil {
+		return // Package-level variable
+	}
+	switch value := value.(type) {
+	case nil:
+		if typ != nil && v.isSliceType(typ) {
+			v.slices[name.Name] = false
+		}
+	case *ast.CompositeLit:
+		if value.Type != nil && v.isSliceType(value.Type) {
+			v.slices[name.Name] = false
+		}
+	case *ast.CallExpr:
+		if fun, ok := value.Fun.(*ast.Ident); ok && fun.Name == "make" && len(value.Args) >= 2 && v.isSliceType(value.Args[0]) {
+			lit, isLit := value.Args[1].(*ast.BasicLit)
+			v.slices[name.Name] = len(value.Args) == 3 || !isLit || lit.Value != "0"
+		}
+	default:
+		delete(v.slices, name.Name) // Redeclared as something unknown
 	}
 }
 
@@ -208,15 +306,11 @@ func (v *memoryAllocVisitor) isAppendCall(call *ast.CallExpr) bool {
 }
 
 func (v *memoryAllocVisitor) isSliceType(expr ast.Expr) bool {
-	// Simple heuristic - check if type looks like []T
-	typeStr := v.getTypeString(expr)
-	return strings.HasPrefix(typeStr, "[]")
+	return isSliceTypeExpr(v.context, expr)
 }
 
 func (v *memoryAllocVisitor) isMapType(expr ast.Expr) bool {
-	// Simple heuristic - check if type looks like map[K]V
-	typeStr := v.getTypeString(expr)
-	return strings.HasPrefix(typeStr, "map[")
+	return isMapTypeExpr(v.context, expr)
 }
 
 func (v *memoryAllocVisitor) getTypeString(expr ast.Expr) string {
@@ -233,6 +327,8 @@ func (v *memoryAllocVisitor) getTypeString(expr ast.Expr) string {
 		return t.Name
 	case *ast.SelectorExpr:
 		return v.getExprString(t.X) + "." + t.Sel.Name
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		return types.ExprString(t) // Instantiated generic type, e.g. Pair[K, V]
 	default:
 		return "unknown"
 	}
@@ -251,69 +347,8 @@ func (v *memoryAllocVisitor) getExprString(expr ast.Expr) string {
 	}
 }
 
-// Suggestion generators
-
-func (v *memoryAllocVisitor) generateLoopAllocationSuggestion(allocType string) string {
-	return fmt.Sprintf(`Move %s allocation outside the loop or reuse existing allocations:
-
-// Instead of:
-for i := 0; i < n; i++ {
-    slice := make([]T, size)  // Allocates each iteration
-    // use slice...
-}
-
-// Do this:
-slice := make([]T, size)  // Allocate once
-for i := 0; i < n; i++ {
-    slice = slice[:0]  // Reset length, keep capacity
-    // use slice...
-}
-
-Or consider using sync.Pool for frequent allocations.`, allocType)
-}
-
-func (v *memoryAllocVisitor) generateCapacitySuggestion() string {
-	return `Specify capacity when creating slices with known size:
-
-// Instead of:
-slice := make([]T, 0)  // Will grow as needed
-
-// Do this:
-slice := make([]T, 0, expectedSize)  // Pre-allocate capacity
-
-This prevents multiple memory allocations and copying during growth.`
-}
-
-func (v *memoryAllocVisitor) generateMapSizeSuggestion() string {
-	return `Specify initial size for maps when size is predictable:
-
-// Instead of:
-m := make(map[string]int)
-
-// Do this:
-m := make(map[string]int, expectedSize)
-
-This reduces hash table rehashing and improves performance.`
-}
-
-func (v *memoryAllocVisitor) generateAppendSuggestion() string {
-	return `Pre-allocate slice capacity to avoid growth in loops:
-
-// Instead of:
-var result []T
-for _, item := range items {
-    result = append(result, process(item))  // Grows each time
-}
-
-// Do this:
-result := make([]T, 0, len(items))  // Pre-allocate capacity
-for _, item := range items {
-    result = append(result, process(item))  // No reallocation
-}`
-}
-
 // createIssue creates a memory allocation issue
-func (v *memoryAllocVisitor) createIssue(node ast.Node, message, suggestion string, severity models.Severity) {
+func (v *memoryAllocVisitor) createIssue(node ast.Node, message, suggestion string, severity models.Severity, confidence float64) {
 	var pos token.Pos
 	switch n := node.(type) {
 	case *ast.CallExpr:
@@ -337,11 +372,34 @@ func (v *memoryAllocVisitor) createIssue(node ast.Node, message, suggestion stri
 		Suggestion:  suggestion,
 		Complexity:  v.getComplexityNote(severity),
 		CodeSnippet: position.String(),
+		Confidence:  confidence,
+		Impact:      v.getImpactNote(severity),
+		FixEffort:   v.getFixEffort(severity),
 	}
 
 	v.issues = append(v.issues, issue)
 }
 
+func (v *memoryAllocVisitor) getImpactNote(severity models.Severity) string {
+	switch severity {
+	case models.SeverityHigh:
+		return "1 allocation instead of one per iteration"
+	case models.SeverityMedium:
+		return "Avoids repeated reallocation and copying"
+	case models.SeverityLow:
+		return "Avoids rehashing as the map grows"
+	default:
+		return "Fewer allocations"
+	}
+}
+
+func (v *memoryAllocVisitor) getFixEffort(severity models.Severity) models.FixEffort {
+	if severity == models.SeverityHigh {
+		return models.EffortSmall // Hoisting may require restructuring the loop
+	}
+	return models.EffortTrivial
+}
+
 func (v *memoryAllocVisitor) getComplexityNote(severity models.Severity) string {
 	switch severity {
 	case models.SeverityHigh:
@@ -0,0 +1,261 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// MaxNesting returns the deepest nesting of control flow in a function body
+// and the line of the first statement at that depth. The bodies of ifs,
+// elses, loops, and case clauses are one level deeper than the statement
+// they belong to; function literals are measured on their own.
+func MaxNesting(fset *token.FileSet, body *ast.BlockStmt) (depth, line int) {
+	var walk func(list []ast.Stmt, nesting int)
+	visit := func(list []ast.Stmt, nesting int) {
+		if len(list) > 0 && nesting > depth {
+			depth, line = nesting, fset.Position(list[0].Pos()).Line
+		}
+		walk(list, nesting)
+	}
+	walk = func(list []ast.Stmt, nesting int) {
+		for _, stmt := range list {
+			if labeled, ok := stmt.(*ast.LabeledStmt); ok {
+				stmt = labeled.Stmt
+			}
+			switch s := stmt.(type) {
+			case *ast.IfStmt:
+				for s != nil {
+					visit(s.Body.List, nesting+1)
+					switch elseBranch := s.Else.(type) {
+					case *ast.IfStmt:
+						s = elseBranch
+					case *ast.BlockStmt:
+						visit(elseBranch.List, nesting+1)
+						s = nil
+					default:
+						s = nil
+					}
+				}
+			case *ast.ForStmt:
+				visit(s.Body.List, nesting+1)
+			case *ast.RangeStmt:
+				visit(s.Body.List, nesting+1)
+			case *ast.SwitchStmt:
+				visitClauses(s.Body, nesting, visit)
+			case *ast.TypeSwitchStmt:
+				visitClauses(s.Body, nesting, visit)
+			case *ast.SelectStmt:
+				visitClauses(s.Body, nesting, visit)
+			case *ast.BlockStmt:
+				walk(s.List, nesting)
+			}
+		}
+	}
+	walk(body.List, 0)
+	return depth, line
+}
+
+func visitClauses(body *ast.BlockStmt, nesting int, visit func([]ast.Stmt, int)) {
+	for _, clause := range body.List {
+		switch cl := clause.(type) {
+		case *ast.CaseClause:
+			visit(cl.Body, nesting+1)
+		case *ast.CommClause:
+			visit(cl.Body, nesting+1)
+		}
+	}
+}
+
+// NestingHint is an if statement that can be turned into an early return (or
+// continue) guard, taking a level of nesting off the code it wraps
+type NestingHint struct {
+	Line     int    // The if statement
+	Exit     string // How the inverted guard leaves: return, continue, or the else branch's own exit
+	Else     int    // Line of the else branch moved into the guard, or 0 for an if without one
+	Chained  []int  // Lines of the ifs directly inside that can then be inverted too
+	From, To int    // Lines of the code unnested
+}
+
+// Levels is how many levels of nesting inverting the if, and the ifs chained
+// inside it, takes off the innermost of the code it wraps
+func (h NestingHint) Levels() int {
+	return 1 + len(h.Chained)
+}
+
+// String phrases the hint, e.g. "Invert the condition at line 12 and return
+// early, then the one at line 15, to unnest up to 2 levels (lines 13-40)"
+func (h NestingHint) String() string {
+	var b strings.Builder
+	if h.Else != 0 {
+		fmt.Fprintf(&b, "Invert the condition at line %d and move the else branch (line %d) up into it to %s early", h.Line, h.Else, h.Exit)
+	} else {
+		fmt.Fprintf(&b, "Invert the condition at line %d and %s early", h.Line, h.Exit)
+	}
+	switch len(h.Chained) {
+	case 0:
+		fmt.Fprintf(&b, ", to unnest 1 level (lines %d-%d)", h.From, h.To)
+	case 1:
+		fmt.Fprintf(&b, ", then the one at line %d, to unnest up to 2 levels (lines %d-%d)", h.Chained[0], h.From, h.To)
+	default:
+		lines := make([]string, len(h.Chained))
+		for i, line := range h.Chained {
+			lines[i] = fmt.Sprint(line)
+		}
+		fmt.Fprintf(&b, ", then the ones at lines %s, to unnest up to %d levels (lines %d-%d)",
+			strings.Join(lines, ", "), h.Levels(), h.From, h.To)
+	}
+	return b.String()
+}
+
+// EarlyReturnHints finds the ifs of a function body that can become guard
+// clauses: an if without an else that ends the function or a loop body, and
+// an if whose short else branch leaves (returns, continues, breaks, or
+// panics) while the if branch goes on. Only ifs wrapping at least minLines
+// lines are kept, the ones unnesting the most code first.
+func EarlyReturnHints(fset *token.FileSet, body *ast.BlockStmt, minLines int) []NestingHint {
+	var hints []NestingHint
+	var walk func(list []ast.Stmt, exit string)
+	walk = func(list []ast.Stmt, exit string) {
+		for i, stmt := range list {
+			if labeled, ok := stmt.(*ast.LabeledStmt); ok {
+				stmt = labeled.Stmt
+			}
+			switch s := stmt.(type) {
+			case *ast.IfStmt:
+				tail := i == len(list)-1 && exit != ""
+				if hint, ok := guardHint(fset, s, tail, exit); ok && hint.To-hint.From+1 >= minLines {
+					hints = append(hints, hint)
+				}
+				for branch := s; branch != nil; {
+					walk(branch.Body.List, "")
+					switch elseBranch := branch.Else.(type) {
+					case *ast.IfStmt:
+						branch = elseBranch
+					case *ast.BlockStmt:
+						walk(elseBranch.List, "")
+						branch = nil
+					default:
+						branch = nil
+					}
+				}
+			case *ast.ForStmt:
+				walk(s.Body.List, "continue")
+			case *ast.RangeStmt:
+				walk(s.Body.List, "continue")
+			case *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+				ast.Inspect(s, func(n ast.Node) bool {
+					switch clause := n.(type) {
+					case *ast.CaseClause:
+						walk(clause.Body, "")
+						return false
+					case *ast.CommClause:
+						walk(clause.Body, "")
+						return false
+					case *ast.FuncLit:
+						return false
+					}
+					return true
+				})
+			case *ast.BlockStmt:
+				walk(s.List, "")
+			}
+		}
+	}
+	walk(body.List, "return")
+
+	slices.SortStableFunc(hints, func(x, y NestingHint) int {
+		return (y.To - y.From) - (x.To - x.From)
+	})
+	return hints
+}
+
+// guardHint matches an if that can be inverted into a guard clause. tail
+// says whether the if is the last statement of a block that exit leaves.
+func guardHint(fset *token.FileSet, s *ast.IfStmt, tail bool, exit string) (NestingHint, bool) {
+	if len(s.Body.List) == 0 {
+		return NestingHint{}, false
+	}
+	hint := NestingHint{
+		Line: fset.Position(s.Pos()).Line,
+		From: fset.Position(s.Body.List[0].Pos()).Line,
+		To:   fset.Position(s.Body.List[len(s.Body.List)-1].End()).Line,
+	}
+
+	switch elseBranch := s.Else.(type) {
+	case nil:
+		if !tail {
+			return NestingHint{}, false
+		}
+		hint.Exit = exit
+	case *ast.BlockStmt:
+		// The else must be the short way out, so the if branch is what stays
+		leaves := terminatingExit(elseBranch.List)
+		if leaves == "" || len(elseBranch.List) > 3 || len(elseBranch.List) >= len(s.Body.List) {
+			return NestingHint{}, false
+		}
+		hint.Exit, hint.Else = leaves, fset.Position(elseBranch.Pos()).Line
+		if !tail {
+			return hint, true
+		}
+	default:
+		return NestingHint{}, false // else if
+	}
+
+	// Once unnested, an if ending the body ends the enclosing block in turn
+	for inner := s; ; {
+		last, ok := inner.Body.List[len(inner.Body.List)-1].(*ast.IfStmt)
+		if !ok || last.Else != nil || len(last.Body.List) == 0 {
+			break
+		}
+		hint.Chained = append(hint.Chained, fset.Position(last.Pos()).Line)
+		inner = last
+	}
+	return hint, true
+}
+
+// terminatingExit returns how a statement list leaves its enclosing block
+// (return, continue, break, or panic) when it always does, or ""
+func terminatingExit(list []ast.Stmt) string {
+	if len(list) == 0 {
+		return ""
+	}
+	switch last := list[len(list)-1].(type) {
+	case *ast.ReturnStmt:
+		return "return"
+	case *ast.BranchStmt:
+		if last.Tok == token.CONTINUE || last.Tok == token.BREAK {
+			return last.Tok.String()
+		}
+	case *ast.ExprStmt:
+		if call, ok := last.X.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "panic" {
+				return "panic"
+			}
+		}
+	}
+	return ""
+}
+
+// nestingSummary describes a function's nesting and the guard clauses that
+// would flatten it, for complexity and length suggestions; "" when there is
+// nothing specific to say
+func nestingSummary(fset *token.FileSet, body *ast.BlockStmt) string {
+	hints := EarlyReturnHints(fset, body, 5)
+	if len(hints) == 0 {
+		return ""
+	}
+	depth, line := MaxNesting(fset, body)
+	var b strings.Builder
+	fmt.Fprintf(&b, "Nesting reaches %d levels (line %d). Flatten it with guard clauses:\n", depth, line)
+	for i, hint := range hints {
+		if i == 3 {
+			break
+		}
+		fmt.Fprintf(&b, "• %s\n", hint)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
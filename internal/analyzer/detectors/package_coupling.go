@@ -0,0 +1,168 @@
+package detectors
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+	"math"
+	"slices"
+	"strings"
+)
+
+// PackageCouplingDetector flags hub packages: packages many analyzed
+// packages import that import many themselves. It measures the import graph
+// of the import_cycles rule, with the same excluded packages.
+type PackageCouplingDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*PackageCouplingDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "package_coupling",
+		Category: "quality",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewPackageCouplingDetectorWithConfig(cfg) },
+	})
+}
+
+func NewPackageCouplingDetector() *PackageCouplingDetector {
+	return &PackageCouplingDetector{}
+}
+
+func NewPackageCouplingDetectorWithConfig(cfg *config.Config) *PackageCouplingDetector {
+	return &PackageCouplingDetector{config: cfg}
+}
+
+func (d *PackageCouplingDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *PackageCouplingDetector) Name() string {
+	return "Package Coupling Detector"
+}
+
+func (d *PackageCouplingDetector) settings() config.PackageCouplingConfig {
+	if d.config == nil {
+		return config.DefaultConfig().Rules.Quality.PackageCoupling
+	}
+	return d.config.Rules.Quality.PackageCoupling
+}
+
+// packageCoupling is a package's place in the import graph
+type packageCoupling struct {
+	models.PackageMetrics
+	importers, imports []string
+	file               string // First file importing another analyzed package
+}
+
+func (d *PackageCouplingDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	if ctx == nil || len(ctx.Imports) == 0 || strings.HasSuffix(file.Name.Name, "_test") {
+		return nil
+	}
+
+	coupling, ok := measureCoupling(d.config, ctx)[ctx.PackagePath(filename)]
+	// The package's first importing file reports it, so it is reported once
+	if !ok || !coupling.Hub || coupling.file != filename {
+		return nil
+	}
+	return []models.Issue{d.createHubIssue(coupling, fset.Position(file.Package).Line, filename)}
+}
+
+// PackageCoupling measures every analyzed package in the import graph,
+// ordered by package path. External test packages are left out.
+func PackageCoupling(cfg *config.Config, ctx *context.AnalysisContext) []models.PackageMetrics {
+	coupling := measureCoupling(cfg, ctx)
+	metrics := make([]models.PackageMetrics, 0, len(coupling))
+	for _, c := range coupling {
+		metrics = append(metrics, c.PackageMetrics)
+	}
+	slices.SortFunc(metrics, func(x, y models.PackageMetrics) int {
+		return cmp.Compare(x.Package, y.Package)
+	})
+	return metrics
+}
+
+func measureCoupling(cfg *config.Config, ctx *context.AnalysisContext) map[string]*packageCoupling {
+	settings := (&PackageCouplingDetector{config: cfg}).settings()
+
+	coupling := make(map[string]*packageCoupling)
+	measured := func(pkg string) *packageCoupling {
+		c, ok := coupling[pkg]
+		if !ok {
+			c = &packageCoupling{PackageMetrics: models.PackageMetrics{Package: pkg}}
+			coupling[pkg] = c
+		}
+		return c
+	}
+
+	for pkg, edges := range NewImportCycleDetectorWithConfig(cfg).importGraph(ctx) {
+		if strings.HasSuffix(pkg, "_test") {
+			continue // External test packages
+		}
+		from := measured(pkg)
+		for _, edge := range edges {
+			if from.file == "" || edge.File < from.file {
+				from.file = edge.File
+			}
+			// Edges are sorted by imported package
+			if n := len(from.imports); n > 0 && from.imports[n-1] == edge.to {
+				continue
+			}
+			from.imports = append(from.imports, edge.to)
+			to := measured(edge.to)
+			to.importers = append(to.importers, pkg)
+		}
+	}
+
+	for _, c := range coupling {
+		slices.Sort(c.importers)
+		c.FanIn, c.FanOut = len(c.importers), len(c.imports)
+		if total := c.FanIn + c.FanOut; total > 0 {
+			c.Instability = math.Round(float64(c.FanOut)/float64(total)*100) / 100
+		}
+		c.Hub = c.FanIn >= settings.HubFanIn && c.FanOut >= settings.HubFanOut
+	}
+	return coupling
+}
+
+func (d *PackageCouplingDetector) createHubIssue(c *packageCoupling, line int, filename string) models.Issue {
+	settings := d.settings()
+	severity := models.SeverityMedium
+	if c.FanIn >= 2*settings.HubFanIn && c.FanOut >= 2*settings.HubFanOut {
+		severity = models.SeverityHigh
+	}
+
+	suggestion := suggestions.Render("hub_package.split", suggestions.Data{})
+	suggestion += fmt.Sprintf("\n\nImported by: %s\nImports: %s", listPackages(c.importers), listPackages(c.imports))
+
+	return models.Issue{
+		Type:        models.IssueHubPackage,
+		Severity:    severity,
+		File:        filename,
+		Line:        line,
+		Column:      1,
+		Message:     fmt.Sprintf("Package %s is a hub: imported by %d packages while importing %d", c.Package, c.FanIn, c.FanOut),
+		Suggestion:  suggestion,
+		Complexity:  fmt.Sprintf("Fan-in: %d, fan-out: %d, instability: %.2f", c.FanIn, c.FanOut, c.Instability),
+		CodeSnippet: fmt.Sprintf("package %s", c.Package),
+		Confidence:  0.9,
+		Impact:      fmt.Sprintf("Changes to its %d imports stop rippling into %d packages", c.FanOut, c.FanIn),
+		FixEffort:   models.EffortLarge,
+	}
+}
+
+// listPackages names the first packages of a list, and how many more there are
+func listPackages(packages []string) string {
+	const shown = 5
+	if len(packages) <= shown {
+		return strings.Join(packages, ", ")
+	}
+	return fmt.Sprintf("%s, and %d more", strings.Join(packages[:shown], ", "), len(packages)-shown)
+}
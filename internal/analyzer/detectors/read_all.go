@@ -0,0 +1,306 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// lineSplitters split a whole input into lines or fields, by import path
+var lineSplitters = map[string]map[string]bool{
+	"strings": {"Split": true, "SplitAfter": true, "SplitSeq": true, "Lines": true, "Fields": true, "FieldsSeq": true},
+	"bytes":   {"Split": true, "SplitAfter": true, "SplitSeq": true, "Lines": true, "Fields": true, "FieldsSeq": true},
+}
+
+// readerWrappers turn a buffer back into a stream, by import path
+var readerWrappers = map[string]map[string]bool{
+	"strings": {"NewReader": true},
+	"bytes":   {"NewReader": true, "NewBuffer": true, "NewBufferString": true},
+}
+
+// ReadAllDetector finds whole inputs read into memory with io.ReadAll or
+// os.ReadFile that are then only scanned front to back, which a bufio.Scanner
+// does in constant memory
+type ReadAllDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*ReadAllDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "read_all",
+		Category: "memory",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewReadAllDetectorWithConfig(cfg) },
+	})
+}
+
+func NewReadAllDetector() *ReadAllDetector {
+	return &ReadAllDetector{}
+}
+
+func NewReadAllDetectorWithConfig(cfg *config.Config) *ReadAllDetector {
+	return &ReadAllDetector{
+		config: cfg,
+	}
+}
+
+func (d *ReadAllDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *ReadAllDetector) Name() string {
+	return "Read All Detector"
+}
+
+func (d *ReadAllDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &readAllVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		detector: d,
+		context:  ctx,
+		settings: d.settings(),
+	}
+
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+			detector.checkFunction(fn)
+		}
+	}
+	return detector.issues
+}
+
+// settings returns the rule config, or the defaults without one
+func (d *ReadAllDetector) settings() config.ReadAllConfig {
+	if d.config != nil && d.config.Rules.Memory.ReadAll.Enabled {
+		return d.config.Rules.Memory.ReadAll
+	}
+	return config.DefaultConfig().Rules.Memory.ReadAll
+}
+
+type readAllVisitor struct {
+	fset     *token.FileSet
+	filename string
+	issues   []models.Issue
+	detector *ReadAllDetector
+	context  *context.AnalysisContext
+	settings config.ReadAllConfig
+
+	// Per function
+	currentFunc string
+	handler     bool
+	parents     map[ast.Node]ast.Node
+	uses        map[any][]*ast.Ident // Variable reads by varKey
+	loops       []ast.Node           // Loops in the function, outermost first
+	scanned     bool                 // A use found while checking actually reads the data
+}
+
+func (v *readAllVisitor) checkFunction(fn *ast.FuncDecl) {
+	v.currentFunc = fn.Name.Name
+	v.handler = isHTTPHandler(v.context, fn.Type)
+	v.parents = make(map[ast.Node]ast.Node)
+	v.uses = make(map[any][]*ast.Ident)
+	v.loops = nil
+
+	var stack []ast.Node
+	var reads []*ast.AssignStmt
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return false
+		}
+		if len(stack) > 0 {
+			v.parents[n] = stack[len(stack)-1]
+		}
+		stack = append(stack, n)
+
+		switch node := n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			if !isBenchmarkTimingLoop(v.context, node) {
+				v.loops = append(v.loops, node)
+			}
+		case *ast.AssignStmt:
+			if len(node.Rhs) == 1 {
+				if call, ok := node.Rhs[0].(*ast.CallExpr); ok {
+					if _, ok := matchCall(v.context, call, v.settings.Sources); ok {
+						reads = append(reads, node)
+					}
+				}
+			}
+		case *ast.Ident:
+			if !v.isDefinition(node) {
+				key := varKey(v.context, node)
+				v.uses[key] = append(v.uses[key], node)
+			}
+		}
+		return true
+	})
+
+	for _, read := range reads {
+		target, ok := read.Lhs[0].(*ast.Ident)
+		if !ok || target.Name == "_" {
+			continue
+		}
+		v.scanned = false
+		if v.usesAreLinear(target, false, 0) && v.scanned {
+			source, _ := matchCall(v.context, read.Rhs[0].(*ast.CallExpr), v.settings.Sources)
+			v.createIssue(read, target.Name, source)
+		}
+	}
+}
+
+// isDefinition reports identifiers that declare rather than read a variable,
+// and field or method names after a dot
+func (v *readAllVisitor) isDefinition(ident *ast.Ident) bool {
+	switch parent := v.parents[ident].(type) {
+	case *ast.SelectorExpr:
+		return parent.Sel == ident
+	case *ast.AssignStmt:
+		if parent.Tok != token.DEFINE {
+			return false
+		}
+		for _, lhs := range parent.Lhs {
+			if lhs == ident {
+				return true
+			}
+		}
+	case *ast.ValueSpec:
+		for _, name := range parent.Names {
+			if name == ident {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// usesAreLinear reports whether every read of a variable scans it front to
+// back. elements is set for the result of a split, whose items may also be
+// indexed.
+func (v *readAllVisitor) usesAreLinear(ident *ast.Ident, elements bool, depth int) bool {
+	uses := v.uses[varKey(v.context, ident)]
+	if len(uses) == 0 || depth > 4 {
+		return false
+	}
+	for _, use := range uses {
+		if !v.isLinear(use, elements, depth) {
+			return false
+		}
+	}
+	return true
+}
+
+// isLinear reports whether expr is only used to read the data in order:
+// ranged over, split into lines, or wrapped back into a reader
+func (v *readAllVisitor) isLinear(expr ast.Expr, elements bool, depth int) bool {
+	switch parent := v.parents[expr].(type) {
+	case *ast.ParenExpr:
+		return v.isLinear(parent, elements, depth)
+
+	case *ast.RangeStmt:
+		if parent.X != expr {
+			return false
+		}
+		v.scanned = true
+		return true
+
+	case *ast.IndexExpr:
+		return elements && parent.X == expr
+
+	case *ast.CallExpr:
+		if parent.Fun == expr || len(parent.Args) == 0 || parent.Args[0] != expr {
+			return false
+		}
+		switch fun := parent.Fun.(type) {
+		case *ast.Ident:
+			if fun.Name == "len" {
+				return true
+			}
+			if fun.Name == "string" && !elements {
+				return v.isLinear(parent, false, depth)
+			}
+			return false
+		case *ast.ArrayType:
+			return !elements && v.isLinear(parent, false, depth) // []byte(s)
+		}
+		pkg, name, ok := packageFunc(v.context, parent)
+		if !ok || elements {
+			return false
+		}
+		if readerWrappers[pkg][name] {
+			v.scanned = true
+			return true
+		}
+		if lineSplitters[pkg][name] {
+			v.scanned = true
+			return v.isLinear(parent, true, depth)
+		}
+		return false
+
+	case *ast.AssignStmt:
+		// lines := strings.Split(string(data), "\n") passes the question on
+		if parent.Tok != token.DEFINE || len(parent.Lhs) != 1 || len(parent.Rhs) != 1 || parent.Rhs[0] != expr {
+			return false
+		}
+		target, ok := parent.Lhs[0].(*ast.Ident)
+		return ok && v.usesAreLinear(target, elements, depth+1)
+	}
+	return false
+}
+
+func (v *readAllVisitor) createIssue(read *ast.AssignStmt, name, source string) {
+	position := v.fset.Position(read.Pos())
+
+	inLoop := false
+	for _, loop := range v.loops {
+		if loop.Pos() < read.Pos() && read.End() <= loop.End() {
+			inLoop = true
+			break
+		}
+	}
+	handler := v.handler && v.settings.EscalateInHandlers
+
+	severity := models.SeverityLow
+	where := ""
+	switch {
+	case inLoop && handler:
+		severity = models.SeverityHigh
+		where = " in a loop of an HTTP handler"
+	case inLoop:
+		severity = models.SeverityMedium
+		where = " in a loop"
+	case handler:
+		severity = models.SeverityMedium
+		where = " in an HTTP handler"
+	}
+
+	entry := "read_all.scan_reader"
+	if source == "os.ReadFile" || source == "io/ioutil.ReadFile" {
+		entry = "read_all.scan_file"
+	}
+
+	issue := models.Issue{
+		Type:        models.IssueReadAll,
+		Severity:    severity,
+		File:        v.filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Function:    v.currentFunc,
+		Message:     fmt.Sprintf("%s reads the whole input into %s%s, which is then only scanned once - streaming needs constant memory", source, name, where),
+		Suggestion:  suggestions.Render(entry, suggestions.Data{Var: name, Type: source}),
+		Complexity:  "O(input size) memory where O(line length) suffices",
+		CodeSnippet: position.String(),
+		Confidence:  0.65, // Inputs may be known to be small
+		Impact:      "Peak memory no longer grows with input size",
+		FixEffort:   models.EffortSmall,
+	}
+
+	v.issues = append(v.issues, issue)
+}
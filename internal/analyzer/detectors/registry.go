@@ -0,0 +1,53 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+)
+
+// Detector finds one kind of issue in a parsed file
+type Detector interface {
+	Name() string
+	Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue
+}
+
+// Registration describes a detector to the analyzer. Each detector registers
+// itself from an init function in its own file, so a new detector can't be
+// written without being wired in.
+type Registration struct {
+	Rule     string // Config rule name, as accepted by IsRuleEnabled
+	Category string // Config rules section: performance, complexity, memory, quality, or concurrency
+	Version  string // Bumped whenever the detector's findings change
+	New      func(cfg *config.Config) Detector
+
+	// Safety of the detector's suggested fixes, for fixes that don't set
+	// their own. Detectors without fixes leave it empty, which counts as
+	// unsafe.
+	FixSafety models.FixSafety
+}
+
+var registry []Registration
+
+// Register adds a detector to the registry. Registering a rule name twice, or
+// one that is still listed as deprecated, is a programming error and panics.
+func Register(registration Registration) {
+	if _, deprecated := config.LookupDeprecatedRule(registration.Rule); deprecated {
+		panic(fmt.Sprintf("detectors: rule %s is deprecated and can't be registered", registration.Rule))
+	}
+	for _, existing := range registry {
+		if existing.Rule == registration.Rule {
+			panic(fmt.Sprintf("detectors: rule %s registered twice", registration.Rule))
+		}
+	}
+	registry = append(registry, registration)
+}
+
+// Registered returns every registered detector in registration order
+func Registered() []Registration {
+	return append([]Registration(nil), registry...)
+}
@@ -7,12 +7,24 @@ import (
 	"gophercheck/internal/config"
 	"gophercheck/internal/context"
 	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
 )
 
 type SliceGrowthDetector struct {
 	config *config.Config
 }
 
+var _ Detector = (*SliceGrowthDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "slice_growth",
+		Category: "memory",
+		Version:  "1.3.0",
+		New:      func(cfg *config.Config) Detector { return NewSliceGrowthDetectorWithConfig(cfg) },
+	})
+}
+
 func NewSliceGrowthDetector() *SliceGrowthDetector {
 	return &SliceGrowthDetector{}
 }
@@ -46,12 +58,27 @@ func (d *SliceGrowthDetector) Detect(file *ast.File, fset *token.FileSet, filena
 	return detector.issues
 }
 
+// sliceInfo is a slice variable declared in the current function
 type sliceInfo struct {
 	name         string
 	declaredLine int
-	hasCapacity  bool
-	usedInLoop   bool
-	appendCount  int
+	hasCapacity  bool // Made with a capacity or non-zero length
+	loopDepth    int  // Loops enclosing the declaration
+}
+
+// growthLoop is a loop being walked, with the appends to slices declared
+// outside it, when it is the outermost loop the slice's declaration doesn't
+// share
+type growthLoop struct {
+	iterations int // Estimated iterations per entry, or unknownIterations
+	appends    map[*sliceInfo]*appendSite
+	order      []*sliceInfo // Slices in order of first append
+}
+
+// appendSite counts the estimated appends to one slice in one loop
+type appendSite struct {
+	first    *ast.AssignStmt
+	estimate int // Estimated appends per run of the loop, or unknownIterations
 }
 
 type sliceGrowthVisitor struct {
@@ -60,8 +87,7 @@ type sliceGrowthVisitor struct {
 	issues      []models.Issue
 	sliceVars   map[string]*sliceInfo
 	currentFunc string
-	inLoop      bool
-	loopDepth   int
+	loops       []*growthLoop
 	detector    *SliceGrowthDetector
 	context     *context.AnalysisContext
 }
@@ -77,22 +103,27 @@ func (v *sliceGrowthVisitor) Visit(node ast.Node) ast.Visitor {
 		return v
 
 	case *ast.ForStmt, *ast.RangeStmt:
-		v.loopDepth++
-		oldInLoop := v.inLoop
-		v.inLoop = true
+		if isBenchmarkTimingLoop(v.context, n) {
+			// Measured benchmark code: visit the body as if it were not in a loop
+			for _, stmt := range getLoopBody(n) {
+				ast.Walk(v, stmt)
+			}
+			return nil
+		}
 
-		// Mark existing slices as used in loop
-		for _, info := range v.sliceVars {
-			info.usedInLoop = true
+		loop := &growthLoop{
+			iterations: loopIterations(v.context, n),
+			appends:    make(map[*sliceInfo]*appendSite),
 		}
+		v.loops = append(v.loops, loop)
 
 		// Visit loop body
 		for _, stmt := range getLoopBody(n) {
 			ast.Walk(v, stmt)
 		}
 
-		v.loopDepth--
-		v.inLoop = oldInLoop
+		v.loops = v.loops[:len(v.loops)-1]
+		v.reportAppends(loop)
 		return nil
 
 	case *ast.AssignStmt:
@@ -119,50 +150,43 @@ func (v *sliceGrowthVisitor) checkSliceDeclaration(decl *ast.GenDecl) {
 	}
 
 	for _, spec := range decl.Specs {
-		if valueSpec, ok := spec.(*ast.ValueSpec); ok {
-			for i, name := range valueSpec.Names {
-				if i < len(valueSpec.Values) {
-					if v.isSliceMake(valueSpec.Values[i]) {
-						position := v.fset.Position(name.Pos())
-						hasCapacity := v.sliceMakeHasCapacity(valueSpec.Values[i])
-
-						v.sliceVars[name.Name] = &sliceInfo{
-							name:         name.Name,
-							declaredLine: position.Line,
-							hasCapacity:  hasCapacity,
-							usedInLoop:   false,
-							appendCount:  0,
-						}
-
-						if requireCapacity && !hasCapacity {
-							v.createSliceGrowthIssue(name, "Slice declared without capacity hint")
-						}
-					}
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		for i, name := range valueSpec.Names {
+			if i >= len(valueSpec.Values) {
+				// var s []T starts out nil, with no capacity
+				if valueSpec.Type != nil && isSliceTypeExpr(v.context, valueSpec.Type) {
+					v.declareSlice(name, false)
 				}
+				continue
+			}
+			if !v.isSliceMake(valueSpec.Values[i]) {
+				continue
+			}
+			hasCapacity := v.sliceMakeHasCapacity(valueSpec.Values[i])
+			v.declareSlice(name, v.sliceMakePreallocates(valueSpec.Values[i]))
+			if requireCapacity && !hasCapacity {
+				v.createSliceGrowthIssue(name, "Slice declared without capacity hint")
 			}
 		}
 	}
 }
 
 func (v *sliceGrowthVisitor) checkSliceAssignment(assign *ast.AssignStmt) {
-	// Check for slice := make([]T, 0) patterns
+	// Check for slice := make([]T, 0) and slice := []T{} patterns
 	if assign.Tok == token.DEFINE && len(assign.Lhs) == 1 && len(assign.Rhs) == 1 {
 		if ident, ok := assign.Lhs[0].(*ast.Ident); ok {
 			if v.isSliceMake(assign.Rhs[0]) {
-				position := v.fset.Position(ident.Pos())
 				hasCapacity := v.sliceMakeHasCapacity(assign.Rhs[0])
+				v.declareSlice(ident, v.sliceMakePreallocates(assign.Rhs[0]))
 
-				v.sliceVars[ident.Name] = &sliceInfo{
-					name:         ident.Name,
-					declaredLine: position.Line,
-					hasCapacity:  hasCapacity,
-					usedInLoop:   v.inLoop,
-					appendCount:  0,
-				}
-
-				if !hasCapacity && v.inLoop {
+				if !hasCapacity && len(v.loops) > 0 {
 					v.createSliceGrowthIssue(ident, "Slice created in loop without capacity")
 				}
+			} else if lit, ok := assign.Rhs[0].(*ast.CompositeLit); ok && lit.Type != nil && isSliceTypeExpr(v.context, lit.Type) {
+				v.declareSlice(ident, false)
 			}
 		}
 	}
@@ -171,13 +195,62 @@ func (v *sliceGrowthVisitor) checkSliceAssignment(assign *ast.AssignStmt) {
 	if len(assign.Rhs) == 1 {
 		if call, ok := assign.Rhs[0].(*ast.CallExpr); ok {
 			if v.isAppendCall(call) {
-				v.trackAppendUsage(assign)
+				v.trackAppendUsage(assign, call)
 			}
 		}
 	}
 }
 
-func (v *sliceGrowthVisitor) trackAppendUsage(assign *ast.AssignStmt) {
+func (v *sliceGrowthVisitor) declareSlice(name *ast.Ident, hasCapacity bool) {
+	v.sliceVars[name.Name] = &sliceInfo{
+		name:         name.Name,
+		declaredLine: v.fset.Position(name.Pos()).Line,
+		hasCapacity:  hasCapacity,
+		loopDepth:    len(v.loops),
+	}
+}
+
+// trackAppendUsage adds an append to a slice without capacity to the
+// estimate of the outermost loop the slice's declaration is outside of. The
+// estimate is the number of appended elements times the iterations of every
+// loop from there to the append.
+func (v *sliceGrowthVisitor) trackAppendUsage(assign *ast.AssignStmt, call *ast.CallExpr) {
+	if len(assign.Lhs) == 0 {
+		return
+	}
+	ident, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return
+	}
+	info, exists := v.sliceVars[ident.Name]
+	if !exists || info.hasCapacity || len(v.loops) <= info.loopDepth {
+		return
+	}
+
+	iterations := make([]int, 0, len(v.loops)-info.loopDepth)
+	for _, loop := range v.loops[info.loopDepth:] {
+		iterations = append(iterations, loop.iterations)
+	}
+	elements := max(len(call.Args)-1, 1)
+	estimate := nestedIterations(append(iterations, elements))
+
+	loop := v.loops[info.loopDepth]
+	site, ok := loop.appends[info]
+	if !ok {
+		site = &appendSite{first: assign}
+		loop.appends[info] = site
+		loop.order = append(loop.order, info)
+	}
+	if site.estimate == unknownIterations || estimate == unknownIterations {
+		site.estimate = unknownIterations
+	} else {
+		site.estimate += estimate
+	}
+}
+
+// reportAppends reports the slices a finished loop appends to at least
+// min_append_count times
+func (v *sliceGrowthVisitor) reportAppends(loop *growthLoop) {
 	detectAppendInLoops := true // default
 	minAppendCount := 3         // default
 
@@ -190,14 +263,15 @@ func (v *sliceGrowthVisitor) trackAppendUsage(assign *ast.AssignStmt) {
 		return
 	}
 
-	if len(assign.Lhs) > 0 {
-		if ident, ok := assign.Lhs[0].(*ast.Ident); ok {
-			if info, exists := v.sliceVars[ident.Name]; exists {
-				info.appendCount++
-				if v.inLoop && !info.hasCapacity && info.appendCount >= minAppendCount {
-					v.createAppendIssue(assign, fmt.Sprintf("Multiple appends (%d) to slice '%s' in loop without pre-allocation", info.appendCount, ident.Name))
-				}
-			}
+	for _, info := range loop.order {
+		site := loop.appends[info]
+		if !meetsEstimate(site.estimate, minAppendCount) {
+			continue
+		}
+		if site.estimate == unknownIterations {
+			v.createAppendIssue(site.first, fmt.Sprintf("Appends to slice '%s' in a loop of unknown length without pre-allocation", info.name), 0.7)
+		} else {
+			v.createAppendIssue(site.first, fmt.Sprintf("Multiple appends (~%d) to slice '%s' in loop without pre-allocation", site.estimate, info.name), 0.8)
 		}
 	}
 }
@@ -223,11 +297,23 @@ func (v *sliceGrowthVisitor) sliceMakeHasCapacity(expr ast.Expr) bool {
 	return false
 }
 
-func (v *sliceGrowthVisitor) isSliceType(expr ast.Expr) bool {
-	if arrayType, ok := expr.(*ast.ArrayType); ok {
-		return arrayType.Len == nil // slice if no length specified
+// sliceMakePreallocates reports whether a make call leaves room to append
+// into: it has a capacity, or a length that isn't zero, which the code may
+// truncate with s[:0] before appending
+func (v *sliceGrowthVisitor) sliceMakePreallocates(expr ast.Expr) bool {
+	if v.sliceMakeHasCapacity(expr) {
+		return true
 	}
-	return false
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 {
+		return false
+	}
+	lit, ok := call.Args[1].(*ast.BasicLit)
+	return !ok || lit.Value != "0"
+}
+
+func (v *sliceGrowthVisitor) isSliceType(expr ast.Expr) bool {
+	return isSliceTypeExpr(v.context, expr)
 }
 
 func (v *sliceGrowthVisitor) isAppendCall(call *ast.CallExpr) bool {
@@ -256,15 +342,18 @@ func (v *sliceGrowthVisitor) createSliceGrowthIssue(node ast.Node, message strin
 		Column:      position.Column,
 		Function:    v.currentFunc,
 		Message:     message + " - may cause multiple reallocations",
-		Suggestion:  v.generateSliceGrowthSuggestion(),
+		Suggestion:  suggestions.Render("slice_growth.capacity", suggestions.Data{Var: identName(node)}),
 		Complexity:  "O(n) amortized growth cost",
 		CodeSnippet: position.String(),
+		Confidence:  0.7, // Expected size may not be known at declaration
+		Impact:      "Avoids repeated reallocation and copying",
+		FixEffort:   models.EffortTrivial,
 	}
 
 	v.issues = append(v.issues, issue)
 }
 
-func (v *sliceGrowthVisitor) createAppendIssue(assign *ast.AssignStmt, message string) {
+func (v *sliceGrowthVisitor) createAppendIssue(assign *ast.AssignStmt, message string, confidence float64) {
 	position := v.fset.Position(assign.Pos())
 
 	issue := models.Issue{
@@ -275,43 +364,21 @@ func (v *sliceGrowthVisitor) createAppendIssue(assign *ast.AssignStmt, message s
 		Column:      position.Column,
 		Function:    v.currentFunc,
 		Message:     message,
-		Suggestion:  v.generateAppendInLoopSuggestion(),
+		Suggestion:  suggestions.Render("slice_growth.append_in_loop", suggestions.Data{Var: identName(assign.Lhs[0])}),
 		Complexity:  "O(n log n) due to slice growth",
 		CodeSnippet: position.String(),
+		Confidence:  confidence,
+		Impact:      "O(n log n)→O(n)",
+		FixEffort:   models.EffortTrivial,
 	}
 
 	v.issues = append(v.issues, issue)
 }
 
-func (v *sliceGrowthVisitor) generateSliceGrowthSuggestion() string {
-	return `Pre-allocate slice capacity when size is known or predictable:
-
-// Instead of:
-slice := make([]T, 0)  // Will grow as needed
-
-// Do this:
-slice := make([]T, 0, expectedSize)  // Pre-allocate capacity
-
-// Or if you know the exact size:
-slice := make([]T, expectedSize)  // Pre-allocate length and capacity
-
-This prevents multiple memory allocations and copying during growth.`
-}
-
-func (v *sliceGrowthVisitor) generateAppendInLoopSuggestion() string {
-	return `Pre-allocate slice capacity before loop to avoid repeated growth:
-
-// Instead of:
-var results []T
-for _, item := range items {
-    results = append(results, process(item))  // Grows each iteration
-}
-
-// Do this:
-results := make([]T, 0, len(items))  // Pre-allocate capacity
-for _, item := range items {
-    results = append(results, process(item))  // No reallocation needed
-}
-
-This changes complexity from O(n log n) to O(n).`
+// identName returns the name of an identifier node, or "" for anything else
+func identName(node ast.Node) string {
+	if ident, ok := node.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
 }
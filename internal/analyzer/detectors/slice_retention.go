@@ -0,0 +1,257 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// SliceRetentionDetector finds small subslices of large buffers that outlive
+// the function, which keep the whole backing array from being collected
+type SliceRetentionDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*SliceRetentionDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "slice_retention",
+		Category: "memory",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewSliceRetentionDetectorWithConfig(cfg) },
+	})
+}
+
+func NewSliceRetentionDetector() *SliceRetentionDetector {
+	return &SliceRetentionDetector{}
+}
+
+func NewSliceRetentionDetectorWithConfig(cfg *config.Config) *SliceRetentionDetector {
+	return &SliceRetentionDetector{
+		config: cfg,
+	}
+}
+
+func (d *SliceRetentionDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *SliceRetentionDetector) Name() string {
+	return "Slice Retention Detector"
+}
+
+func (d *SliceRetentionDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &sliceRetentionVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		context:  ctx,
+		settings: d.settings(),
+	}
+
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+			detector.checkFunction(fn)
+		}
+	}
+	return detector.issues
+}
+
+// settings returns the rule config, or the defaults without one
+func (d *SliceRetentionDetector) settings() config.SliceRetentionConfig {
+	if d.config != nil && d.config.Rules.Memory.SliceRetention.Enabled {
+		return d.config.Rules.Memory.SliceRetention
+	}
+	return config.DefaultConfig().Rules.Memory.SliceRetention
+}
+
+type sliceRetentionVisitor struct {
+	fset     *token.FileSet
+	filename string
+	issues   []models.Issue
+	context  *context.AnalysisContext
+	settings config.SliceRetentionConfig
+
+	// Per function: large buffers by variable, with where they came from
+	currentFunc string
+	buffers     map[any]string
+}
+
+func (v *sliceRetentionVisitor) checkFunction(fn *ast.FuncDecl) {
+	v.currentFunc = fn.Name.Name
+	v.buffers = make(map[any]string)
+
+	// Buffers are found in source order, so a later subslice sees them
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			v.trackBuffers(stmt)
+			v.checkStores(stmt)
+		case *ast.ReturnStmt:
+			for _, result := range stmt.Results {
+				if buffer, source, ok := v.retainedSubslice(result); ok {
+					v.createIssue(result, buffer, source, "returned")
+				}
+			}
+		}
+		return true
+	})
+}
+
+// trackBuffers records variables assigned a whole input, a large make, or a
+// string conversion of either
+func (v *sliceRetentionVisitor) trackBuffers(assign *ast.AssignStmt) {
+	for i, rhs := range assign.Rhs {
+		call, ok := rhs.(*ast.CallExpr)
+		if !ok || i >= len(assign.Lhs) {
+			continue
+		}
+		// data, err := os.ReadFile(...) has one call for two targets
+		target, ok := assign.Lhs[i].(*ast.Ident)
+		if !ok || target.Name == "_" {
+			continue
+		}
+
+		if source, ok := matchCall(v.context, call, v.settings.LargeSources); ok {
+			v.buffers[varKey(v.context, target)] = source
+			continue
+		}
+		if v.isLargeMake(call) {
+			v.buffers[varKey(v.context, target)] = "make"
+			continue
+		}
+		// s := string(data) copies the buffer into an equally large string
+		if fun, ok := call.Fun.(*ast.Ident); ok && fun.Name == "string" && len(call.Args) == 1 {
+			if arg, ok := call.Args[0].(*ast.Ident); ok {
+				if source, tracked := v.buffers[varKey(v.context, arg)]; tracked {
+					v.buffers[varKey(v.context, target)] = source
+				}
+			}
+		}
+	}
+}
+
+// checkStores reports subslices stored somewhere that outlives the function:
+// struct fields, map or slice elements, package variables, or appended to a
+// collection
+func (v *sliceRetentionVisitor) checkStores(assign *ast.AssignStmt) {
+	for i, rhs := range assign.Rhs {
+		if i >= len(assign.Lhs) {
+			break
+		}
+		lhs := assign.Lhs[i]
+
+		values := []ast.Expr{rhs}
+		if call, ok := rhs.(*ast.CallExpr); ok {
+			if fun, ok := call.Fun.(*ast.Ident); ok && fun.Name == "append" && len(call.Args) > 1 {
+				values = call.Args[1:]
+			} else {
+				continue
+			}
+		} else if !v.outlivesFunction(lhs) {
+			continue
+		}
+
+		for _, value := range values {
+			if buffer, source, ok := v.retainedSubslice(value); ok {
+				v.createIssue(value, buffer, source, "stored")
+			}
+		}
+	}
+}
+
+// outlivesFunction reports whether an assignment target is reachable after
+// the function returns
+func (v *sliceRetentionVisitor) outlivesFunction(lhs ast.Expr) bool {
+	switch target := lhs.(type) {
+	case *ast.SelectorExpr, *ast.IndexExpr, *ast.StarExpr:
+		return true
+	case *ast.Ident:
+		if v.context == nil || v.context.TypeInfo == nil {
+			return false
+		}
+		obj := v.context.TypeInfo.ObjectOf(target)
+		return obj != nil && obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope()
+	}
+	return false
+}
+
+// retainedSubslice reports whether expr is buf[i:j] (not the whole of buf)
+// for a tracked large buffer
+func (v *sliceRetentionVisitor) retainedSubslice(expr ast.Expr) (string, string, bool) {
+	slice, ok := expr.(*ast.SliceExpr)
+	if !ok || (slice.Low == nil && slice.High == nil) {
+		return "", "", false
+	}
+	ident, ok := slice.X.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+	source, tracked := v.buffers[varKey(v.context, ident)]
+	return ident.Name, source, tracked
+}
+
+func (v *sliceRetentionVisitor) isLargeMake(call *ast.CallExpr) bool {
+	fun, ok := call.Fun.(*ast.Ident)
+	if !ok || fun.Name != "make" || len(call.Args) < 2 {
+		return false
+	}
+	size := call.Args[len(call.Args)-1] // Capacity if given, else length
+	if v.context != nil && v.context.TypeInfo != nil {
+		if tv, ok := v.context.TypeInfo.Types[size]; ok && tv.Value != nil {
+			n, exact := constant.Int64Val(constant.ToInt(tv.Value))
+			return exact && n >= int64(v.settings.MinBufferBytes)
+		}
+	}
+	if lit, ok := size.(*ast.BasicLit); ok && lit.Kind == token.INT {
+		n, err := strconv.ParseInt(lit.Value, 0, 64)
+		return err == nil && n >= int64(v.settings.MinBufferBytes)
+	}
+	return false
+}
+
+func (v *sliceRetentionVisitor) createIssue(expr ast.Expr, buffer, source, how string) {
+	position := v.fset.Position(expr.Pos())
+	text := types.ExprString(expr)
+
+	cloneFunc := "bytes.Clone"
+	if v.context != nil && v.context.TypeInfo != nil {
+		if tv, ok := v.context.TypeInfo.Types[expr]; ok && tv.Type != nil {
+			if basic, ok := tv.Type.Underlying().(*types.Basic); ok && basic.Info()&types.IsString != 0 {
+				cloneFunc = "strings.Clone"
+			}
+		}
+	}
+
+	origin := fmt.Sprintf("the whole result of %s", source)
+	if source == "make" {
+		origin = "a large make"
+	}
+
+	issue := models.Issue{
+		Type:        models.IssueSliceRetention,
+		Severity:    models.SeverityMedium,
+		File:        v.filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Function:    v.currentFunc,
+		Message:     fmt.Sprintf("Subslice %s is %s but shares %s's backing array (%s) - the entire buffer stays in memory", text, how, buffer, origin),
+		Suggestion:  suggestions.Render("slice_retention.clone", suggestions.Data{Var: text, Type: cloneFunc}),
+		Complexity:  "Retains O(len(" + buffer + ")) bytes for O(len(subslice))",
+		CodeSnippet: position.String(),
+		Confidence:  0.7, // The buffer may be short-lived or small in practice
+		Impact:      "Frees the large buffer once the function returns",
+		FixEffort:   models.EffortTrivial,
+	}
+
+	v.issues = append(v.issues, issue)
+}
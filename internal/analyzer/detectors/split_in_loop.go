@@ -0,0 +1,246 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// splitFuncs are the splitting and joining functions checked in loops, by
+// import path, with what one call allocates
+var splitFuncs = map[string]map[string]string{
+	"strings": {
+		"Split":       "a []string of every field",
+		"SplitN":      "a []string of up to n fields",
+		"SplitAfter":  "a []string of every field",
+		"SplitAfterN": "a []string of up to n fields",
+		"Fields":      "a []string of every field",
+		"FieldsFunc":  "a []string of every field",
+		"Join":        "a new string copying every element",
+	},
+	"bytes": {
+		"Split":  "a [][]byte of every field",
+		"SplitN": "a [][]byte of up to n fields",
+		"Fields": "a [][]byte of every field",
+		"Join":   "a new []byte copying every element",
+	},
+}
+
+// SplitInLoopDetector finds strings.Split, Fields, and Join calls repeated in
+// loops on inputs that don't change, and splits where only one field is used
+type SplitInLoopDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*SplitInLoopDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "split_in_loop",
+		Category: "performance",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewSplitInLoopDetectorWithConfig(cfg) },
+	})
+}
+
+func NewSplitInLoopDetector() *SplitInLoopDetector {
+	return &SplitInLoopDetector{}
+}
+
+func NewSplitInLoopDetectorWithConfig(cfg *config.Config) *SplitInLoopDetector {
+	return &SplitInLoopDetector{
+		config: cfg,
+	}
+}
+
+func (d *SplitInLoopDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *SplitInLoopDetector) Name() string {
+	return "Split In Loop Detector"
+}
+
+func (d *SplitInLoopDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &splitInLoopVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		detector: d,
+		context:  ctx,
+		assigned: make(map[ast.Node]map[string]bool),
+		handled:  make(map[*ast.CallExpr]bool),
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+type splitInLoopVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	loops       []ast.Node                   // Enclosing loops, innermost last
+	assigned    map[ast.Node]map[string]bool // Variables each loop changes
+	handled     map[*ast.CallExpr]bool       // Calls already reported via an index expression
+	detector    *SplitInLoopDetector
+	context     *context.AnalysisContext
+}
+
+func (v *splitInLoopVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Name != nil {
+			v.currentFunc = n.Name.Name
+		}
+		return v
+
+	case *ast.ForStmt, *ast.RangeStmt:
+		if isBenchmarkTimingLoop(v.context, n) {
+			// Measured benchmark code: visit the body as if it were not in a loop
+			for _, stmt := range getLoopBody(n) {
+				ast.Walk(v, stmt)
+			}
+			return nil
+		}
+
+		v.loops = append(v.loops, n)
+		for _, stmt := range getLoopBody(n) {
+			ast.Walk(v, stmt)
+		}
+		v.loops = v.loops[:len(v.loops)-1]
+		return nil
+
+	case *ast.IndexExpr:
+		if len(v.loops) > 0 {
+			if call, ok := n.X.(*ast.CallExpr); ok {
+				v.checkIndexedSplit(n, call)
+			}
+		}
+		return v
+
+	case *ast.CallExpr:
+		if len(v.loops) > 0 && !v.handled[n] {
+			v.checkHoistable(n)
+		}
+		return v
+
+	default:
+		return v
+	}
+}
+
+// settings returns the rule config, or everything enabled without one
+func (v *splitInLoopVisitor) settings() config.SplitInLoopConfig {
+	if v.detector.config != nil && v.detector.config.Rules.Performance.SplitInLoop.Enabled {
+		return v.detector.config.Rules.Performance.SplitInLoop
+	}
+	return config.SplitInLoopConfig{
+		Enabled:        true,
+		DetectHoisting: true,
+		SuggestCut:     true,
+	}
+}
+
+// splitFunc returns the qualified name of a checked function and what it
+// allocates, e.g. "strings.Split"
+func (v *splitInLoopVisitor) splitFunc(call *ast.CallExpr) (string, string, bool) {
+	pkg, name, ok := packageFunc(v.context, call)
+	if !ok {
+		return "", "", false
+	}
+	allocates, ok := splitFuncs[pkg][name]
+	return pkg + "." + name, allocates, ok
+}
+
+// checkHoistable reports a call whose arguments are the same on every
+// iteration of the innermost loop
+func (v *splitInLoopVisitor) checkHoistable(call *ast.CallExpr) bool {
+	if !v.settings().DetectHoisting {
+		return false
+	}
+	funcName, allocates, ok := v.splitFunc(call)
+	if !ok {
+		return false
+	}
+
+	loop := v.loops[len(v.loops)-1]
+	assigned, ok := v.assigned[loop]
+	if !ok {
+		assigned = assignedInLoop(loop)
+		v.assigned[loop] = assigned
+	}
+	for _, arg := range call.Args {
+		if !isLoopInvariant(arg, assigned) {
+			return false
+		}
+	}
+
+	severity := models.SeverityMedium
+	if len(v.loops) >= 2 {
+		severity = models.SeverityHigh
+	}
+	v.addIssue(call, models.Issue{
+		Severity:   severity,
+		Message:    fmt.Sprintf("%s is called on every loop iteration with the same arguments", funcName),
+		Suggestion: suggestions.Render("split_in_loop.hoist", suggestions.Data{Var: types.ExprString(call), Type: funcName}),
+		Complexity: fmt.Sprintf("O(n) allocations: %s on every iteration, 1 in total when hoisted", allocates),
+		Confidence: 0.85,
+		Impact:     "O(n)→O(1) allocations",
+		FixEffort:  models.EffortTrivial,
+	})
+	return true
+}
+
+// checkIndexedSplit reports Split(...)[0] or [1] in a loop, which allocates
+// every field to read one of them
+func (v *splitInLoopVisitor) checkIndexedSplit(index *ast.IndexExpr, call *ast.CallExpr) {
+	funcName, _, ok := v.splitFunc(call)
+	if !ok || (funcName != "strings.Split" && funcName != "strings.SplitN") {
+		return
+	}
+
+	// Reported as hoistable, which makes the index free
+	if v.checkHoistable(call) {
+		v.handled[call] = true
+		return
+	}
+
+	lit, ok := index.Index.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT || (lit.Value != "0" && lit.Value != "1") || !v.settings().SuggestCut {
+		return
+	}
+	v.handled[call] = true
+
+	input := types.ExprString(call.Args[0])
+	v.addIssue(index, models.Issue{
+		Severity:   models.SeverityLow,
+		Message:    fmt.Sprintf("%s(%s, ...)[%s] in a loop allocates every field to use one", funcName, input, lit.Value),
+		Suggestion: suggestions.Render("split_in_loop.cut", suggestions.Data{Var: input}),
+		Complexity: "O(n) allocations: a []string per iteration where strings.Cut allocates nothing",
+		Confidence: 0.8,
+		Impact:     "Removes one allocation per iteration",
+		FixEffort:  models.EffortTrivial,
+	})
+}
+
+// addIssue fills in the type and location of issue and records it
+func (v *splitInLoopVisitor) addIssue(node ast.Node, issue models.Issue) {
+	position := v.fset.Position(node.Pos())
+
+	issue.Type = models.IssueSplitInLoop
+	issue.File = v.filename
+	issue.Line = position.Line
+	issue.Column = position.Column
+	issue.Function = v.currentFunc
+	issue.CodeSnippet = position.String()
+
+	v.issues = append(v.issues, issue)
+}
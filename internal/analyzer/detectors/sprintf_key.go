@@ -0,0 +1,302 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// keyFormatters are the fmt functions whose result is used as a map key
+var keyFormatters = map[string]bool{
+	"Sprintf": true, "Sprint": true, "Sprintln": true,
+}
+
+// SprintfKeyDetector finds composite map keys built with fmt.Sprintf for
+// every lookup, e.g. m[fmt.Sprintf("%d-%s", a, b)], which allocates and goes
+// through reflection where a struct key costs nothing
+type SprintfKeyDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*SprintfKeyDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "sprintf_key",
+		Category: "performance",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewSprintfKeyDetectorWithConfig(cfg) },
+	})
+}
+
+func NewSprintfKeyDetector() *SprintfKeyDetector {
+	return &SprintfKeyDetector{}
+}
+
+func NewSprintfKeyDetectorWithConfig(cfg *config.Config) *SprintfKeyDetector {
+	return &SprintfKeyDetector{
+		config: cfg,
+	}
+}
+
+func (d *SprintfKeyDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *SprintfKeyDetector) Name() string {
+	return "Sprintf Key Detector"
+}
+
+func (d *SprintfKeyDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &sprintfKeyVisitor{
+		fset:     fset,
+		filename: filename,
+		issues:   make([]models.Issue, 0),
+		detector: d,
+		context:  ctx,
+		settings: d.settings(),
+		pkgName:  file.Name.Name,
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+// settings returns the rule config, or the defaults without one
+func (d *SprintfKeyDetector) settings() config.SprintfKeyConfig {
+	if d.config != nil && d.config.Rules.Performance.SprintfKey.Enabled {
+		return d.config.Rules.Performance.SprintfKey
+	}
+	return config.DefaultConfig().Rules.Performance.SprintfKey
+}
+
+type sprintfKeyVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	pkgName     string
+	loops       []ast.Node // Enclosing loops, innermost last
+	detector    *SprintfKeyDetector
+	context     *context.AnalysisContext
+	settings    config.SprintfKeyConfig
+}
+
+func (v *sprintfKeyVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Name != nil {
+			v.currentFunc = n.Name.Name
+		}
+		return v
+
+	case *ast.ForStmt, *ast.RangeStmt:
+		if isBenchmarkTimingLoop(v.context, n) {
+			for _, stmt := range getLoopBody(n) {
+				ast.Walk(v, stmt)
+			}
+			return nil
+		}
+
+		v.loops = append(v.loops, n)
+		for _, stmt := range getLoopBody(n) {
+			ast.Walk(v, stmt)
+		}
+		v.loops = v.loops[:len(v.loops)-1]
+		return nil
+
+	case *ast.IndexExpr:
+		if call, ok := n.Index.(*ast.CallExpr); ok && v.inScope() {
+			if funcName, ok := v.keyFormatter(call); ok {
+				v.createIssue(n.Index, call, funcName, types.ExprString(n.X))
+			}
+		}
+		return v
+
+	case *ast.AssignStmt:
+		if v.settings.TrackKeyVariables && len(v.loops) > 0 {
+			v.checkKeyVariable(n)
+		}
+		return v
+
+	default:
+		return v
+	}
+}
+
+// inScope reports whether a key built here is checked: inside a loop, or
+// anywhere unless the config limits the rule to loops
+func (v *sprintfKeyVisitor) inScope() bool {
+	return len(v.loops) > 0 || !v.settings.OnlyInLoops
+}
+
+// keyFormatter returns e.g. "fmt.Sprintf" for calls formatting a string
+func (v *sprintfKeyVisitor) keyFormatter(call *ast.CallExpr) (string, bool) {
+	pkg, name, ok := packageFunc(v.context, call)
+	if !ok || pkg != "fmt" || !keyFormatters[name] || len(call.Args) == 0 {
+		return "", false
+	}
+	return "fmt." + name, true
+}
+
+// checkKeyVariable reports key := fmt.Sprintf(...) in a loop when the key is
+// only ever used to index maps
+func (v *sprintfKeyVisitor) checkKeyVariable(assign *ast.AssignStmt) {
+	if len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return
+	}
+	target, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok || target.Name == "_" {
+		return
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	funcName, ok := v.keyFormatter(call)
+	if !ok {
+		return
+	}
+
+	key := varKey(v.context, target)
+	loop := v.loops[len(v.loops)-1]
+	var maps []string
+	uses := 0
+	ast.Inspect(loop, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.IndexExpr:
+			if ident, ok := e.Index.(*ast.Ident); ok && ident != target && varKey(v.context, ident) == key {
+				maps = append(maps, types.ExprString(e.X))
+			}
+		case *ast.Ident:
+			if e != target && varKey(v.context, e) == key {
+				uses++
+			}
+		}
+		return true
+	})
+	if len(maps) == 0 || len(maps) != uses {
+		return // Also logged, returned, or stored as a string
+	}
+
+	v.createIssue(call, call, funcName, maps[0])
+}
+
+func (v *sprintfKeyVisitor) createIssue(node ast.Node, call *ast.CallExpr, funcName, mapName string) {
+	position := v.fset.Position(node.Pos())
+
+	severity := models.SeverityLow
+	where := ""
+	if len(v.loops) > 0 {
+		severity = models.SeverityMedium
+		where = " on every loop iteration"
+		if len(v.loops) >= 2 {
+			severity = models.SeverityHigh
+		} else if v.context != nil {
+			info, ok := v.context.LoopContext[v.loops[0]]
+			if ok && info.BoundType == context.BoundConstant && info.EstimatedMax > 0 && info.EstimatedMax <= 10 {
+				severity = models.SeverityLow
+			}
+		}
+	}
+
+	// Formatting arguments, without the format string
+	args := call.Args
+	if funcName == "fmt.Sprintf" {
+		args = args[1:]
+	}
+
+	entry := "sprintf_map_key.struct"
+	data := suggestions.Data{Var: mapName, Type: funcName}
+	switch {
+	case len(v.loops) > 0 && v.argsInvariant(args):
+		entry = "sprintf_map_key.hoist"
+		data.Source = types.ExprString(call)
+	case len(args) == 1:
+		entry = "sprintf_map_key.direct"
+		data.Source = types.ExprString(args[0])
+		data.Type = v.typeString(args[0])
+	default:
+		data.Type, data.Source = v.structKey(args)
+	}
+
+	issue := models.Issue{
+		Type:        models.IssueSprintfKey,
+		Severity:    severity,
+		File:        v.filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Function:    v.currentFunc,
+		Message:     fmt.Sprintf("%s builds the key for %s%s - formatting allocates and uses reflection on each lookup", funcName, mapName, where),
+		Suggestion:  suggestions.Render(entry, data),
+		Complexity:  "One string allocation and reflective formatting per lookup",
+		CodeSnippet: position.String(),
+		Confidence:  0.8, // The key format may be shared with other code
+		Impact:      "Allocation-free lookups",
+		FixEffort:   models.EffortSmall,
+	}
+
+	v.issues = append(v.issues, issue)
+}
+
+// argsInvariant reports whether every formatted value is the same on each
+// iteration of the innermost loop, so the key can be built once before it
+func (v *sprintfKeyVisitor) argsInvariant(args []ast.Expr) bool {
+	assigned := assignedInLoop(v.loops[len(v.loops)-1])
+	for _, arg := range args {
+		if !isLoopInvariant(arg, assigned) {
+			return false
+		}
+	}
+	return true
+}
+
+// structKey returns a struct type with one field per formatted value, and
+// the literal building it from the arguments
+func (v *sprintfKeyVisitor) structKey(args []ast.Expr) (string, string) {
+	var fields, values []string
+	used := make(map[string]bool)
+	for i, arg := range args {
+		name := ""
+		switch e := arg.(type) {
+		case *ast.Ident:
+			name = e.Name
+		case *ast.SelectorExpr:
+			name = e.Sel.Name
+		}
+		if name == "" || used[name] {
+			name = fmt.Sprintf("f%d", i)
+		}
+		used[name] = true
+
+		fields = append(fields, fmt.Sprintf("%s %s", name, v.typeString(arg)))
+		values = append(values, types.ExprString(arg))
+	}
+	return fmt.Sprintf("struct{ %s }", strings.Join(fields, "; ")), fmt.Sprintf("{%s}", strings.Join(values, ", "))
+}
+
+// typeString returns the type of expr as written in this package, or "T"
+// without type info
+func (v *sprintfKeyVisitor) typeString(expr ast.Expr) string {
+	if v.context == nil || v.context.TypeInfo == nil {
+		return "T"
+	}
+	t := v.context.TypeInfo.TypeOf(expr)
+	if t == nil {
+		return "T"
+	}
+	t = types.Default(t)
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if pkg.Name() == v.pkgName {
+			return ""
+		}
+		return pkg.Name()
+	})
+}
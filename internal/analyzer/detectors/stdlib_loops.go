@@ -0,0 +1,664 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"go/version"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// StdlibLoopDetector finds loops that spell out a function of the slices or
+// maps packages: searches, minimum and maximum scans, reversals, and key,
+// value, or entry copies. Suggestions respect the go directive of the file's
+// module, since slices and maps need Go 1.21 and their iterators Go 1.23.
+type StdlibLoopDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*StdlibLoopDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "stdlib_loops",
+		Category: "quality",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewStdlibLoopDetectorWithConfig(cfg) },
+	})
+}
+
+func NewStdlibLoopDetector() *StdlibLoopDetector {
+	return &StdlibLoopDetector{}
+}
+
+func NewStdlibLoopDetectorWithConfig(cfg *config.Config) *StdlibLoopDetector {
+	return &StdlibLoopDetector{
+		config: cfg,
+	}
+}
+
+func (d *StdlibLoopDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *StdlibLoopDetector) Name() string {
+	return "Stdlib Loop Detector"
+}
+
+func (d *StdlibLoopDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	settings := d.settings()
+	goVersion := settings.DefaultGoVersion
+	if ctx != nil {
+		if v, ok := ctx.GoVersions[filename]; ok {
+			goVersion = v
+		}
+	}
+
+	detector := &stdlibLoopVisitor{
+		fset:      fset,
+		filename:  filename,
+		issues:    make([]models.Issue, 0),
+		context:   ctx,
+		settings:  settings,
+		goVersion: goVersion,
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+// settings returns the rule config, or the defaults without one
+func (d *StdlibLoopDetector) settings() config.StdlibLoopsConfig {
+	if d.config != nil && d.config.Rules.Quality.StdlibLoops.Enabled {
+		return d.config.Rules.Quality.StdlibLoops
+	}
+	return config.DefaultConfig().Rules.Quality.StdlibLoops
+}
+
+type stdlibLoopVisitor struct {
+	fset        *token.FileSet
+	filename    string
+	issues      []models.Issue
+	currentFunc string
+	context     *context.AnalysisContext
+	settings    config.StdlibLoopsConfig
+	goVersion   string // Go version the file is built with, "" if unknown
+}
+
+// stdlibMatch is a loop and the call that replaces it
+type stdlibMatch struct {
+	funcName    string // e.g. "slices.Contains"
+	replacement string // Statement or expression replacing the loop
+	minVersion  string // First Go version providing funcName
+	entry       string // Suggestion catalog entry
+}
+
+func (v *stdlibLoopVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Name != nil {
+			v.currentFunc = n.Name.Name
+		}
+	case *ast.BlockStmt:
+		v.checkStmts(n.List)
+	case *ast.CaseClause:
+		v.checkStmts(n.Body)
+	case *ast.CommClause:
+		v.checkStmts(n.Body)
+	}
+	return v
+}
+
+// checkStmts matches each loop in a statement list together with the
+// statements around it, which hold the result's declaration and the
+// fallback return of a search
+func (v *stdlibLoopVisitor) checkStmts(stmts []ast.Stmt) {
+	for i, stmt := range stmts {
+		var prev, next ast.Stmt
+		if i > 0 {
+			prev = stmts[i-1]
+		}
+		if i+1 < len(stmts) {
+			next = stmts[i+1]
+		}
+
+		var match *stdlibMatch
+		switch loop := stmt.(type) {
+		case *ast.RangeStmt:
+			match = v.matchRange(loop, prev, next)
+		case *ast.ForStmt:
+			if v.settings.DetectSlices {
+				match = v.matchReverse(loop)
+			}
+		}
+		if match != nil && v.supports(match.minVersion) {
+			v.createIssue(stmt, match)
+		}
+	}
+}
+
+// supports reports whether the file's Go version has a function added in
+// minVersion; an unknown version is assumed to be recent
+func (v *stdlibLoopVisitor) supports(minVersion string) bool {
+	if v.goVersion == "" {
+		return true
+	}
+	return version.Compare("go"+v.goVersion, "go"+minVersion) >= 0
+}
+
+func (v *stdlibLoopVisitor) matchRange(loop *ast.RangeStmt, prev, next ast.Stmt) *stdlibMatch {
+	if len(loop.Body.List) != 1 {
+		return nil
+	}
+	switch v.rangeKind(loop.X) {
+	case "slice", "":
+		if !v.settings.DetectSlices {
+			return nil
+		}
+		if match := v.matchSearch(loop, next); match != nil {
+			return match
+		}
+		return v.matchExtreme(loop)
+	case "map":
+		if !v.settings.DetectMaps {
+			return nil
+		}
+		if match := v.matchCollect(loop, prev, next); match != nil {
+			return match
+		}
+		return v.matchCopy(loop, prev)
+	}
+	return nil
+}
+
+// matchSearch matches a linear search for an element or for one that
+// satisfies a function:
+//
+//	for _, x := range s { if x == target { return true } }; return false
+//	for i, x := range s { if x == target { return i } }; return -1
+//	for _, x := range s { if x == target { found = true; break } }
+func (v *stdlibLoopVisitor) matchSearch(loop *ast.RangeStmt, next ast.Stmt) *stdlibMatch {
+	value := identOf(loop.Value)
+	ifStmt, ok := loop.Body.List[0].(*ast.IfStmt)
+	if value == nil || !ok || ifStmt.Init != nil || ifStmt.Else != nil {
+		return nil
+	}
+	loopVars := rangeVarNames(loop)
+
+	var funcSuffix, argument string
+	switch cond := ifStmt.Cond.(type) {
+	case *ast.BinaryExpr:
+		if cond.Op != token.EQL {
+			return nil
+		}
+		target := cond.Y
+		if !isIdentNamed(cond.X, value.Name) {
+			target = cond.X
+			if !isIdentNamed(cond.Y, value.Name) {
+				return nil
+			}
+		}
+		if mentions(target, loopVars) {
+			return nil
+		}
+		argument = types.ExprString(target)
+	case *ast.CallExpr:
+		if len(cond.Args) != 1 || !isIdentNamed(cond.Args[0], value.Name) || mentions(cond.Fun, loopVars) {
+			return nil
+		}
+		funcSuffix = "Func"
+		argument = types.ExprString(cond.Fun)
+	default:
+		return nil
+	}
+
+	slice := types.ExprString(loop.X)
+	body := ifStmt.Body.List
+	switch {
+	case len(body) == 1 && returnsIdent(body[0], "true") && returnsIdent(next, "false"):
+		funcName := "slices.Contains" + funcSuffix
+		return &stdlibMatch{
+			funcName:    funcName,
+			replacement: fmt.Sprintf("return %s(%s, %s)", funcName, slice, argument),
+			minVersion:  "1.21",
+			entry:       "stdlib_loop.replace",
+		}
+
+	case len(body) == 1 && loop.Key != nil && returnsIdent(body[0], identName(loop.Key)) && returnsMinusOne(next):
+		funcName := "slices.Index" + funcSuffix
+		return &stdlibMatch{
+			funcName:    funcName,
+			replacement: fmt.Sprintf("return %s(%s, %s)", funcName, slice, argument),
+			minVersion:  "1.21",
+			entry:       "stdlib_loop.replace",
+		}
+
+	case len(body) == 2 && isBreak(body[1]):
+		assign, ok := body[0].(*ast.AssignStmt)
+		if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 || !isIdentNamed(assign.Rhs[0], "true") {
+			return nil
+		}
+		flag, ok := assign.Lhs[0].(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		funcName := "slices.Contains" + funcSuffix
+		return &stdlibMatch{
+			funcName:    funcName,
+			replacement: fmt.Sprintf("%s = %s(%s, %s)", flag.Name, funcName, slice, argument),
+			minVersion:  "1.21",
+			entry:       "stdlib_loop.replace",
+		}
+	}
+	return nil
+}
+
+// matchExtreme matches a running maximum or minimum:
+//
+//	for _, x := range s { if x > m { m = x } }
+func (v *stdlibLoopVisitor) matchExtreme(loop *ast.RangeStmt) *stdlibMatch {
+	value := identOf(loop.Value)
+	ifStmt, ok := loop.Body.List[0].(*ast.IfStmt)
+	if value == nil || !ok || ifStmt.Init != nil || ifStmt.Else != nil || len(ifStmt.Body.List) != 1 {
+		return nil
+	}
+	cond, ok := ifStmt.Cond.(*ast.BinaryExpr)
+	if !ok {
+		return nil
+	}
+
+	// Normalize to "x op m"
+	op, current := cond.Op, cond.Y
+	if !isIdentNamed(cond.X, value.Name) {
+		if !isIdentNamed(cond.Y, value.Name) {
+			return nil
+		}
+		current = cond.X
+		switch op {
+		case token.GTR:
+			op = token.LSS
+		case token.GEQ:
+			op = token.LEQ
+		case token.LSS:
+			op = token.GTR
+		case token.LEQ:
+			op = token.GEQ
+		}
+	}
+	result, ok := current.(*ast.Ident)
+	if !ok || result.Name == value.Name {
+		return nil
+	}
+
+	funcName := ""
+	switch op {
+	case token.GTR, token.GEQ:
+		funcName = "slices.Max"
+	case token.LSS, token.LEQ:
+		funcName = "slices.Min"
+	default:
+		return nil
+	}
+
+	assign, ok := ifStmt.Body.List[0].(*ast.AssignStmt)
+	if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 ||
+		!isIdentNamed(assign.Lhs[0], result.Name) || !isIdentNamed(assign.Rhs[0], value.Name) {
+		return nil
+	}
+	if !v.isOrderedElement(loop.X) {
+		return nil
+	}
+
+	// for _, x := range s[1:] after m := s[0] scans all of s
+	slice := loop.X
+	if sliceExpr, ok := slice.(*ast.SliceExpr); ok && sliceExpr.High == nil && !sliceExpr.Slice3 {
+		if lit, ok := sliceExpr.Low.(*ast.BasicLit); ok && lit.Value == "1" {
+			slice = sliceExpr.X
+		}
+	}
+
+	return &stdlibMatch{
+		funcName:    funcName,
+		replacement: fmt.Sprintf("%s = %s(%s)", result.Name, funcName, types.ExprString(slice)),
+		minVersion:  "1.21",
+		entry:       "stdlib_loop.max_min",
+	}
+}
+
+// matchReverse matches an in-place reversal:
+//
+//	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 { s[i], s[j] = s[j], s[i] }
+//	for i := 0; i < len(s)/2; i++ { s[i], s[len(s)-1-i] = s[len(s)-1-i], s[i] }
+func (v *stdlibLoopVisitor) matchReverse(loop *ast.ForStmt) *stdlibMatch {
+	if loop.Init == nil || loop.Cond == nil || len(loop.Body.List) != 1 {
+		return nil
+	}
+	swap, ok := loop.Body.List[0].(*ast.AssignStmt)
+	if !ok {
+		return nil
+	}
+	slice := parallelSwap(swap)
+	if slice == "" {
+		return nil
+	}
+	init, ok := loop.Init.(*ast.AssignStmt)
+	if !ok || init.Tok != token.DEFINE || len(init.Rhs) == 0 || !isIntLiteral(init.Rhs[0], "0") {
+		return nil
+	}
+	cond, ok := loop.Cond.(*ast.BinaryExpr)
+	if !ok || cond.Op != token.LSS {
+		return nil
+	}
+
+	switch len(init.Lhs) {
+	case 2:
+		// i, j := 0, len(s)-1; i < j
+		if len(init.Rhs) != 2 || !isIdentNamed(cond.X, identName(init.Lhs[0])) || !isIdentNamed(cond.Y, identName(init.Lhs[1])) {
+			return nil
+		}
+		last, ok := init.Rhs[1].(*ast.BinaryExpr)
+		if !ok || last.Op != token.SUB || !isIntLiteral(last.Y, "1") || !isLenOf(last.X, slice) {
+			return nil
+		}
+	case 1:
+		// i := 0; i < len(s)/2
+		half, ok := cond.Y.(*ast.BinaryExpr)
+		if !ok || half.Op != token.QUO || !isIntLiteral(half.Y, "2") || !isLenOf(half.X, slice) ||
+			!isIdentNamed(cond.X, identName(init.Lhs[0])) {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	return &stdlibMatch{
+		funcName:    "slices.Reverse",
+		replacement: fmt.Sprintf("slices.Reverse(%s)", slice),
+		minVersion:  "1.21",
+		entry:       "stdlib_loop.replace",
+	}
+}
+
+// matchCollect matches keys or values appended to a slice, optionally
+// sorted afterwards:
+//
+//	for k := range m { keys = append(keys, k) }; sort.Strings(keys)
+func (v *stdlibLoopVisitor) matchCollect(loop *ast.RangeStmt, prev, next ast.Stmt) *stdlibMatch {
+	assign, ok := loop.Body.List[0].(*ast.AssignStmt)
+	if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return nil
+	}
+	out, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok || !isIdentNamed(call.Fun, "append") || len(call.Args) != 2 || call.Ellipsis.IsValid() ||
+		!isIdentNamed(call.Args[0], out.Name) {
+		return nil
+	}
+
+	iterator := ""
+	switch {
+	case loop.Key != nil && identName(loop.Key) != "_" && isIdentNamed(call.Args[1], identName(loop.Key)):
+		iterator = "maps.Keys"
+	case loop.Value != nil && isIdentNamed(call.Args[1], identName(loop.Value)):
+		iterator = "maps.Values"
+	default:
+		return nil
+	}
+	seq := fmt.Sprintf("%s(%s)", iterator, types.ExprString(loop.X))
+
+	match := &stdlibMatch{minVersion: "1.23", entry: "stdlib_loop.replace"}
+	switch {
+	case !declaresEmpty(prev, out.Name):
+		match.funcName = "slices.AppendSeq"
+		match.replacement = fmt.Sprintf("%s = slices.AppendSeq(%s, %s)", out.Name, out.Name, seq)
+	case v.sorts(next, out.Name):
+		match.funcName = "slices.Sorted"
+		match.replacement = fmt.Sprintf("%s := slices.Sorted(%s)", out.Name, seq)
+	default:
+		match.funcName = "slices.Collect"
+		match.replacement = fmt.Sprintf("%s := slices.Collect(%s)", out.Name, seq)
+	}
+	match.funcName = iterator + " with " + match.funcName
+	return match
+}
+
+// matchCopy matches entries copied between maps:
+//
+//	for k, val := range src { dst[k] = val }
+func (v *stdlibLoopVisitor) matchCopy(loop *ast.RangeStmt, prev ast.Stmt) *stdlibMatch {
+	key, value := identOf(loop.Key), identOf(loop.Value)
+	if key == nil || value == nil {
+		return nil
+	}
+	assign, ok := loop.Body.List[0].(*ast.AssignStmt)
+	if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 || !isIdentNamed(assign.Rhs[0], value.Name) {
+		return nil
+	}
+	index, ok := assign.Lhs[0].(*ast.IndexExpr)
+	if !ok || !isIdentNamed(index.Index, key.Name) || sameExpr(index.X, loop.X) || v.rangeKind(index.X) != "map" {
+		return nil
+	}
+	if mentions(index.X, rangeVarNames(loop)) {
+		return nil
+	}
+
+	dst, src := types.ExprString(index.X), types.ExprString(loop.X)
+	if declaresEmpty(prev, dst) {
+		return &stdlibMatch{
+			funcName:    "maps.Clone",
+			replacement: fmt.Sprintf("%s := maps.Clone(%s)", dst, src),
+			minVersion:  "1.21",
+			entry:       "stdlib_loop.clone",
+		}
+	}
+	return &stdlibMatch{
+		funcName:    "maps.Copy",
+		replacement: fmt.Sprintf("maps.Copy(%s, %s)", dst, src),
+		minVersion:  "1.21",
+		entry:       "stdlib_loop.replace",
+	}
+}
+
+// rangeKind classifies what a loop ranges over: "slice" (including arrays),
+// "map", "other", or "" without type info
+func (v *stdlibLoopVisitor) rangeKind(expr ast.Expr) string {
+	if v.context == nil || v.context.TypeInfo == nil {
+		return ""
+	}
+	t := v.context.TypeInfo.TypeOf(expr)
+	if t == nil {
+		return ""
+	}
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	switch t.Underlying().(type) {
+	case *types.Slice, *types.Array:
+		return "slice"
+	case *types.Map:
+		return "map"
+	}
+	return "other"
+}
+
+// isOrderedElement reports whether a slice's elements can be compared with
+// <, which slices.Max and slices.Min require. Without type info the
+// comparison in the loop is taken as proof.
+func (v *stdlibLoopVisitor) isOrderedElement(expr ast.Expr) bool {
+	if v.context == nil || v.context.TypeInfo == nil {
+		return true
+	}
+	t := v.context.TypeInfo.TypeOf(expr)
+	if t == nil {
+		return true
+	}
+	var elem types.Type
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		elem = u.Elem()
+	case *types.Array:
+		elem = u.Elem()
+	default:
+		return false
+	}
+	basic, ok := elem.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsOrdered != 0
+}
+
+// sorts reports whether stmt sorts the named slice
+func (v *stdlibLoopVisitor) sorts(stmt ast.Stmt, name string) bool {
+	exprStmt, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+	call, ok := exprStmt.X.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 || !isIdentNamed(call.Args[0], name) {
+		return false
+	}
+	pkg, funcName, ok := packageFunc(v.context, call)
+	if !ok {
+		return false
+	}
+	switch pkg {
+	case "sort":
+		return funcName == "Strings" || funcName == "Ints" || funcName == "Float64s"
+	case "slices":
+		return funcName == "Sort"
+	}
+	return false
+}
+
+// declaresEmpty reports whether stmt declares name as an empty slice or
+// map: var x []T, x := []T{}, x := make([]T, 0, n), or x := make(map[K]V)
+func declaresEmpty(stmt ast.Stmt, name string) bool {
+	switch s := stmt.(type) {
+	case *ast.DeclStmt:
+		gen, ok := s.Decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR || len(gen.Specs) != 1 {
+			return false
+		}
+		spec, ok := gen.Specs[0].(*ast.ValueSpec)
+		if !ok || len(spec.Names) != 1 || spec.Names[0].Name != name {
+			return false
+		}
+		return len(spec.Values) == 0 || (len(spec.Values) == 1 && isEmptyValue(spec.Values[0]))
+	case *ast.AssignStmt:
+		return s.Tok == token.DEFINE && len(s.Lhs) == 1 && len(s.Rhs) == 1 &&
+			isIdentNamed(s.Lhs[0], name) && isEmptyValue(s.Rhs[0])
+	}
+	return false
+}
+
+func isEmptyValue(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		return len(e.Elts) == 0
+	case *ast.CallExpr:
+		if !isIdentNamed(e.Fun, "make") || len(e.Args) == 0 {
+			return false
+		}
+		if _, ok := e.Args[0].(*ast.MapType); ok {
+			return true
+		}
+		return len(e.Args) >= 2 && isIntLiteral(e.Args[1], "0")
+	}
+	return false
+}
+
+// rangeVarNames returns the names of a range statement's iteration variables
+func rangeVarNames(loop *ast.RangeStmt) map[string]bool {
+	names := make(map[string]bool)
+	for _, expr := range []ast.Expr{loop.Key, loop.Value} {
+		if name := identName(expr); name != "" && name != "_" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// mentions reports whether expr refers to any of the named variables
+func mentions(expr ast.Expr, names map[string]bool) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && names[ident.Name] {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func identOf(expr ast.Expr) *ast.Ident {
+	ident, ok := expr.(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return nil
+	}
+	return ident
+}
+
+func isIdentNamed(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && name != "" && ident.Name == name
+}
+
+func isIntLiteral(expr ast.Expr, value string) bool {
+	lit, ok := expr.(*ast.BasicLit)
+	return ok && lit.Kind == token.INT && lit.Value == value
+}
+
+// isLenOf matches len(slice)
+func isLenOf(expr ast.Expr, slice string) bool {
+	call, ok := expr.(*ast.CallExpr)
+	return ok && isIdentNamed(call.Fun, "len") && len(call.Args) == 1 && types.ExprString(call.Args[0]) == slice
+}
+
+// returnsIdent matches return name
+func returnsIdent(stmt ast.Stmt, name string) bool {
+	ret, ok := stmt.(*ast.ReturnStmt)
+	return ok && len(ret.Results) == 1 && isIdentNamed(ret.Results[0], name)
+}
+
+// returnsMinusOne matches return -1
+func returnsMinusOne(stmt ast.Stmt) bool {
+	ret, ok := stmt.(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return false
+	}
+	unary, ok := ret.Results[0].(*ast.UnaryExpr)
+	return ok && unary.Op == token.SUB && isIntLiteral(unary.X, "1")
+}
+
+func isBreak(stmt ast.Stmt) bool {
+	branch, ok := stmt.(*ast.BranchStmt)
+	return ok && branch.Tok == token.BREAK && branch.Label == nil
+}
+
+func (v *stdlibLoopVisitor) createIssue(loop ast.Stmt, match *stdlibMatch) {
+	position := v.fset.Position(loop.Pos())
+
+	issue := models.Issue{
+		Type:        models.IssueStdlibLoop,
+		Severity:    models.SeverityLow,
+		File:        v.filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Function:    v.currentFunc,
+		Message:     fmt.Sprintf("Loop reimplements %s (Go %s+)", match.funcName, match.minVersion),
+		Suggestion:  suggestions.Render(match.entry, suggestions.Data{Var: match.replacement, Type: match.funcName, Source: match.minVersion}),
+		Complexity:  "Same complexity, more code to read and get wrong",
+		CodeSnippet: position.String(),
+		Confidence:  0.8,
+		Impact:      "Shorter code that states its intent",
+		FixEffort:   models.EffortTrivial,
+	}
+
+	v.issues = append(v.issues, issue)
+}
@@ -3,17 +3,32 @@ package detectors
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
 	"strings"
 
 	"gophercheck/internal/config"
 	"gophercheck/internal/context"
 	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
 )
 
 type StringConcatDetector struct {
 	config *config.Config
 }
 
+var _ Detector = (*StringConcatDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "string_concat",
+		Category: "performance",
+		Version:  "1.1.0",
+		New:      func(cfg *config.Config) Detector { return NewStringConcatDetectorWithConfig(cfg) },
+
+		FixSafety: models.FixSafe,
+	})
+}
+
 func NewStringConcatDetector() *StringConcatDetector {
 	return &StringConcatDetector{}
 }
@@ -39,6 +54,9 @@ func (d *StringConcatDetector) Detect(file *ast.File, fset *token.FileSet, filen
 		issues:   make([]models.Issue, 0),
 		detector: d,
 		context:  ctx,
+		file:     file,
+		labels:   make(map[ast.Node]*ast.LabeledStmt),
+		fixed:    make(map[types.Object]bool),
 	}
 
 	ast.Walk(detector, file)
@@ -53,6 +71,12 @@ type stringConcatVisitor struct {
 	currentFunc string
 	detector    *StringConcatDetector
 	context     *context.AnalysisContext
+
+	// State for building suggested fixes
+	file      *ast.File
+	outerLoop ast.Node                      // Outermost loop around the current statement
+	labels    map[ast.Node]*ast.LabeledStmt // Labeled loops, by loop
+	fixed     map[types.Object]bool         // Variables that already have a fix
 }
 
 func (v *stringConcatVisitor) Visit(node ast.Node) ast.Visitor {
@@ -63,8 +87,23 @@ func (v *stringConcatVisitor) Visit(node ast.Node) ast.Visitor {
 		}
 		return v
 
+	case *ast.LabeledStmt:
+		v.labels[n.Stmt] = n
+		return v
+
 	case *ast.ForStmt, *ast.RangeStmt:
+		if isBenchmarkTimingLoop(v.context, n) {
+			// Measured benchmark code: visit the body as if it were not in a loop
+			for _, stmt := range getLoopBody(n) {
+				ast.Walk(v, stmt)
+			}
+			return nil
+		}
+
 		oldInLoop := v.inLoop
+		if !v.inLoop {
+			v.outerLoop = n
+		}
 		v.inLoop = true
 
 		for _, stmt := range getLoopBody(n) {
@@ -72,6 +111,9 @@ func (v *stringConcatVisitor) Visit(node ast.Node) ast.Visitor {
 		}
 
 		v.inLoop = oldInLoop
+		if !v.inLoop {
+			v.outerLoop = nil
+		}
 		return nil
 
 	case *ast.AssignStmt:
@@ -170,22 +212,28 @@ func (v *stringConcatVisitor) createIssue(assign *ast.AssignStmt, message string
 		Column:      position.Column,
 		Function:    v.currentFunc,
 		Message:     message + " - creates new strings on each iteration",
-		Suggestion:  v.generateSuggestion(),
+		Suggestion:  suggestions.Render("string_concatenation.builder", suggestions.Data{Var: identName(assign.Lhs[0])}),
 		Complexity:  "O(n²) due to string copying",
 		CodeSnippet: position.String(),
+		Confidence:  v.calculateConfidence(assign.Lhs[0]),
+		Impact:      "O(n²)→O(n)",
+		FixEffort:   models.EffortTrivial,
+
+		SuggestedFix: v.builderFix(assign),
 	}
 
 	v.issues = append(v.issues, issue)
 }
 
-func (v *stringConcatVisitor) generateSuggestion() string {
-	return `Use strings.Builder for efficient string concatenation:
-	
-var builder strings.Builder
-for _, item := range items {
-    builder.WriteString(item)
-}
-result := builder.String()
-
-This provides O(n) performance instead of O(n²).`
+// calculateConfidence is high when type info confirms a string target and
+// lower when only the variable-name heuristic matched
+func (v *stringConcatVisitor) calculateConfidence(expr ast.Expr) float64 {
+	if v.context != nil && v.context.TypeInfo != nil {
+		if tv, ok := v.context.TypeInfo.Types[expr]; ok && tv.Type != nil {
+			if basic, ok := tv.Type.Underlying().(*types.Basic); ok && basic.Info()&types.IsString != 0 {
+				return 0.95
+			}
+		}
+	}
+	return 0.7
 }
@@ -0,0 +1,222 @@
+package detectors
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"gophercheck/internal/models"
+)
+
+// concatStmt is one `s += x` or `s = s + x` statement and its appended value
+type concatStmt struct {
+	stmt  *ast.AssignStmt
+	value ast.Expr
+}
+
+// builderFix rewrites every concatenation to the assigned variable within the
+// outermost loop into strings.Builder writes. It returns nil unless the
+// rewrite is clearly safe: type info confirms a string declared before the
+// loop, and inside the loop the variable is only used by these statements.
+func (v *stringConcatVisitor) builderFix(assign *ast.AssignStmt) *models.SuggestedFix {
+	ident, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok || v.outerLoop == nil || v.context == nil || v.context.TypeInfo == nil {
+		return nil
+	}
+
+	obj := v.context.TypeInfo.ObjectOf(ident)
+	if obj == nil || v.fixed[obj] || obj.Pos() >= v.outerLoop.Pos() {
+		return nil
+	}
+	if basic, ok := obj.Type().Underlying().(*types.Basic); !ok || basic.Info()&types.IsString == 0 {
+		return nil
+	}
+
+	concats, ok := v.collectConcats(obj)
+	if !ok {
+		return nil
+	}
+
+	builder := ident.Name + "Builder"
+	if v.nameInUse(builder) {
+		return nil
+	}
+	stringsPkg, importEdit := v.stringsImport()
+	if stringsPkg == "" {
+		return nil
+	}
+
+	loopStart := v.outerLoop.Pos()
+	if labeled, ok := v.labels[v.outerLoop]; ok {
+		loopStart = labeled.Pos()
+	}
+	start := v.fset.Position(loopStart)
+	indent := strings.Repeat("\t", start.Column-1)
+	lineStart := start.Offset - (start.Column - 1)
+
+	edits := []models.TextEdit{{
+		Start: lineStart,
+		End:   lineStart,
+		NewText: fmt.Sprintf("%svar %s %s.Builder\n%s%s.WriteString(%s)\n",
+			indent, builder, stringsPkg, indent, builder, ident.Name),
+	}}
+	for _, concat := range concats {
+		edits = append(edits, models.TextEdit{
+			Start:   v.fset.Position(concat.stmt.Pos()).Offset,
+			End:     v.fset.Position(concat.stmt.End()).Offset,
+			NewText: fmt.Sprintf("%s.WriteString(%s)", builder, v.exprText(concat.value)),
+		})
+	}
+	loopEnd := v.fset.Position(v.outerLoop.End()).Offset
+	edits = append(edits, models.TextEdit{
+		Start:   loopEnd,
+		End:     loopEnd,
+		NewText: fmt.Sprintf("\n%s%s = %s.String()", indent, ident.Name, builder),
+	})
+	if importEdit != nil {
+		edits = append(edits, *importEdit)
+	}
+
+	v.fixed[obj] = true
+	return &models.SuggestedFix{
+		Description: fmt.Sprintf("Build %s with a strings.Builder", ident.Name),
+		Safety:      v.builderFixSafety(obj),
+		Edits:       edits,
+	}
+}
+
+// builderFixSafety is safe unless code outside the loop could observe the
+// variable before the builder is copied back into it after the loop: when the
+// variable is package-level, or the loop can leave the function early.
+// Those fixes are likely safe, as the variable is usually dead by then.
+func (v *stringConcatVisitor) builderFixSafety(obj types.Object) models.FixSafety {
+	if obj.Parent() == obj.Pkg().Scope() {
+		return models.FixLikelySafe
+	}
+	leaves := false
+	ast.Inspect(v.outerLoop, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			leaves = true
+		case *ast.BranchStmt:
+			leaves = leaves || n.Tok == token.GOTO
+		case *ast.CallExpr:
+			leaves = leaves || isIdentNamed(n.Fun, "panic")
+		}
+		return !leaves
+	})
+	if leaves {
+		return models.FixLikelySafe
+	}
+	return models.FixSafe
+}
+
+// collectConcats finds the concatenations to obj in the outermost loop. It
+// fails if obj is referenced there in any other way.
+func (v *stringConcatVisitor) collectConcats(obj types.Object) ([]concatStmt, bool) {
+	var concats []concatStmt
+	permitted := make(map[*ast.Ident]bool)
+
+	ast.Inspect(v.outerLoop, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return true
+		}
+		target, ok := assign.Lhs[0].(*ast.Ident)
+		if !ok || v.context.TypeInfo.Uses[target] != obj {
+			return true
+		}
+
+		switch assign.Tok {
+		case token.ADD_ASSIGN:
+			concats = append(concats, concatStmt{assign, assign.Rhs[0]})
+			permitted[target] = true
+		case token.ASSIGN:
+			binExpr, ok := assign.Rhs[0].(*ast.BinaryExpr)
+			if !ok || binExpr.Op != token.ADD {
+				return true
+			}
+			if x, ok := binExpr.X.(*ast.Ident); ok && v.context.TypeInfo.Uses[x] == obj {
+				concats = append(concats, concatStmt{assign, binExpr.Y})
+				permitted[target] = true
+				permitted[x] = true
+			}
+		}
+		return true
+	})
+
+	safe := len(concats) > 0
+	ast.Inspect(v.outerLoop, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && v.context.TypeInfo.ObjectOf(ident) == obj && !permitted[ident] {
+			safe = false
+		}
+		return safe
+	})
+	return concats, safe
+}
+
+func (v *stringConcatVisitor) nameInUse(name string) bool {
+	inUse := false
+	ast.Inspect(v.file, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == name {
+			inUse = true
+		}
+		return !inUse
+	})
+	return inUse
+}
+
+// stringsImport returns the name the file uses for package strings, and an
+// edit adding the import when the file doesn't have it
+func (v *stringConcatVisitor) stringsImport() (string, *models.TextEdit) {
+	for _, spec := range v.file.Imports {
+		if path, _ := strconv.Unquote(spec.Path.Value); path != "strings" {
+			continue
+		}
+		if spec.Name == nil {
+			return "strings", nil
+		}
+		if spec.Name.Name != "_" && spec.Name.Name != "." {
+			return spec.Name.Name, nil
+		}
+	}
+
+	for _, decl := range v.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Lparen.IsValid() {
+			offset := v.fset.Position(gen.Lparen).Offset + 1
+			return "strings", &models.TextEdit{Start: offset, End: offset, NewText: "\n\t\"strings\""}
+		}
+		// Turn a single import into a block so the new one joins it
+		var spec bytes.Buffer
+		if err := printer.Fprint(&spec, v.fset, gen.Specs[0]); err != nil {
+			return "", nil
+		}
+		return "strings", &models.TextEdit{
+			Start:   v.fset.Position(gen.Pos()).Offset,
+			End:     v.fset.Position(gen.End()).Offset,
+			NewText: "import (\n\t" + spec.String() + "\n\t\"strings\"\n)",
+		}
+	}
+
+	offset := v.fset.Position(v.file.Name.End()).Offset
+	return "strings", &models.TextEdit{Start: offset, End: offset, NewText: "\n\nimport \"strings\""}
+}
+
+func (v *stringConcatVisitor) exprText(expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, v.fset, expr); err != nil {
+		return ""
+	}
+	return buf.String()
+}
@@ -0,0 +1,17 @@
+package detectors
+
+import (
+	"go/ast"
+
+	"gophercheck/internal/context"
+)
+
+// isBenchmarkTimingLoop reports whether loop is a benchmark's b.N / b.Loop()
+// loop that the walker marked as not a hot loop
+func isBenchmarkTimingLoop(ctx *context.AnalysisContext, loop ast.Node) bool {
+	if ctx == nil {
+		return false
+	}
+	info, ok := ctx.LoopContext[loop]
+	return ok && info.IsBenchmarkLoop
+}
@@ -0,0 +1,217 @@
+package detectors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// receiverSizes computes struct sizes as the gc compiler lays them out on
+// 64-bit platforms, so results don't depend on the machine running the check
+var receiverSizes = types.SizesFor("gc", "amd64")
+
+// ValueReceiverDetector finds methods with value receivers on large structs,
+// which copy the whole struct on every call
+type ValueReceiverDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*ValueReceiverDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "value_receiver",
+		Category: "memory",
+		Version:  "1.1.0",
+		New:      func(cfg *config.Config) Detector { return NewValueReceiverDetectorWithConfig(cfg) },
+	})
+}
+
+func NewValueReceiverDetector() *ValueReceiverDetector {
+	return &ValueReceiverDetector{}
+}
+
+func NewValueReceiverDetectorWithConfig(cfg *config.Config) *ValueReceiverDetector {
+	return &ValueReceiverDetector{
+		config: cfg,
+	}
+}
+
+func (d *ValueReceiverDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *ValueReceiverDetector) Name() string {
+	return "Value Receiver Size Detector"
+}
+
+func (d *ValueReceiverDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	// Sizes come from type info; without it there is nothing to measure
+	if ctx == nil || ctx.TypeInfo == nil {
+		return nil
+	}
+
+	maxSize := 128
+	if d.config != nil && d.config.Rules.Memory.ValueReceiver.Enabled {
+		maxSize = d.config.Rules.Memory.ValueReceiver.MaxSizeBytes
+	}
+
+	var issues []models.Issue
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 {
+			continue
+		}
+		recv := fn.Recv.List[0]
+		if _, isPointer := recv.Type.(*ast.StarExpr); isPointer {
+			continue
+		}
+
+		size, typeName, atLeast, ok := structSize(ctx.TypeInfo.TypeOf(recv.Type))
+		if !ok || size <= int64(maxSize) {
+			continue
+		}
+
+		loopCalls := countLoopCalls(ctx, ctx.TypeInfo.Defs[fn.Name])
+		issues = append(issues, newValueReceiverIssue(fset, filename, fn, recv, typeName, size, atLeast, maxSize, loopCalls))
+	}
+	return issues
+}
+
+// structSize returns the size of a named struct type. The size of a generic
+// type depends on its type arguments, so only the fields that don't mention a
+// type parameter are counted and the result is a lower bound (atLeast).
+func structSize(t types.Type) (size int64, typeName string, atLeast, ok bool) {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return 0, "", false, false
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return 0, "", false, false
+	}
+	typeName = types.TypeString(named, func(*types.Package) string { return "" })
+	if !hasTypeParams(named) {
+		return receiverSizes.Sizeof(named), typeName, false, true
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		if field := st.Field(i).Type(); !hasTypeParams(field) {
+			size += receiverSizes.Sizeof(field)
+		}
+	}
+	return size, typeName, true, true
+}
+
+// countLoopCalls counts the calls to method made inside loops anywhere in
+// the analyzed files
+func countLoopCalls(ctx *context.AnalysisContext, method types.Object) int {
+	if method == nil {
+		return 0
+	}
+	count := 0
+	for ident, obj := range ctx.TypeInfo.Uses {
+		if fn, ok := obj.(*types.Func); ok {
+			obj = fn.Origin() // Calls on an instantiated generic type use a copy of the method
+		}
+		if obj != method {
+			continue
+		}
+		for loop := range ctx.LoopContext {
+			if loop.Pos() <= ident.Pos() && ident.Pos() < loop.End() {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// assignsReceiver reports whether a method body assigns to its receiver or
+// the receiver's fields; with a pointer receiver those writes would become
+// visible to the caller
+func assignsReceiver(fn *ast.FuncDecl, recv *ast.Field) bool {
+	if len(recv.Names) == 0 || fn.Body == nil {
+		return false
+	}
+	name := recv.Names[0].Name
+	assigns := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		var targets []ast.Expr
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			targets = stmt.Lhs
+		case *ast.IncDecStmt:
+			targets = []ast.Expr{stmt.X}
+		}
+		for _, target := range targets {
+			for {
+				if sel, ok := target.(*ast.SelectorExpr); ok {
+					target = sel.X
+					continue
+				}
+				if index, ok := target.(*ast.IndexExpr); ok {
+					target = index.X
+					continue
+				}
+				break
+			}
+			if ident, ok := target.(*ast.Ident); ok && ident.Name == name {
+				assigns = true
+			}
+		}
+		return !assigns
+	})
+	return assigns
+}
+
+func newValueReceiverIssue(fset *token.FileSet, filename string, fn *ast.FuncDecl, recv *ast.Field, typeName string, size int64, atLeast bool, maxSize, loopCalls int) models.Issue {
+	position := fset.Position(fn.Pos())
+	method := typeName + "." + fn.Name.Name
+	sizeText := fmt.Sprintf("%d bytes", size)
+	if atLeast {
+		sizeText = "at least " + sizeText
+	}
+
+	severity := models.SeverityLow
+	if size >= int64(4*maxSize) {
+		severity = models.SeverityMedium
+	}
+	message := fmt.Sprintf("Method '%s' has a value receiver of %s - every call copies the whole struct", method, sizeText)
+	complexity := fmt.Sprintf("%d-byte copy per call", size)
+	if loopCalls > 0 {
+		severity++
+		message = fmt.Sprintf("Method '%s' has a value receiver of %s and is called inside loops - the struct is copied every iteration", method, sizeText)
+		complexity = fmt.Sprintf("%d-byte copy per call, O(n) copies at %d call site(s) in loops", size, loopCalls)
+	}
+
+	suggestionID := "large_value_receiver.pointer"
+	confidence := 0.85
+	if assignsReceiver(fn, recv) {
+		// The method relies on working on a copy, so switching isn't a drop-in change
+		suggestionID = "large_value_receiver.mutates_copy"
+		confidence = 0.6
+	}
+
+	return models.Issue{
+		Type:        models.IssueLargeReceiver,
+		Severity:    severity,
+		File:        filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Function:    fn.Name.Name,
+		Message:     message,
+		Suggestion:  suggestions.Render(suggestionID, suggestions.Data{Var: method, Type: typeName}),
+		Complexity:  complexity,
+		CodeSnippet: position.String(),
+		Confidence:  confidence,
+		Impact:      fmt.Sprintf("Avoids a %d-byte copy per call", size),
+		FixEffort:   models.EffortTrivial,
+	}
+}
@@ -0,0 +1,512 @@
+package detectors
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"go/version"
+	"maps"
+	"slices"
+	"strings"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+// WorkerPoolDetector finds loops that make expensive calls (network,
+// database, disk, or subprocess I/O from the analysis expensive_calls list)
+// one at a time although the iterations don't depend on each other, and
+// suggests running them with bounded concurrency through an errgroup
+type WorkerPoolDetector struct {
+	config *config.Config
+}
+
+var _ Detector = (*WorkerPoolDetector)(nil)
+
+func init() {
+	Register(Registration{
+		Rule:     "worker_pool",
+		Category: "performance",
+		Version:  "1.0.0",
+		New:      func(cfg *config.Config) Detector { return NewWorkerPoolDetectorWithConfig(cfg) },
+	})
+}
+
+func NewWorkerPoolDetector() *WorkerPoolDetector {
+	return &WorkerPoolDetector{}
+}
+
+func NewWorkerPoolDetectorWithConfig(cfg *config.Config) *WorkerPoolDetector {
+	return &WorkerPoolDetector{
+		config: cfg,
+	}
+}
+
+func (d *WorkerPoolDetector) SetConfig(cfg *config.Config) {
+	d.config = cfg
+}
+
+func (d *WorkerPoolDetector) Name() string {
+	return "Worker Pool Detector"
+}
+
+func (d *WorkerPoolDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	detector := &workerPoolVisitor{
+		fset:           fset,
+		filename:       filename,
+		issues:         make([]models.Issue, 0),
+		detector:       d,
+		context:        ctx,
+		settings:       d.settings(),
+		expensiveCalls: config.DefaultExpensiveCalls(),
+	}
+	if d.config != nil {
+		detector.expensiveCalls = d.config.Analysis.ExpensiveCalls
+	}
+	if ctx != nil {
+		detector.goVersion = ctx.GoVersions[filename]
+	}
+
+	ast.Walk(detector, file)
+	return detector.issues
+}
+
+// settings returns the rule config, or the defaults without one
+func (d *WorkerPoolDetector) settings() config.WorkerPoolConfig {
+	if d.config != nil && d.config.Rules.Performance.WorkerPool.Enabled {
+		return d.config.Rules.Performance.WorkerPool
+	}
+	return config.DefaultConfig().Rules.Performance.WorkerPool
+}
+
+type workerPoolVisitor struct {
+	fset           *token.FileSet
+	filename       string
+	issues         []models.Issue
+	currentFunc    string
+	loopDepth      int
+	goVersion      string
+	expensiveCalls []string
+	detector       *WorkerPoolDetector
+	context        *context.AnalysisContext
+	settings       config.WorkerPoolConfig
+}
+
+func (v *workerPoolVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Name != nil {
+			v.currentFunc = n.Name.Name
+		}
+		return v
+
+	case *ast.ForStmt, *ast.RangeStmt:
+		if isBenchmarkTimingLoop(v.context, n) {
+			for _, stmt := range getLoopBody(n) {
+				ast.Walk(v, stmt)
+			}
+			return nil
+		}
+
+		v.checkLoop(n)
+		v.loopDepth++
+		for _, stmt := range getLoopBody(n) {
+			ast.Walk(v, stmt)
+		}
+		v.loopDepth--
+		return nil
+
+	default:
+		return v
+	}
+}
+
+func (v *workerPoolVisitor) checkLoop(loop ast.Node) {
+	if info := v.loopInfo(loop); info != nil && info.BoundType == context.BoundConstant && info.EstimatedMax > 0 && info.EstimatedMax <= 2 {
+		return // Too few iterations to gain anything
+	}
+	loopVars, ok := v.iterationVars(loop)
+	if !ok {
+		return
+	}
+
+	body := &ast.BlockStmt{List: getLoopBody(loop)}
+	call, name := v.expensiveCall(body)
+	if call == nil {
+		return
+	}
+	shared, ok := v.independent(body, loopVars)
+	if !ok {
+		return
+	}
+
+	severity := models.SeverityMedium
+	if v.loopDepth > 0 {
+		severity = models.SeverityHigh
+	}
+
+	position := v.fset.Position(call.Pos())
+	issue := models.Issue{
+		Type:        models.IssueSequentialIO,
+		Severity:    severity,
+		File:        v.filename,
+		Line:        position.Line,
+		Column:      position.Column,
+		Function:    v.currentFunc,
+		Message:     fmt.Sprintf("%s runs once per loop iteration, one call at a time, although the iterations are independent", name),
+		Suggestion:  suggestions.Render("sequential_io.errgroup", suggestions.Data{Var: strings.Join(shared, ", "), Source: v.exampleCode(loop, loopVars, shared), Type: name, Iterations: v.settings.Limit}),
+		Complexity:  "O(n) sequential round trips",
+		CodeSnippet: position.String(),
+		Confidence:  0.65, // The callee may be rate limited or order dependent
+		Impact:      fmt.Sprintf("Up to %dx lower wall-clock time", v.settings.Limit),
+		FixEffort:   models.EffortSmall,
+	}
+
+	v.issues = append(v.issues, issue)
+}
+
+func (v *workerPoolVisitor) loopInfo(loop ast.Node) *context.LoopInfo {
+	if v.context == nil {
+		return nil
+	}
+	return v.context.LoopContext[loop]
+}
+
+// iterationVars returns the variables a loop declares per iteration. Only
+// counted loops and ranges over slices and maps qualify: reading a channel,
+// iterator, or scanner is itself sequential.
+func (v *workerPoolVisitor) iterationVars(loop ast.Node) (map[string]bool, bool) {
+	vars := make(map[string]bool)
+	switch l := loop.(type) {
+	case *ast.RangeStmt:
+		if l.Tok != token.DEFINE && (l.Key != nil || l.Value != nil) {
+			return nil, false
+		}
+		if v.context != nil && v.context.TypeInfo != nil {
+			if t := v.context.TypeInfo.TypeOf(l.X); t != nil {
+				switch t.Underlying().(type) {
+				case *types.Chan, *types.Signature:
+					return nil, false
+				}
+			}
+		}
+		for _, expr := range []ast.Expr{l.Key, l.Value} {
+			if name := identName(expr); name != "" && name != "_" {
+				vars[name] = true
+			}
+		}
+	case *ast.ForStmt:
+		init, ok := l.Init.(*ast.AssignStmt)
+		if !ok || init.Tok != token.DEFINE {
+			return nil, false
+		}
+		if _, ok := l.Post.(*ast.IncDecStmt); !ok {
+			return nil, false
+		}
+		for _, lhs := range init.Lhs {
+			if name := identName(lhs); name != "" {
+				vars[name] = true
+			}
+		}
+	}
+	return vars, true
+}
+
+// expensiveCall returns the first expensive call made directly by the loop
+// body, outside any function literal
+func (v *workerPoolVisitor) expensiveCall(body *ast.BlockStmt) (*ast.CallExpr, string) {
+	var found *ast.CallExpr
+	name := ""
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if entry, ok := matchCall(v.context, node, v.expensiveCalls); ok {
+				found, name = node, entry
+			} else if entry, ok := matchMethod(v.context, node, v.expensiveCalls); ok {
+				found, name = node, entry
+			}
+		}
+		return found == nil
+	})
+	return found, name
+}
+
+// independent reports whether loop iterations can run concurrently: the body
+// only writes its own variables, results indexed by the iteration variable,
+// and an outer err; it returns only on errors, doesn't break out early, and
+// doesn't coordinate through channels or goroutines. shared lists the outer slices and maps appended or
+// inserted into, which need a mutex once the iterations run concurrently.
+func (v *workerPoolVisitor) independent(body *ast.BlockStmt, loopVars map[string]bool) ([]string, bool) {
+	local := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if node.Tok == token.DEFINE {
+				for _, lhs := range node.Lhs {
+					local[identName(lhs)] = true
+				}
+			}
+		case *ast.ValueSpec:
+			for _, name := range node.Names {
+				local[name.Name] = true
+			}
+		}
+		return true
+	})
+
+	errorReturns := v.errorReturns(body)
+
+	var shared []string
+	seen := make(map[string]bool)
+	ok := true
+	var visit func(n ast.Node, nested bool) bool
+	visit = func(n ast.Node, nested bool) bool {
+		switch node := n.(type) {
+		case *ast.GoStmt, *ast.SendStmt, *ast.DeferStmt, *ast.SelectStmt, *ast.LabeledStmt:
+			ok = false
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if !errorReturns[node] {
+				ok = false // Stops at the first result, e.g. the first success
+			}
+		case *ast.UnaryExpr:
+			if node.Op == token.ARROW {
+				ok = false
+			}
+		case *ast.BranchStmt:
+			if node.Label != nil || node.Tok == token.GOTO || (node.Tok == token.BREAK && !nested) {
+				ok = false
+			}
+		case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt:
+			// break inside these leaves them, not the loop
+			for _, child := range childNodes(node) {
+				ast.Inspect(child, func(inner ast.Node) bool { return inner != nil && visit(inner, true) })
+			}
+			return false
+		case *ast.IncDecStmt:
+			if root := rootIdent(node.X); root == nil || !local[root.Name] {
+				ok = false
+			}
+		case *ast.AssignStmt:
+			if node.Tok == token.DEFINE {
+				return ok
+			}
+			for i, lhs := range node.Lhs {
+				name, write := v.outerWrite(node, i, lhs, local, loopVars)
+				switch write {
+				case "invalid":
+					ok = false
+				case "shared":
+					if !seen[name] {
+						seen[name] = true
+						shared = append(shared, name)
+					}
+				}
+			}
+		}
+		return ok
+	}
+	ast.Inspect(body, func(n ast.Node) bool { return n != nil && visit(n, false) })
+	return shared, ok
+}
+
+// errorReturns returns the return statements inside if err != nil blocks,
+// which an errgroup reproduces by returning the first error
+func (v *workerPoolVisitor) errorReturns(body *ast.BlockStmt) map[*ast.ReturnStmt]bool {
+	returns := make(map[*ast.ReturnStmt]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		ifStmt, ok := n.(*ast.IfStmt)
+		if !ok || !v.isErrorCheck(ifStmt.Cond) {
+			return true
+		}
+		ast.Inspect(ifStmt.Body, func(inner ast.Node) bool {
+			switch node := inner.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				returns[node] = true
+			}
+			return true
+		})
+		return true
+	})
+	return returns
+}
+
+// isErrorCheck matches err != nil, judging the variable by its type when
+// known and by its name otherwise
+func (v *workerPoolVisitor) isErrorCheck(cond ast.Expr) bool {
+	binary, ok := cond.(*ast.BinaryExpr)
+	if !ok || binary.Op != token.NEQ || !isNilIdent(binary.Y) {
+		return false
+	}
+	ident, ok := binary.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	if v.context != nil && v.context.TypeInfo != nil {
+		if t := v.context.TypeInfo.TypeOf(ident); t != nil {
+			return types.Identical(t, types.Universe.Lookup("error").Type())
+		}
+	}
+	return ident.Name == "err"
+}
+
+// outerWrite classifies an assignment target: "" for local variables,
+// per-iteration slots, and err; "shared" for slices appended to and maps
+// written outside the loop; "invalid" for anything carried between
+// iterations
+func (v *workerPoolVisitor) outerWrite(assign *ast.AssignStmt, i int, lhs ast.Expr, local, loopVars map[string]bool) (string, string) {
+	if identName(lhs) == "_" {
+		return "", ""
+	}
+	root := rootIdent(lhs)
+	if root == nil || loopVars[root.Name] {
+		return "", "invalid"
+	}
+	if local[root.Name] {
+		return "", ""
+	}
+	if assign.Tok != token.ASSIGN {
+		return "", "invalid" // total += x
+	}
+
+	switch target := lhs.(type) {
+	case *ast.Ident:
+		if target.Name == "err" {
+			return "", "" // The errgroup returns the first error
+		}
+		if len(assign.Rhs) == len(assign.Lhs) {
+			if call, ok := assign.Rhs[i].(*ast.CallExpr); ok && isIdentNamed(call.Fun, "append") &&
+				len(call.Args) > 0 && isIdentNamed(call.Args[0], target.Name) {
+				return target.Name, "shared"
+			}
+		}
+	case *ast.IndexExpr:
+		if index, ok := target.Index.(*ast.Ident); ok && loopVars[index.Name] && v.kindOf(target.X) != "map" {
+			return "", "" // results[i] = ..., one slot per iteration
+		}
+		if v.kindOf(target.X) != "slice" {
+			return types.ExprString(target.X), "shared"
+		}
+	}
+	return "", "invalid"
+}
+
+// kindOf returns "slice", "map", or "" when unknown
+func (v *workerPoolVisitor) kindOf(expr ast.Expr) string {
+	if v.context == nil || v.context.TypeInfo == nil {
+		return ""
+	}
+	t := v.context.TypeInfo.TypeOf(expr)
+	if t == nil {
+		return ""
+	}
+	switch t.Underlying().(type) {
+	case *types.Slice, *types.Array:
+		return "slice"
+	case *types.Map:
+		return "map"
+	}
+	return ""
+}
+
+// childNodes returns the parts of a loop or switch statement to inspect
+func childNodes(node ast.Node) []ast.Node {
+	switch n := node.(type) {
+	case *ast.ForStmt:
+		return []ast.Node{n.Body}
+	case *ast.RangeStmt:
+		return []ast.Node{n.Body}
+	case *ast.SwitchStmt:
+		return []ast.Node{n.Body}
+	case *ast.TypeSwitchStmt:
+		return []ast.Node{n.Body}
+	}
+	return nil
+}
+
+// exampleCode rewrites the loop with errgroup, keeping its own header and
+// body so the suggestion uses the function's actual names
+func (v *workerPoolVisitor) exampleCode(loop ast.Node, loopVars map[string]bool, shared []string) string {
+	var header string
+	switch l := loop.(type) {
+	case *ast.RangeStmt:
+		header = "for range " + v.nodeText(l.X)
+		if l.Key != nil {
+			header = "for " + v.nodeText(l.Key)
+			if l.Value != nil {
+				header += ", " + v.nodeText(l.Value)
+			}
+			header += " := range " + v.nodeText(l.X)
+		}
+	case *ast.ForStmt:
+		header = fmt.Sprintf("for %s; %s; %s", v.nodeText(l.Init), v.nodeText(l.Cond), v.nodeText(l.Post))
+	}
+
+	var code strings.Builder
+	code.WriteString("var g errgroup.Group\n")
+	fmt.Fprintf(&code, "g.SetLimit(%d)\n", v.settings.Limit)
+	if len(shared) > 0 {
+		code.WriteString("var mu sync.Mutex // Guards " + strings.Join(shared, ", ") + "\n")
+	}
+	code.WriteString(header + " {\n")
+	if v.goVersion != "" && version.Compare("go"+v.goVersion, "go1.22") < 0 {
+		for _, name := range slices.Sorted(maps.Keys(loopVars)) {
+			fmt.Fprintf(&code, "    %s := %s // Per-iteration copy before Go 1.22\n", name, name)
+		}
+	}
+	code.WriteString("    g.Go(func() error {\n")
+	if returnsResults(getLoopBody(loop)) {
+		code.WriteString("        // Return only the error here; store results in captured variables\n")
+	}
+	for _, stmt := range getLoopBody(loop) {
+		for _, line := range strings.Split(v.nodeText(stmt), "\n") {
+			code.WriteString("        " + line + "\n")
+		}
+	}
+	code.WriteString("        return nil\n")
+	code.WriteString("    })\n")
+	code.WriteString("}\n")
+	code.WriteString("if err := g.Wait(); err != nil {\n")
+	code.WriteString("    return err\n")
+	code.WriteString("}")
+	return code.String()
+}
+
+func (v *workerPoolVisitor) nodeText(node ast.Node) string {
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces, Tabwidth: 4}
+	if err := cfg.Fprint(&buf, v.fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// returnsResults reports whether the loop body returns more than an error,
+// which a g.Go function can't
+func returnsResults(body []ast.Stmt) bool {
+	found := false
+	for _, stmt := range body {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				if len(node.Results) > 1 {
+					found = true
+				}
+			}
+			return !found
+		})
+	}
+	return found
+}
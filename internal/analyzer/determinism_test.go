@@ -0,0 +1,63 @@
+package analyzer_test
+
+import (
+	"bytes"
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/models"
+)
+
+var reportFormats = []string{"console", "json", "jsonl", "csv", "quickfix", "sarif", "sonar", "html"}
+
+// TestReportsAreDeterministic analyzes every testdata package twice with
+// fresh analyzers, the second time with its files shuffled, and requires
+// every report format to come out byte-identical
+func TestReportsAreDeterministic(t *testing.T) {
+	for i, dir := range testPackages(t) {
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			t.Parallel()
+			files := packageFiles(t, dir)
+			shuffled := slices.Clone(files)
+			rng := rand.New(rand.NewSource(int64(i)))
+			rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+			if len(files) > 1 && slices.Equal(shuffled, files) {
+				slices.Reverse(shuffled)
+			}
+
+			want, got := reports(t, files), reports(t, shuffled)
+			for _, format := range reportFormats {
+				if !bytes.Equal(got[format], want[format]) {
+					t.Errorf("%s report differs with files in order %v", format, shuffled)
+				}
+			}
+		})
+	}
+}
+
+// reports analyzes files and renders the result in every format
+func reports(t *testing.T, files []string) map[string][]byte {
+	t.Helper()
+	result, err := analyzer.NewAnalyzerWithConfig(testConfig()).AnalyzeFiles(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clearTimings(result)
+
+	rendered := make(map[string][]byte)
+	for _, format := range reportFormats {
+		cfg := testConfig()
+		cfg.Output.Format = format
+		rendered[format] = []byte(analyzer.NewReportGeneratorWithConfig(cfg).Generate(result))
+	}
+	return rendered
+}
+
+// clearTimings drops the parts of a result expected to differ between runs
+func clearTimings(result *models.AnalysisResult) {
+	result.AnalysisDuration = ""
+	result.RuleTimings = nil
+}
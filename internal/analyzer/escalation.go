@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"cmp"
+	"fmt"
+	"gophercheck/internal/models"
+	"slices"
+)
+
+// escalateRepeats raises the issues of a type beyond the repeat_escalation
+// threshold in one function (or file) by its levels, in source order, and
+// says so in their messages
+func (a *Analyzer) escalateRepeats(issues []models.Issue) {
+	if a.config == nil || !a.config.Analysis.RepeatEscalation.Enabled {
+		return
+	}
+	settings := a.config.Analysis.RepeatEscalation
+
+	type repeatKey struct {
+		issueType models.IssueType
+		scope     string // Function name, or "" per file
+	}
+	repeats := make(map[repeatKey][]int)
+	for i, issue := range issues {
+		if len(settings.Rules) > 0 && !slices.Contains(settings.Rules, string(issue.Type)) {
+			continue
+		}
+		key := repeatKey{issueType: issue.Type}
+		if settings.Scope == "function" {
+			if issue.Function == "" {
+				continue // Package-level code has no function to repeat in
+			}
+			key.scope = issue.Function
+		}
+		repeats[key] = append(repeats[key], i)
+	}
+
+	for key, indices := range repeats {
+		slices.SortFunc(indices, func(x, y int) int {
+			return cmp.Or(cmp.Compare(issues[x].Line, issues[y].Line), cmp.Compare(issues[x].Column, issues[y].Column))
+		})
+		where := "this file"
+		if key.scope != "" {
+			where = fmt.Sprintf("function '%s'", key.scope)
+		}
+		// Issues of one type on one line, e.g. found by several detectors
+		// with merge_duplicates off, count once
+		occurrence, lastLine := 0, 0
+		for _, i := range indices {
+			issue := &issues[i]
+			if issue.Line != lastLine {
+				occurrence, lastLine = occurrence+1, issue.Line
+			}
+			if occurrence <= settings.Threshold {
+				continue
+			}
+			escalated := issue.Severity.Upgrade(settings.Levels)
+			if escalated == issue.Severity {
+				continue // Already critical
+			}
+			// Worded without the occurrence, which would change the
+			// fingerprint whenever an earlier one is added or fixed
+			issue.Message += fmt.Sprintf(" (escalated from %s: more than %d %s issues in %s)",
+				issue.Severity, settings.Threshold, key.issueType, where)
+			issue.Severity = escalated
+		}
+	}
+}
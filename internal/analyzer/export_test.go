@@ -0,0 +1,17 @@
+package analyzer
+
+import (
+	"gophercheck/internal/analyzer/detectors"
+	"gophercheck/internal/config"
+)
+
+// NewAnalyzerWithEveryDetector returns an analyzer running every registered
+// detector, whatever cfg enables, each wrapped by wrap
+func NewAnalyzerWithEveryDetector(cfg *config.Config, wrap func(rule string, detector Detector) Detector) *Analyzer {
+	a := NewAnalyzerWithConfig(cfg)
+	a.detectors, a.rules, a.fixSafety = nil, nil, nil
+	for _, registration := range detectors.Registered() {
+		a.addDetector(registration.Rule, registration.FixSafety, wrap(registration.Rule, registration.New(cfg)))
+	}
+	return a
+}
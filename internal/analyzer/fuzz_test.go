@@ -0,0 +1,122 @@
+package analyzer_test
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"slices"
+	"testing"
+
+	"gophercheck/internal/analyzer"
+)
+
+// fuzzSeeds are shapes the testdata samples have few of
+var fuzzSeeds = []string{
+	// Method values and expressions
+	`package p
+type T struct{ buf [64]int }
+func (t T) Sum() int { return len(t.buf) }
+func (t *T) Reset() { t.buf = [64]int{} }
+func use(ts []T) (total int) {
+	for _, t := range ts {
+		sum, reset := t.Sum, (*T).Reset
+		total += sum()
+		reset(&t)
+		defer T.Sum(t)
+	}
+	return total
+}`,
+	// Generic instantiations, explicit and inferred
+	`package p
+type Pair[K comparable, V any] struct { Key K; Value V }
+type List[T any] []T
+func Map[S ~[]E, E, R any](s S, f func(E) R) []R {
+	var out []R
+	for _, v := range s { out = append(out, f(v)) }
+	return out
+}
+func use(keys []string) []Pair[string, int] {
+	lengths := Map[[]string, string, int](keys, func(s string) int { return len(s) })
+	pairs := make([]Pair[string, int], 0)
+	for i, key := range keys { pairs = append(pairs, Pair[string, int]{key, lengths[i]}) }
+	var l List[Pair[string, int]]
+	return append(l, pairs...)
+}`,
+	// Empty and missing bodies
+	`package p
+func empty() {}
+func external(n int) int
+type T struct{}
+func (T) method() {}
+func loops(ch chan int) {
+	for {}
+	for range ch {}
+	for i := 0; i < 10; i++ {}
+	func() {}()
+	go func() {}()
+	select {}
+}`,
+}
+
+// FuzzDetectors runs every registered detector over arbitrary source and
+// fails if any of them panics. The corpus is seeded with the testdata files
+// and fuzzSeeds; crashing inputs are saved to this package's
+// testdata/fuzz/FuzzDetectors and replayed by plain go test from then on.
+//
+//	go test ./internal/analyzer -run '^$' -fuzz FuzzDetectors -fuzztime 5m
+func FuzzDetectors(f *testing.F) {
+	for _, dir := range testPackages(f) {
+		for _, file := range packageFiles(f, dir) {
+			src, err := os.ReadFile(file)
+			if err != nil {
+				f.Fatal(err)
+			}
+			f.Add(src)
+		}
+	}
+	for _, seed := range fuzzSeeds {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, src []byte) {
+		cfg := testConfig()
+		cfg.Analysis.MinConfidence = 0 // Exercise every finding
+		engine := analyzer.NewAnalyzerWithEveryDetector(cfg, func(_ string, detector analyzer.Detector) analyzer.Detector {
+			return detector
+		})
+		engine.AddSource("fuzz.go", withoutImports(src))
+		result, err := engine.AnalyzeFiles([]string{"fuzz.go"})
+		if err != nil {
+			return
+		}
+		for _, failure := range result.DetectorFailures {
+			t.Errorf("detector %s panicked: %s", failure.Rule, failure.Panic)
+		}
+	})
+}
+
+// withoutImports drops the imports of src, which would otherwise be
+// type-checked from source on every input, leaving detectors on the untyped
+// fallbacks they use when type checking fails. Source that doesn't parse is
+// returned as is.
+func withoutImports(src []byte) []byte {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "fuzz.go", src, parser.ParseComments)
+	if err != nil {
+		return src
+	}
+	file.Imports = nil
+	file.Decls = slices.DeleteFunc(file.Decls, func(decl ast.Decl) bool {
+		gen, ok := decl.(*ast.GenDecl)
+		return ok && gen.Tok == token.IMPORT
+	})
+
+	var out bytes.Buffer
+	if err := format.Node(&out, fset, file); err != nil {
+		return src
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,51 @@
+package analyzer_test
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"gophercheck/internal/analyzer"
+)
+
+// TestGenericFindingsNameTheDeclaration requires issues in generic functions
+// and in methods on instantiated receivers to carry the declared name, with
+// no type arguments, and exactly the rules the generic code breaks
+func TestGenericFindingsNameTheDeclaration(t *testing.T) {
+	want := map[string][]string{
+		"Fill":      {"memory_allocation", "slice_growth"},
+		"Index":     {"memory_allocation"},
+		"Zip":       {"memory_allocation", "slice_growth"},
+		"Union":     {"memory_allocation"},
+		"Table.Sum": {"large_value_receiver"},
+		"Set.Keys":  {"slice_growth", "stdlib_loop"},
+		"Grid.Sum":  {"large_value_receiver"},
+	}
+
+	files := packageFiles(t, filepath.Join(testdataRoot, "generics"))
+	result, err := analyzer.NewAnalyzerWithConfig(testConfig()).AnalyzeFiles(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string][]string)
+	for _, issue := range result.Issues {
+		got[issue.Function] = append(got[issue.Function], string(issue.Type))
+	}
+	for function := range got {
+		slices.Sort(got[function])
+	}
+
+	functions := slices.Sorted(maps.Keys(got))
+	for function := range want {
+		if _, ok := got[function]; !ok {
+			functions = append(functions, function)
+		}
+	}
+	for _, function := range functions {
+		if !slices.Equal(got[function], want[function]) {
+			t.Errorf("%q: rules %v, want %v", function, got[function], want[function])
+		}
+	}
+}
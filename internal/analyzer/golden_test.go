@@ -0,0 +1,123 @@
+package analyzer_test
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"gophercheck/internal/analyzer"
+)
+
+var update = flag.Bool("update", false, "Rewrite the golden files with the current findings")
+
+// goldenName is the file in each testdata package listing its expected
+// findings
+const goldenName = "findings.golden.json"
+
+// finding is what a golden file records of an issue, leaving out messages and
+// suggestions so rewording them doesn't churn the expectations
+type finding struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Function string `json:"function,omitempty"`
+}
+
+func compareFindings(a, b finding) int {
+	return cmp.Or(
+		cmp.Compare(a.File, b.File),
+		cmp.Compare(a.Line, b.Line),
+		cmp.Compare(a.Rule, b.Rule),
+		cmp.Compare(a.Severity, b.Severity),
+		cmp.Compare(a.Function, b.Function),
+	)
+}
+
+// TestGoldenFindings analyzes every testdata package and compares its
+// findings against the package's golden file. Run with -update to rewrite
+// them, and review the diff before committing.
+func TestGoldenFindings(t *testing.T) {
+	for _, dir := range testPackages(t) {
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			t.Parallel()
+			got := findings(t, packageFiles(t, dir))
+			golden := filepath.Join(dir, goldenName)
+
+			if *update {
+				data, err := json.MarshalIndent(got, "", "  ")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, append(data, '\n'), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			data, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			var want []finding
+			if err := json.Unmarshal(data, &want); err != nil {
+				t.Fatalf("%s: %v", golden, err)
+			}
+			slices.SortFunc(want, compareFindings)
+
+			missing, unexpected := diffFindings(want, got)
+			for _, f := range missing {
+				t.Errorf("missing    %+v", f)
+			}
+			for _, f := range unexpected {
+				t.Errorf("unexpected %+v", f)
+			}
+		})
+	}
+}
+
+// findings analyzes files and returns their issues as golden findings,
+// sorted. A detector panicking fails the test.
+func findings(t *testing.T, files []string) []finding {
+	t.Helper()
+	result, err := analyzer.NewAnalyzerWithConfig(testConfig()).AnalyzeFiles(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, failure := range result.DetectorFailures {
+		t.Errorf("%s: detector %s panicked: %s", failure.File, failure.Rule, failure.Panic)
+	}
+
+	got := make([]finding, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		got = append(got, finding{
+			File:     filepath.Base(issue.File),
+			Line:     issue.Line,
+			Severity: issue.Severity.String(),
+			Rule:     string(issue.Type),
+			Function: issue.Function,
+		})
+	}
+	slices.SortFunc(got, compareFindings)
+	return got
+}
+
+// diffFindings returns the findings only in want and only in got, both
+// sorted, counting repeats
+func diffFindings(want, got []finding) (missing, unexpected []finding) {
+	for len(want) > 0 && len(got) > 0 {
+		switch c := compareFindings(want[0], got[0]); {
+		case c < 0:
+			missing, want = append(missing, want[0]), want[1:]
+		case c > 0:
+			unexpected, got = append(unexpected, got[0]), got[1:]
+		default:
+			want, got = want[1:], got[1:]
+		}
+	}
+	return append(missing, want...), append(unexpected, got...)
+}
@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"gophercheck/internal/models"
+)
+
+const htmlReportStyle = `body{font-family:system-ui,sans-serif;margin:2em;color:#222}
+table{border-collapse:collapse;width:100%}th,td{border-bottom:1px solid #ddd;padding:.4em;text-align:left;vertical-align:top}
+.CRITICAL{color:#b00020;font-weight:bold}.HIGH{color:#d84315}.MEDIUM{color:#b26a00}.LOW{color:#555}
+pre.diff{background:#f6f8fa;padding:.5em;overflow-x:auto}.diff .add{color:#116329}.diff .del{color:#82071e}`
+
+// generateHTML creates a standalone page with the score and a table of the
+// issues, each rule linking to its section of the rule reference when
+// output.docs_base_url is set and each suggested fix shown as a collapsed diff
+func (r *ReportGenerator) generateHTML(result *models.AnalysisResult) string {
+	var report strings.Builder
+	report.WriteString("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>GopherCheck Report</title>\n")
+	fmt.Fprintf(&report, "<style>%s</style></head><body>\n", htmlReportStyle)
+	fmt.Fprintf(&report, "<h1>GopherCheck Analysis (%d files analyzed)</h1>\n", len(result.Files))
+
+	if result.Graded() {
+		fmt.Fprintf(&report, "<p>Performance Score: %d/100 (Grade %s)", result.PerformanceScore, html.EscapeString(result.Grade))
+		if parts := categoryScores(result.CategoryScores, "%s %d"); len(parts) > 0 {
+			fmt.Fprintf(&report, " · %s", strings.Join(parts, " · "))
+		}
+		report.WriteString("</p>\n")
+	} else {
+		report.WriteString("<p>Performance Score: n/a (no files analyzed)</p>\n")
+	}
+	if result.Partial {
+		fmt.Fprintf(&report, "<p>Partial result: the time budget of %s ran out; the score only covers the files analyzed.</p>\n", html.EscapeString(result.TimeBudget))
+	}
+	fmt.Fprintf(&report, "<p>Scope: %s</p>\n", html.EscapeString(result.Scope.String()))
+
+	if len(result.Issues) == 0 {
+		report.WriteString("<p>No issues found.</p>\n</body></html>\n")
+		return report.String()
+	}
+
+	report.WriteString("<table>\n<thead><tr><th>Severity</th><th>Rule</th><th>Location</th><th>Function</th><th>Message</th></tr></thead>\n<tbody>\n")
+	for _, issue := range result.Issues {
+		rule := html.EscapeString(string(issue.Type))
+		if issue.DocsURL != "" {
+			rule = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(issue.DocsURL), rule)
+		}
+		fmt.Fprintf(&report, "<tr><td class=\"%s\">%s</td><td>%s</td><td>%s:%d</td><td>%s</td><td>%s%s</td></tr>\n",
+			issue.Severity, issue.Severity, rule,
+			html.EscapeString(issue.File), issue.Line,
+			html.EscapeString(issue.Function), html.EscapeString(issue.Message), htmlFixDiff(issue.SuggestedFix))
+	}
+	report.WriteString("</tbody>\n</table>\n</body></html>\n")
+	return report.String()
+}
+
+// htmlFixDiff renders a suggested fix's diff in a collapsed block, its added
+// and removed lines colored, or nothing when there is no diff
+func htmlFixDiff(fix *models.SuggestedFix) string {
+	if fix == nil || fix.Diff == "" {
+		return ""
+	}
+
+	var block strings.Builder
+	fmt.Fprintf(&block, "<details><summary>Suggested fix (%s): %s</summary><pre class=\"diff\">",
+		html.EscapeString(string(fix.Safety)), html.EscapeString(fix.Description))
+	for _, line := range strings.SplitAfter(strings.TrimSuffix(fix.Diff, "\n"), "\n") {
+		class := ""
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			class = "add"
+		case strings.HasPrefix(line, "-"):
+			class = "del"
+		}
+		if class == "" {
+			block.WriteString(html.EscapeString(line))
+			continue
+		}
+		fmt.Fprintf(&block, "<span class=\"%s\">%s</span>", class, html.EscapeString(line))
+	}
+	block.WriteString("</pre></details>")
+	return block.String()
+}
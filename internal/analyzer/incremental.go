@@ -0,0 +1,246 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"gophercheck/internal/analyzer/detectors"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+)
+
+// cachedFile is a parsed file kept between incremental analyses
+type cachedFile struct {
+	file      *ast.File
+	hash      [sha256.Size]byte            // Of the source the file was parsed from
+	functions map[string][sha256.Size]byte // Source hash of each function, by name
+}
+
+// AnalyzeIncremental reports the issues in changed, analyzed in the context
+// of filenames, the full set of files being watched (changed files missing
+// from it are added). Parsed files and type information are kept between
+// calls: only files whose contents changed are parsed again, only their
+// packages are type-checked again, and detectors run only on changed and
+// re-parsed files. The score and counts cover the files reported.
+func (a *Analyzer) AnalyzeIncremental(filenames, changed []string) (*models.AnalysisResult, error) {
+	startTime := time.Now()
+	result := a.newResult()
+	if a.cache == nil {
+		a.cache = make(map[string]*cachedFile)
+	}
+	a.edited = make(map[string][]string)
+
+	watched := make(map[string]bool, len(filenames))
+	for _, filename := range filenames {
+		watched[filename] = true
+	}
+	targets := make(map[string]bool, len(changed))
+	for _, filename := range changed {
+		targets[filename] = true
+	}
+	for _, filename := range changed {
+		if !watched[filename] {
+			watched[filename] = true
+			filenames = append(filenames, filename)
+		}
+	}
+
+	reparsed := make(map[string]bool)
+	broken := make(map[string]models.SkippedFile) // No longer parsing
+	for _, filename := range filenames {
+		if skipped, ok := a.oversized(filename); ok {
+			watched[filename] = false
+			if targets[filename] {
+				result.SkippedFiles = append(result.SkippedFiles, skipped)
+			}
+			continue
+		}
+		src, err := a.ReadSource(filename)
+		if err != nil {
+			watched[filename] = false // Deleted or unreadable; dropped below
+			continue
+		}
+		hash := sha256.Sum256(src)
+		cached, ok := a.cache[filename]
+		if ok && cached.hash == hash {
+			continue
+		}
+		file, err := parser.ParseFile(a.fileSet, filename, src, parser.ParseComments)
+		if err != nil {
+			broken[filename] = parseErrorSkip(filename, err)
+			continue // Keep the last version that parsed as context
+		}
+		functions := functionHashes(a.fileSet, file, src)
+		if ok {
+			a.edited[filename] = editedFunctions(cached.functions, functions)
+			a.forget(cached.file)
+		}
+		a.cache[filename] = &cachedFile{file: file, hash: hash, functions: functions}
+		a.resolveModule(filename)
+		reparsed[filename] = true
+	}
+	for filename, cached := range a.cache {
+		if !watched[filename] {
+			a.forget(cached.file)
+			delete(a.cache, filename)
+		}
+	}
+
+	a.recheckPackages(filenames, reparsed)
+
+	for _, filename := range filenames {
+		if skipped, isBroken := broken[filename]; isBroken {
+			if targets[filename] {
+				result.SkippedFiles = append(result.SkippedFiles, skipped)
+			}
+			continue
+		}
+		cached, ok := a.cache[filename]
+		if !ok || (!targets[filename] && !reparsed[filename]) {
+			continue
+		}
+		if skipped, ok := a.generated(cached.file, filename); ok {
+			if targets[filename] {
+				result.SkippedFiles = append(result.SkippedFiles, skipped)
+			}
+			continue
+		}
+		result.Files = append(result.Files, filename)
+		if module := a.modules.ModuleFor(filename); module != nil {
+			result.AddModuleFile(module.Path, a.paths.NormalizeDir(module.Dir))
+		}
+		for _, issue := range a.reportableIssues(cached.file, filename) {
+			result.AddIssue(issue)
+		}
+	}
+
+	a.finishResult(result, startTime)
+	return result, nil
+}
+
+// EditedFunctions returns the names of the functions in filename whose source
+// changed in the last AnalyzeIncremental call, including added and removed
+// functions. Moving a function without editing it doesn't count.
+func (a *Analyzer) EditedFunctions(filename string) []string {
+	return a.edited[filename]
+}
+
+// functionHashes hashes the source of every function declared in file, by
+// the name issues give it (Type.Method for methods)
+func functionHashes(fset *token.FileSet, file *ast.File, src []byte) map[string][sha256.Size]byte {
+	tokenFile := fset.File(file.Pos())
+	sources := make(map[string][]byte)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		start, end := tokenFile.Offset(fn.Pos()), tokenFile.Offset(fn.End())
+		name := detectors.DeclaredName(fn)
+		sources[name] = append(sources[name], src[start:end]...)
+	}
+
+	hashes := make(map[string][sha256.Size]byte, len(sources))
+	for name, source := range sources {
+		hashes[name] = sha256.Sum256(source)
+	}
+	return hashes
+}
+
+// editedFunctions lists the functions that differ between two versions of a
+// file, in name order
+func editedFunctions(before, after map[string][sha256.Size]byte) []string {
+	var edited []string
+	for name, hash := range after {
+		if previous, ok := before[name]; !ok || previous != hash {
+			edited = append(edited, name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			edited = append(edited, name)
+		}
+	}
+	slices.Sort(edited)
+	return edited
+}
+
+// recheckPackages type-checks every package holding a re-parsed file and
+// rebuilds the analysis context. Calls, data sizes, methods, and imports span
+// files, so they are rebuilt from all files; loops only for re-parsed ones.
+func (a *Analyzer) recheckPackages(filenames []string, reparsed map[string]bool) {
+	stale := make(map[string]bool)
+	for filename := range reparsed {
+		stale[filepath.Dir(filename)] = true
+	}
+
+	var files []string
+	var parsed, all []*ast.File
+	for _, filename := range filenames {
+		cached, ok := a.cache[filename]
+		if !ok {
+			continue
+		}
+		all = append(all, cached.file)
+		if stale[filepath.Dir(filename)] {
+			files = append(files, filename)
+			parsed = append(parsed, cached.file)
+		}
+	}
+	if len(parsed) > 0 {
+		a.buildTypeInfo(parsed, files, a.newImporter())
+	}
+
+	a.context.CallGraph = make(map[string]*context.CallInfo)
+	a.context.DataSizes = make(map[string]*context.DataSizeInfo)
+	a.context.Methods = make(map[string][]*ast.FuncDecl)
+	for _, file := range all {
+		a.analyzeCallPatterns(file)
+		a.analyzeDataSizes(file)
+		a.analyzeMethods(file)
+	}
+	a.context.Imports = make(map[string][]context.Import)
+	for _, filename := range filenames {
+		if cached, ok := a.cache[filename]; ok {
+			a.analyzeImports(a.fileSet, cached.file, filename)
+		}
+	}
+	for _, filename := range filenames {
+		if cached, ok := a.cache[filename]; ok && reparsed[filename] {
+			a.analyzeLoopPatterns(cached.file)
+		}
+	}
+}
+
+// forget drops everything recorded about a file that is being replaced or is
+// no longer analyzed, so the shared maps don't grow on every change
+func (a *Analyzer) forget(file *ast.File) {
+	info := a.context.TypeInfo
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case nil:
+			return false
+		case *ast.Ident:
+			delete(info.Defs, node)
+			delete(info.Uses, node)
+		case *ast.SelectorExpr:
+			delete(info.Selections, node)
+		case *ast.ForStmt, *ast.RangeStmt:
+			delete(a.context.LoopContext, node)
+		}
+		if expr, ok := n.(ast.Expr); ok {
+			delete(info.Types, expr)
+		}
+		delete(info.Scopes, n)
+		return true
+	})
+
+	if tokenFile := a.fileSet.File(file.Pos()); tokenFile != nil {
+		a.fileSet.RemoveFile(tokenFile)
+	}
+}
@@ -0,0 +1,42 @@
+package analyzer
+
+import (
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+)
+
+// IssueLimiter enforces the max_issues and max_issues_per_rule output limits,
+// counting what it turns away so reports can say how much was omitted
+type IssueLimiter struct {
+	maxIssues  int
+	maxPerRule int
+	reported   int
+	perRule    map[models.IssueType]int
+	Omitted    int
+}
+
+func NewIssueLimiter(cfg *config.Config) *IssueLimiter {
+	limiter := &IssueLimiter{perRule: make(map[models.IssueType]int)}
+	if cfg != nil {
+		limiter.maxIssues = cfg.Output.MaxIssues
+		limiter.maxPerRule = cfg.Output.MaxIssuesPerRule
+	}
+	return limiter
+}
+
+// Allow reports whether issue still fits within the limits, counting it
+// either way
+func (l *IssueLimiter) Allow(issue models.Issue) bool {
+	if l.Full() || (l.maxPerRule > 0 && l.perRule[issue.Type] >= l.maxPerRule) {
+		l.Omitted++
+		return false
+	}
+	l.reported++
+	l.perRule[issue.Type]++
+	return true
+}
+
+// Full reports whether the overall max_issues limit has been reached
+func (l *IssueLimiter) Full() bool {
+	return l.maxIssues > 0 && l.reported >= l.maxIssues
+}
@@ -0,0 +1,405 @@
+package analyzer
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+
+	"gophercheck/internal/analyzer/detectors"
+	"gophercheck/internal/models"
+)
+
+// MetricsFormats are the output formats of FormatMetrics
+var MetricsFormats = []string{"console", "csv", "json"}
+
+// AnalyzeMetrics analyzes filenames like AnalyzeFiles and also measures every
+// function declared in them, counting the issues reported inside each
+func (a *Analyzer) AnalyzeMetrics(filenames []string) ([]models.FunctionMetrics, *models.AnalysisResult, error) {
+	a.collectMetrics = true
+	a.functions = nil
+	a.fileLOC = make(map[string]int)
+	defer func() { a.collectMetrics = false }()
+
+	result, err := a.AnalyzeFiles(filenames)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	issueLines := make(map[string][]int)
+	for _, issue := range result.Issues {
+		issueLines[issue.File] = append(issueLines[issue.File], issue.Line)
+	}
+	functions := a.functions
+	slices.SortStableFunc(functions, func(x, y models.FunctionMetrics) int {
+		return cmp.Or(cmp.Compare(x.File, y.File), cmp.Compare(x.Line, y.Line))
+	})
+	for i := range functions {
+		for _, line := range issueLines[functions[i].File] {
+			if line >= functions[i].Line && line <= functions[i].EndLine {
+				functions[i].Issues++
+			}
+		}
+	}
+	a.functions = nil
+	return functions, result, nil
+}
+
+// fileMetrics measures the functions declared in file
+func (a *Analyzer) fileMetrics(file *ast.File, filename string) []models.FunctionMetrics {
+	var functions []models.FunctionMetrics
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		if a.symbols.active() && !a.symbols.matchesFunction(fn) {
+			continue
+		}
+
+		cognitive := &cognitiveCounter{name: fn.Name.Name}
+		cognitive.stmts(fn.Body.List, 0)
+
+		metrics := models.FunctionMetrics{
+			File:       filename,
+			Line:       a.fileSet.Position(fn.Pos()).Line,
+			EndLine:    a.fileSet.Position(fn.End()).Line,
+			Function:   fn.Name.Name,
+			LOC:        detectors.LinesOfCode(a.fileSet, fn.Body),
+			Cyclomatic: detectors.CyclomaticComplexity(fn.Body),
+			Cognitive:  cognitive.total,
+			Nesting:    cognitive.maxNesting,
+			Params:     paramCount(fn.Type),
+		}
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			metrics.Receiver = types.ExprString(fn.Recv.List[0].Type)
+		}
+		functions = append(functions, metrics)
+	}
+	return functions
+}
+
+// codeLines counts the lines of a file holding code, the same way function
+// lengths are counted
+func codeLines(fset *token.FileSet, file *ast.File) int {
+	lines := make(map[int]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n.(type) {
+		case nil:
+			return false
+		case *ast.CommentGroup:
+			return false
+		}
+		lines[fset.Position(n.Pos()).Line] = true
+		return true
+	})
+	return len(lines)
+}
+
+// paramCount counts parameters, with a, b int counting as two
+func paramCount(fnType *ast.FuncType) int {
+	if fnType.Params == nil {
+		return 0
+	}
+	count := 0
+	for _, field := range fnType.Params.List {
+		count += max(len(field.Names), 1)
+	}
+	return count
+}
+
+// cognitiveCounter computes cognitive complexity: like cyclomatic complexity
+// it counts branches and loops, but each one costs more the deeper it is
+// nested, while else branches, runs of the same boolean operator, and
+// switch cases add only one
+type cognitiveCounter struct {
+	name       string // Function being measured, for recursion
+	total      int
+	maxNesting int
+}
+
+func (c *cognitiveCounter) stmts(list []ast.Stmt, nesting int) {
+	for _, stmt := range list {
+		c.stmt(stmt, nesting)
+	}
+}
+
+// nest records entering a block at the given nesting level
+func (c *cognitiveCounter) nest(nesting int) int {
+	c.maxNesting = max(c.maxNesting, nesting)
+	return nesting
+}
+
+func (c *cognitiveCounter) stmt(stmt ast.Stmt, nesting int) {
+	switch s := stmt.(type) {
+	case *ast.IfStmt:
+		c.total += 1 + nesting
+		c.ifChain(s, nesting)
+
+	case *ast.ForStmt:
+		c.total += 1 + nesting
+		c.nodes(nesting, s.Init, s.Cond, s.Post)
+		c.stmts(s.Body.List, c.nest(nesting+1))
+
+	case *ast.RangeStmt:
+		c.total += 1 + nesting
+		c.nodes(nesting, s.X)
+		c.stmts(s.Body.List, c.nest(nesting+1))
+
+	case *ast.SwitchStmt:
+		c.total += 1 + nesting
+		c.nodes(nesting, s.Init, s.Tag)
+		c.clauses(s.Body, nesting)
+
+	case *ast.TypeSwitchStmt:
+		c.total += 1 + nesting
+		c.nodes(nesting, s.Init, s.Assign)
+		c.clauses(s.Body, nesting)
+
+	case *ast.SelectStmt:
+		c.total += 1 + nesting
+		c.clauses(s.Body, nesting)
+
+	case *ast.BranchStmt:
+		if s.Tok == token.GOTO || s.Label != nil {
+			c.total++
+		}
+
+	case *ast.LabeledStmt:
+		c.stmt(s.Stmt, nesting)
+
+	case *ast.BlockStmt:
+		c.stmts(s.List, nesting)
+
+	default:
+		c.nodes(nesting, stmt)
+	}
+}
+
+// ifChain counts an if statement's condition and branches; else and else if
+// add one each, without a nesting penalty
+func (c *cognitiveCounter) ifChain(s *ast.IfStmt, nesting int) {
+	c.nodes(nesting, s.Init, s.Cond)
+	c.stmts(s.Body.List, c.nest(nesting+1))
+	switch elseBranch := s.Else.(type) {
+	case *ast.IfStmt:
+		c.total++
+		c.ifChain(elseBranch, nesting)
+	case *ast.BlockStmt:
+		c.total++
+		c.stmts(elseBranch.List, c.nest(nesting+1))
+	}
+}
+
+func (c *cognitiveCounter) clauses(body *ast.BlockStmt, nesting int) {
+	for _, clause := range body.List {
+		switch cl := clause.(type) {
+		case *ast.CaseClause:
+			for _, expr := range cl.List {
+				c.nodes(nesting, expr)
+			}
+			c.stmts(cl.Body, c.nest(nesting+1))
+		case *ast.CommClause:
+			c.nodes(nesting, cl.Comm)
+			c.stmts(cl.Body, c.nest(nesting+1))
+		}
+	}
+}
+
+// nodes counts the expressions in simple statements and conditions: boolean
+// operator runs, recursive calls, and the bodies of function literals
+func (c *cognitiveCounter) nodes(nesting int, nodes ...ast.Node) {
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch e := n.(type) {
+			case *ast.FuncLit:
+				c.stmts(e.Body.List, c.nest(nesting+1))
+				return false
+			case *ast.BinaryExpr:
+				if e.Op == token.LAND || e.Op == token.LOR {
+					c.total += c.booleanRuns(e)
+					for _, operand := range logicalOperands(e) {
+						c.nodes(nesting, operand)
+					}
+					return false
+				}
+			case *ast.CallExpr:
+				if ident, ok := detectors.Uninstantiate(e.Fun).(*ast.Ident); ok && ident.Name == c.name {
+					c.total++ // Recursion
+				}
+			}
+			return true
+		})
+	}
+}
+
+// booleanRuns counts the runs of identical operators in a chain of && and
+// ||: a && b && c is one run, a && b || c two
+func (c *cognitiveCounter) booleanRuns(expr *ast.BinaryExpr) int {
+	var ops []token.Token
+	var walk func(ast.Expr)
+	walk = func(e ast.Expr) {
+		if paren, ok := e.(*ast.ParenExpr); ok {
+			e = paren.X
+		}
+		binary, ok := e.(*ast.BinaryExpr)
+		if !ok || (binary.Op != token.LAND && binary.Op != token.LOR) {
+			return
+		}
+		walk(binary.X)
+		ops = append(ops, binary.Op)
+		walk(binary.Y)
+	}
+	walk(expr)
+
+	runs := 0
+	for i, op := range ops {
+		if i == 0 || op != ops[i-1] {
+			runs++
+		}
+	}
+	return runs
+}
+
+// logicalOperands returns the operands of a chain of && and ||
+func logicalOperands(expr ast.Expr) []ast.Node {
+	if paren, ok := expr.(*ast.ParenExpr); ok {
+		expr = paren.X
+	}
+	binary, ok := expr.(*ast.BinaryExpr)
+	if !ok || (binary.Op != token.LAND && binary.Op != token.LOR) {
+		return []ast.Node{expr}
+	}
+	return append(logicalOperands(binary.X), logicalOperands(binary.Y)...)
+}
+
+// FormatMetrics renders function metrics as a console table, CSV, or JSON
+func FormatMetrics(functions []models.FunctionMetrics, format string) (string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "console":
+		w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, "LOC\tCYCLO\tCOGN\tNEST\tPARAMS\tISSUES\t  FUNCTION")
+		for _, m := range functions {
+			fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\t%d\t  %s (%s:%d)\n",
+				m.LOC, m.Cyclomatic, m.Cognitive, m.Nesting, m.Params, m.Issues, m.QualifiedName(), m.File, m.Line)
+		}
+		if err := w.Flush(); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&buf, "\nFunctions measured: %d\n", len(functions))
+
+	case "csv":
+		if err := WriteMetricsCSV(&buf, functions); err != nil {
+			return "", err
+		}
+
+	case "json":
+		if functions == nil {
+			functions = []models.FunctionMetrics{}
+		}
+		data, err := json.MarshalIndent(functions, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+
+	default:
+		return "", fmt.Errorf("invalid metrics format: %s (valid: %v)", format, MetricsFormats)
+	}
+	return buf.String(), nil
+}
+
+// WriteMetricsCSV writes one row per function, after a header row
+func WriteMetricsCSV(out io.Writer, functions []models.FunctionMetrics) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"file", "line", "function", "receiver", "loc", "cyclomatic", "cognitive", "nesting_depth", "params", "issues"}); err != nil {
+		return err
+	}
+	for _, m := range functions {
+		row := []string{
+			m.File, strconv.Itoa(m.Line), m.Function, m.Receiver,
+			strconv.Itoa(m.LOC), strconv.Itoa(m.Cyclomatic), strconv.Itoa(m.Cognitive),
+			strconv.Itoa(m.Nesting), strconv.Itoa(m.Params), strconv.Itoa(m.Issues),
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// PackageMetrics measures the coupling of every package in the import graph
+// of the last analysis, ordered by package path
+func (a *Analyzer) PackageMetrics() []models.PackageMetrics {
+	return detectors.PackageCoupling(a.config, a.context)
+}
+
+// FormatPackageMetrics renders package coupling metrics as a table, CSV, or
+// JSON
+func FormatPackageMetrics(packages []models.PackageMetrics, format string) (string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "console":
+		w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, "FAN-IN\tFAN-OUT\tINSTABILITY\t  PACKAGE")
+		for _, m := range packages {
+			hub := ""
+			if m.Hub {
+				hub = " (hub)"
+			}
+			fmt.Fprintf(w, "%d\t%d\t%.2f\t  %s%s\n", m.FanIn, m.FanOut, m.Instability, m.Package, hub)
+		}
+		if err := w.Flush(); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&buf, "\nPackages measured: %d\n", len(packages))
+
+	case "csv":
+		w := csv.NewWriter(&buf)
+		if err := w.Write([]string{"package", "fan_in", "fan_out", "instability", "hub"}); err != nil {
+			return "", err
+		}
+		for _, m := range packages {
+			row := []string{
+				m.Package, strconv.Itoa(m.FanIn), strconv.Itoa(m.FanOut),
+				strconv.FormatFloat(m.Instability, 'f', 2, 64), strconv.FormatBool(m.Hub),
+			}
+			if err := w.Write(row); err != nil {
+				return "", err
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return "", err
+		}
+
+	case "json":
+		if packages == nil {
+			packages = []models.PackageMetrics{}
+		}
+		data, err := json.MarshalIndent(packages, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+
+	default:
+		return "", fmt.Errorf("invalid metrics format: %s (valid: %v)", format, MetricsFormats)
+	}
+	return buf.String(), nil
+}
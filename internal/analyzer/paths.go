@@ -0,0 +1,85 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"strings"
+
+	"gophercheck/internal/models"
+	"gophercheck/internal/workspace"
+)
+
+// pathNormalizer rewrites file paths so the same file is reported identically
+// regardless of how it was passed on the command line
+type pathNormalizer struct {
+	mode     string              // "relative" or "absolute"
+	modules  *workspace.Resolver // Finds the module or go.work root of a file
+	original map[string]string   // normalized path -> path used for parsing
+}
+
+func newPathNormalizer(mode string, modules *workspace.Resolver) *pathNormalizer {
+	return &pathNormalizer{
+		mode:     mode,
+		modules:  modules,
+		original: make(map[string]string),
+	}
+}
+
+// Normalize returns the reported form of filename: relative to the go.work
+// or module root with forward slashes in relative mode, or an absolute path
+// in absolute mode. Files outside any module fall back to their absolute path.
+// Pseudo-files such as "<stdin>" are reported verbatim.
+func (n *pathNormalizer) Normalize(filename string) string {
+	if strings.HasPrefix(filename, "<") {
+		return filename
+	}
+
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return filename
+	}
+
+	normalized := abs
+	if n.mode != "absolute" {
+		if root := n.modules.Root(abs); root != "" {
+			if rel, err := filepath.Rel(root, abs); err == nil {
+				normalized = filepath.ToSlash(rel)
+			}
+		}
+	}
+
+	n.original[normalized] = filename
+	return normalized
+}
+
+// Original maps a normalized path back to the path that was analyzed
+func (n *pathNormalizer) Original(normalized string) string {
+	if original, ok := n.original[normalized]; ok {
+		return original
+	}
+	return normalized
+}
+
+// NormalizeDir returns the reported form of a directory, "." for the root
+func (n *pathNormalizer) NormalizeDir(dir string) string {
+	if n.mode == "absolute" {
+		return dir
+	}
+	if root := n.modules.Root(filepath.Join(dir, "go.mod")); root != "" {
+		if rel, err := filepath.Rel(root, dir); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return dir
+}
+
+// normalizeIssue rewrites the file references of an issue in place
+func (n *pathNormalizer) normalizeIssue(issue *models.Issue) {
+	original := issue.File
+	issue.File = n.Normalize(original)
+	if strings.HasPrefix(issue.CodeSnippet, original) {
+		issue.CodeSnippet = issue.File + strings.TrimPrefix(issue.CodeSnippet, original)
+	}
+	for i := range issue.Cycle {
+		issue.Cycle[i].File = n.Normalize(issue.Cycle[i].File)
+	}
+}
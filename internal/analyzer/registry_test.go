@@ -0,0 +1,79 @@
+package analyzer_test
+
+import (
+	"go/ast"
+	"go/token"
+	"sync"
+	"testing"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/analyzer/detectors"
+	"gophercheck/internal/config"
+	"gophercheck/internal/context"
+	"gophercheck/internal/models"
+)
+
+// countingDetector counts the issues a detector finds
+type countingDetector struct {
+	analyzer.Detector
+	mu     *sync.Mutex
+	counts map[string]int
+	rule   string
+}
+
+func (d countingDetector) Detect(file *ast.File, fset *token.FileSet, filename string, ctx *context.AnalysisContext) []models.Issue {
+	issues := d.Detector.Detect(file, fset, filename, ctx)
+	d.mu.Lock()
+	d.counts[d.rule] += len(issues)
+	d.mu.Unlock()
+	return issues
+}
+
+// TestEveryDetectorRunsOverTestdata runs every registered detector, enabled
+// by default or not, over every testdata package. It fails if a detector
+// panics on any file or finds nothing anywhere, which leaves it untested.
+func TestEveryDetectorRunsOverTestdata(t *testing.T) {
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	wrap := func(rule string, detector analyzer.Detector) analyzer.Detector {
+		return countingDetector{Detector: detector, mu: &mu, counts: counts, rule: rule}
+	}
+
+	// One run over every package, so detectors of the import graph see
+	// testdata/layers import across packages
+	var files []string
+	for _, dir := range testPackages(t) {
+		files = append(files, packageFiles(t, dir)...)
+	}
+	result, err := analyzer.NewAnalyzerWithEveryDetector(registryConfig(), wrap).AnalyzeFiles(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, failure := range result.DetectorFailures {
+		t.Errorf("%s: detector %s panicked: %s", failure.File, failure.Rule, failure.Panic)
+	}
+
+	for _, registration := range detectors.Registered() {
+		if counts[registration.Rule] == 0 {
+			t.Errorf("detector %s found nothing in testdata", registration.Rule)
+		}
+	}
+}
+
+// registryConfig adds to the test defaults what the detectors of the import
+// graph need to report testdata/layers: a dependency rule it breaks, hub
+// thresholds its two packages reach, and its two-package cycle counted
+// although the paths contain /testdata
+func registryConfig() *config.Config {
+	cfg := testConfig()
+	cfg.Rules.Quality.ImportCycles.MaxCycleLength = 1
+	cfg.Rules.Quality.ImportCycles.IgnoreTestPackages = false
+	cfg.Rules.Quality.DependencyRules.Rules = []config.DependencyRule{{
+		From:   "testdata/layers/model",
+		Deny:   []string{"testdata/layers/store"},
+		Reason: "models don't know how they're stored",
+	}}
+	cfg.Rules.Quality.PackageCoupling.HubFanIn = 1
+	cfg.Rules.Quality.PackageCoupling.HubFanOut = 1
+	return cfg
+}
@@ -1,11 +1,16 @@
 package analyzer
 
 import (
+	"cmp"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"gophercheck/internal/config"
 	"gophercheck/internal/models"
@@ -36,14 +41,93 @@ func NewReportGeneratorWithConfig(cfg *config.Config) *ReportGenerator {
 
 // Generate creates a formatted report from analysis results
 func (r *ReportGenerator) Generate(result *models.AnalysisResult) string {
+	result = r.prepareResult(result)
+
 	switch r.format {
 	case "json":
 		return r.generateJSON(result)
+	case "jsonl":
+		return r.generateJSONL(result)
+	case "csv":
+		return r.generateCSV(result)
+	case "quickfix":
+		return r.generateQuickfix(result)
+	case "sarif":
+		return r.generateSARIF(result)
+	case "sonar":
+		return r.generateSonar(result)
+	case "html":
+		return r.generateHTML(result)
 	default:
 		return r.generateConsole(result)
 	}
 }
 
+// prepareResult applies the report-only effort filter, ordering, and issue
+// limits. The score and severity counts are left untouched so filtered
+// reports stay comparable.
+func (r *ReportGenerator) prepareResult(result *models.AnalysisResult) *models.AnalysisResult {
+	if r.config == nil {
+		prepared := *result
+		prepared.Issues = r.sortIssues(result.Issues)
+		return &prepared
+	}
+
+	issues := result.Issues
+	if maxEffort, ok := models.ParseFixEffort(r.config.Output.MaxFixEffort); ok {
+		issues = make([]models.Issue, 0, len(result.Issues))
+		for _, issue := range result.Issues {
+			if issue.FixEffort.Cost() <= maxEffort.Cost() {
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	// Limits apply after sorting so the most important issues are kept
+	limiter := NewIssueLimiter(r.config)
+	limited := make([]models.Issue, 0, len(issues))
+	for _, issue := range r.sortIssues(issues) {
+		if limiter.Allow(issue) {
+			limited = append(limited, issue)
+		}
+	}
+
+	prepared := *result
+	prepared.Issues = limited
+	prepared.OmittedIssues += limiter.Omitted
+	return &prepared
+}
+
+// sortIssues returns a sorted copy of issues according to Output.SortBy.
+// Issues start in the canonical order, which also breaks ties in the impact
+// order, so every format and section lists them the same way.
+func (r *ReportGenerator) sortIssues(issues []models.Issue) []models.Issue {
+	sortedIssues := slices.Clone(issues)
+	models.SortIssues(sortedIssues)
+
+	if r.config != nil && r.config.Output.SortBy == "impact" {
+		slices.SortStableFunc(sortedIssues, func(a, b models.Issue) int {
+			return cmp.Compare(b.ImpactScore(), a.ImpactScore())
+		})
+	}
+
+	if r.groupByOwner() {
+		// Keep the order within each owner; unowned issues go last
+		sort.SliceStable(sortedIssues, func(i, j int) bool {
+			a, b := sortedIssues[i].Owner(), sortedIssues[j].Owner()
+			if (a == "") != (b == "") {
+				return b == ""
+			}
+			return a < b
+		})
+	}
+	return sortedIssues
+}
+
+func (r *ReportGenerator) groupByOwner() bool {
+	return r.config != nil && r.config.Output.GroupBy == "owner"
+}
+
 // generateJSON creates a JSON report
 func (r *ReportGenerator) generateJSON(result *models.AnalysisResult) string {
 	data, err := json.MarshalIndent(result, "", "  ")
@@ -53,6 +137,88 @@ func (r *ReportGenerator) generateJSON(result *models.AnalysisResult) string {
 	return string(data)
 }
 
+// generateJSONL creates one compact JSON issue per line. The CLI streams this
+// format directly from the analyzer; this is used when a full result exists
+// already (e.g. watch mode).
+func (r *ReportGenerator) generateJSONL(result *models.AnalysisResult) string {
+	var report strings.Builder
+	for _, issue := range result.Issues {
+		data, err := json.Marshal(issue)
+		if err != nil {
+			return fmt.Sprintf("Error generating JSONL report: %v", err)
+		}
+		report.Write(data)
+		report.WriteString("\n")
+	}
+	return report.String()
+}
+
+// generateCSV creates one row per issue for spreadsheets. The fingerprint
+// column lets triage notes be matched to issues again after the code moves.
+func (r *ReportGenerator) generateCSV(result *models.AnalysisResult) string {
+	var report strings.Builder
+	w := csv.NewWriter(&report)
+	w.Write([]string{"fingerprint", "rule", "severity", "category", "file", "line", "function", "complexity", "confidence", "fix_effort", "message"})
+	for _, issue := range result.Issues {
+		w.Write([]string{
+			issue.Fingerprint(),
+			string(issue.Type),
+			issue.Severity.String(),
+			issue.Type.Category(),
+			issue.File,
+			strconv.Itoa(issue.Line),
+			issue.Function,
+			issue.Complexity,
+			strconv.FormatFloat(issue.Confidence, 'f', 2, 64),
+			string(issue.FixEffort),
+			issue.Message,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Sprintf("Error generating CSV report: %v", err)
+	}
+	return report.String()
+}
+
+// generateQuickfix creates one "file:line:col: kind: message" line per issue,
+// which vim's default errorformat and VS Code problem matchers understand.
+// By default CRITICAL and HIGH issues are errors, MEDIUM warnings, and LOW
+// infos; output.severity_levels.quickfix changes that. Issues are listed in
+// file order so jumping to the next one moves forward; issues on the same
+// line keep the canonical order.
+func (r *ReportGenerator) generateQuickfix(result *models.AnalysisResult) string {
+	issues := slices.Clone(result.Issues)
+	models.SortIssues(issues)
+	slices.SortStableFunc(issues, func(a, b models.Issue) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line))
+	})
+
+	levels := r.severityLevels()
+	var report strings.Builder
+	for _, issue := range issues {
+		kind := levels.Level(models.ConsumerQuickfix, issue.Severity)
+		message := strings.Join(strings.Fields(issue.Message), " ") // One line per issue
+		fmt.Fprintf(&report, "%s:%d:%d: %s: %s [%s %s]\n",
+			issue.File, issue.Line, max(issue.Column, 1), kind, message, issue.Severity, issue.Type)
+	}
+	return report.String()
+}
+
+// severityLevels returns the levels consumers give severities under the
+// report's configuration
+func (r *ReportGenerator) severityLevels() models.SeverityLevels {
+	var overrides map[string]map[string]string
+	if r.config != nil {
+		overrides = r.config.Output.SeverityLevels
+	}
+	levels, err := models.NewSeverityLevels(overrides)
+	if err != nil {
+		levels, _ = models.NewSeverityLevels(nil) // Rejected when the config was loaded
+	}
+	return levels
+}
+
 func (r *ReportGenerator) generateConsole(result *models.AnalysisResult) string {
 	useVerbose := false
 	if r.config != nil {
@@ -85,14 +251,31 @@ func (r *ReportGenerator) generateMinimalConsole(result *models.AnalysisResult)
 
 	// Issues Summary
 	r.writeIssuesSummary(&report, result, useColors)
+	r.writeModuleSummary(&report, result, useColors)
+	r.writeOwnerSummary(&report, result, useColors)
 
-	// Show only CRITICAL and HIGH issues
+	// Show only CRITICAL and HIGH issues, new ones apart when ages are known
 	highPriorityIssues := r.filterHighPriorityIssues(result.Issues)
-	if len(highPriorityIssues) > 0 {
-		r.writeHighPriorityIssues(&report, highPriorityIssues, useColors)
+	if result.Since != "" {
+		introduced, preExisting := splitByAge(highPriorityIssues)
+		if len(introduced) > 0 {
+			r.writeHighPriorityIssues(&report, fmt.Sprintf("New Critical & High Priority (since %s):", result.Since), introduced, useColors)
+		}
+		if len(preExisting) > 0 {
+			r.writeHighPriorityIssues(&report, "Pre-existing Critical & High Priority:", preExisting, useColors)
+		}
+	} else if len(highPriorityIssues) > 0 {
+		r.writeHighPriorityIssues(&report, "Critical & High Priority:", highPriorityIssues, useColors)
 	}
+	r.writeDocsLinks(&report, highPriorityIssues, useColors)
 
 	// Footer
+	r.writeOmittedNotice(&report, result, useColors)
+	r.writeSkippedNotice(&report, result, useColors, false)
+	r.writeFailureNotice(&report, result, useColors)
+	r.writeTimingNotice(&report, result, useColors, false)
+	r.writeSuppressionNotice(&report, result, useColors)
+	r.writePolicyNotice(&report, result, useColors)
 	if useColors {
 		report.WriteString(color.WhiteString("\n📊 Completed in %s\n\n", result.AnalysisDuration))
 		report.WriteString(color.WhiteString("💡 Run with --verbose for details and suggestions\n"))
@@ -145,6 +328,7 @@ func (r *ReportGenerator) generateVerboseConsole(result *models.AnalysisResult)
 		if showSuggestions {
 			report.WriteString("\n")
 			r.writeDetailedIssuesWithColors(&report, result, useColors)
+			r.writeDocsLinks(&report, result.Issues, useColors)
 		}
 	} else {
 		if useColors {
@@ -155,6 +339,12 @@ func (r *ReportGenerator) generateVerboseConsole(result *models.AnalysisResult)
 	}
 
 	// Footer
+	r.writeOmittedNotice(&report, result, useColors)
+	r.writeSkippedNotice(&report, result, useColors, true)
+	r.writeFailureNotice(&report, result, useColors)
+	r.writeTimingNotice(&report, result, useColors, true)
+	r.writeSuppressionNotice(&report, result, useColors)
+	r.writePolicyNotice(&report, result, useColors)
 	if useColors {
 		report.WriteString(color.WhiteString("Analysis completed in %s\n", result.AnalysisDuration))
 	} else {
@@ -164,8 +354,235 @@ func (r *ReportGenerator) generateVerboseConsole(result *models.AnalysisResult)
 	return report.String()
 }
 
-// writePerformanceScore writes the performance score with color coding
+// SummaryLine renders a single machine-parsable line with the headline numbers,
+// e.g. "gophercheck: score=82 grade=B critical=0 high=3 medium=17 low=5
+// performance=90 complexity=84 memory=70 quality=95 duration=1.2s", ending
+// in partial=true when the time budget ran out. A result without analyzed
+// files has score=n/a and grade=n/a, and no category scores.
+func SummaryLine(result *models.AnalysisResult) string {
+	if !result.Graded() {
+		line := fmt.Sprintf("gophercheck: score=n/a grade=n/a duration=%s", result.AnalysisDuration)
+		if result.Partial {
+			line += " partial=true"
+		}
+		return line
+	}
+	fields := []string{
+		fmt.Sprintf("score=%d grade=%s critical=%d high=%d medium=%d low=%d",
+			result.PerformanceScore, result.Grade,
+			result.IssuesBySeverity["CRITICAL"], result.IssuesBySeverity["HIGH"],
+			result.IssuesBySeverity["MEDIUM"], result.IssuesBySeverity["LOW"]),
+	}
+	fields = append(fields, categoryScores(result.CategoryScores, "%s=%d")...)
+	fields = append(fields, fmt.Sprintf("duration=%s", result.AnalysisDuration))
+	if result.Partial {
+		fields = append(fields, "partial=true")
+	}
+	return "gophercheck: " + strings.Join(fields, " ")
+}
+
+// categoryScores formats each category's sub-score with format, taking the
+// category and score, in display order
+func categoryScores(scores map[string]int, format string) []string {
+	parts := make([]string, 0, len(scores))
+	for _, category := range models.AllCategories {
+		if score, ok := scores[category]; ok {
+			parts = append(parts, fmt.Sprintf(format, category, score))
+		}
+	}
+	return parts
+}
+
+// writeOmittedNotice tells the reader when max_issues limits hid issues or
+// cut the analysis short
+func (r *ReportGenerator) writeOmittedNotice(report *strings.Builder, result *models.AnalysisResult, useColors bool) {
+	var lines []string
+	if result.OmittedIssues > 0 {
+		lines = append(lines, fmt.Sprintf("%d more issues omitted (raise --max-issues/--max-issues-per-rule to see them)", result.OmittedIssues))
+	}
+	if result.StoppedEarly {
+		lines = append(lines, "Analysis stopped at the issue limit; remaining files were not analyzed")
+	}
+	if result.Partial {
+		lines = append(lines, fmt.Sprintf("Partial result: the time budget of %s ran out after %d files; the score only covers them", result.TimeBudget, len(result.Files)))
+	}
+
+	for _, line := range lines {
+		if useColors {
+			report.WriteString(color.YellowString("\n✂️  %s\n", line))
+		} else {
+			report.WriteString(fmt.Sprintf("\n%s\n", line))
+		}
+	}
+}
+
+// writeSkippedNotice writes the scope of the analysis on one line and lists
+// files that were found but not analyzed, so a clean report isn't mistaken
+// for clean code in them. Unless listAll is set, only files skipped for
+// being too large or not parsing are listed; excluded and generated files
+// were left out on purpose.
+func (r *ReportGenerator) writeSkippedNotice(report *strings.Builder, result *models.AnalysisResult, useColors, listAll bool) {
+	if useColors {
+		report.WriteString(color.WhiteString("\n🔭 Scope: %s\n", result.Scope))
+	} else {
+		report.WriteString(fmt.Sprintf("\nScope: %s\n", result.Scope))
+	}
+
+	hidden := 0
+	for _, skipped := range result.SkippedFiles {
+		if !listAll && skipped.Kind != models.SkipTooLarge && skipped.Kind != models.SkipParseError {
+			hidden++
+			continue
+		}
+		report.WriteString(fmt.Sprintf("   %s - %s\n", skipped.File, skipped.Reason))
+	}
+	if hidden > 0 {
+		report.WriteString(fmt.Sprintf("   (%d more skipped files listed with --verbose)\n", hidden))
+	}
+}
+
+// writeFailureNotice lists detectors that crashed on a file, whose findings
+// there are missing from the report
+func (r *ReportGenerator) writeFailureNotice(report *strings.Builder, result *models.AnalysisResult, useColors bool) {
+	if len(result.DetectorFailures) == 0 {
+		return
+	}
+
+	header := fmt.Sprintf("%d detector failures (please report them as bugs):", len(result.DetectorFailures))
+	if useColors {
+		report.WriteString(color.RedString("\n💥 %s\n", header))
+	} else {
+		report.WriteString(fmt.Sprintf("\n%s\n", header))
+	}
+	for _, failure := range result.DetectorFailures {
+		report.WriteString(fmt.Sprintf("   %s on %s: %s\n", failure.Rule, failure.File, failure.Panic))
+	}
+}
+
+// writeTimingNotice lists the rules that went over analysis.rule_budget on a
+// file and, when verbose, the rules that took longest overall
+func (r *ReportGenerator) writeTimingNotice(report *strings.Builder, result *models.AnalysisResult, useColors, verbose bool) {
+	if len(result.SlowRules) > 0 {
+		header := fmt.Sprintf("%d rule runs over their time budget:", len(result.SlowRules))
+		if useColors {
+			report.WriteString(color.YellowString("\n⏳ %s\n", header))
+		} else {
+			report.WriteString(fmt.Sprintf("\n%s\n", header))
+		}
+		for _, slow := range result.SlowRules {
+			line := fmt.Sprintf("   %s took %s on %s (budget %s)", slow.Rule, formatMS(slow.DurationMS), slow.File, formatMS(slow.BudgetMS))
+			if slow.Disabled {
+				line += "; disabled for the remaining files"
+			}
+			report.WriteString(line + "\n")
+		}
+	}
+
+	if !verbose || len(result.RuleTimings) == 0 {
+		return
+	}
+	const shown = 3
+	var slowest []string
+	for _, timing := range result.RuleTimings[:min(shown, len(result.RuleTimings))] {
+		slowest = append(slowest, fmt.Sprintf("%s %s", timing.Rule, formatMS(timing.TotalMS)))
+	}
+	report.WriteString(fmt.Sprintf("\nSlowest rules: %s\n", strings.Join(slowest, ", ")))
+}
+
+// writeSuppressionNotice counts the //gophercheck:ignore directives in
+// effect, the findings they silenced, and the issues the baseline accepts,
+// and warns about directives past their until date, whose findings are back
+func (r *ReportGenerator) writeSuppressionNotice(report *strings.Builder, result *models.AnalysisResult, useColors bool) {
+	var exceptions []string
+	if result.ActiveSuppressions > 0 {
+		exceptions = append(exceptions, fmt.Sprintf("%d //gophercheck:ignore directives silenced %d findings",
+			result.ActiveSuppressions, result.SuppressedIssues))
+	}
+	if baseline := r.config.Output.Baseline; baseline != "" && result.Since == baseline && !result.BaselineExpired {
+		exceptions = append(exceptions, fmt.Sprintf("baseline %s accepts %d issues", baseline, result.TotalIssues-result.NewIssues))
+	}
+	if len(exceptions) > 0 {
+		report.WriteString(fmt.Sprintf("\nExceptions: %s (review them with gophercheck suppressions)\n", strings.Join(exceptions, "; ")))
+	}
+	if len(result.ExpiredSuppressions) == 0 {
+		return
+	}
+
+	header := fmt.Sprintf("%d expired suppressions; their findings are reported again:", len(result.ExpiredSuppressions))
+	if useColors {
+		report.WriteString(color.YellowString("\n⏰ %s\n", header))
+	} else {
+		report.WriteString(fmt.Sprintf("\n%s\n", header))
+	}
+	for _, expired := range result.ExpiredSuppressions {
+		line := fmt.Sprintf("   %s:%d %s until=%s, %d findings", expired.File, expired.Line, strings.Join(expired.Rules, ","), expired.Until, expired.Matched)
+		if expired.Reason != "" {
+			line += fmt.Sprintf(" (%s)", expired.Reason)
+		}
+		report.WriteString(line + "\n")
+	}
+}
+
+// formatMS renders milliseconds as a duration, e.g. "1.25s" or "340µs"
+func formatMS(ms float64) string {
+	d := time.Duration(ms * float64(time.Millisecond))
+	if d >= time.Millisecond {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Microsecond).String()
+}
+
+// writePolicyNotice lists the local config settings the organization policy
+// overrode, so a local override doesn't silently stop working
+func (r *ReportGenerator) writePolicyNotice(report *strings.Builder, result *models.AnalysisResult, useColors bool) {
+	if len(result.PolicyRejections) == 0 {
+		return
+	}
+
+	header := fmt.Sprintf("%d local config settings rejected by policy:", len(result.PolicyRejections))
+	if useColors {
+		report.WriteString(color.YellowString("\n🔒 %s\n", header))
+	} else {
+		report.WriteString(fmt.Sprintf("\n%s\n", header))
+	}
+	for _, rejection := range result.PolicyRejections {
+		report.WriteString(fmt.Sprintf("   %s\n", rejection))
+	}
+}
+
+// writeDocsLinks lists the documentation link of each rule among the issues
+// shown, once per rule rather than on every issue
+func (r *ReportGenerator) writeDocsLinks(report *strings.Builder, issues []models.Issue, useColors bool) {
+	links := make(map[models.IssueType]string)
+	var types []models.IssueType
+	for _, issue := range issues {
+		if _, seen := links[issue.Type]; !seen && issue.DocsURL != "" {
+			links[issue.Type] = issue.DocsURL
+			types = append(types, issue.Type)
+		}
+	}
+	if len(types) == 0 {
+		return
+	}
+	slices.Sort(types)
+
+	if useColors {
+		report.WriteString(color.WhiteString("\n📖 Rule docs:\n"))
+	} else {
+		report.WriteString("\nRule docs:\n")
+	}
+	for _, issueType := range types {
+		report.WriteString(fmt.Sprintf("   %-28s %s\n", issueType, links[issueType]))
+	}
+}
+
+// writePerformanceScore writes the performance score with color coding, or
+// that there is none when no file was analyzed
 func (r *ReportGenerator) writePerformanceScore(report *strings.Builder, result *models.AnalysisResult) {
+	if !result.Graded() {
+		report.WriteString("Performance Score: n/a (no files analyzed)\n\n")
+		return
+	}
 	score := result.PerformanceScore
 	var scoreColor func(a ...interface{}) string
 	var emoji string
@@ -201,9 +618,27 @@ func (r *ReportGenerator) writePerformanceScore(report *strings.Builder, result
 
 	if useColors {
 		scoreText := scoreColor(fmt.Sprintf("%d", score))
-		report.WriteString(fmt.Sprintf("%s Performance Score: %s/100\n\n", emoji, scoreText))
+		report.WriteString(fmt.Sprintf("%s Performance Score: %s/100 (Grade %s)\n", emoji, scoreText, scoreColor(result.Grade)))
+	} else {
+		report.WriteString(fmt.Sprintf("Performance Score: %d/100 (Grade %s)\n", score, result.Grade))
+	}
+
+	r.writeCategoryScores(report, result, useColors)
+	report.WriteString("\n")
+}
+
+// writeCategoryScores writes the per-category sub-scores on one line
+func (r *ReportGenerator) writeCategoryScores(report *strings.Builder, result *models.AnalysisResult, useColors bool) {
+	parts := categoryScores(result.CategoryScores, "%s %d")
+	if len(parts) == 0 {
+		return
+	}
+
+	line := strings.Join(parts, " · ")
+	if useColors {
+		report.WriteString(color.WhiteString("   📈 %s\n", line))
 	} else {
-		report.WriteString(fmt.Sprintf("Performance Score: %d/100\n\n", score))
+		report.WriteString(fmt.Sprintf("   Categories: %s\n", line))
 	}
 }
 
@@ -253,6 +688,126 @@ func (r *ReportGenerator) writeSummaryWithColors(report *strings.Builder, result
 	}
 	report.WriteString(fmt.Sprintf("   Files analyzed: %d\n", len(result.Files)))
 	report.WriteString(fmt.Sprintf("   Issues found: %d\n", result.TotalIssues))
+	if result.TestIssues > 0 {
+		report.WriteString(fmt.Sprintf("   In non-test files: %d, in test files: %d\n",
+			result.TotalIssues-result.TestIssues, result.TestIssues))
+	}
+	report.WriteString("\n")
+	r.writeModuleSummary(report, result, useColors)
+	r.writeOwnerSummary(report, result, useColors)
+}
+
+// writeOwnerSummary lists issues per owner when grouping by owner, owners with
+// the most critical and high issues first
+func (r *ReportGenerator) writeOwnerSummary(report *strings.Builder, result *models.AnalysisResult, useColors bool) {
+	if !r.groupByOwner() || len(result.Issues) == 0 {
+		return
+	}
+
+	type ownerCounts struct {
+		owner                 string
+		total, critical, high int
+	}
+	var owners []*ownerCounts
+	byOwner := make(map[string]*ownerCounts)
+	for _, issue := range result.Issues {
+		owner := issue.Owner()
+		counts, ok := byOwner[owner]
+		if !ok {
+			counts = &ownerCounts{owner: owner}
+			byOwner[owner] = counts
+			owners = append(owners, counts)
+		}
+		counts.total++
+		switch issue.Severity {
+		case models.SeverityCritical:
+			counts.critical++
+		case models.SeverityHigh:
+			counts.high++
+		}
+	}
+	sort.SliceStable(owners, func(i, j int) bool {
+		if owners[i].critical+owners[i].high != owners[j].critical+owners[j].high {
+			return owners[i].critical+owners[i].high > owners[j].critical+owners[j].high
+		}
+		if owners[i].total != owners[j].total {
+			return owners[i].total > owners[j].total
+		}
+		return owners[i].owner < owners[j].owner
+	})
+
+	if useColors {
+		report.WriteString(color.WhiteString("👥 Owners:\n"))
+	} else {
+		report.WriteString("Owners:\n")
+	}
+	for _, counts := range owners {
+		report.WriteString(fmt.Sprintf("   %s: %d issues", ownerLabel(counts.owner), counts.total))
+		if counts.critical+counts.high > 0 {
+			report.WriteString(fmt.Sprintf(" (%d critical, %d high)", counts.critical, counts.high))
+		}
+		report.WriteString("\n")
+	}
+	report.WriteString("\n")
+}
+
+// ownerLabel names an owner in reports
+func ownerLabel(owner string) string {
+	if owner == "" {
+		return "(unowned)"
+	}
+	return owner
+}
+
+// blameText describes the last commit to touch a flagged line, e.g.
+// "alice@example.com, 3 months ago (1a2b3c4)"
+func blameText(blame *models.LineBlame) string {
+	author := blame.Author
+	if blame.Email != "" {
+		author = blame.Email
+	}
+	commit := blame.Commit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	return fmt.Sprintf("%s, %s (%s)", author, lineAge(time.Since(blame.Time)), commit)
+}
+
+// lineAge renders how long ago a line was last changed
+func lineAge(age time.Duration) string {
+	days := int(age.Hours() / 24)
+	switch {
+	case days < 1:
+		return "today"
+	case days < 60:
+		return fmt.Sprintf("%d days ago", days)
+	case days < 730:
+		return fmt.Sprintf("%d months ago", days/30)
+	default:
+		return fmt.Sprintf("%d years ago", days/365)
+	}
+}
+
+// writeModuleSummary lists files and issues per module. Nothing is written
+// for single-module runs, where it would repeat the summary.
+func (r *ReportGenerator) writeModuleSummary(report *strings.Builder, result *models.AnalysisResult, useColors bool) {
+	if len(result.Modules) < 2 {
+		return
+	}
+
+	if useColors {
+		report.WriteString(color.WhiteString("📦 Modules:\n"))
+	} else {
+		report.WriteString("Modules:\n")
+	}
+	for _, module := range result.Modules {
+		report.WriteString(fmt.Sprintf("   %s (%s): %d files, %d issues",
+			module.Path, module.Dir, module.Files, module.TotalIssues))
+		if critical, high := module.IssuesBySeverity["CRITICAL"], module.IssuesBySeverity["HIGH"]; critical+high > 0 {
+			report.WriteString(fmt.Sprintf(" (%d critical, %d high)", critical, high))
+		}
+		report.WriteString("\n")
+	}
 	report.WriteString("\n")
 }
 
@@ -286,15 +841,47 @@ func (r *ReportGenerator) writeDetailedIssuesWithColors(report *strings.Builder,
 	}
 	report.WriteString(strings.Repeat("─", 50) + "\n\n")
 
-	sortedIssues := make([]models.Issue, len(result.Issues))
-	copy(sortedIssues, result.Issues)
+	sortedIssues := r.sortIssues(result.Issues)
+	if result.Since == "" {
+		r.writeIssueCards(report, sortedIssues, 1, useColors)
+		return
+	}
 
-	sort.Slice(sortedIssues, func(i, j int) bool {
-		return sortedIssues[i].Severity > sortedIssues[j].Severity
-	})
+	introduced, preExisting := splitByAge(sortedIssues)
+	sections := []struct {
+		title  string
+		issues []models.Issue
+	}{
+		{fmt.Sprintf("New since %s (%d)", result.Since, len(introduced)), introduced},
+		{fmt.Sprintf("Pre-existing (%d)", len(preExisting)), preExisting},
+	}
+	index := 1
+	for _, section := range sections {
+		if len(section.issues) == 0 {
+			continue
+		}
+		if useColors {
+			report.WriteString(color.WhiteString("%s:\n\n", section.title))
+		} else {
+			report.WriteString(section.title + ":\n\n")
+		}
+		r.writeIssueCards(report, section.issues, index, useColors)
+		index += len(section.issues)
+	}
+}
 
+// writeIssueCards writes a card per issue, numbered from index, under owner
+// headings when grouping by owner
+func (r *ReportGenerator) writeIssueCards(report *strings.Builder, sortedIssues []models.Issue, index int, useColors bool) {
 	for i, issue := range sortedIssues {
-		r.writeIssueCard(report, issue, i+1, useColors)
+		if r.groupByOwner() && (i == 0 || issue.Owner() != sortedIssues[i-1].Owner()) {
+			header := fmt.Sprintf("Owner: %s\n\n", ownerLabel(issue.Owner()))
+			if useColors {
+				header = color.WhiteString("👥 %s", header)
+			}
+			report.WriteString(header)
+		}
+		r.writeIssueCard(report, issue, index+i, useColors)
 		report.WriteString("\n")
 	}
 }
@@ -337,6 +924,30 @@ func (r *ReportGenerator) writeIssueCard(report *strings.Builder, issue models.I
 			r.writeCardLine(report, complexityText, cardWidth)
 		}
 
+		// Confidence
+		confidenceText := fmt.Sprintf(" 🎯 Confidence: %.0f%%", issue.Confidence*100)
+		r.writeCardLine(report, confidenceText, cardWidth)
+
+		// Impact and effort
+		if issue.Impact != "" {
+			impactText := fmt.Sprintf(" 💰 %s (%s fix)", issue.Impact, issue.FixEffort)
+			r.writeCardLine(report, impactText, cardWidth)
+		}
+
+		// Merged findings
+		if len(issue.RelatedTypes) > 0 {
+			relatedText := fmt.Sprintf(" 🔗 Also: %s", r.joinIssueTypes(issue.RelatedTypes))
+			r.writeCardLine(report, relatedText, cardWidth)
+		}
+
+		// Ownership
+		if len(issue.Owners) > 0 {
+			r.writeCardLine(report, fmt.Sprintf(" 👥 %s", strings.Join(issue.Owners, " ")), cardWidth)
+		}
+		if issue.Blame != nil {
+			r.writeCardLine(report, fmt.Sprintf(" 📝 %s", blameText(issue.Blame)), cardWidth)
+		}
+
 		// Brief message (truncated)
 		messageText := fmt.Sprintf(" 💭 %s", r.truncateMessage(issue.Message, cardWidth-6))
 		r.writeCardLine(report, messageText, cardWidth)
@@ -357,6 +968,11 @@ func (r *ReportGenerator) writeIssueCard(report *strings.Builder, issue models.I
 			}
 		}
 
+		if issue.SuggestedFix != nil {
+			r.writeCardLine(report, "", cardWidth)
+			r.writeCardLine(report, fmt.Sprintf(" 🔧 Fix available (%s): %s", issue.SuggestedFix.Safety, fixCommand(issue.SuggestedFix)), cardWidth)
+		}
+
 		// Card footer
 		report.WriteString("└" + strings.Repeat("─", cardWidth-2) + "┘\n")
 
@@ -372,6 +988,19 @@ func (r *ReportGenerator) writeIssueCard(report *strings.Builder, issue models.I
 		if issue.Complexity != "" {
 			report.WriteString(fmt.Sprintf("Complexity: %s\n", issue.Complexity))
 		}
+		report.WriteString(fmt.Sprintf("Confidence: %.0f%%\n", issue.Confidence*100))
+		if issue.Impact != "" {
+			report.WriteString(fmt.Sprintf("Impact: %s (%s fix)\n", issue.Impact, issue.FixEffort))
+		}
+		if len(issue.RelatedTypes) > 0 {
+			report.WriteString(fmt.Sprintf("Also flagged as: %s\n", r.joinIssueTypes(issue.RelatedTypes)))
+		}
+		if len(issue.Owners) > 0 {
+			report.WriteString(fmt.Sprintf("Owners: %s\n", strings.Join(issue.Owners, " ")))
+		}
+		if issue.Blame != nil {
+			report.WriteString(fmt.Sprintf("Last changed: %s\n", blameText(issue.Blame)))
+		}
 
 		report.WriteString(fmt.Sprintf("Issue: %s\n", issue.Message))
 		report.WriteString("Suggestion:\n")
@@ -381,10 +1010,21 @@ func (r *ReportGenerator) writeIssueCard(report *strings.Builder, issue models.I
 				report.WriteString(fmt.Sprintf("  %s\n", strings.TrimSpace(line)))
 			}
 		}
+		if issue.SuggestedFix != nil {
+			report.WriteString(fmt.Sprintf("Fix available (%s): %s (%s)\n", issue.SuggestedFix.Safety, issue.SuggestedFix.Description, fixCommand(issue.SuggestedFix)))
+		}
 		report.WriteString(strings.Repeat("-", 50) + "\n")
 	}
 }
 
+func (r *ReportGenerator) joinIssueTypes(types []models.IssueType) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ", ")
+}
+
 func (r *ReportGenerator) truncateMessage(message string, maxLen int) string {
 	if len(message) <= maxLen {
 		return message
@@ -445,6 +1085,27 @@ func (r *ReportGenerator) writeIssuesSummary(report *strings.Builder, result *mo
 		report.WriteString(fmt.Sprintf("  %d CRITICAL   %d HIGH   %d MEDIUM   %d LOW\n",
 			critical, high, medium, low))
 	}
+	if result.TestIssues > 0 {
+		report.WriteString(fmt.Sprintf("  (%d in test files)\n", result.TestIssues))
+	}
+	if result.BaselineExpired {
+		report.WriteString(fmt.Sprintf("  %d new: baseline %s has expired\n", result.NewIssues, result.Since))
+	} else if result.Since != "" {
+		report.WriteString(fmt.Sprintf("  %d new since %s, %d pre-existing\n",
+			result.NewIssues, result.Since, result.TotalIssues-result.NewIssues))
+	}
+}
+
+// splitByAge separates new issues from pre-existing ones, keeping their order
+func splitByAge(issues []models.Issue) (introduced, preExisting []models.Issue) {
+	for _, issue := range issues {
+		if issue.Age == models.AgePreExisting {
+			preExisting = append(preExisting, issue)
+		} else {
+			introduced = append(introduced, issue)
+		}
+	}
+	return introduced, preExisting
 }
 
 func (r *ReportGenerator) filterHighPriorityIssues(issues []models.Issue) []models.Issue {
@@ -457,18 +1118,14 @@ func (r *ReportGenerator) filterHighPriorityIssues(issues []models.Issue) []mode
 	return highPriority
 }
 
-func (r *ReportGenerator) writeHighPriorityIssues(report *strings.Builder, issues []models.Issue, useColors bool) {
+func (r *ReportGenerator) writeHighPriorityIssues(report *strings.Builder, title string, issues []models.Issue, useColors bool) {
 	if useColors {
-		report.WriteString(color.WhiteString("\nCritical & High Priority:\n"))
+		report.WriteString(color.WhiteString("\n%s\n", title))
 	} else {
-		report.WriteString("\nCritical & High Priority:\n")
+		report.WriteString("\n" + title + "\n")
 	}
 
-	sortedIssues := make([]models.Issue, len(issues))
-	copy(sortedIssues, issues)
-	sort.Slice(sortedIssues, func(i, j int) bool {
-		return sortedIssues[i].Severity > sortedIssues[j].Severity
-	})
+	sortedIssues := r.sortIssues(issues)
 
 	for _, issue := range sortedIssues {
 		severity := issue.Severity.String()
@@ -505,18 +1162,27 @@ func (r *ReportGenerator) getShortDescription(issue models.Issue) string {
 		return fmt.Sprintf("%s() (%s)", funcName, issue.Complexity)
 	case models.IssueCyclomaticComplex:
 		return fmt.Sprintf("%s() (%s)", funcName, issue.Complexity)
-	case models.IssueNestedLoops:
+	case models.IssueTypeComplexity:
+		return issue.Complexity
+	case models.IssueNestedLoops, models.IssueInefficientSort, models.IssueIfChain:
 		return fmt.Sprintf("%s() (%s)", funcName, issue.Complexity)
 	case models.IssueMemoryAlloc:
 		return fmt.Sprintf("%s() (%s)", funcName, issue.Complexity)
 	case models.IssueSliceGrowth:
 		return fmt.Sprintf("%s() (%s)", funcName, issue.Complexity)
+	case models.IssueAppendMisuse, models.IssueMapMutation, models.IssueConcurrentMap, models.IssueLargeReceiver, models.IssueSliceRetention, models.IssueReadAll,
+		models.IssueAllocFreeAPI:
+		return fmt.Sprintf("%s() (%s)", funcName, issue.Complexity)
 	case models.IssueInefficinetDS:
 		return fmt.Sprintf("%s() (%s)", funcName, issue.Complexity)
 	case models.IssueStringConcat:
 		return fmt.Sprintf("%s() (%s)", funcName, issue.Complexity)
-	case models.IssueImportCycle:
-		return issue.Complexity // For import cycles, complexity field contains cycle info
+	case models.IssueSplitInLoop, models.IssueLogInLoop, models.IssueBuilderMisuse, models.IssueHandlerAlloc,
+		models.IssueSingleCaseSelect, models.IssueBusyWait, models.IssueErrorfInLoop, models.IssueStdlibLoop,
+		models.IssueSprintfKey, models.IssueSequentialIO, models.IssueConversionCache:
+		return fmt.Sprintf("%s() (%s)", funcName, issue.Complexity)
+	case models.IssueImportCycle, models.IssueHubPackage, models.IssueDependencyRule:
+		return issue.Complexity // For package-level issues, complexity field contains graph info
 	default:
 		return fmt.Sprintf("%s()", funcName)
 	}
@@ -563,3 +1229,15 @@ func (r *ReportGenerator) writeCardLine(report *strings.Builder, text string, ca
 
 	report.WriteString(fmt.Sprintf("│%s%s│\n", text, strings.Repeat(" ", paddingNeeded)))
 }
+
+// fixCommand tells how to apply a fix of its safety level
+func fixCommand(fix *models.SuggestedFix) string {
+	switch fix.Safety {
+	case models.FixSafe:
+		return "gophercheck fix"
+	case models.FixLikelySafe:
+		return "gophercheck fix --include-likely-safe"
+	default:
+		return "review the diff and apply it by hand"
+	}
+}
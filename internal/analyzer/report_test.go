@@ -0,0 +1,141 @@
+package analyzer_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/models"
+)
+
+func TestReportsLinkRulesToDocs(t *testing.T) {
+	const base = "https://example.com/rules.md"
+	cfg := testConfig()
+	cfg.Output.DocsBaseURL = base
+
+	result := models.NewAnalysisResultWithConfig(cfg)
+	result.Files = []string{"a.go"}
+	result.AddIssue(models.Issue{
+		Type: models.IssueSliceGrowth, Severity: models.SeverityHigh, File: "a.go", Line: 7,
+		Message: "grows <slowly>", DocsURL: models.IssueSliceGrowth.DocsURL(base),
+	})
+	result.CalculateScoreWithConfig()
+
+	link := base + "#slice_growth"
+	for format, want := range map[string]string{
+		"console": link,
+		"json":    `"docs_url": "` + link + `"`,
+		"sarif":   `"helpUri": "` + link + `"`,
+		"html":    `<a href="` + link + `">slice_growth</a>`,
+	} {
+		cfg.Output.Format = format
+		report := analyzer.NewReportGeneratorWithConfig(cfg).Generate(result)
+		if !strings.Contains(report, want) {
+			t.Errorf("%s report doesn't contain %s:\n%s", format, want, report)
+		}
+	}
+
+	cfg.Output.Format = "html"
+	if report := analyzer.NewReportGeneratorWithConfig(cfg).Generate(result); strings.Contains(report, "<slowly>") {
+		t.Errorf("html report doesn't escape messages:\n%s", report)
+	}
+}
+
+func TestSeverityLevelOverridesReachWriters(t *testing.T) {
+	cfg := testConfig()
+	cfg.Output.SeverityLevels = map[string]map[string]string{
+		"quickfix": {"MEDIUM": "info"},
+		"sarif":    {"MEDIUM": "none"},
+		"sonar":    {"MEDIUM": "INFO"},
+	}
+
+	result := models.NewAnalysisResultWithConfig(cfg)
+	result.Files = []string{"a.go"}
+	result.AddIssue(models.Issue{Type: models.IssueNestedLoops, Severity: models.SeverityMedium, File: "a.go", Line: 3, Message: "nested"})
+	result.CalculateScoreWithConfig()
+
+	for format, want := range map[string]string{
+		"quickfix": "a.go:3:1: info: nested",
+		"sarif":    `"level": "none"`,
+		"sonar":    `"severity": "INFO"`,
+	} {
+		cfg.Output.Format = format
+		if report := analyzer.NewReportGeneratorWithConfig(cfg).Generate(result); !strings.Contains(report, want) {
+			t.Errorf("%s report doesn't contain %s:\n%s", format, want, report)
+		}
+	}
+}
+
+func TestReportsShowSuggestedFixes(t *testing.T) {
+	cfg := testConfig()
+	result := models.NewAnalysisResultWithConfig(cfg)
+	result.Files = []string{"a.go"}
+	result.AddIssue(models.Issue{
+		Type: models.IssueSliceGrowth, Severity: models.SeverityHigh, File: "a.go", Line: 7, Message: "grows",
+		SuggestedFix: &models.SuggestedFix{
+			Description: "Preallocate <out>",
+			Safety:      models.FixSafe,
+			Diff:        "--- a.go\n+++ a.go\n@@ -7 +7 @@\n-\tvar out []int\n+\tout := make([]int, 0, len(in))\n",
+			TextEdits: []models.RangeEdit{{
+				Range:   models.Range{Start: models.Position{Line: 6, Character: 1}, End: models.Position{Line: 6, Character: 14}},
+				NewText: "out := make([]int, 0, len(in))",
+			}},
+		},
+	})
+	result.CalculateScoreWithConfig()
+
+	cfg.Output.Format = "sarif"
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				Fixes []struct {
+					Description struct {
+						Text string `json:"text"`
+					} `json:"description"`
+					ArtifactChanges []struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Replacements []struct {
+							DeletedRegion struct {
+								StartLine, StartColumn, EndLine, EndColumn int
+							} `json:"deletedRegion"`
+							InsertedContent struct {
+								Text string `json:"text"`
+							} `json:"insertedContent"`
+						} `json:"replacements"`
+					} `json:"artifactChanges"`
+				} `json:"fixes"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	report := analyzer.NewReportGeneratorWithConfig(cfg).Generate(result)
+	if err := json.Unmarshal([]byte(report), &log); err != nil {
+		t.Fatalf("invalid SARIF: %v\n%s", err, report)
+	}
+	fixes := log.Runs[0].Results[0].Fixes
+	if len(fixes) != 1 || len(fixes[0].ArtifactChanges) != 1 || len(fixes[0].ArtifactChanges[0].Replacements) != 1 {
+		t.Fatalf("SARIF fixes = %+v, want one fix with one replacement", fixes)
+	}
+	change := fixes[0].ArtifactChanges[0]
+	replacement := change.Replacements[0]
+	if change.ArtifactLocation.URI != "a.go" || replacement.InsertedContent.Text != "out := make([]int, 0, len(in))" {
+		t.Errorf("SARIF change = %+v, want the edit to a.go", change)
+	}
+	if region := replacement.DeletedRegion; region.StartLine != 7 || region.StartColumn != 2 || region.EndLine != 7 || region.EndColumn != 15 {
+		t.Errorf("deleted region = %+v, want 7:2-7:15, counting from 1", region)
+	}
+
+	cfg.Output.Format = "html"
+	report = analyzer.NewReportGeneratorWithConfig(cfg).Generate(result)
+	for _, want := range []string{
+		"Suggested fix (safe): Preallocate &lt;out&gt;",
+		`<span class="del">-	var out []int`,
+		`<span class="add">+	out := make([]int, 0, len(in))`,
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("html report doesn't contain %s:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"slices"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+)
+
+// ResultStore keeps the latest issues of every file analyzed during a watch
+// session. Watch mode analyzes only changed files; patching their findings
+// into the store keeps the score and counts comparable to a full run, and
+// replacing a file's issues as a whole keeps findings from earlier versions of
+// the file from piling up as duplicates.
+type ResultStore struct {
+	config *config.Config
+	files  []string                  // Reported file names, in order of first analysis
+	issues map[string][]models.Issue // Latest issues, by reported file name
+}
+
+func NewResultStore(cfg *config.Config) *ResultStore {
+	return &ResultStore{
+		config: cfg,
+		issues: make(map[string][]models.Issue),
+	}
+}
+
+// Update replaces the issues of every file analyzed in result
+func (s *ResultStore) Update(result *models.AnalysisResult) {
+	for _, file := range result.Files {
+		if _, ok := s.issues[file]; !ok {
+			s.files = append(s.files, file)
+		}
+		s.issues[file] = []models.Issue{}
+	}
+	for _, issue := range result.Issues {
+		s.issues[issue.File] = append(s.issues[issue.File], issue)
+	}
+}
+
+// Remove drops files that were deleted or are no longer watched
+func (s *ResultStore) Remove(files ...string) {
+	for _, file := range files {
+		delete(s.issues, file)
+	}
+	s.files = slices.DeleteFunc(s.files, func(file string) bool {
+		_, ok := s.issues[file]
+		return !ok
+	})
+}
+
+// Retain drops every file but files, e.g. those deleted or renamed since they
+// were analyzed, and returns the dropped ones
+func (s *ResultStore) Retain(files []string) []string {
+	keep := make(map[string]bool, len(files))
+	for _, file := range files {
+		keep[file] = true
+	}
+	var dropped []string
+	for _, file := range s.files {
+		if !keep[file] {
+			dropped = append(dropped, file)
+		}
+	}
+	s.Remove(dropped...)
+	return dropped
+}
+
+// Issues returns the latest issues of one file
+func (s *ResultStore) Issues(file string) []models.Issue {
+	return s.issues[file]
+}
+
+// FunctionIssues returns the latest issues reported in one function of a
+// file, including those in function literals inside it
+func (s *ResultStore) FunctionIssues(file, function string) []models.Issue {
+	var issues []models.Issue
+	for _, issue := range s.issues[file] {
+		if issue.DeclaredFunction() == function {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// DiffIssues matches two sets of issues by fingerprint and returns those only
+// in after (added) and those only in before (removed)
+func DiffIssues(before, after []models.Issue) (added, removed []models.Issue) {
+	remaining := make(map[string]int)
+	for _, issue := range before {
+		remaining[issue.Fingerprint()]++
+	}
+	for _, issue := range after {
+		fingerprint := issue.Fingerprint()
+		if remaining[fingerprint] > 0 {
+			remaining[fingerprint]--
+			continue
+		}
+		added = append(added, issue)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		fingerprint := before[i].Fingerprint()
+		if remaining[fingerprint] > 0 {
+			remaining[fingerprint]--
+			removed = append(removed, before[i])
+		}
+	}
+	slices.Reverse(removed)
+	return added, removed
+}
+
+// Result returns the codebase-wide result: every stored issue, scored as a
+// full run would score it
+func (s *ResultStore) Result() *models.AnalysisResult {
+	var result *models.AnalysisResult
+	if s.config != nil {
+		result = models.NewAnalysisResultWithConfig(s.config)
+	} else {
+		result = models.NewAnalysisResult()
+	}
+
+	result.Files = slices.Clone(s.files)
+	for _, file := range s.files {
+		result.Issues = append(result.Issues, s.issues[file]...)
+	}
+	result.Recompute()
+	return result
+}
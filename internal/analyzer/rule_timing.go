@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"time"
+
+	"gophercheck/internal/models"
+)
+
+// ruleClock is the time one rule has spent since the last finished result
+type ruleClock struct {
+	total, slowest time.Duration
+	files          int
+	slowestFile    string
+}
+
+// timeRule records how long the detector at index took on a file and
+// enforces analysis.rule_budget: a run over the rule's budget is reported,
+// and with the disable action the detector skips the rest of the files
+func (a *Analyzer) timeRule(index int, filename string, elapsed time.Duration) {
+	rule := a.rules[index]
+	if a.clocks == nil {
+		a.clocks = make(map[string]*ruleClock)
+	}
+	clock, ok := a.clocks[rule]
+	if !ok {
+		clock = &ruleClock{}
+		a.clocks[rule] = clock
+	}
+	normalized := a.paths.Normalize(filename)
+	clock.total += elapsed
+	clock.files++
+	if elapsed > clock.slowest {
+		clock.slowest, clock.slowestFile = elapsed, normalized
+	}
+
+	if a.config == nil {
+		return
+	}
+	budget := a.config.Analysis.RuleBudget.Budget(rule)
+	if budget <= 0 || elapsed <= budget {
+		return
+	}
+	slow := models.SlowRule{
+		Rule:       rule,
+		File:       normalized,
+		DurationMS: models.Milliseconds(elapsed),
+		BudgetMS:   models.Milliseconds(budget),
+	}
+	if a.config.Analysis.RuleBudget.Action == "disable" {
+		if a.disabled == nil {
+			a.disabled = make([]bool, len(a.detectors))
+		}
+		a.disabled[index] = true
+		slow.Disabled = true
+	}
+	a.slow = append(a.slow, slow)
+}
+
+// ruleDisabled reports whether rule_budget turned off the detector at index.
+// It stays off for the life of the analyzer, so across watch-mode changes.
+func (a *Analyzer) ruleDisabled(index int) bool {
+	return index < len(a.disabled) && a.disabled[index]
+}
+
+// addTimings moves the rule times since the last finished result into it
+func (a *Analyzer) addTimings(result *models.AnalysisResult) {
+	for rule, clock := range a.clocks {
+		result.AddRuleTiming(models.RuleTiming{
+			Rule:        rule,
+			TotalMS:     models.Milliseconds(clock.total),
+			Files:       clock.files,
+			SlowestFile: clock.slowestFile,
+			SlowestMS:   models.Milliseconds(clock.slowest),
+		})
+	}
+	result.SlowRules = append(result.SlowRules, a.slow...)
+	a.clocks, a.slow = nil, nil
+}
@@ -0,0 +1,192 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"gophercheck/internal/models"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifLog is a SARIF 2.1.0 log with the parts code scanning tools such as
+// GitHub's read
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+// sarifRule describes an issue type; results refer to it by index
+type sarifRule struct {
+	ID              string       `json:"id"`
+	FullDescription sarifMessage `json:"fullDescription"`
+	HelpURI         string       `json:"helpUri,omitempty"` // Its section of the rule reference
+	Properties      struct {
+		Category string `json:"category"`
+	} `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Fixes               []sarifFix        `json:"fixes,omitempty"`
+	Properties          sarifProperties   `json:"properties"`
+}
+
+// sarifFix is a suggested fix as replacements in the result's file
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation struct {
+		URI string `json:"uri"`
+	} `json:"artifactLocation"`
+	Replacements []sarifReplacement `json:"replacements"`
+}
+
+// sarifReplacement replaces a region, whose lines and columns count from 1
+// with the end column exclusive, with new text
+type sarifReplacement struct {
+	DeletedRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+		EndLine     int `json:"endLine"`
+		EndColumn   int `json:"endColumn"`
+	} `json:"deletedRegion"`
+	InsertedContent sarifMessage `json:"insertedContent"`
+}
+
+// sarifProperties carries what SARIF has no field for
+type sarifProperties struct {
+	Severity   string  `json:"severity"`
+	Confidence float64 `json:"confidence"`
+	Function   string  `json:"function,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region struct {
+			StartLine   int `json:"startLine"`
+			StartColumn int `json:"startColumn,omitempty"`
+		} `json:"region"`
+	} `json:"physicalLocation"`
+}
+
+// generateSARIF creates a SARIF 2.1.0 log for code scanning tools. Each issue
+// type found is a rule, linked to the rule reference under
+// output.docs_base_url, and each issue a result at the level
+// output.severity_levels.sarif gives its severity, with its confidence and
+// severity as properties and its fingerprint as a partial fingerprint so
+// results keep their identity as code moves.
+func (r *ReportGenerator) generateSARIF(result *models.AnalysisResult) string {
+	var types []models.IssueType
+	for _, issue := range result.Issues {
+		if !slices.Contains(types, issue.Type) {
+			types = append(types, issue.Type)
+		}
+	}
+	slices.SortFunc(types, func(a, b models.IssueType) int {
+		return slices.Index(models.AllIssueTypes, a) - slices.Index(models.AllIssueTypes, b)
+	})
+
+	docsBase := ""
+	if r.config != nil {
+		docsBase = r.config.Output.DocsBaseURL
+	}
+	driver := sarifDriver{Name: "gophercheck", Rules: make([]sarifRule, len(types))}
+	for i, issueType := range types {
+		rule := &driver.Rules[i]
+		rule.ID = string(issueType)
+		rule.FullDescription.Text = issueType.Description()
+		rule.HelpURI = issueType.DocsURL(docsBase)
+		rule.Properties.Category = issueType.Category()
+	}
+
+	levels := r.severityLevels()
+	results := make([]sarifResult, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		var location sarifLocation
+		location.PhysicalLocation.ArtifactLocation.URI = filepath.ToSlash(issue.File)
+		location.PhysicalLocation.Region.StartLine = max(issue.Line, 1)
+		location.PhysicalLocation.Region.StartColumn = issue.Column
+
+		results = append(results, sarifResult{
+			RuleID:              string(issue.Type),
+			RuleIndex:           slices.Index(types, issue.Type),
+			Level:               levels.Level(models.ConsumerSARIF, issue.Severity),
+			Message:             sarifMessage{Text: issue.Message},
+			Locations:           []sarifLocation{location},
+			PartialFingerprints: map[string]string{"gophercheck/v1": issue.Fingerprint()},
+			Fixes:               sarifFixes(issue),
+			Properties: sarifProperties{
+				Severity:   issue.Severity.String(),
+				Confidence: issue.Confidence,
+				Function:   issue.Function,
+			},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("Error generating SARIF report: %v", err)
+	}
+	return string(data)
+}
+
+// sarifFixes turns an issue's suggested fix into a SARIF fix from its text
+// edits. SARIF counts columns in UTF-16 code units like the edits do, but
+// from 1 where the edits count from 0.
+func sarifFixes(issue models.Issue) []sarifFix {
+	fix := issue.SuggestedFix
+	if fix == nil || len(fix.TextEdits) == 0 {
+		return nil
+	}
+
+	var change sarifArtifactChange
+	change.ArtifactLocation.URI = filepath.ToSlash(issue.File)
+	for _, edit := range fix.TextEdits {
+		var replacement sarifReplacement
+		replacement.DeletedRegion.StartLine = edit.Range.Start.Line + 1
+		replacement.DeletedRegion.StartColumn = edit.Range.Start.Character + 1
+		replacement.DeletedRegion.EndLine = edit.Range.End.Line + 1
+		replacement.DeletedRegion.EndColumn = edit.Range.End.Character + 1
+		replacement.InsertedContent.Text = edit.NewText
+		change.Replacements = append(change.Replacements, replacement)
+	}
+	return []sarifFix{{Description: sarifMessage{Text: fix.Description}, ArtifactChanges: []sarifArtifactChange{change}}}
+}
@@ -0,0 +1,70 @@
+package analyzer_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/models"
+)
+
+func TestSARIFReport(t *testing.T) {
+	cfg := testConfig()
+	cfg.Output.Format = "sarif"
+	cfg.Output.SeverityLevels = map[string]map[string]string{"sarif": {"MEDIUM": "note"}}
+
+	result := models.NewAnalysisResultWithConfig(cfg)
+	result.Files = []string{"a.go"}
+	result.AddIssue(models.Issue{Type: models.IssueSliceGrowth, Severity: models.SeverityHigh, File: "a.go", Line: 7, Column: 3, Function: "Fill", Message: "grows", Confidence: 0.9})
+	result.AddIssue(models.Issue{Type: models.IssueNestedLoops, Severity: models.SeverityMedium, File: "a.go", Line: 12, Function: "Pairs", Message: "nested", Confidence: 0.7})
+	result.CalculateScoreWithConfig()
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID     string `json:"ruleId"`
+				RuleIndex  int    `json:"ruleIndex"`
+				Level      string `json:"level"`
+				Properties struct {
+					Confidence float64 `json:"confidence"`
+					Severity   string  `json:"severity"`
+				} `json:"properties"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	report := analyzer.NewReportGeneratorWithConfig(cfg).Generate(result)
+	if err := json.Unmarshal([]byte(report), &log); err != nil {
+		t.Fatalf("invalid SARIF: %v\n%s", err, report)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("version %q with %d runs, want 2.1.0 with one", log.Version, len(log.Runs))
+	}
+
+	run := log.Runs[0]
+	want := map[string]struct {
+		level      string
+		confidence float64
+	}{
+		"slice_growth": {"error", 0.9},
+		"nested_loops": {"note", 0.7}, // Overridden from warning
+	}
+	if len(run.Results) != len(want) {
+		t.Fatalf("%d results, want %d", len(run.Results), len(want))
+	}
+	for _, got := range run.Results {
+		if rule := run.Tool.Driver.Rules[got.RuleIndex].ID; rule != got.RuleID {
+			t.Errorf("%s: rule index points at %s", got.RuleID, rule)
+		}
+		if w := want[got.RuleID]; got.Level != w.level || got.Properties.Confidence != w.confidence {
+			t.Errorf("%s: level %s, confidence %v; want %s, %v", got.RuleID, got.Level, got.Properties.Confidence, w.level, w.confidence)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+)
+
+// IssuesAtRef analyzes filenames as they were at a git ref, with the same
+// configuration, so current issues can be classified as new or pre-existing.
+// Files that didn't exist at the ref are left out.
+func IssuesAtRef(cfg *config.Config, filenames []string, ref string) ([]models.Issue, error) {
+	previous := *cfg
+	previous.Ownership = config.OwnershipConfig{}
+	previous.Output.StopAtMaxIssues = false
+	engine := NewAnalyzerWithConfig(&previous)
+
+	roots := make(map[string]string) // Repository root by directory
+	var files []string
+	for _, filename := range filenames {
+		abs, err := filepath.Abs(filename)
+		if err != nil {
+			continue
+		}
+		dir := filepath.Dir(abs)
+		root, ok := roots[dir]
+		if !ok {
+			if root, err = gitOutput(dir, "rev-parse", "--show-toplevel"); err != nil {
+				return nil, fmt.Errorf("%s is not in a git repository", filename)
+			}
+			if _, err := gitOutput(root, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+				return nil, fmt.Errorf("unknown git ref %q", ref)
+			}
+			roots[dir] = root
+		}
+
+		rel, err := filepath.Rel(root, abs)
+		if err != nil {
+			continue
+		}
+		src, err := exec.Command("git", "-C", root, "show", ref+":"+filepath.ToSlash(rel)).Output()
+		if err != nil {
+			continue // Added since ref
+		}
+		engine.AddSource(filename, src)
+		files = append(files, filename)
+	}
+
+	result, err := engine.AnalyzeFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	return result.Issues, nil
+}
+
+// LoadBaseline reads the issues of an earlier JSON report
+func LoadBaseline(path string) ([]models.Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var baseline models.AnalysisResult
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("%s is not a JSON report: %w", path, err)
+	}
+	return baseline.Issues, nil
+}
+
+// BaselineDate returns when a baseline was last updated: its last git
+// commit, or its modification time when it isn't committed
+func BaselineDate(path string) (time.Time, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	committed, err := gitOutput(filepath.Dir(abs), "log", "-1", "--format=%cI", "--", filepath.Base(abs))
+	if err == nil && committed != "" {
+		if date, err := time.Parse(time.RFC3339, committed); err == nil {
+			return date, nil
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	return strings.TrimSpace(string(output)), err
+}
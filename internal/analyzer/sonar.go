@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"gophercheck/internal/models"
+)
+
+// sonarReport is SonarQube's generic external issue format, imported with
+// sonar.externalIssuesReportPaths
+type sonarReport struct {
+	Issues []sonarIssue `json:"issues"`
+}
+
+type sonarIssue struct {
+	EngineID        string        `json:"engineId"`
+	RuleID          string        `json:"ruleId"`
+	Severity        string        `json:"severity"`
+	Type            string        `json:"type"`
+	PrimaryLocation sonarLocation `json:"primaryLocation"`
+}
+
+type sonarLocation struct {
+	Message   string `json:"message"`
+	FilePath  string `json:"filePath"`
+	TextRange struct {
+		StartLine int `json:"startLine"`
+	} `json:"textRange"`
+}
+
+// generateSonar creates a SonarQube external issues report: every issue is a
+// code smell at the severity output.severity_levels.sonar gives it
+func (r *ReportGenerator) generateSonar(result *models.AnalysisResult) string {
+	levels := r.severityLevels()
+	report := sonarReport{Issues: make([]sonarIssue, 0, len(result.Issues))}
+	for _, issue := range result.Issues {
+		location := sonarLocation{Message: issue.Message, FilePath: filepath.ToSlash(issue.File)}
+		location.TextRange.StartLine = max(issue.Line, 1)
+		report.Issues = append(report.Issues, sonarIssue{
+			EngineID:        "gophercheck",
+			RuleID:          string(issue.Type),
+			Severity:        levels.Level(models.ConsumerSonar, issue.Severity),
+			Type:            "CODE_SMELL",
+			PrimaryLocation: location,
+		})
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("Error generating Sonar report: %v", err)
+	}
+	return string(data)
+}
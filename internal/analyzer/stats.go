@@ -0,0 +1,229 @@
+package analyzer
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"gophercheck/internal/models"
+)
+
+// StatsFormats are the output formats of FormatStats
+var StatsFormats = []string{"console", "json"}
+
+// AnalyzeStats analyzes filenames and summarizes them: sizes, distributions
+// of function length and complexity, the top largest functions, how densely
+// each rule fires, and how coupled the packages are
+func (a *Analyzer) AnalyzeStats(filenames []string, top int) (*models.CodebaseStats, error) {
+	functions, result, err := a.AnalyzeMetrics(filenames)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.CodebaseStats{
+		Files:            len(result.Files),
+		Functions:        len(functions),
+		TotalIssues:      len(result.Issues),
+		PerformanceScore: result.PerformanceScore,
+		Grade:            result.Grade,
+		CategoryScores:   result.CategoryScores,
+	}
+
+	packages := make(map[string]bool)
+	for _, filename := range result.Files {
+		packages[filepath.Dir(filename)] = true
+	}
+	stats.Packages = len(packages)
+	stats.LOC = a.resultLOC(result)
+
+	stats.FunctionLength = distribution(functions, func(m models.FunctionMetrics) int { return m.LOC })
+	stats.Cyclomatic = distribution(functions, func(m models.FunctionMetrics) int { return m.Cyclomatic })
+	stats.Cognitive = distribution(functions, func(m models.FunctionMetrics) int { return m.Cognitive })
+
+	largest := slices.Clone(functions)
+	slices.SortStableFunc(largest, func(x, y models.FunctionMetrics) int {
+		return cmp.Compare(y.LOC, x.LOC)
+	})
+	stats.Largest = largest[:min(top, len(largest))]
+
+	stats.Rules = ruleDensities(result.Issues, stats.LOC)
+
+	stats.Coupling = a.PackageMetrics()
+	slices.SortStableFunc(stats.Coupling, func(x, y models.PackageMetrics) int {
+		return cmp.Or(cmp.Compare(y.FanIn, x.FanIn), cmp.Compare(y.FanOut, x.FanOut))
+	})
+	return stats, nil
+}
+
+// RuleDensities counts the issues of a result from AnalyzeMetrics per rule,
+// relative to the lines of code of its files, and returns them with that
+// line count
+func (a *Analyzer) RuleDensities(result *models.AnalysisResult) ([]models.RuleDensity, int) {
+	loc := a.resultLOC(result)
+	return ruleDensities(result.Issues, loc), loc
+}
+
+// resultLOC adds up the lines of code of a result's files
+func (a *Analyzer) resultLOC(result *models.AnalysisResult) int {
+	loc := 0
+	for _, filename := range result.Files {
+		loc += a.fileLOC[filename]
+	}
+	return loc
+}
+
+// distribution summarizes one measurement over functions, with percentiles
+// taken by nearest rank
+func distribution(functions []models.FunctionMetrics, measure func(models.FunctionMetrics) int) models.Distribution {
+	if len(functions) == 0 {
+		return models.Distribution{}
+	}
+
+	values := make([]int, len(functions))
+	total := 0
+	for i, m := range functions {
+		values[i] = measure(m)
+		total += values[i]
+	}
+	slices.Sort(values)
+
+	percentile := func(p float64) int {
+		rank := int(math.Ceil(p / 100 * float64(len(values))))
+		return values[max(rank-1, 0)]
+	}
+	return models.Distribution{
+		Mean: math.Round(float64(total)/float64(len(values))*10) / 10,
+		P50:  percentile(50),
+		P90:  percentile(90),
+		P99:  percentile(99),
+		Max:  values[len(values)-1],
+	}
+}
+
+// ruleDensities counts issues per rule, most frequent first
+func ruleDensities(issues []models.Issue, loc int) []models.RuleDensity {
+	byRule := make(map[models.IssueType]*models.RuleDensity)
+	functions := make(map[models.IssueType]map[string]bool)
+	for _, issue := range issues {
+		density, ok := byRule[issue.Type]
+		if !ok {
+			density = &models.RuleDensity{Rule: string(issue.Type), Category: issue.Type.Category()}
+			byRule[issue.Type] = density
+			functions[issue.Type] = make(map[string]bool)
+		}
+		density.Issues++
+		if issue.Function != "" {
+			functions[issue.Type][issue.File+"\x00"+issue.Function] = true
+		}
+	}
+
+	densities := make([]models.RuleDensity, 0, len(byRule))
+	for rule, density := range byRule {
+		density.Functions = len(functions[rule])
+		if loc > 0 {
+			density.PerKLOC = math.Round(float64(density.Issues)*1000/float64(loc)*100) / 100
+		}
+		densities = append(densities, *density)
+	}
+	slices.SortFunc(densities, func(x, y models.RuleDensity) int {
+		if c := cmp.Compare(y.Issues, x.Issues); c != 0 {
+			return c
+		}
+		return strings.Compare(x.Rule, y.Rule)
+	})
+	return densities
+}
+
+// FormatStats renders codebase stats as a console summary or JSON
+func FormatStats(stats *models.CodebaseStats, format string) (string, error) {
+	switch format {
+	case "console":
+		return formatStatsConsole(stats)
+	case "json":
+		data, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data) + "\n", nil
+	default:
+		return "", fmt.Errorf("invalid stats format: %s (valid: %v)", format, StatsFormats)
+	}
+}
+
+// mostImported returns up to top packages other analyzed packages import,
+// from coupling sorted by fan-in
+func mostImported(coupling []models.PackageMetrics, top int) []models.PackageMetrics {
+	end := 0
+	for end < len(coupling) && end < top && coupling[end].FanIn > 0 {
+		end++
+	}
+	return coupling[:end]
+}
+
+func formatStatsConsole(stats *models.CodebaseStats) (string, error) {
+	var report strings.Builder
+	fmt.Fprintf(&report, "Files: %d  Packages: %d  Functions: %d  Lines of code: %d\n",
+		stats.Files, stats.Packages, stats.Functions, stats.LOC)
+	fmt.Fprintf(&report, "Issues: %d  Score: %d/100 (%s)\n", stats.TotalIssues, stats.PerformanceScore, stats.Grade)
+	if parts := categoryScores(stats.CategoryScores, "%s %d"); len(parts) > 0 {
+		fmt.Fprintf(&report, "Categories: %s\n", strings.Join(parts, " · "))
+	}
+	report.WriteString("\n")
+
+	w := tabwriter.NewWriter(&report, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PER FUNCTION\tMEAN\tP50\tP90\tP99\tMAX")
+	for _, row := range []struct {
+		name string
+		dist models.Distribution
+	}{
+		{"Lines of code", stats.FunctionLength},
+		{"Cyclomatic complexity", stats.Cyclomatic},
+		{"Cognitive complexity", stats.Cognitive},
+	} {
+		fmt.Fprintf(w, "%s\t%.1f\t%d\t%d\t%d\t%d\n", row.name, row.dist.Mean, row.dist.P50, row.dist.P90, row.dist.P99, row.dist.Max)
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+
+	if len(stats.Largest) > 0 {
+		report.WriteString("\nLargest functions:\n")
+		for _, m := range stats.Largest {
+			fmt.Fprintf(&report, "  %5d  %s (%s:%d)\n", m.LOC, m.QualifiedName(), m.File, m.Line)
+		}
+	}
+
+	if coupled := mostImported(stats.Coupling, 10); len(coupled) > 0 {
+		report.WriteString("\nMost imported packages:\n")
+		w = tabwriter.NewWriter(&report, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "  PACKAGE\tFAN-IN\tFAN-OUT\tINSTABILITY")
+		for _, m := range coupled {
+			name := m.Package
+			if m.Hub {
+				name += " (hub)"
+			}
+			fmt.Fprintf(w, "  %s\t%d\t%d\t%.2f\n", name, m.FanIn, m.FanOut, m.Instability)
+		}
+		if err := w.Flush(); err != nil {
+			return "", err
+		}
+	}
+
+	if len(stats.Rules) > 0 {
+		report.WriteString("\n")
+		w = tabwriter.NewWriter(&report, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "RULE\tCATEGORY\tISSUES\tPER KLOC\tFUNCTIONS")
+		for _, rule := range stats.Rules {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%.2f\t%d\n", rule.Rule, rule.Category, rule.Issues, rule.PerKLOC, rule.Functions)
+		}
+		if err := w.Flush(); err != nil {
+			return "", err
+		}
+	}
+	return report.String(), nil
+}
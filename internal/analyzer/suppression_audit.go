@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+	"gophercheck/internal/ownership"
+)
+
+// SuppressionAuditFormats are the output formats of FormatSuppressionAudit
+var SuppressionAuditFormats = []string{"console", "json"}
+
+// AuditSuppressions analyzes filenames and lists the exceptions in them: every
+// //gophercheck:ignore directive and, when previous holds the issues of the
+// baseline, every current issue it accepts as pre-existing. Each comes with
+// the last commit to its line, from git blame.
+func (a *Analyzer) AuditSuppressions(filenames []string, baseline string, previous []models.Issue) (*models.SuppressionAudit, error) {
+	suppressions, result, err := a.AnalyzeSuppressions(filenames)
+	if err != nil {
+		return nil, err
+	}
+
+	blamer := ownership.NewAnnotator(config.OwnershipConfig{Blame: true})
+	now := time.Now()
+	audit := &models.SuppressionAudit{Suppressions: []models.AuditedSuppression{}, Baseline: baseline}
+	for _, s := range suppressions {
+		blame := blamer.Blame(s.File, s.Line)
+		audit.Suppressions = append(audit.Suppressions, models.AuditedSuppression{
+			Suppression: s,
+			Blame:       blame,
+			AgeDays:     ageDays(blame, now),
+		})
+	}
+
+	if baseline == "" || previous == nil {
+		return audit, nil
+	}
+	result.ClassifyAges(previous, baseline)
+	for _, issue := range result.Issues {
+		if issue.Age != models.AgePreExisting {
+			continue
+		}
+		blame := blamer.Blame(issue.File, issue.Line)
+		audit.BaselineEntries = append(audit.BaselineEntries, models.BaselineEntry{
+			File:     issue.File,
+			Line:     issue.Line,
+			Rule:     string(issue.Type),
+			Function: issue.Function,
+			Message:  issue.Message,
+			Blame:    blame,
+			AgeDays:  ageDays(blame, now),
+		})
+	}
+	slices.SortStableFunc(audit.BaselineEntries, func(x, y models.BaselineEntry) int {
+		return cmp.Or(cmp.Compare(x.File, y.File), cmp.Compare(x.Line, y.Line))
+	})
+	return audit, nil
+}
+
+// ageDays returns the whole days since a line was last committed, or 0 when
+// it isn't committed
+func ageDays(blame *models.LineBlame, now time.Time) int {
+	if blame == nil {
+		return 0
+	}
+	return int(now.Sub(blame.Time).Hours() / 24)
+}
+
+// FormatSuppressionAudit renders an audit in the given format
+func FormatSuppressionAudit(audit *models.SuppressionAudit, format string) (string, error) {
+	switch format {
+	case "console":
+		return formatSuppressionAuditConsole(audit)
+	case "json":
+		data, err := json.MarshalIndent(audit, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data) + "\n", nil
+	default:
+		return "", fmt.Errorf("invalid suppressions format: %s (valid: %v)", format, SuppressionAuditFormats)
+	}
+}
+
+func formatSuppressionAuditConsole(audit *models.SuppressionAudit) (string, error) {
+	var report strings.Builder
+	fmt.Fprintf(&report, "Inline suppressions: %d\n", len(audit.Suppressions))
+	if len(audit.Suppressions) > 0 {
+		w := tabwriter.NewWriter(&report, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "  LOCATION\tRULES\tAUTHOR\tAGE\tUNTIL\tFINDINGS\tREASON")
+		for _, s := range audit.Suppressions {
+			until := s.Until
+			if s.Expired {
+				until += " (expired)"
+			}
+			fmt.Fprintf(w, "  %s:%d\t%s\t%s\t%s\t%s\t%d\t%s\n", s.File, s.Line, strings.Join(s.Rules, ","),
+				blameAuthor(s.Blame), blameAge(s.Blame), dash(until), s.Matched, dash(s.Reason))
+		}
+		if err := w.Flush(); err != nil {
+			return "", err
+		}
+	}
+
+	switch {
+	case audit.Baseline == "":
+		return report.String(), nil
+	case audit.BaselineExpired:
+		fmt.Fprintf(&report, "\nBaseline %s has expired and accepts no issues\n", audit.Baseline)
+		return report.String(), nil
+	}
+	fmt.Fprintf(&report, "\nBaseline entries in %s: %d\n", audit.Baseline, len(audit.BaselineEntries))
+	if len(audit.BaselineEntries) > 0 {
+		w := tabwriter.NewWriter(&report, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "  LOCATION\tRULE\tFUNCTION\tAUTHOR\tAGE")
+		for _, entry := range audit.BaselineEntries {
+			fmt.Fprintf(w, "  %s:%d\t%s\t%s\t%s\t%s\n", entry.File, entry.Line, entry.Rule,
+				dash(entry.Function), blameAuthor(entry.Blame), blameAge(entry.Blame))
+		}
+		if err := w.Flush(); err != nil {
+			return "", err
+		}
+	}
+	return report.String(), nil
+}
+
+// blameAuthor returns who last committed a line, "-" when it isn't committed
+func blameAuthor(blame *models.LineBlame) string {
+	switch {
+	case blame == nil:
+		return "-"
+	case blame.Email != "":
+		return blame.Email
+	default:
+		return blame.Author
+	}
+}
+
+// blameAge renders how long ago a line was last committed, "-" when it isn't
+// committed
+func blameAge(blame *models.LineBlame) string {
+	if blame == nil {
+		return "-"
+	}
+	return lineAge(time.Since(blame.Time))
+}
+
+func dash(text string) string {
+	if text == "" {
+		return "-"
+	}
+	return text
+}
@@ -0,0 +1,190 @@
+package analyzer
+
+import (
+	"bytes"
+	"cmp"
+	"go/ast"
+	"slices"
+	"strings"
+	"time"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+)
+
+// ignoreDirective silences findings, e.g.
+//
+//	//gophercheck:ignore slice_growth,memory_allocation until=2025-12-31 reason=migration
+const ignoreDirective = "//gophercheck:ignore"
+
+// suppression is a directive in a file being analyzed, with the lines it
+// covers
+type suppression struct {
+	models.Suppression
+	from, to int
+}
+
+// fileSuppressions reads the //gophercheck:ignore directives of a file. A
+// directive after code covers its own line, one on a line of its own the
+// next line, and one in a function's doc comment the whole function. Rules
+// renamed since are replaced by their new ids. A directive whose until date
+// has passed, or can't be read, is expired: it silences nothing.
+func (a *Analyzer) fileSuppressions(file *ast.File, filename string) []*suppression {
+	var src []byte
+	var suppressions []*suppression
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			rest, ok := strings.CutPrefix(comment.Text, ignoreDirective)
+			if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+				continue
+			}
+			directive, ok := parseIgnoreDirective(rest)
+			if !ok {
+				continue
+			}
+			if src == nil {
+				var err error
+				if src, err = a.ReadSource(filename); err != nil {
+					return nil
+				}
+			}
+
+			position := a.fileSet.Position(comment.Pos())
+			directive.File = a.paths.Normalize(filename)
+			directive.Line = position.Line
+			s := &suppression{Suppression: directive, from: position.Line + 1, to: position.Line + 1}
+			lineStart := position.Offset - (position.Column - 1)
+			if len(bytes.TrimSpace(src[lineStart:position.Offset])) > 0 {
+				s.to = position.Line
+				s.from = position.Line
+			}
+			if fn := docOf(file, group); fn != nil {
+				s.from = a.fileSet.Position(fn.Pos()).Line
+				s.to = a.fileSet.Position(fn.End()).Line
+			}
+			suppressions = append(suppressions, s)
+		}
+	}
+	return suppressions
+}
+
+// parseIgnoreDirective reads the rules, until date, and reason following
+// the directive. The reason runs to the end of the comment.
+func parseIgnoreDirective(text string) (models.Suppression, bool) {
+	var directive models.Suppression
+	text, directive.Reason, _ = strings.Cut(text, "reason=")
+	directive.Reason = strings.TrimSpace(directive.Reason)
+
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return directive, false
+	}
+	for _, rule := range strings.Split(fields[0], ",") {
+		if rule == "" {
+			continue
+		}
+		if deprecation, ok := config.LookupDeprecatedRule(rule); ok {
+			directive.Rules = append(directive.Rules, deprecation.ReplacedBy...)
+			continue
+		}
+		directive.Rules = append(directive.Rules, rule)
+	}
+	for _, field := range fields[1:] {
+		if until, ok := strings.CutPrefix(field, "until="); ok {
+			directive.Until = until
+		}
+	}
+	directive.Expired = expired(directive.Until, time.Now())
+	return directive, len(directive.Rules) > 0
+}
+
+// expired reports whether the last day of a suppression, YYYY-MM-DD in local
+// time, is over. A date that can't be read counts as over, so a typo
+// doesn't silence findings forever.
+func expired(until string, now time.Time) bool {
+	if until == "" {
+		return false
+	}
+	last, err := time.ParseInLocation("2006-01-02", until, time.Local)
+	if err != nil {
+		return true
+	}
+	return !now.Before(last.AddDate(0, 0, 1))
+}
+
+// docOf returns the function whose doc comment group is
+func docOf(file *ast.File, group *ast.CommentGroup) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Doc == group {
+			return fn
+		}
+	}
+	return nil
+}
+
+// overlaps reports whether the suppression covers a line of any of ranges
+func (s *suppression) overlaps(ranges []lineRange) bool {
+	for _, r := range ranges {
+		if s.from <= r.end && r.start <= s.to {
+			return true
+		}
+	}
+	return false
+}
+
+// suppressed reports whether a suppression in effect covers an issue,
+// counting the match; an expired one covering it counts it too, but lets it
+// through
+func suppressed(suppressions []*suppression, issue models.Issue) bool {
+	for _, s := range suppressions {
+		if issue.Line < s.from || issue.Line > s.to || !slices.Contains(s.Rules, string(issue.Type)) {
+			continue
+		}
+		s.Matched++
+		if !s.Expired {
+			return true
+		}
+	}
+	return false
+}
+
+// recordSuppressions notes the findings a file's suppressions silenced and
+// counts the suppressions in effect and those that expired, for the next
+// finished result. With a symbol filter, only suppressions covering a line
+// of symbolLines count, like the issues.
+func (a *Analyzer) recordSuppressions(suppressions []*suppression, silenced int, symbolLines []lineRange) {
+	a.suppressed += silenced
+	for _, s := range suppressions {
+		if a.symbols.active() && !s.overlaps(symbolLines) {
+			continue
+		}
+		if s.Expired {
+			a.expired = append(a.expired, s.Suppression)
+		} else {
+			a.active++
+		}
+		if a.collectSuppressions {
+			a.suppressions = append(a.suppressions, s.Suppression)
+		}
+	}
+}
+
+// AnalyzeSuppressions analyzes filenames like AnalyzeFiles and also returns
+// every //gophercheck:ignore directive in them, expired or not, in file and
+// line order
+func (a *Analyzer) AnalyzeSuppressions(filenames []string) ([]models.Suppression, *models.AnalysisResult, error) {
+	a.collectSuppressions = true
+	a.suppressions = nil
+	defer func() { a.collectSuppressions = false }()
+
+	result, err := a.AnalyzeFiles(filenames)
+	if err != nil {
+		return nil, nil, err
+	}
+	suppressions := a.suppressions
+	slices.SortStableFunc(suppressions, func(x, y models.Suppression) int {
+		return cmp.Or(cmp.Compare(x.File, y.File), cmp.Compare(x.Line, y.Line))
+	})
+	a.suppressions = nil
+	return suppressions, result, nil
+}
@@ -0,0 +1,61 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"gophercheck/internal/analyzer"
+)
+
+const suppressionsSource = `package p
+
+type Cache struct{ items []string }
+
+func (c *Cache) Fill(n int) {
+	for i := 0; i < n; i++ {
+		//gophercheck:ignore slice_growth reason=bounded
+		c.items = append(c.items, "")
+		//gophercheck:ignore memory_allocation until=2001-01-01
+		_ = make([]int, i)
+	}
+}
+
+func Other(n int) (out [][]int) {
+	for i := 0; i < n; i++ {
+		//gophercheck:ignore slice_growth reason=bounded
+		out = append(out, nil)
+		//gophercheck:ignore memory_allocation until=2001-01-01
+		_ = make([]int, i)
+	}
+	return out
+}
+`
+
+// TestSuppressionsFollowSymbolFilter requires --only-function, --func, and
+// --only-type to count only the suppressions in the symbols they select, in
+// effect and expired alike
+func TestSuppressionsFollowSymbolFilter(t *testing.T) {
+	for _, tc := range []struct {
+		functions, types []string
+		active, expired  int
+	}{
+		{nil, nil, 2, 2},
+		{nil, []string{"Cache"}, 1, 1},
+		{[]string{"Other"}, nil, 1, 1},
+		{[]string{"Fill"}, nil, 1, 1},
+		{[]string{"Cache.Fill"}, nil, 1, 1},
+	} {
+		engine := analyzer.NewAnalyzerWithConfig(testConfig())
+		if err := engine.SetSymbolFilter(tc.functions, tc.types); err != nil {
+			t.Fatal(err)
+		}
+		engine.AddSource("cache.go", []byte(suppressionsSource))
+		result, err := engine.AnalyzeFiles([]string{"cache.go"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.ActiveSuppressions != tc.active || len(result.ExpiredSuppressions) != tc.expired {
+			t.Errorf("functions %v, types %v: %d active and %d expired suppressions, want %d and %d",
+				tc.functions, tc.types, result.ActiveSuppressions, len(result.ExpiredSuppressions), tc.active, tc.expired)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"path"
+
+	"gophercheck/internal/analyzer/detectors"
+)
+
+// symbolFilter restricts reported issues to the functions and types named by
+// glob patterns, as --only-function and --only-type give them
+type symbolFilter struct {
+	functions []string // Func, Method, or Type.Method patterns
+	types     []string // Type patterns, covering the declaration and its methods
+}
+
+// SetSymbolFilter restricts reported issues to the functions and methods
+// matching one of functions and the types, with their methods, matching one
+// of types. Patterns are globs as in path.Match, e.g. Handler* or Server.*;
+// a function pattern matches Func, Method, or Type.Method. No patterns
+// report issues everywhere. The filter only narrows what is reported: the
+// detectors still analyze whole files, so rules that look across functions
+// see the code outside the symbols too.
+func (a *Analyzer) SetSymbolFilter(functions, types []string) error {
+	for _, pattern := range append(append([]string(nil), functions...), types...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid symbol pattern %q: %w", pattern, err)
+		}
+	}
+	a.symbols = symbolFilter{functions: functions, types: types}
+	return nil
+}
+
+func (f symbolFilter) active() bool {
+	return len(f.functions) > 0 || len(f.types) > 0
+}
+
+// matchesFunction reports whether a function or method is one of the
+// filter's symbols, by its own name or its type's
+func (f symbolFilter) matchesFunction(fn *ast.FuncDecl) bool {
+	if matchesAny(f.functions, fn.Name.Name) || matchesAny(f.functions, detectors.DeclaredName(fn)) {
+		return true
+	}
+	receiver := detectors.ReceiverType(fn)
+	return receiver != "" && matchesAny(f.types, receiver)
+}
+
+// symbolLines returns the lines of file the filter's symbols span: matching
+// functions and methods, and the declarations and methods of matching types
+func (a *Analyzer) symbolLines(file *ast.File) []lineRange {
+	var lines []lineRange
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if a.symbols.matchesFunction(decl) {
+				lines = append(lines, a.nodeLines(decl))
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok && matchesAny(a.symbols.types, typeSpec.Name.Name) {
+					lines = append(lines, a.nodeLines(typeSpec))
+				}
+			}
+		}
+	}
+	return lines
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"go/ast"
+	"strings"
+
+	"gophercheck/internal/models"
+)
+
+// lineRange is an inclusive range of source lines
+type lineRange struct {
+	start, end int
+}
+
+func (r lineRange) contains(line int) bool {
+	return line >= r.start && line <= r.end
+}
+
+// isBenchmarkLoop recognizes the timing loop of a benchmark:
+// for i := 0; i < b.N; i++ / for b.Loop() / for range b.N. Callers only ask
+// inside the Benchmark functions of test files, where a b without type info
+// is the *testing.B.
+func (a *Analyzer) isBenchmarkLoop(node ast.Node) bool {
+	switch loop := node.(type) {
+	case *ast.ForStmt:
+		switch cond := loop.Cond.(type) {
+		case *ast.BinaryExpr:
+			return a.isBenchmarkField(cond.Y, "N")
+		case *ast.CallExpr:
+			return len(cond.Args) == 0 && a.isBenchmarkField(cond.Fun, "Loop")
+		}
+	case *ast.RangeStmt:
+		return a.isBenchmarkField(loop.X, "N")
+	}
+	return false
+}
+
+// isBenchmarkField matches b.<name> where b is a *testing.B
+func (a *Analyzer) isBenchmarkField(expr ast.Expr, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	if tv, ok := a.context.TypeInfo.Types[sel.X]; ok && tv.Type != nil {
+		return tv.Type.String() == "*testing.B"
+	}
+	// Without type info, fall back to the conventional parameter name
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == "b"
+}
+
+// testFunctionExclusions returns the line ranges of a test file whose
+// findings the test_functions config says to drop: Example functions, and
+// Fuzz functions outside their f.Fuzz target
+func (a *Analyzer) testFunctionExclusions(file *ast.File) (excluded, allowed []lineRange) {
+	settings := a.config.Tests.TestFunctions
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || fn.Recv != nil {
+			continue
+		}
+		name := fn.Name.Name
+		fnRange := a.nodeLines(fn)
+
+		switch {
+		case settings.IgnoreExamples && strings.HasPrefix(name, "Example"):
+			excluded = append(excluded, fnRange)
+
+		case settings.IgnoreFuzzSetup && strings.HasPrefix(name, "Fuzz"):
+			excluded = append(excluded, fnRange)
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Fuzz" {
+					for _, arg := range call.Args {
+						if lit, ok := arg.(*ast.FuncLit); ok {
+							allowed = append(allowed, a.nodeLines(lit))
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+	return excluded, allowed
+}
+
+// filterTestFunctionIssues drops issues inside excluded test functions
+func (a *Analyzer) filterTestFunctionIssues(file *ast.File, issues []models.Issue) []models.Issue {
+	excluded, allowed := a.testFunctionExclusions(file)
+	if len(excluded) == 0 {
+		return issues
+	}
+
+	kept := issues[:0]
+	for _, issue := range issues {
+		if inRanges(issue.Line, excluded) && !inRanges(issue.Line, allowed) {
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	return kept
+}
+
+func (a *Analyzer) nodeLines(node ast.Node) lineRange {
+	return lineRange{
+		start: a.fileSet.Position(node.Pos()).Line,
+		end:   a.fileSet.Position(node.End()).Line,
+	}
+}
+
+func inRanges(line int, ranges []lineRange) bool {
+	for _, r := range ranges {
+		if r.contains(line) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package analyzer_test
+
+import (
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gophercheck/internal/config"
+)
+
+// testdataRoot holds the sample packages, relative to this package
+const testdataRoot = "../../testdata"
+
+// testPackages returns every directory under testdata holding Go files
+func testPackages(t testing.TB) []string {
+	t.Helper()
+	var packages []string
+	err := filepath.WalkDir(testdataRoot, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+		if dir := filepath.Dir(path); !slices.Contains(packages, dir) {
+			packages = append(packages, dir)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return packages
+}
+
+// packageFiles returns the Go files of a testdata package
+func packageFiles(t testing.TB, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// testConfig analyzes testdata with the defaults, whatever config file the
+// working directory has, so expectations only change with detectors
+func testConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Files.IncludeTests = true
+	cfg.Output.Colors = false
+	return cfg
+}
@@ -0,0 +1,188 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gophercheck/internal/models"
+)
+
+// TreemapFormats are the output formats of FormatTreemap
+var TreemapFormats = []string{"json", "svg"}
+
+// AnalyzeTreemap analyzes filenames and arranges the results as a directory
+// tree sized by lines of code, with the score penalty of each file's issues
+func (a *Analyzer) AnalyzeTreemap(filenames []string) (*models.TreemapNode, *models.AnalysisResult, error) {
+	_, result, err := a.AnalyzeMetrics(filenames)
+	if err != nil {
+		return nil, nil, err
+	}
+	return BuildTreemap(result, a.fileLOC), result, nil
+}
+
+// BuildTreemap arranges the analyzed files of result under their directories,
+// with loc giving the lines of code of each file
+func BuildTreemap(result *models.AnalysisResult, loc map[string]int) *models.TreemapNode {
+	files := make(map[string]*models.TreemapNode, len(result.Files))
+	for _, filename := range result.Files {
+		files[filename] = &models.TreemapNode{LOC: loc[filename]}
+	}
+	for _, issue := range result.Issues {
+		if file, ok := files[issue.File]; ok {
+			file.Issues++
+			file.Penalty += result.Penalty(issue)
+		}
+	}
+
+	root := &models.TreemapNode{Name: ".", Path: ".", Children: []*models.TreemapNode{}}
+	for _, filename := range result.Files {
+		dir := root
+		parts := strings.Split(filepath.ToSlash(filename), "/")
+		for i, part := range parts[:len(parts)-1] {
+			dir = treemapChild(dir, part, strings.Join(parts[:i+1], "/"))
+		}
+		file := files[filename]
+		file.Name, file.Path = parts[len(parts)-1], filename
+		dir.Children = append(dir.Children, file)
+	}
+
+	collapseTreemap(root)
+	sumTreemap(root)
+	return root
+}
+
+// treemapChild returns the subdirectory name of dir, adding it if needed
+func treemapChild(dir *models.TreemapNode, name, dirPath string) *models.TreemapNode {
+	for _, child := range dir.Children {
+		if !child.IsFile() && child.Name == name {
+			return child
+		}
+	}
+	child := &models.TreemapNode{Name: name, Path: dirPath, Children: []*models.TreemapNode{}}
+	dir.Children = append(dir.Children, child)
+	return child
+}
+
+// collapseTreemap merges directories holding a single subdirectory and
+// nothing else, so deep module paths don't nest rectangles needlessly
+func collapseTreemap(node *models.TreemapNode) {
+	for _, child := range node.Children {
+		for !child.IsFile() && len(child.Children) == 1 && !child.Children[0].IsFile() {
+			only := child.Children[0]
+			child.Name = path.Join(child.Name, only.Name)
+			child.Path = only.Path
+			child.Children = only.Children
+		}
+		collapseTreemap(child)
+	}
+}
+
+// sumTreemap totals directories from their children, sorts children largest
+// first, and sets every node's density
+func sumTreemap(node *models.TreemapNode) {
+	if !node.IsFile() {
+		node.LOC, node.Issues, node.Penalty = 0, 0, 0
+		for _, child := range node.Children {
+			sumTreemap(child)
+			node.LOC += child.LOC
+			node.Issues += child.Issues
+			node.Penalty += child.Penalty
+		}
+		slices.SortStableFunc(node.Children, func(x, y *models.TreemapNode) int {
+			if x.LOC != y.LOC {
+				return y.LOC - x.LOC
+			}
+			return strings.Compare(x.Name, y.Name)
+		})
+	}
+	if node.LOC > 0 {
+		node.Density = math.Round(float64(node.Penalty)*100/float64(node.LOC)*100) / 100
+	}
+}
+
+// FormatTreemap renders a treemap as JSON or as an SVG image
+func FormatTreemap(root *models.TreemapNode, format string) (string, error) {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(root, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data) + "\n", nil
+	case "svg":
+		return TreemapSVG(root, 1200, 800), nil
+	default:
+		return "", fmt.Errorf("invalid treemap format: %s (valid: %v)", format, TreemapFormats)
+	}
+}
+
+// TreemapSVG draws files as rectangles with area proportional to their lines
+// of code, colored from green to red by penalty density relative to the
+// densest file. Directories split their rectangle along its longer side.
+func TreemapSVG(root *models.TreemapNode, width, height int) string {
+	maxDensity := 0.0
+	walkTreemapFiles(root, func(file *models.TreemapNode) {
+		maxDensity = max(maxDensity, file.Density)
+	})
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n",
+		width, height, width, height)
+	layoutTreemap(&svg, root, 0, 0, float64(width), float64(height), maxDensity)
+	svg.WriteString("</svg>\n")
+	return svg.String()
+}
+
+func layoutTreemap(svg *strings.Builder, node *models.TreemapNode, x, y, w, h, maxDensity float64) {
+	if node.IsFile() {
+		fmt.Fprintf(svg, `<g><title>%s: %d lines, %d issues, penalty %d (%.2f per 100 lines)</title>`,
+			html.EscapeString(node.Path), node.LOC, node.Issues, node.Penalty, node.Density)
+		fmt.Fprintf(svg, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="white"/>`,
+			x, y, w, h, heatColor(node.Density, maxDensity))
+		if w > 60 && h > 14 {
+			fmt.Fprintf(svg, `<text x="%.1f" y="%.1f">%s</text>`, x+3, y+12, html.EscapeString(node.Name))
+		}
+		svg.WriteString("</g>\n")
+		return
+	}
+	if node.LOC == 0 {
+		return
+	}
+
+	offset := 0.0
+	for _, child := range node.Children {
+		share := float64(child.LOC) / float64(node.LOC)
+		if w >= h {
+			layoutTreemap(svg, child, x+offset, y, w*share, h, maxDensity)
+			offset += w * share
+		} else {
+			layoutTreemap(svg, child, x, y+offset, w, h*share, maxDensity)
+			offset += h * share
+		}
+	}
+}
+
+// heatColor shades from green (no penalty) to red (the densest file)
+func heatColor(density, maxDensity float64) string {
+	heat := 0.0
+	if maxDensity > 0 {
+		heat = density / maxDensity
+	}
+	return fmt.Sprintf("hsl(%d, 70%%, 60%%)", int(math.Round(120*(1-heat))))
+}
+
+func walkTreemapFiles(node *models.TreemapNode, visit func(*models.TreemapNode)) {
+	if node.IsFile() {
+		visit(node)
+		return
+	}
+	for _, child := range node.Children {
+		walkTreemapFiles(child, visit)
+	}
+}
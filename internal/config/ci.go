@@ -0,0 +1,63 @@
+package config
+
+import "fmt"
+
+// CIConfig controls the profile applied automatically when running under CI
+type CIConfig struct {
+	// When to apply the profile: "auto" (detect from environment), "always", or "never"
+	Detect string `yaml:"detect" json:"detect"`
+
+	// Keep colored, emoji output in CI logs
+	Colors bool `yaml:"colors" json:"colors"`
+
+	// Directory the JSON report is also written to ("" to disable)
+	ArtifactDir string `yaml:"artifact_dir" json:"artifact_dir"`
+
+	// Deprecated: the summary line is printed outside CI too, per
+	// output.summary_line, which LoadConfig copies this to
+	SummaryLine *bool `yaml:"summary_line,omitempty" json:"summary_line,omitempty"`
+}
+
+// DetectCI returns the CI provider the process is running under, or "" when
+// none of the well-known environment variables are set
+func DetectCI(getenv func(string) string) string {
+	switch {
+	case getenv("GITHUB_ACTIONS") == "true":
+		return "github-actions"
+	case getenv("GITLAB_CI") != "":
+		return "gitlab-ci"
+	case getenv("CI") != "" && getenv("CI") != "false" && getenv("CI") != "0":
+		return "ci"
+	}
+	return ""
+}
+
+// CIProvider decides whether the CI profile applies under the configured
+// detect mode, returning the provider name or ""
+func (c *Config) CIProvider(getenv func(string) string) string {
+	switch c.CI.Detect {
+	case "never":
+		return ""
+	case "always":
+		if provider := DetectCI(getenv); provider != "" {
+			return provider
+		}
+		return "ci"
+	default:
+		return DetectCI(getenv)
+	}
+}
+
+// ApplyCIProfile switches output to the CI settings. With colors off, the
+// existing exit policy (non-zero below the fair threshold) also applies.
+func (c *Config) ApplyCIProfile() {
+	c.Output.Colors = c.CI.Colors
+}
+
+func (c *Config) validateCI() error {
+	switch c.CI.Detect {
+	case "", "auto", "always", "never":
+		return nil
+	}
+	return fmt.Errorf("invalid ci.detect: %s (valid: auto, always, never)", c.CI.Detect)
+}
@@ -3,8 +3,14 @@ package config
 
 import (
 	"fmt"
+	"go/version"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -26,6 +32,36 @@ type Config struct {
 
 	// File patterns
 	Files FilesConfig `yaml:"files" json:"files"`
+
+	// Relaxed rule profile applied to _test.go files
+	Tests TestProfileConfig `yaml:"tests" json:"tests"`
+
+	// Profile applied when running under CI
+	CI CIConfig `yaml:"ci" json:"ci"`
+
+	// Annotate issues with who owns the flagged code
+	Ownership OwnershipConfig `yaml:"ownership" json:"ownership"`
+
+	// Post run summaries to Slack or Teams
+	Notifications NotificationsConfig `yaml:"notifications" json:"notifications"`
+
+	// Organization policy bundle this config may tighten but not weaken
+	Policy PolicyConfig `yaml:"policy,omitempty" json:"policy,omitempty"`
+
+	// Local settings the policy overrode, filled in by ApplyPolicy
+	PolicyRejections []string `yaml:"-" json:"-"`
+
+	// Migration warnings for deprecated rule ids in the config or policy,
+	// which have been replaced by their new ids (see DeprecatedRules)
+	RuleWarnings []string `yaml:"-" json:"-"`
+}
+
+type OwnershipConfig struct {
+	// Add the owning teams of each flagged file from the repository's CODEOWNERS
+	CodeOwners bool `yaml:"codeowners" json:"codeowners"`
+
+	// Add the author and date of the last commit to each flagged line (git blame)
+	Blame bool `yaml:"blame" json:"blame"`
 }
 
 type AnalysisConfig struct {
@@ -37,6 +73,83 @@ type AnalysisConfig struct {
 
 	// Parallel analysis
 	MaxWorkers int `yaml:"max_workers" json:"max_workers"`
+
+	// Analyze one package at a time, releasing its syntax trees and type info
+	// before the next, for trees too large to hold in memory at once. Call
+	// frequencies are then only estimated from calls within each package.
+	// Watch mode keeps every file parsed regardless.
+	LowMemory bool `yaml:"low_memory" json:"low_memory"`
+
+	// Stop analyzing once this much time has passed, e.g. "30s", and mark the
+	// result partial. Files are analyzed a package at a time, those with the
+	// most issues per KB in the latest history snapshot first. Empty for no
+	// limit; watch mode ignores it.
+	TimeBudget string `yaml:"time_budget,omitempty" json:"time_budget,omitempty"`
+
+	// Drop issues whose detector confidence is below this value (0.0-1.0)
+	MinConfidence float64 `yaml:"min_confidence" json:"min_confidence"`
+
+	// Merge issues from different detectors on the same statement
+	MergeDuplicates bool `yaml:"merge_duplicates" json:"merge_duplicates"`
+
+	// Letter grades by minimum score, highest first
+	GradingScale []GradeBand `yaml:"grading_scale" json:"grading_scale"`
+
+	// Fail the run (exit status 1) when a category's sub-score is below its
+	// minimum here, e.g. memory: 70
+	MinCategoryScores map[string]int `yaml:"min_category_scores,omitempty" json:"min_category_scores,omitempty"`
+
+	// Calls that do network or disk I/O, as "pkg/path.Func" or
+	// "pkg/path.Type.Method" (methods are only recognized with type info)
+	ExpensiveCalls []string `yaml:"expensive_calls" json:"expensive_calls"`
+
+	// Number of levels to lower the severity of issues in rarely run code:
+	// init and setup functions, error paths, and test helpers (0 = off)
+	RareCodeDowngrade int `yaml:"rare_code_downgrade" json:"rare_code_downgrade"`
+
+	// Raise the severity of a rule's issues once it fires more than a
+	// threshold number of times in one function or file
+	RepeatEscalation RepeatEscalationConfig `yaml:"repeat_escalation" json:"repeat_escalation"`
+
+	// How long one rule may take on one file before it is reported, or
+	// turned off for the rest of the run
+	RuleBudget RuleBudgetConfig `yaml:"rule_budget" json:"rule_budget"`
+
+	// Lowest severity to report issues of each type at, after all other
+	// adjustments (LOW, MEDIUM, HIGH, CRITICAL)
+	SeverityFloors map[string]string `yaml:"severity_floors,omitempty" json:"severity_floors,omitempty"`
+}
+
+// DefaultExpensiveCalls lists common blocking network, database, disk, and
+// subprocess calls
+func DefaultExpensiveCalls() []string {
+	return []string{
+		"net/http.Get", "net/http.Head", "net/http.Post", "net/http.PostForm",
+		"net/http.Client.Do", "net/http.Client.Get", "net/http.Client.Head", "net/http.Client.Post",
+		"database/sql.DB.Query", "database/sql.DB.QueryContext",
+		"database/sql.DB.QueryRow", "database/sql.DB.QueryRowContext",
+		"database/sql.DB.Exec", "database/sql.DB.ExecContext",
+		"net.Dial", "net.DialTimeout",
+		"os.ReadFile", "os.WriteFile",
+		"os/exec.Cmd.Run", "os/exec.Cmd.Output", "os/exec.Cmd.CombinedOutput",
+	}
+}
+
+// GradeBand assigns Grade to scores of at least MinScore
+type GradeBand struct {
+	Grade    string `yaml:"grade" json:"grade"`
+	MinScore int    `yaml:"min_score" json:"min_score"`
+}
+
+// DefaultGradingScale is the conventional A-F scale
+func DefaultGradingScale() []GradeBand {
+	return []GradeBand{
+		{Grade: "A", MinScore: 90},
+		{Grade: "B", MinScore: 80},
+		{Grade: "C", MinScore: 70},
+		{Grade: "D", MinScore: 60},
+		{Grade: "F", MinScore: 0},
+	}
 }
 
 type ScoreThresholds struct {
@@ -46,6 +159,39 @@ type ScoreThresholds struct {
 	Poor      int `yaml:"poor" json:"poor"`           // < 50
 }
 
+// RepeatEscalationConfig escalates repeat offenders: with a threshold of 4,
+// the 5th and later issues of one type in the same function (or file) are
+// raised Levels severity levels, as their cost compounds
+type RepeatEscalationConfig struct {
+	Enabled   bool     `yaml:"enabled" json:"enabled"`
+	Scope     string   `yaml:"scope" json:"scope"`                     // "function" or "file"
+	Threshold int      `yaml:"threshold" json:"threshold"`             // Issues of a type allowed before escalating
+	Levels    int      `yaml:"levels" json:"levels"`                   // Severity levels to raise the rest by
+	Rules     []string `yaml:"rules,omitempty" json:"rules,omitempty"` // Issue types to escalate (empty = all)
+}
+
+// RuleBudgetConfig keeps one expensive rule from making the whole analysis
+// unusable. A detector can't be stopped mid-file, so the budget is checked
+// after each file it analyzes.
+type RuleBudgetConfig struct {
+	PerFile string            `yaml:"per_file" json:"per_file"`               // Duration such as "2s"; empty for no limit
+	Action  string            `yaml:"action" json:"action"`                   // "warn", or "disable" the rule for the remaining files
+	Rules   map[string]string `yaml:"rules,omitempty" json:"rules,omitempty"` // Per-rule overrides of per_file, by rule name
+}
+
+// Budget returns the per-file budget of a rule, or 0 for no limit
+func (b RuleBudgetConfig) Budget(rule string) time.Duration {
+	perFile, ok := b.Rules[rule]
+	if !ok {
+		perFile = b.PerFile
+	}
+	budget, err := time.ParseDuration(perFile)
+	if err != nil {
+		return 0
+	}
+	return budget
+}
+
 type OutputConfig struct {
 	// Default output format
 	Format string `yaml:"format" json:"format"`
@@ -61,6 +207,61 @@ type OutputConfig struct {
 
 	// Output file path (optional)
 	OutputFile string `yaml:"output_file,omitempty" json:"output_file,omitempty"`
+
+	// With the csv format, also write per-function metrics as CSV to this path (optional)
+	MetricsFile string `yaml:"metrics_file,omitempty" json:"metrics_file,omitempty"`
+
+	// In watch mode, keep an errors file for editor quickfix lists and problem matchers at this path (optional)
+	QuickfixFile string `yaml:"quickfix_file,omitempty" json:"quickfix_file,omitempty"`
+
+	// Also write the import cycles found as a Graphviz DOT graph to this path (optional)
+	CycleGraphFile string `yaml:"cycle_graph_file,omitempty" json:"cycle_graph_file,omitempty"`
+
+	// Print a final machine-parsable summary line to stderr, whatever the format
+	SummaryLine bool `yaml:"summary_line" json:"summary_line"`
+
+	// Write the summary line to this path instead of stderr (optional)
+	SummaryFile string `yaml:"summary_file,omitempty" json:"summary_file,omitempty"`
+
+	// Issue ordering in reports: "severity" or "impact" (best payoff per effort first)
+	SortBy string `yaml:"sort_by" json:"sort_by"`
+
+	// Only report issues at or below this fix effort: "trivial", "small", "large" (empty = all)
+	MaxFixEffort string `yaml:"max_fix_effort,omitempty" json:"max_fix_effort,omitempty"`
+
+	// How file paths are reported: "relative" (to the module root) or "absolute"
+	PathMode string `yaml:"path_mode" json:"path_mode"`
+
+	// Rule reference that issues link to, with an anchor per issue type, e.g.
+	// an internal mirror of docs/rules.md ("" for no links)
+	DocsBaseURL string `yaml:"docs_base_url" json:"docs_base_url"`
+
+	// Levels that output consumers (sarif, sonar, lsp, quickfix) give each
+	// severity, overriding their defaults, e.g. {quickfix: {MEDIUM: info}}
+	SeverityLevels map[string]map[string]string `yaml:"severity_levels,omitempty" json:"severity_levels,omitempty"`
+
+	// Report at most this many issues in total / per rule (0 = unlimited)
+	MaxIssues        int `yaml:"max_issues,omitempty" json:"max_issues,omitempty"`
+	MaxIssuesPerRule int `yaml:"max_issues_per_rule,omitempty" json:"max_issues_per_rule,omitempty"`
+
+	// Stop analyzing further files once max_issues issues have been found
+	StopAtMaxIssues bool `yaml:"stop_at_max_issues,omitempty" json:"stop_at_max_issues,omitempty"`
+
+	// Group reported issues: "owner" (see ownership) or empty for no grouping
+	GroupBy string `yaml:"group_by,omitempty" json:"group_by,omitempty"`
+
+	// Classify issues as new or pre-existing relative to a git ref, or to the
+	// issues of an earlier JSON report (optional, at most one)
+	NewSince string `yaml:"new_since,omitempty" json:"new_since,omitempty"`
+	Baseline string `yaml:"baseline,omitempty" json:"baseline,omitempty"`
+
+	// A baseline older than this, e.g. "90d" or "720h", has expired and its
+	// issues count as new again (empty for no limit). Its age is that of its
+	// last git commit, or else of the file.
+	BaselineTTL string `yaml:"baseline_ttl,omitempty" json:"baseline_ttl,omitempty"`
+
+	// POST the JSON result to a central endpoint after each run
+	Upload UploadConfig `yaml:"upload" json:"upload"`
 }
 
 type RulesConfig struct {
@@ -75,6 +276,9 @@ type RulesConfig struct {
 
 	// Memory rules
 	Memory MemoryRules `yaml:"memory" json:"memory"`
+
+	// Goroutine, channel, sync primitive, and context rules
+	Concurrency ConcurrencyRules `yaml:"concurrency" json:"concurrency"`
 }
 
 type ComplexityRules struct {
@@ -85,6 +289,14 @@ type ComplexityRules struct {
 
 	// Function length thresholds
 	FunctionLength FunctionLengthConfig `yaml:"function_length" json:"function_length"`
+
+	// Thresholds for the total cyclomatic complexity of a type's methods,
+	// which catches types doing too much even when every method is simple.
+	// Checked by the cyclomatic_complexity rule.
+	TypeComplexity ThresholdConfig `yaml:"type_complexity" json:"type_complexity"`
+
+	// if/else-if chains that read better as a switch or a lookup map
+	IfChain IfChainConfig `yaml:"if_chain" json:"if_chain"`
 }
 
 type PerformanceRules struct {
@@ -98,6 +310,37 @@ type PerformanceRules struct {
 
 	// Data structure usage
 	DataStructure DataStructureConfig `yaml:"data_structure" json:"data_structure"`
+
+	// strings.Split / Fields / Join in loops
+	SplitInLoop SplitInLoopConfig `yaml:"split_in_loop" json:"split_in_loop"`
+
+	// Logging in loops and hot functions
+	LogInLoop LogInLoopConfig `yaml:"log_in_loop" json:"log_in_loop"`
+
+	// strings.Builder and bytes.Buffer misuse
+	BuilderUsage BuilderUsageConfig `yaml:"builder_usage" json:"builder_usage"`
+
+	// Per-request allocations in HTTP handlers
+	HandlerAlloc HandlerAllocConfig `yaml:"handler_alloc" json:"handler_alloc"`
+
+	// Deprecated: moved to rules.concurrency.busy_wait, which LoadConfig
+	// copies it to
+	BusyWait *BusyWaitConfig `yaml:"busy_wait,omitempty" json:"busy_wait,omitempty"`
+
+	// Errors built on every loop iteration but only used on failure
+	ErrorfInLoop ErrorfInLoopConfig `yaml:"errorf_in_loop" json:"errorf_in_loop"`
+
+	// Hand-rolled sorts and sorting inside loops
+	InefficientSort InefficientSortConfig `yaml:"inefficient_sort" json:"inefficient_sort"`
+
+	// Map keys formatted with fmt.Sprintf on every lookup
+	SprintfKey SprintfKeyConfig `yaml:"sprintf_key" json:"sprintf_key"`
+
+	// Loops making independent expensive calls one at a time
+	WorkerPool WorkerPoolConfig `yaml:"worker_pool" json:"worker_pool"`
+
+	// The same few values converted to strings over and over in loops
+	ConversionCache ConversionCacheConfig `yaml:"conversion_cache" json:"conversion_cache"`
 }
 
 type QualityRules struct {
@@ -105,6 +348,18 @@ type QualityRules struct {
 
 	// Import cycle detection
 	ImportCycles ImportCycleConfig `yaml:"import_cycles" json:"import_cycles"`
+
+	// Map mutation during range
+	MapMutation MapMutationConfig `yaml:"map_mutation" json:"map_mutation"`
+
+	// Loops that rewrite slices and maps package functions
+	StdlibLoops StdlibLoopsConfig `yaml:"stdlib_loops" json:"stdlib_loops"`
+
+	// Hub packages many packages import that also import many
+	PackageCoupling PackageCouplingConfig `yaml:"package_coupling" json:"package_coupling"`
+
+	// Allowed and forbidden dependency directions between packages
+	DependencyRules DependencyRulesConfig `yaml:"dependency_rules" json:"dependency_rules"`
 }
 
 type MemoryRules struct {
@@ -115,6 +370,31 @@ type MemoryRules struct {
 
 	// Slice growth patterns
 	SliceGrowth SliceGrowthConfig `yaml:"slice_growth" json:"slice_growth"`
+
+	// Misused append results
+	AppendUsage AppendUsageConfig `yaml:"append_usage" json:"append_usage"`
+
+	// Value receivers on large structs
+	ValueReceiver ValueReceiverConfig `yaml:"value_receiver" json:"value_receiver"`
+
+	// Small subslices that pin large buffers
+	SliceRetention SliceRetentionConfig `yaml:"slice_retention" json:"slice_retention"`
+
+	// Whole inputs read into memory only to be scanned once
+	ReadAll ReadAllConfig `yaml:"read_all" json:"read_all"`
+
+	// Allocating APIs with allocation-free alternatives, used in hot code
+	AllocFreeAPI AllocFreeAPIConfig `yaml:"alloc_free_api" json:"alloc_free_api"`
+}
+
+type ConcurrencyRules struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// Single-case selects, busy selects, and spin loops
+	BusyWait BusyWaitConfig `yaml:"busy_wait" json:"busy_wait"`
+
+	// Unsynchronized map writes from goroutines started in a loop
+	ConcurrentMapWrite ConcurrentMapWriteConfig `yaml:"concurrent_map_write" json:"concurrent_map_write"`
 }
 
 // Individual rule configurations
@@ -130,8 +410,27 @@ type FunctionLengthConfig struct {
 	MediumThreshold   int  `yaml:"medium_threshold" json:"medium_threshold"`     // lines
 	HighThreshold     int  `yaml:"high_threshold" json:"high_threshold"`         // lines
 	CriticalThreshold int  `yaml:"critical_threshold" json:"critical_threshold"` // lines
-	CountComments     bool `yaml:"count_comments" json:"count_comments"`
-	CountEmptyLines   bool `yaml:"count_empty_lines" json:"count_empty_lines"`
+	CountComments     bool `yaml:"count_comments" json:"count_comments"`         // Count comment-only lines toward the length
+	CountEmptyLines   bool `yaml:"count_empty_lines" json:"count_empty_lines"`   // Count blank lines toward the length
+
+	// How length is measured, which the thresholds are in: "lines" (lines
+	// where code starts, the default), "source_lines" (every line that isn't
+	// blank or only a comment, closing brackets included), or "statements"
+	// (statements, independent of formatting). The count_ options apply to
+	// both line measures.
+	Measure string `yaml:"measure" json:"measure"`
+}
+
+// Function length measures
+const (
+	LengthMeasureLines       = "lines"
+	LengthMeasureSourceLines = "source_lines"
+	LengthMeasureStatements  = "statements"
+)
+
+type IfChainConfig struct {
+	Enabled     bool `yaml:"enabled" json:"enabled"`
+	MinBranches int  `yaml:"min_branches" json:"min_branches"` // if and else-if conditions a chain needs to be flagged
 }
 
 type NestedLoopConfig struct {
@@ -149,51 +448,373 @@ type StringConcatConfig struct {
 }
 
 type DataStructureConfig struct {
-	Enabled             bool `yaml:"enabled" json:"enabled"`
-	DetectLinearSearch  bool `yaml:"detect_linear_search" json:"detect_linear_search"`
-	MinSearchComplexity int  `yaml:"min_search_complexity" json:"min_search_complexity"`
-	SuggestMaps         bool `yaml:"suggest_maps" json:"suggest_maps"`
+	Enabled              bool `yaml:"enabled" json:"enabled"`
+	DetectLinearSearch   bool `yaml:"detect_linear_search" json:"detect_linear_search"`
+	MinSearchComplexity  int  `yaml:"min_search_complexity" json:"min_search_complexity"`
+	SuggestMaps          bool `yaml:"suggest_maps" json:"suggest_maps"`
+	DetectMembershipSets bool `yaml:"detect_membership_sets" json:"detect_membership_sets"` // Repeated membership tests that a map[T]struct{} set answers in O(1)
+	DetectMinMaxScans    bool `yaml:"detect_min_max_scans" json:"detect_min_max_scans"`     // Minimum or maximum rescanned in a loop, where container/heap fits
+	DetectQueueShifts    bool `yaml:"detect_queue_shifts" json:"detect_queue_shifts"`       // x = x[1:] queues that a ring buffer or deque would reuse
+	DetectSortedInserts  bool `yaml:"detect_sorted_inserts" json:"detect_sorted_inserts"`   // Append then re-sort, where slices.Insert at a binary search position fits
+}
+
+type SplitInLoopConfig struct {
+	Enabled        bool `yaml:"enabled" json:"enabled"`
+	DetectHoisting bool `yaml:"detect_hoisting" json:"detect_hoisting"` // Calls on inputs the loop doesn't change
+	SuggestCut     bool `yaml:"suggest_cut" json:"suggest_cut"`         // Split(...)[0] and [1] that strings.Cut can replace
+}
+
+type LogInLoopConfig struct {
+	Enabled          bool     `yaml:"enabled" json:"enabled"`
+	LoggerPackages   []string `yaml:"logger_packages" json:"logger_packages"`       // Import paths whose functions and types log
+	FlagHotFunctions bool     `yaml:"flag_hot_functions" json:"flag_hot_functions"` // Also flag logging in high-frequency functions outside loops
+}
+
+type BuilderUsageConfig struct {
+	Enabled       bool `yaml:"enabled" json:"enabled"`
+	SuggestGrow   bool `yaml:"suggest_grow" json:"suggest_grow"`     // Writes in loops with a known iteration count and no Grow
+	DetectCopies  bool `yaml:"detect_copies" json:"detect_copies"`   // Builders and Buffers passed or assigned by value
+	DetectFprintf bool `yaml:"detect_fprintf" json:"detect_fprintf"` // fmt.Fprintf into a builder where WriteString will do
+	SuggestType   bool `yaml:"suggest_type" json:"suggest_type"`     // bytes.Buffer only built into a string, strings.Builder only turned into []byte
+}
+
+type HandlerAllocConfig struct {
+	Enabled           bool `yaml:"enabled" json:"enabled"`
+	IncludeFrameworks bool `yaml:"include_frameworks" json:"include_frameworks"`   // gin, echo, and fiber handlers as well as net/http
+	DetectRegexp      bool `yaml:"detect_regexp" json:"detect_regexp"`             // Constant patterns compiled per request
+	DetectTemplates   bool `yaml:"detect_templates" json:"detect_templates"`       // Templates parsed per request
+	DetectMapLiterals bool `yaml:"detect_map_literals" json:"detect_map_literals"` // Constant lookup maps built per request
+	MinMapEntries     int  `yaml:"min_map_entries" json:"min_map_entries"`
+}
+
+type BusyWaitConfig struct {
+	Enabled          bool `yaml:"enabled" json:"enabled"`
+	DetectSingleCase bool `yaml:"detect_single_case" json:"detect_single_case"` // select with one case, a plain channel operation
+	DetectBusySelect bool `yaml:"detect_busy_select" json:"detect_busy_select"` // Loops around a select with an empty default
+	DetectSpinLoops  bool `yaml:"detect_spin_loops" json:"detect_spin_loops"`   // Loops with an empty body
+}
+
+type ErrorfInLoopConfig struct {
+	Enabled          bool `yaml:"enabled" json:"enabled"`
+	IncludeErrorsNew bool `yaml:"include_errors_new" json:"include_errors_new"` // Also flag errors.New, which a sentinel always replaces
+}
+
+type InefficientSortConfig struct {
+	Enabled            bool `yaml:"enabled" json:"enabled"`
+	DetectHandRolled   bool `yaml:"detect_hand_rolled" json:"detect_hand_rolled"`     // Bubble, selection, and insertion sorts
+	DetectRepeatedSort bool `yaml:"detect_repeated_sort" json:"detect_repeated_sort"` // sort.Slice on the same data every iteration
+}
+
+type SprintfKeyConfig struct {
+	Enabled           bool `yaml:"enabled" json:"enabled"`
+	OnlyInLoops       bool `yaml:"only_in_loops" json:"only_in_loops"`             // Ignore keys built once per call
+	TrackKeyVariables bool `yaml:"track_key_variables" json:"track_key_variables"` // key := fmt.Sprintf(...) used only as a map index
+}
+
+type WorkerPoolConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	Limit   int  `yaml:"limit" json:"limit"` // Concurrency limit used in the suggested errgroup.SetLimit
+}
+
+type ConversionCacheConfig struct {
+	Enabled            bool `yaml:"enabled" json:"enabled"`
+	DetectInvariant    bool `yaml:"detect_invariant" json:"detect_invariant"`         // strconv/fmt conversions of values the loop doesn't change
+	DetectSmallSets    bool `yaml:"detect_small_sets" json:"detect_small_sets"`       // Conversions of x % N, bytes, and bools that a table covers
+	DetectEnumSwitches bool `yaml:"detect_enum_switches" json:"detect_enum_switches"` // Switches mapping constants to string literals, run in loops
+	MaxTableSize       int  `yaml:"max_table_size" json:"max_table_size"`             // Largest lookup table suggested
+	MinEnumCases       int  `yaml:"min_enum_cases" json:"min_enum_cases"`             // Cases a switch needs to count as an enum-to-string switch
 }
 
 type ImportCycleConfig struct {
 	Enabled            bool     `yaml:"enabled" json:"enabled"`
 	MaxCycleLength     int      `yaml:"max_cycle_length" json:"max_cycle_length"`
 	IgnoreTestPackages bool     `yaml:"ignore_test_packages" json:"ignore_test_packages"`
-	IgnoreVendor       bool     `yaml:"ignore_vendor" json:"ignore_vendor"`
 	ExcludePackages    []string `yaml:"exclude_packages" json:"exclude_packages"`
+
+	// Deprecated: moved to files.ignore_vendor, which LoadConfig copies it to
+	IgnoreVendor *bool `yaml:"ignore_vendor,omitempty" json:"ignore_vendor,omitempty"`
+}
+
+// PackageCouplingConfig sets when a package counts as a hub: imported by at
+// least HubFanIn analyzed packages while itself importing at least HubFanOut
+type PackageCouplingConfig struct {
+	Enabled   bool `yaml:"enabled" json:"enabled"`
+	HubFanIn  int  `yaml:"hub_fan_in" json:"hub_fan_in"`
+	HubFanOut int  `yaml:"hub_fan_out" json:"hub_fan_out"`
+}
+
+// DependencyRulesConfig declares which packages may import which. A package
+// is checked against the first rule whose From matches it; packages no rule
+// matches may import anything.
+type DependencyRulesConfig struct {
+	Enabled bool             `yaml:"enabled" json:"enabled"`
+	Rules   []DependencyRule `yaml:"rules,omitempty" json:"rules,omitempty"`
+}
+
+// DependencyRule restricts the imports of the packages matching From.
+// Patterns are import paths, relative to the module root for packages of the
+// importing module, in the glob syntax of files.exclude_packages.
+type DependencyRule struct {
+	From   string   `yaml:"from" json:"from"`
+	Allow  []string `yaml:"allow,omitempty" json:"allow,omitempty"`   // Only these packages of the module may be imported
+	Deny   []string `yaml:"deny,omitempty" json:"deny,omitempty"`     // These packages, of any module, may not be imported
+	Reason string   `yaml:"reason,omitempty" json:"reason,omitempty"` // Shown with violations
+}
+
+type StdlibLoopsConfig struct {
+	Enabled          bool   `yaml:"enabled" json:"enabled"`
+	DetectSlices     bool   `yaml:"detect_slices" json:"detect_slices"`           // slices.Contains, Index, Max, Min, Reverse
+	DetectMaps       bool   `yaml:"detect_maps" json:"detect_maps"`               // maps.Keys, Values, Copy
+	DefaultGoVersion string `yaml:"default_go_version" json:"default_go_version"` // Assumed for files outside a module
+}
+
+type MapMutationConfig struct {
+	Enabled             bool `yaml:"enabled" json:"enabled"`
+	DetectRangeMutation bool `yaml:"detect_range_mutation" json:"detect_range_mutation"` // Inserts/deletes on a map being ranged over
+
+	// Deprecated: moved to rules.concurrency.concurrent_map_write.enabled,
+	// which LoadConfig copies it to
+	DetectConcurrentWrites *bool `yaml:"detect_concurrent_writes,omitempty" json:"detect_concurrent_writes,omitempty"`
+}
+
+type ConcurrentMapWriteConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
 }
 
 type AllocationConfig struct {
 	Enabled              bool `yaml:"enabled" json:"enabled"`
 	DetectInLoops        bool `yaml:"detect_in_loops" json:"detect_in_loops"`
 	RequireCapacityHints bool `yaml:"require_capacity_hints" json:"require_capacity_hints"`
-	MinLoopIterations    int  `yaml:"min_loop_iterations" json:"min_loop_iterations"`
+	MinLoopIterations    int  `yaml:"min_loop_iterations" json:"min_loop_iterations"` // Estimated iterations before append in a loop is reported (unknown counts as many)
 }
 
 type SliceGrowthConfig struct {
 	Enabled             bool `yaml:"enabled" json:"enabled"`
 	RequireCapacity     bool `yaml:"require_capacity" json:"require_capacity"`
 	DetectAppendInLoops bool `yaml:"detect_append_in_loops" json:"detect_append_in_loops"`
-	MinAppendCount      int  `yaml:"min_append_count" json:"min_append_count"`
+	MinAppendCount      int  `yaml:"min_append_count" json:"min_append_count"` // Estimated appends to one slice per loop run before it is reported
+}
+
+type AppendUsageConfig struct {
+	Enabled          bool `yaml:"enabled" json:"enabled"`
+	DetectDiscarded  bool `yaml:"detect_discarded" json:"detect_discarded"`     // append(s, x) with the result dropped
+	DetectAliasing   bool `yaml:"detect_aliasing" json:"detect_aliasing"`       // s = append(t, ...) sharing t's backing array
+	DetectSelfAppend bool `yaml:"detect_self_append" json:"detect_self_append"` // s = append(s, s...) and prepends in loops
+}
+
+type ValueReceiverConfig struct {
+	Enabled      bool `yaml:"enabled" json:"enabled"`
+	MaxSizeBytes int  `yaml:"max_size_bytes" json:"max_size_bytes"` // Largest receiver copied without a finding
+}
+
+type SliceRetentionConfig struct {
+	Enabled        bool     `yaml:"enabled" json:"enabled"`
+	LargeSources   []string `yaml:"large_sources" json:"large_sources"`       // Functions returning whole inputs, e.g. "os.ReadFile"
+	MinBufferBytes int      `yaml:"min_buffer_bytes" json:"min_buffer_bytes"` // make([]byte, n) counts as large from this size
+}
+
+type ReadAllConfig struct {
+	Enabled            bool     `yaml:"enabled" json:"enabled"`
+	Sources            []string `yaml:"sources" json:"sources"`                           // Functions reading a whole input, e.g. "io.ReadAll"
+	EscalateInHandlers bool     `yaml:"escalate_in_handlers" json:"escalate_in_handlers"` // Raise severity in HTTP handlers, which read per request
+}
+
+type AllocFreeAPIConfig struct {
+	Enabled          bool             `yaml:"enabled" json:"enabled"`
+	FlagHotFunctions bool             `yaml:"flag_hot_functions" json:"flag_hot_functions"` // Also flag calls in high-frequency functions outside loops
+	Alternatives     []APIAlternative `yaml:"alternatives" json:"alternatives"`             // Calls to flag and what to use instead
+}
+
+// APIAlternative maps an allocating call to an allocation-free one
+type APIAlternative struct {
+	// Call to flag, as "pkg/path.Func" or "pkg/path.Type.Method" (methods
+	// are only recognized with type info)
+	Call string `yaml:"call" json:"call"`
+
+	// Only flag calls whose first argument is one of these format strings,
+	// e.g. "%d" for fmt.Sprintf
+	Formats []string `yaml:"formats,omitempty" json:"formats,omitempty"`
+
+	// Only flag calls whose result is passed straight to one of these calls,
+	// e.g. "strings.Builder.WriteString"
+	ArgumentOf []string `yaml:"argument_of,omitempty" json:"argument_of,omitempty"`
+
+	// What to call instead, and how
+	Replacement string `yaml:"replacement" json:"replacement"`
+	Note        string `yaml:"note,omitempty" json:"note,omitempty"`
+}
+
+// DefaultAPIAlternatives lists standard library calls with allocation-free
+// counterparts
+func DefaultAPIAlternatives() []APIAlternative {
+	builderWrites := []string{"strings.Builder.WriteString", "bytes.Buffer.WriteString", "bufio.Writer.WriteString"}
+	return []APIAlternative{
+		{
+			Call:        "regexp.Regexp.FindAllString",
+			Replacement: "re.FindAllStringIndex(s, n)",
+			Note:        "Returns match offsets; slice s only where a match is actually needed as a string.",
+		},
+		{
+			Call:        "regexp.Regexp.FindAllStringSubmatch",
+			Replacement: "re.FindAllStringSubmatchIndex(s, n)",
+			Note:        "Returns submatch offsets; slice s only where a submatch is actually needed as a string.",
+		},
+		{
+			Call:        "time.Time.Format",
+			Replacement: "buf = t.AppendFormat(buf[:0], layout)",
+			Note:        "Formats into a buffer reused across iterations instead of a new string each time.",
+		},
+		{
+			Call:        "strconv.Itoa",
+			ArgumentOf:  builderWrites,
+			Replacement: "w.Write(strconv.AppendInt(scratch[:0], int64(n), 10))",
+			Note:        "Appends the digits to a reused scratch buffer instead of allocating a string to copy.",
+		},
+		{
+			Call:        "strconv.FormatInt",
+			ArgumentOf:  builderWrites,
+			Replacement: "w.Write(strconv.AppendInt(scratch[:0], n, base))",
+			Note:        "Appends the digits to a reused scratch buffer instead of allocating a string to copy.",
+		},
+		{
+			Call:        "strconv.FormatFloat",
+			ArgumentOf:  builderWrites,
+			Replacement: "w.Write(strconv.AppendFloat(scratch[:0], f, fmt, prec, bitSize))",
+			Note:        "Appends the digits to a reused scratch buffer instead of allocating a string to copy.",
+		},
+		{
+			Call:        "strconv.Quote",
+			ArgumentOf:  builderWrites,
+			Replacement: "w.Write(strconv.AppendQuote(scratch[:0], s))",
+			Note:        "Appends the quoted string to a reused scratch buffer instead of allocating a string to copy.",
+		},
+		{
+			Call:        "fmt.Sprintf",
+			Formats:     []string{"%d"},
+			Replacement: "strconv.Itoa(n)",
+			Note:        "Skips parsing the format and boxing n in an interface; use strconv.FormatInt for other integer types.",
+		},
+		{
+			Call:        "fmt.Sprintf",
+			Formats:     []string{"%t"},
+			Replacement: "strconv.FormatBool(b)",
+			Note:        "Returns one of two constant strings without allocating.",
+		},
+		{
+			Call:        "fmt.Sprintf",
+			Formats:     []string{"%s"},
+			Replacement: "the string itself, or its String method",
+			Note:        "Formatting a single string copies it for nothing.",
+		},
+	}
 }
 
 type FilesConfig struct {
-	// Include patterns
+	// Globs of the files to collect and watch, relative to each directory
+	// named (the working directory for package patterns such as ./...); **
+	// matches any number of directories, e.g. "**/mocks/**"
 	Include []string `yaml:"include" json:"include"`
-
-	// Exclude patterns
 	Exclude []string `yaml:"exclude" json:"exclude"`
 
+	// Exclude files by package import path, after package patterns such as
+	// ./... are resolved: "*/generated/*" drops every generated package and
+	// "*.pb.go" generated files (* also matches "/")
+	ExcludePackages []string `yaml:"exclude_packages,omitempty" json:"exclude_packages,omitempty"`
+
 	// Whether to analyze test files
 	IncludeTests bool `yaml:"include_tests" json:"include_tests"`
 
-	// Whether to follow symlinks
+	// Whether to leave vendor directories out when collecting and watching
+	// files, and vendored packages out of import analysis. A path inside a
+	// vendor directory named on the command line is still analyzed.
+	IgnoreVendor bool `yaml:"ignore_vendor" json:"ignore_vendor"`
+
+	// Whether to descend into symlinked directories when collecting and
+	// watching files; each real directory is still visited only once
 	FollowSymlinks bool `yaml:"follow_symlinks" json:"follow_symlinks"`
 
-	// Max file size (in KB)
+	// Whether to skip files with a "Code generated ... DO NOT EDIT." header;
+	// they are still type-checked, and listed in the result's skipped_files
+	SkipGenerated bool `yaml:"skip_generated" json:"skip_generated"`
+
+	// Max file size (in KB, 0 = unlimited); larger files are skipped and
+	// listed in the result's skipped_files
 	MaxFileSize int `yaml:"max_file_size" json:"max_file_size"`
 }
 
+// TestProfileConfig relaxes rules for test files, where long table-driven
+// functions and nested loops over fixtures are usually fine
+type TestProfileConfig struct {
+	// Rules (as named in IsRuleEnabled) that never run on test files
+	DisabledRules []string `yaml:"disabled_rules" json:"disabled_rules"`
+
+	// Number of levels to lower the severity of remaining test-file issues
+	DowngradeSeverity int `yaml:"downgrade_severity" json:"downgrade_severity"`
+
+	// Adjustments for Benchmark, Example, and Fuzz functions
+	TestFunctions TestFunctionConfig `yaml:"test_functions" json:"test_functions"`
+}
+
+type TestFunctionConfig struct {
+	// Treat the body of a b.N / b.Loop() timing loop in a Benchmark function
+	// of a test file as measured code, not a hot loop: allocations there are
+	// expected and it doesn't add nesting depth
+	AllowBenchmarkLoopAllocs bool `yaml:"allow_benchmark_loop_allocs" json:"allow_benchmark_loop_allocs"`
+
+	// Skip all findings in Example functions, which exist for documentation
+	IgnoreExamples bool `yaml:"ignore_examples" json:"ignore_examples"`
+
+	// Skip findings in Fuzz functions outside the f.Fuzz target (seed corpus setup)
+	IgnoreFuzzSetup bool `yaml:"ignore_fuzz_setup" json:"ignore_fuzz_setup"`
+}
+
+// Budget returns TimeBudget as a duration, or 0 for no limit
+func (a AnalysisConfig) Budget() time.Duration {
+	budget, err := time.ParseDuration(a.TimeBudget)
+	if err != nil {
+		return 0
+	}
+	return budget
+}
+
+// BaselineMaxAge returns BaselineTTL as a duration, or 0 for no limit
+func (o OutputConfig) BaselineMaxAge() time.Duration {
+	ttl, err := parseDays(o.BaselineTTL)
+	if err != nil {
+		return 0
+	}
+	return ttl
+}
+
+// parseDays parses a duration that may also be a whole number of days,
+// e.g. "90d"
+func parseDays(duration string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(duration, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", duration)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(duration)
+}
+
+// IsRuleEnabledForTests checks if a rule should run on _test.go files
+func (c *Config) IsRuleEnabledForTests(ruleType string) bool {
+	if !c.IsRuleEnabled(ruleType) {
+		return false
+	}
+	if ruleType == "nested_loops" && c.Rules.Performance.NestedLoops.IgnoreTest {
+		return false
+	}
+	for _, disabled := range c.Tests.DisabledRules {
+		if disabled == ruleType {
+			return false
+		}
+	}
+	return true
+}
+
 func DefaultConfig() *Config {
 	return &Config{
 		Version: "1.0",
@@ -204,14 +825,34 @@ func DefaultConfig() *Config {
 				Fair:      50,
 				Poor:      0,
 			},
-			EnabledCategories: []string{"performance", "complexity", "memory", "quality"},
+			EnabledCategories: []string{"performance", "complexity", "memory", "quality", "concurrency"},
+			RepeatEscalation: RepeatEscalationConfig{
+				Enabled:   false,
+				Scope:     "function",
+				Threshold: 4,
+				Levels:    1,
+			},
+			RuleBudget: RuleBudgetConfig{
+				PerFile: "2s",
+				Action:  "warn",
+			},
 			MaxWorkers:        4,
+			MinConfidence:     0.6,
+			MergeDuplicates:   true,
+			GradingScale:      DefaultGradingScale(),
+			ExpensiveCalls:    DefaultExpensiveCalls(),
+			RareCodeDowngrade: 1,
 		},
 		Output: OutputConfig{
 			Format:          "console",
 			Colors:          true,
 			Verbose:         false,
 			ShowSuggestions: false,
+			SortBy:          "severity",
+			SummaryLine:     true,
+			PathMode:        "relative",
+			DocsBaseURL:     "https://github.com/ktaffy/gophercheck/blob/main/docs/rules.md",
+			Upload:          DefaultUploadConfig(),
 		},
 		Rules: RulesConfig{
 			Complexity: ComplexityRules{
@@ -229,6 +870,17 @@ func DefaultConfig() *Config {
 					CriticalThreshold: 200,
 					CountComments:     false,
 					CountEmptyLines:   false,
+					Measure:           LengthMeasureLines,
+				},
+				TypeComplexity: ThresholdConfig{
+					Enabled:           true,
+					MediumThreshold:   60,
+					HighThreshold:     100,
+					CriticalThreshold: 150,
+				},
+				IfChain: IfChainConfig{
+					Enabled:     true,
+					MinBranches: 4,
 				},
 			},
 			Performance: PerformanceRules{
@@ -246,10 +898,70 @@ func DefaultConfig() *Config {
 					StringVarNames:       []string{"str", "result", "output", "text", "content", "message", "data"},
 				},
 				DataStructure: DataStructureConfig{
-					Enabled:             true,
-					DetectLinearSearch:  true,
-					MinSearchComplexity: 2,
-					SuggestMaps:         true,
+					Enabled:              true,
+					DetectLinearSearch:   true,
+					MinSearchComplexity:  2,
+					SuggestMaps:          true,
+					DetectMembershipSets: true,
+					DetectMinMaxScans:    true,
+					DetectQueueShifts:    true,
+					DetectSortedInserts:  true,
+				},
+				SplitInLoop: SplitInLoopConfig{
+					Enabled:        true,
+					DetectHoisting: true,
+					SuggestCut:     true,
+				},
+				LogInLoop: LogInLoopConfig{
+					Enabled: true,
+					LoggerPackages: []string{
+						"log", "log/slog", "fmt",
+						"github.com/sirupsen/logrus", "go.uber.org/zap",
+						"github.com/rs/zerolog", "github.com/rs/zerolog/log",
+						"github.com/golang/glog", "k8s.io/klog/v2",
+					},
+					FlagHotFunctions: true,
+				},
+				BuilderUsage: BuilderUsageConfig{
+					Enabled:       true,
+					SuggestGrow:   true,
+					DetectCopies:  true,
+					DetectFprintf: true,
+					SuggestType:   true,
+				},
+				HandlerAlloc: HandlerAllocConfig{
+					Enabled:           true,
+					IncludeFrameworks: true,
+					DetectRegexp:      true,
+					DetectTemplates:   true,
+					DetectMapLiterals: true,
+					MinMapEntries:     3,
+				},
+				ErrorfInLoop: ErrorfInLoopConfig{
+					Enabled:          true,
+					IncludeErrorsNew: true,
+				},
+				InefficientSort: InefficientSortConfig{
+					Enabled:            true,
+					DetectHandRolled:   true,
+					DetectRepeatedSort: true,
+				},
+				SprintfKey: SprintfKeyConfig{
+					Enabled:           true,
+					OnlyInLoops:       true,
+					TrackKeyVariables: true,
+				},
+				WorkerPool: WorkerPoolConfig{
+					Enabled: true,
+					Limit:   8,
+				},
+				ConversionCache: ConversionCacheConfig{
+					Enabled:            true,
+					DetectInvariant:    true,
+					DetectSmallSets:    true,
+					DetectEnumSwitches: true,
+					MaxTableSize:       256,
+					MinEnumCases:       3,
 				},
 			},
 			Quality: QualityRules{
@@ -258,9 +970,26 @@ func DefaultConfig() *Config {
 					Enabled:            true,
 					MaxCycleLength:     5,
 					IgnoreTestPackages: true,
-					IgnoreVendor:       true,
 					ExcludePackages:    []string{},
 				},
+				MapMutation: MapMutationConfig{
+					Enabled:             true,
+					DetectRangeMutation: true,
+				},
+				StdlibLoops: StdlibLoopsConfig{
+					Enabled:          true,
+					DetectSlices:     true,
+					DetectMaps:       true,
+					DefaultGoVersion: "1.21",
+				},
+				PackageCoupling: PackageCouplingConfig{
+					Enabled:   true,
+					HubFanIn:  10,
+					HubFanOut: 10,
+				},
+				DependencyRules: DependencyRulesConfig{
+					Enabled: true,
+				},
 			},
 			Memory: MemoryRules{
 				Enabled: true,
@@ -276,15 +1005,69 @@ func DefaultConfig() *Config {
 					DetectAppendInLoops: true,
 					MinAppendCount:      3,
 				},
+				AppendUsage: AppendUsageConfig{
+					Enabled:          true,
+					DetectDiscarded:  true,
+					DetectAliasing:   true,
+					DetectSelfAppend: true,
+				},
+				ValueReceiver: ValueReceiverConfig{
+					Enabled:      true,
+					MaxSizeBytes: 128,
+				},
+				SliceRetention: SliceRetentionConfig{
+					Enabled:        true,
+					LargeSources:   []string{"os.ReadFile", "io.ReadAll", "io/ioutil.ReadFile", "io/ioutil.ReadAll"},
+					MinBufferBytes: 64 * 1024,
+				},
+				ReadAll: ReadAllConfig{
+					Enabled:            true,
+					Sources:            []string{"os.ReadFile", "io.ReadAll", "io/ioutil.ReadFile", "io/ioutil.ReadAll"},
+					EscalateInHandlers: true,
+				},
+				AllocFreeAPI: AllocFreeAPIConfig{
+					Enabled:          true,
+					FlagHotFunctions: true,
+					Alternatives:     DefaultAPIAlternatives(),
+				},
+			},
+			Concurrency: ConcurrencyRules{
+				Enabled: true,
+				BusyWait: BusyWaitConfig{
+					Enabled:          true,
+					DetectSingleCase: true,
+					DetectBusySelect: true,
+					DetectSpinLoops:  true,
+				},
+				ConcurrentMapWrite: ConcurrentMapWriteConfig{
+					Enabled: true,
+				},
 			},
 		},
 		Files: FilesConfig{
 			Include:        []string{"**/*.go"},
-			Exclude:        []string{"vendor/**", ".git/**", "node_modules/**"},
+			Exclude:        []string{".git/**", "node_modules/**"},
 			IncludeTests:   false,
+			IgnoreVendor:   true,
+			SkipGenerated:  true,
 			FollowSymlinks: false,
 			MaxFileSize:    1024, // 1MB
 		},
+		Tests: TestProfileConfig{
+			DisabledRules:     []string{"cyclomatic_complexity", "function_length", "if_chain"},
+			DowngradeSeverity: 1,
+			TestFunctions: TestFunctionConfig{
+				AllowBenchmarkLoopAllocs: true,
+				IgnoreExamples:           true,
+				IgnoreFuzzSetup:          true,
+			},
+		},
+		CI: CIConfig{
+			Detect:      "auto",
+			Colors:      false,
+			ArtifactDir: "gophercheck-results",
+		},
+		Notifications: DefaultNotificationsConfig(),
 	}
 }
 
@@ -307,17 +1090,25 @@ func LoadConfig(configPath string) (*Config, error) {
 	}
 
 	config := DefaultConfig() // Start with defaults
+	config.seedDeprecatedRules()
 
 	// Parse YAML
 	if err := yaml.Unmarshal(data, config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file %s: %w", configPath, err)
 	}
 
+	config.migrateDeprecatedRules()
+
 	// Validate configuration
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	// A policy path in a config file is relative to that file
+	if config.Policy.Path != "" && !filepath.IsAbs(config.Policy.Path) {
+		config.Policy.Path = filepath.Join(filepath.Dir(configPath), config.Policy.Path)
+	}
+
 	return config, nil
 }
 
@@ -350,7 +1141,7 @@ func (c *Config) Validate() error {
 	}
 
 	// Validate output format
-	validFormats := []string{"console", "json", "html"}
+	validFormats := []string{"console", "json", "jsonl", "csv", "quickfix", "sarif", "sonar", "html"}
 	formatValid := false
 	for _, format := range validFormats {
 		if c.Output.Format == format {
@@ -362,22 +1153,184 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid output format: %s (valid: %v)", c.Output.Format, validFormats)
 	}
 
+	// Validate grading scale
+	for i, band := range c.Analysis.GradingScale {
+		if band.Grade == "" {
+			return fmt.Errorf("grading_scale entries need a grade")
+		}
+		if i > 0 && band.MinScore >= c.Analysis.GradingScale[i-1].MinScore {
+			return fmt.Errorf("grading_scale must be in descending order of min_score")
+		}
+	}
+
+	// Validate report ordering and effort filter
+	if c.Output.SortBy != "" && c.Output.SortBy != "severity" && c.Output.SortBy != "impact" {
+		return fmt.Errorf("invalid sort_by: %s (valid: severity, impact)", c.Output.SortBy)
+	}
+	if c.Output.GroupBy != "" && c.Output.GroupBy != "owner" {
+		return fmt.Errorf("invalid group_by: %s (valid: owner)", c.Output.GroupBy)
+	}
+	if c.Output.NewSince != "" && c.Output.Baseline != "" {
+		return fmt.Errorf("new_since and baseline can't be used together")
+	}
+	if ttl := c.Output.BaselineTTL; ttl != "" {
+		if maxAge, err := parseDays(ttl); err != nil || maxAge <= 0 {
+			return fmt.Errorf("output.baseline_ttl must be a positive duration such as 90d or 720h: %s", ttl)
+		}
+	}
+	switch c.Output.MaxFixEffort {
+	case "", "trivial", "small", "large":
+	default:
+		return fmt.Errorf("invalid max_fix_effort: %s (valid: trivial, small, large)", c.Output.MaxFixEffort)
+	}
+
+	// Validate path mode
+	if c.Output.PathMode != "" && c.Output.PathMode != "relative" && c.Output.PathMode != "absolute" {
+		return fmt.Errorf("invalid path_mode: %s (valid: relative, absolute)", c.Output.PathMode)
+	}
+
+	// Validate issue limits
+	if c.Output.MaxIssues < 0 || c.Output.MaxIssuesPerRule < 0 {
+		return fmt.Errorf("max_issues and max_issues_per_rule must not be negative")
+	}
+	if c.Output.StopAtMaxIssues && c.Output.MaxIssues == 0 {
+		return fmt.Errorf("stop_at_max_issues requires max_issues")
+	}
+
+	// Validate CI profile
+	if err := c.validateCI(); err != nil {
+		return err
+	}
+
+	if err := c.validateUpload(); err != nil {
+		return err
+	}
+	if err := c.validateNotifications(); err != nil {
+		return err
+	}
+
+	// Validate test profile
+	if c.Tests.DowngradeSeverity < 0 {
+		return fmt.Errorf("tests.downgrade_severity must not be negative")
+	}
+
+	if c.Analysis.RareCodeDowngrade < 0 {
+		return fmt.Errorf("rare_code_downgrade must not be negative")
+	}
+	for category, minimum := range c.Analysis.MinCategoryScores {
+		if !slices.Contains(c.Analysis.EnabledCategories, category) {
+			return fmt.Errorf("min_category_scores: %s is not an enabled category (enabled: %s)", category, strings.Join(c.Analysis.EnabledCategories, ", "))
+		}
+		if minimum < 0 || minimum > 100 {
+			return fmt.Errorf("min_category_scores: %s minimum must be between 0 and 100", category)
+		}
+	}
+	for _, pattern := range slices.Concat(c.Files.Include, c.Files.Exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid files pattern %q: %w", pattern, err)
+		}
+	}
+	if c.Analysis.TimeBudget != "" {
+		if budget, err := time.ParseDuration(c.Analysis.TimeBudget); err != nil || budget <= 0 {
+			return fmt.Errorf("invalid time_budget: %s (a positive duration such as 30s)", c.Analysis.TimeBudget)
+		}
+	}
+	if rb := c.Analysis.RuleBudget; rb.PerFile != "" || len(rb.Rules) > 0 {
+		if rb.Action != "warn" && rb.Action != "disable" {
+			return fmt.Errorf("invalid rule_budget.action: %s (valid: warn, disable)", rb.Action)
+		}
+		if budget, err := time.ParseDuration(rb.PerFile); rb.PerFile != "" && (err != nil || budget <= 0) {
+			return fmt.Errorf("invalid rule_budget.per_file: %s (a positive duration such as 2s)", rb.PerFile)
+		}
+		for rule, perFile := range rb.Rules {
+			if group, _ := c.ruleSwitches(rule); group == nil {
+				return fmt.Errorf("rule_budget.rules: unknown rule %s", rule)
+			}
+			if budget, err := time.ParseDuration(perFile); err != nil || budget <= 0 {
+				return fmt.Errorf("invalid rule_budget.rules entry %s: %s (a positive duration such as 2s)", rule, perFile)
+			}
+		}
+	}
+	if re := c.Analysis.RepeatEscalation; re.Enabled {
+		if re.Scope != "function" && re.Scope != "file" {
+			return fmt.Errorf("invalid repeat_escalation.scope: %s (valid: function, file)", re.Scope)
+		}
+		if re.Threshold < 1 || re.Levels < 1 {
+			return fmt.Errorf("repeat_escalation threshold and levels must be at least 1")
+		}
+	}
+	for issueType, severity := range c.Analysis.SeverityFloors {
+		if severityRank(severity) < 0 {
+			return fmt.Errorf("invalid severity_floors entry %s: %s (valid: LOW, MEDIUM, HIGH, CRITICAL)", issueType, severity)
+		}
+	}
+
 	// Validate worker count
 	if c.Analysis.MaxWorkers < 1 {
 		return fmt.Errorf("max_workers must be at least 1")
 	}
 
+	// Validate confidence threshold
+	if c.Analysis.MinConfidence < 0 || c.Analysis.MinConfidence > 1 {
+		return fmt.Errorf("min_confidence must be between 0 and 1")
+	}
+
 	// Validate complexity thresholds
 	cc := c.Rules.Complexity.CyclomaticComplexity
 	if cc.Enabled && (cc.MediumThreshold >= cc.HighThreshold || cc.HighThreshold >= cc.CriticalThreshold) {
 		return fmt.Errorf("cyclomatic complexity thresholds must be in ascending order")
 	}
+	tc := c.Rules.Complexity.TypeComplexity
+	if tc.Enabled && (tc.MediumThreshold >= tc.HighThreshold || tc.HighThreshold >= tc.CriticalThreshold) {
+		return fmt.Errorf("type complexity thresholds must be in ascending order")
+	}
+	if ic := c.Rules.Complexity.IfChain; ic.Enabled && ic.MinBranches < 2 {
+		return fmt.Errorf("if_chain.min_branches must be at least 2")
+	}
 
 	// Validate function length thresholds
 	fl := c.Rules.Complexity.FunctionLength
 	if fl.Enabled && (fl.MediumThreshold >= fl.HighThreshold || fl.HighThreshold >= fl.CriticalThreshold) {
 		return fmt.Errorf("function length thresholds must be in ascending order")
 	}
+	switch fl.Measure {
+	case "", LengthMeasureLines, LengthMeasureSourceLines, LengthMeasureStatements:
+	default:
+		return fmt.Errorf("invalid function_length.measure: %s (valid: lines, source_lines, statements)", fl.Measure)
+	}
+
+	// Validate expensive calls
+	for _, call := range c.Analysis.ExpensiveCalls {
+		if !strings.Contains(call, ".") {
+			return fmt.Errorf("invalid expensive_calls entry: %s (want pkg/path.Func or pkg/path.Type.Method)", call)
+		}
+	}
+	for _, alt := range c.Rules.Memory.AllocFreeAPI.Alternatives {
+		if !strings.Contains(alt.Call, ".") || alt.Replacement == "" {
+			return fmt.Errorf("invalid alloc_free_api alternative %q (want a pkg/path.Func or pkg/path.Type.Method call and a replacement)", alt.Call)
+		}
+	}
+	if wp := c.Rules.Performance.WorkerPool; wp.Enabled && wp.Limit < 1 {
+		return fmt.Errorf("worker_pool.limit must be at least 1")
+	}
+	if cc := c.Rules.Performance.ConversionCache; cc.Enabled && (cc.MaxTableSize < 2 || cc.MinEnumCases < 2) {
+		return fmt.Errorf("conversion_cache.max_table_size and min_enum_cases must be at least 2")
+	}
+
+	if pc := c.Rules.Quality.PackageCoupling; pc.Enabled && (pc.HubFanIn < 1 || pc.HubFanOut < 1) {
+		return fmt.Errorf("package_coupling.hub_fan_in and hub_fan_out must be at least 1")
+	}
+
+	for i, rule := range c.Rules.Quality.DependencyRules.Rules {
+		if rule.From == "" || len(rule.Allow)+len(rule.Deny) == 0 {
+			return fmt.Errorf("dependency_rules.rules[%d] needs a from pattern and allow or deny patterns", i)
+		}
+	}
+
+	// Validate assumed Go version
+	if v := c.Rules.Quality.StdlibLoops.DefaultGoVersion; v != "" && !version.IsValid("go"+v) {
+		return fmt.Errorf("invalid stdlib_loops.default_go_version: %s (e.g. 1.21)", v)
+	}
 
 	return nil
 }
@@ -411,25 +1364,74 @@ func GenerateConfig(configPath string) error {
 
 // IsRuleEnabled checks if a specific rule is enabled
 func (c *Config) IsRuleEnabled(ruleType string) bool {
+	group, rule := c.ruleSwitches(ruleType)
+	return group != nil && *group && *rule
+}
+
+// ruleSwitches returns the enabled flags of a rule's group and of the rule
+// itself, or nils for an unknown rule
+func (c *Config) ruleSwitches(ruleType string) (group, rule *bool) {
 	switch ruleType {
 	case "cyclomatic_complexity":
-		return c.Rules.Complexity.Enabled && c.Rules.Complexity.CyclomaticComplexity.Enabled
+		return &c.Rules.Complexity.Enabled, &c.Rules.Complexity.CyclomaticComplexity.Enabled
 	case "function_length":
-		return c.Rules.Complexity.Enabled && c.Rules.Complexity.FunctionLength.Enabled
+		return &c.Rules.Complexity.Enabled, &c.Rules.Complexity.FunctionLength.Enabled
+	case "if_chain":
+		return &c.Rules.Complexity.Enabled, &c.Rules.Complexity.IfChain.Enabled
 	case "nested_loops":
-		return c.Rules.Performance.Enabled && c.Rules.Performance.NestedLoops.Enabled
+		return &c.Rules.Performance.Enabled, &c.Rules.Performance.NestedLoops.Enabled
 	case "string_concat":
-		return c.Rules.Performance.Enabled && c.Rules.Performance.StringConcat.Enabled
+		return &c.Rules.Performance.Enabled, &c.Rules.Performance.StringConcat.Enabled
 	case "data_structure":
-		return c.Rules.Performance.Enabled && c.Rules.Performance.DataStructure.Enabled
+		return &c.Rules.Performance.Enabled, &c.Rules.Performance.DataStructure.Enabled
+	case "split_in_loop":
+		return &c.Rules.Performance.Enabled, &c.Rules.Performance.SplitInLoop.Enabled
+	case "log_in_loop":
+		return &c.Rules.Performance.Enabled, &c.Rules.Performance.LogInLoop.Enabled
+	case "builder_usage":
+		return &c.Rules.Performance.Enabled, &c.Rules.Performance.BuilderUsage.Enabled
+	case "handler_alloc":
+		return &c.Rules.Performance.Enabled, &c.Rules.Performance.HandlerAlloc.Enabled
+	case "errorf_in_loop":
+		return &c.Rules.Performance.Enabled, &c.Rules.Performance.ErrorfInLoop.Enabled
+	case "inefficient_sort":
+		return &c.Rules.Performance.Enabled, &c.Rules.Performance.InefficientSort.Enabled
+	case "sprintf_key":
+		return &c.Rules.Performance.Enabled, &c.Rules.Performance.SprintfKey.Enabled
+	case "worker_pool":
+		return &c.Rules.Performance.Enabled, &c.Rules.Performance.WorkerPool.Enabled
+	case "conversion_cache":
+		return &c.Rules.Performance.Enabled, &c.Rules.Performance.ConversionCache.Enabled
 	case "import_cycles":
-		return c.Rules.Quality.Enabled && c.Rules.Quality.ImportCycles.Enabled
+		return &c.Rules.Quality.Enabled, &c.Rules.Quality.ImportCycles.Enabled
+	case "map_mutation":
+		return &c.Rules.Quality.Enabled, &c.Rules.Quality.MapMutation.Enabled
+	case "stdlib_loops":
+		return &c.Rules.Quality.Enabled, &c.Rules.Quality.StdlibLoops.Enabled
+	case "package_coupling":
+		return &c.Rules.Quality.Enabled, &c.Rules.Quality.PackageCoupling.Enabled
+	case "dependency_rules":
+		return &c.Rules.Quality.Enabled, &c.Rules.Quality.DependencyRules.Enabled
 	case "memory_allocation":
-		return c.Rules.Memory.Enabled && c.Rules.Memory.Allocation.Enabled
+		return &c.Rules.Memory.Enabled, &c.Rules.Memory.Allocation.Enabled
 	case "slice_growth":
-		return c.Rules.Memory.Enabled && c.Rules.Memory.SliceGrowth.Enabled
+		return &c.Rules.Memory.Enabled, &c.Rules.Memory.SliceGrowth.Enabled
+	case "append_usage":
+		return &c.Rules.Memory.Enabled, &c.Rules.Memory.AppendUsage.Enabled
+	case "value_receiver":
+		return &c.Rules.Memory.Enabled, &c.Rules.Memory.ValueReceiver.Enabled
+	case "slice_retention":
+		return &c.Rules.Memory.Enabled, &c.Rules.Memory.SliceRetention.Enabled
+	case "read_all":
+		return &c.Rules.Memory.Enabled, &c.Rules.Memory.ReadAll.Enabled
+	case "alloc_free_api":
+		return &c.Rules.Memory.Enabled, &c.Rules.Memory.AllocFreeAPI.Enabled
+	case "busy_wait":
+		return &c.Rules.Concurrency.Enabled, &c.Rules.Concurrency.BusyWait.Enabled
+	case "concurrent_map_write":
+		return &c.Rules.Concurrency.Enabled, &c.Rules.Concurrency.ConcurrentMapWrite.Enabled
 	default:
-		return false
+		return nil, nil
 	}
 }
 
@@ -454,6 +1456,15 @@ func (c *Config) GetThreshold(ruleType, severity string) int {
 		case "critical":
 			return c.Rules.Complexity.FunctionLength.CriticalThreshold
 		}
+	case "type_complexity":
+		switch severity {
+		case "medium":
+			return c.Rules.Complexity.TypeComplexity.MediumThreshold
+		case "high":
+			return c.Rules.Complexity.TypeComplexity.HighThreshold
+		case "critical":
+			return c.Rules.Complexity.TypeComplexity.CriticalThreshold
+		}
 	}
 	return 0
 }
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RuleDeprecation records a rule id that was renamed, split, or retired.
+// Config files (and policies) naming it keep working, with a migration
+// warning, for at least one release after Since; then the entry is removed
+// and the old id becomes unknown.
+type RuleDeprecation struct {
+	Rule       string   `json:"rule"`                  // Old id
+	ReplacedBy []string `json:"replaced_by,omitempty"` // Ids that took over its findings; empty if the rule was dropped
+	Since      string   `json:"since"`                 // Release that deprecated it, e.g. "v1.6.0"
+}
+
+// DeprecatedRules lists the retired rule ids still accepted, e.g.
+//
+//	{Rule: "data_structure", ReplacedBy: []string{"map_lookup", "set_membership"}, Since: "v1.6.0"}
+var DeprecatedRules []RuleDeprecation
+
+// LookupDeprecatedRule returns the deprecation of a rule id, if it is retired
+func LookupDeprecatedRule(rule string) (RuleDeprecation, bool) {
+	i := slices.IndexFunc(DeprecatedRules, func(d RuleDeprecation) bool { return d.Rule == rule })
+	if i < 0 {
+		return RuleDeprecation{}, false
+	}
+	return DeprecatedRules[i], true
+}
+
+// Warning describes the deprecation for a reference to the old id at where,
+// e.g. "tests.disabled_rules"
+func (d RuleDeprecation) Warning(where string) string {
+	switch len(d.ReplacedBy) {
+	case 0:
+		return fmt.Sprintf("%s: rule %s was retired in %s and is ignored; remove it", where, d.Rule, d.Since)
+	case 1:
+		return fmt.Sprintf("%s: rule %s was renamed to %s in %s; use the new id", where, d.Rule, d.ReplacedBy[0], d.Since)
+	default:
+		return fmt.Sprintf("%s: rule %s was split into %s in %s; use the new ids", where, d.Rule, strings.Join(d.ReplacedBy, ", "), d.Since)
+	}
+}
+
+// migrateRules maps deprecated ids in a list of rules to their replacements,
+// returning the new list and a warning for each deprecated id
+func migrateRules(where string, rules []string) ([]string, []string) {
+	var migrated, warnings []string
+	for _, rule := range rules {
+		deprecation, ok := LookupDeprecatedRule(rule)
+		if !ok {
+			migrated = append(migrated, rule)
+			continue
+		}
+		warnings = append(warnings, deprecation.Warning(where))
+		for _, replacement := range deprecation.ReplacedBy {
+			if !slices.Contains(rules, replacement) && !slices.Contains(migrated, replacement) {
+				migrated = append(migrated, replacement)
+			}
+		}
+	}
+	return migrated, warnings
+}
+
+// migrateSeverities maps the deprecated ids keying a severity map to their
+// replacements, without overriding entries the map has for the new ids
+func migrateSeverities(where string, severities map[string]string) []string {
+	var warnings []string
+	for rule, severity := range severities {
+		deprecation, ok := LookupDeprecatedRule(rule)
+		if !ok {
+			continue
+		}
+		warnings = append(warnings, deprecation.Warning(where))
+		delete(severities, rule)
+		for _, replacement := range deprecation.ReplacedBy {
+			if _, set := severities[replacement]; !set {
+				severities[replacement] = severity
+			}
+		}
+	}
+	slices.Sort(warnings)
+	return warnings
+}
+
+// seedDeprecatedRules gives deprecated sections their defaults before a config
+// file is parsed into c, so a section that sets only some fields keeps the
+// defaults of the others when migrateDeprecatedRules moves it
+func (c *Config) seedDeprecatedRules() {
+	busyWait := c.Rules.Concurrency.BusyWait
+	c.Rules.Performance.BusyWait = &busyWait
+}
+
+// migrateDeprecatedRules rewrites references to deprecated rule ids to their
+// replacements, and deprecated settings to the ones that replaced them, and
+// records a migration warning for each in RuleWarnings
+func (c *Config) migrateDeprecatedRules() {
+	var warnings []string
+	c.Tests.DisabledRules, warnings = migrateRules("tests.disabled_rules", c.Tests.DisabledRules)
+	c.RuleWarnings = append(c.RuleWarnings, warnings...)
+	c.RuleWarnings = append(c.RuleWarnings, migrateSeverities("analysis.severity_floors", c.Analysis.SeverityFloors)...)
+
+	if ignore := c.Rules.Quality.ImportCycles.IgnoreVendor; ignore != nil {
+		c.Files.IgnoreVendor = *ignore
+		c.Rules.Quality.ImportCycles.IgnoreVendor = nil
+		c.RuleWarnings = append(c.RuleWarnings, "rules.quality.import_cycles.ignore_vendor moved to files.ignore_vendor, which the file collector and watcher share; set it there")
+	}
+	if busyWait := c.Rules.Performance.BusyWait; busyWait != nil {
+		if *busyWait != DefaultConfig().Rules.Concurrency.BusyWait {
+			c.Rules.Concurrency.BusyWait = *busyWait
+			c.RuleWarnings = append(c.RuleWarnings, "rules.performance.busy_wait moved to rules.concurrency.busy_wait; set it there")
+		}
+		c.Rules.Performance.BusyWait = nil
+	}
+	if concurrent := c.Rules.Quality.MapMutation.DetectConcurrentWrites; concurrent != nil {
+		c.Rules.Concurrency.ConcurrentMapWrite.Enabled = *concurrent
+		c.Rules.Quality.MapMutation.DetectConcurrentWrites = nil
+		c.RuleWarnings = append(c.RuleWarnings, "rules.quality.map_mutation.detect_concurrent_writes moved to rules.concurrency.concurrent_map_write.enabled, a rule of its own; set it there")
+	}
+	if summary := c.CI.SummaryLine; summary != nil {
+		c.Output.SummaryLine = *summary
+		c.CI.SummaryLine = nil
+		c.RuleWarnings = append(c.RuleWarnings, "ci.summary_line moved to output.summary_line, as the summary line is printed outside CI too; set it there")
+	}
+}
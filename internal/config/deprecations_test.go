@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func loadTestConfig(t *testing.T, yaml string) *Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".gophercheck.yml")
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	return cfg
+}
+
+func TestDeprecatedConcurrencySettingsMove(t *testing.T) {
+	cfg := loadTestConfig(t, `
+rules:
+  performance:
+    busy_wait:
+      detect_spin_loops: false
+  quality:
+    map_mutation:
+      detect_concurrent_writes: false
+`)
+
+	busyWait := cfg.Rules.Concurrency.BusyWait
+	if busyWait.DetectSpinLoops {
+		t.Error("rules.concurrency.busy_wait.detect_spin_loops = true, want the false set under rules.performance")
+	}
+	if !busyWait.Enabled || !busyWait.DetectSingleCase || !busyWait.DetectBusySelect {
+		t.Errorf("rules.concurrency.busy_wait = %+v, want the fields left unset to keep their defaults", busyWait)
+	}
+	if cfg.Rules.Concurrency.ConcurrentMapWrite.Enabled {
+		t.Error("rules.concurrency.concurrent_map_write.enabled = true, want the false set by detect_concurrent_writes")
+	}
+	if cfg.IsRuleEnabled("concurrent_map_write") {
+		t.Error("concurrent_map_write is enabled, want it off")
+	}
+	if cfg.Rules.Performance.BusyWait != nil || cfg.Rules.Quality.MapMutation.DetectConcurrentWrites != nil {
+		t.Error("deprecated settings were left set after migration")
+	}
+	if len(cfg.RuleWarnings) != 2 {
+		t.Errorf("RuleWarnings = %q, want one warning per moved setting", cfg.RuleWarnings)
+	}
+}
+
+func TestConcurrencySettingsWithoutDeprecations(t *testing.T) {
+	cfg := loadTestConfig(t, `
+rules:
+  concurrency:
+    busy_wait:
+      enabled: false
+`)
+
+	if cfg.IsRuleEnabled("busy_wait") {
+		t.Error("busy_wait is enabled, want it off")
+	}
+	if !cfg.IsRuleEnabled("concurrent_map_write") {
+		t.Error("concurrent_map_write is disabled, want its default")
+	}
+	if len(cfg.RuleWarnings) != 0 {
+		t.Errorf("RuleWarnings = %q, want none", cfg.RuleWarnings)
+	}
+}
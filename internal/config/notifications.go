@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NotificationsConfig posts a summary of each run to chat webhooks, for
+// nightly scheduled runs and watch mode
+type NotificationsConfig struct {
+	// Send notifications (or pass --notify for a single run)
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// Environment variables holding the Slack and Teams incoming webhook URLs,
+	// which are secrets; a webhook whose variable is unset is skipped
+	SlackWebhookEnv string `yaml:"slack_webhook_env" json:"slack_webhook_env"`
+	TeamsWebhookEnv string `yaml:"teams_webhook_env" json:"teams_webhook_env"`
+
+	// Where the score and critical issues of the last notified run are kept,
+	// for the score delta and to tell which critical issues are new
+	StateFile string `yaml:"state_file" json:"state_file"`
+
+	// Link to each listed issue, with {file}, {line}, and {commit} replaced,
+	// e.g. https://github.com/acme/app/blob/{commit}/{file}#L{line} ("" for no links)
+	LinkTemplate string `yaml:"link_template,omitempty" json:"link_template,omitempty"`
+
+	// Most new critical issues to list; the rest are counted
+	MaxIssues int `yaml:"max_issues" json:"max_issues"`
+
+	// Timeout of each webhook request, in seconds
+	TimeoutSeconds int `yaml:"timeout_seconds" json:"timeout_seconds"`
+
+	// Send at most one notification per this many minutes; runs in between
+	// are held back, and the next notification covers them (0 for no limit)
+	ThrottleMinutes int `yaml:"throttle_minutes" json:"throttle_minutes"`
+
+	// Only notify when there are new issues at least this severe since the
+	// last notified run, which are then listed instead of the critical ones,
+	// e.g. HIGH ("" to notify for every run)
+	MinSeverity string `yaml:"min_severity,omitempty" json:"min_severity,omitempty"`
+
+	// Hold notifications back between these local times, e.g. "22:00-07:00"
+	QuietHours string `yaml:"quiet_hours,omitempty" json:"quiet_hours,omitempty"`
+}
+
+// DefaultNotificationsConfig has notifications off, reading the webhooks
+// from GOPHERCHECK_SLACK_WEBHOOK and GOPHERCHECK_TEAMS_WEBHOOK once enabled
+func DefaultNotificationsConfig() NotificationsConfig {
+	return NotificationsConfig{
+		SlackWebhookEnv: "GOPHERCHECK_SLACK_WEBHOOK",
+		TeamsWebhookEnv: "GOPHERCHECK_TEAMS_WEBHOOK",
+		StateFile:       ".gophercheck/notify-state.json",
+		MaxIssues:       10,
+		TimeoutSeconds:  30,
+		ThrottleMinutes: 15,
+	}
+}
+
+// Quiet reports whether t falls within quiet_hours
+func (n NotificationsConfig) Quiet(t time.Time) bool {
+	start, end, err := parseQuietHours(n.QuietHours)
+	if err != nil || start == end {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end // Across midnight
+}
+
+// parseQuietHours returns the minutes of the day a "15:04-15:04" range
+// starts and ends at; an empty range is never quiet
+func parseQuietHours(hours string) (int, int, error) {
+	if hours == "" {
+		return 0, 0, nil
+	}
+	from, to, found := strings.Cut(hours, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("notifications.quiet_hours must look like 22:00-07:00: %s", hours)
+	}
+	var minutes [2]int
+	for i, clock := range []string{from, to} {
+		t, err := time.Parse("15:04", strings.TrimSpace(clock))
+		if err != nil {
+			return 0, 0, fmt.Errorf("notifications.quiet_hours must look like 22:00-07:00: %s", hours)
+		}
+		minutes[i] = t.Hour()*60 + t.Minute()
+	}
+	return minutes[0], minutes[1], nil
+}
+
+func (c *Config) validateNotifications() error {
+	notifications := c.Notifications
+	if notifications.MaxIssues < 0 || notifications.TimeoutSeconds < 1 {
+		return fmt.Errorf("notifications.max_issues must not be negative and notifications.timeout_seconds must be at least 1")
+	}
+	if notifications.Enabled && notifications.StateFile == "" {
+		return fmt.Errorf("notifications.state_file is required when notifications are enabled")
+	}
+	if template := notifications.LinkTemplate; template != "" && !strings.Contains(template, "{file}") {
+		return fmt.Errorf("notifications.link_template must contain {file}: %s", template)
+	}
+	if notifications.ThrottleMinutes < 0 {
+		return fmt.Errorf("notifications.throttle_minutes must not be negative")
+	}
+	if severity := notifications.MinSeverity; severity != "" && severityRank(severity) < 0 {
+		return fmt.Errorf("notifications.min_severity must be LOW, MEDIUM, HIGH, or CRITICAL: %s", severity)
+	}
+	if _, _, err := parseQuietHours(notifications.QuietHours); err != nil {
+		return err
+	}
+	return nil
+}
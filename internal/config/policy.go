@@ -0,0 +1,229 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PolicyConfig points at an organization policy bundle
+type PolicyConfig struct {
+	// Policy file, relative to the config file
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+
+	// Expected SHA-256 of the policy file; the policy is rejected if it was changed
+	SHA256 string `yaml:"sha256,omitempty" json:"sha256,omitempty"`
+}
+
+// Policy is an organization policy bundle: rules, severities, and fail
+// thresholds that platform teams pin for every repository. Local configs may
+// tighten these settings and add their own, but not weaken them.
+type Policy struct {
+	Name string `yaml:"name"`
+
+	// Rules that stay enabled whatever the local config says
+	RequiredRules []string `yaml:"required_rules"`
+
+	// Lowest severity to report issues of each type at
+	SeverityFloors map[string]string `yaml:"severity_floors"`
+
+	// Highest thresholds local configs may use
+	MaxThresholds PolicyThresholds `yaml:"max_thresholds"`
+
+	// Lowest score_thresholds.fair (the score below which runs fail) local configs may use
+	MinFailScore int `yaml:"min_fail_score"`
+
+	// Highest min_confidence local configs may use, so findings can't be filtered away (0 = no limit)
+	MaxMinConfidence float64 `yaml:"max_min_confidence"`
+
+	warnings []string // Deprecated rule ids the policy names
+}
+
+// PolicyThresholds caps the severity thresholds of the threshold rules
+type PolicyThresholds struct {
+	CyclomaticComplexity ThresholdCeiling `yaml:"cyclomatic_complexity"`
+	FunctionLength       ThresholdCeiling `yaml:"function_length"`
+}
+
+// ThresholdCeiling is the highest medium, high, and critical threshold
+// allowed (0 = no limit)
+type ThresholdCeiling struct {
+	Medium   int `yaml:"medium"`
+	High     int `yaml:"high"`
+	Critical int `yaml:"critical"`
+}
+
+// LoadPolicy reads a policy bundle, checking it against checksum unless that
+// is empty
+func LoadPolicy(path, checksum string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy %s: %w", path, err)
+	}
+	if checksum != "" {
+		sum := sha256.Sum256(data)
+		if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, checksum) {
+			return nil, fmt.Errorf("policy %s doesn't match its pinned sha256 (got %s)", path, actual)
+		}
+	}
+
+	var policy Policy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", path, err)
+	}
+	var warnings []string
+	policy.RequiredRules, warnings = migrateRules(path+": required_rules", policy.RequiredRules)
+	policy.warnings = append(warnings, migrateSeverities(path+": severity_floors", policy.SeverityFloors)...)
+	if err := policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+	return &policy, nil
+}
+
+func (p *Policy) validate() error {
+	var known Config
+	for _, rule := range p.RequiredRules {
+		if group, _ := known.ruleSwitches(rule); group == nil {
+			return fmt.Errorf("unknown required rule: %s", rule)
+		}
+	}
+	for issueType, severity := range p.SeverityFloors {
+		if severityRank(severity) < 0 {
+			return fmt.Errorf("invalid severity_floors entry %s: %s (valid: LOW, MEDIUM, HIGH, CRITICAL)", issueType, severity)
+		}
+	}
+	if p.MaxMinConfidence < 0 || p.MaxMinConfidence > 1 {
+		return fmt.Errorf("max_min_confidence must be between 0 and 1")
+	}
+	return nil
+}
+
+// ApplyPolicy loads the configured policy and enforces it, recording every
+// local setting it overrode in PolicyRejections. Without a policy it does
+// nothing.
+func (c *Config) ApplyPolicy() error {
+	if c.Policy.Path == "" {
+		return nil
+	}
+	policy, err := LoadPolicy(c.Policy.Path, c.Policy.SHA256)
+	if err != nil {
+		return err
+	}
+	c.PolicyRejections = policy.Enforce(c)
+	c.RuleWarnings = append(c.RuleWarnings, policy.warnings...)
+	return nil
+}
+
+// Enforce overrides the settings of c that are weaker than the policy and
+// returns a note for each one
+func (p *Policy) Enforce(c *Config) []string {
+	name := p.Name
+	if name == "" {
+		name = "policy"
+	}
+	var rejected []string
+	reject := func(format string, args ...any) {
+		rejected = append(rejected, fmt.Sprintf(format, args...)+" (enforced by "+name+")")
+	}
+
+	for _, rule := range p.RequiredRules {
+		if c.IsRuleEnabled(rule) {
+			continue
+		}
+		c.enableRule(rule)
+		reject("rule %s is required and can't be disabled", rule)
+	}
+
+	for issueType, floor := range p.SeverityFloors {
+		local, ok := c.Analysis.SeverityFloors[issueType]
+		if ok && severityRank(local) >= severityRank(floor) {
+			continue
+		}
+		if ok {
+			reject("severity_floors.%s %s is below the policy floor %s", issueType, local, floor)
+		}
+		if c.Analysis.SeverityFloors == nil {
+			c.Analysis.SeverityFloors = make(map[string]string)
+		}
+		c.Analysis.SeverityFloors[issueType] = floor
+	}
+
+	complexity := &c.Rules.Complexity
+	capThresholds("cyclomatic_complexity", p.MaxThresholds.CyclomaticComplexity, reject,
+		&complexity.CyclomaticComplexity.MediumThreshold, &complexity.CyclomaticComplexity.HighThreshold, &complexity.CyclomaticComplexity.CriticalThreshold)
+	capThresholds("function_length", p.MaxThresholds.FunctionLength, reject,
+		&complexity.FunctionLength.MediumThreshold, &complexity.FunctionLength.HighThreshold, &complexity.FunctionLength.CriticalThreshold)
+
+	if thresholds := &c.Analysis.ScoreThresholds; thresholds.Fair < p.MinFailScore {
+		reject("score_thresholds.fair %d is below the minimum of %d", thresholds.Fair, p.MinFailScore)
+		thresholds.Fair = p.MinFailScore
+		thresholds.Good = max(thresholds.Good, thresholds.Fair)
+		thresholds.Excellent = max(thresholds.Excellent, thresholds.Good)
+	}
+	if p.MaxMinConfidence > 0 && c.Analysis.MinConfidence > p.MaxMinConfidence {
+		reject("min_confidence %.2f is above the maximum of %.2f", c.Analysis.MinConfidence, p.MaxMinConfidence)
+		c.Analysis.MinConfidence = p.MaxMinConfidence
+	}
+
+	slices.Sort(rejected)
+	return rejected
+}
+
+// enableRule turns a rule on. If its whole group was off, the group is turned
+// on with only this rule enabled, so no other rule comes back with it.
+func (c *Config) enableRule(ruleType string) {
+	group, rule := c.ruleSwitches(ruleType)
+	if !*group {
+		groups := reflect.ValueOf(&c.Rules).Elem()
+		for i := 0; i < groups.NumField(); i++ {
+			rules := groups.Field(i)
+			if rules.FieldByName("Enabled").Addr().Interface() != group {
+				continue
+			}
+			for j := 0; j < rules.NumField(); j++ {
+				if rules.Field(j).Kind() != reflect.Struct {
+					continue
+				}
+				if enabled := rules.Field(j).FieldByName("Enabled"); enabled.Kind() == reflect.Bool {
+					enabled.SetBool(false)
+				}
+			}
+		}
+		*group = true
+	}
+	*rule = true
+}
+
+// capThresholds lowers the medium, high, and critical thresholds of a rule to
+// the ceiling
+func capThresholds(rule string, ceiling ThresholdCeiling, reject func(string, ...any), medium, high, critical *int) {
+	for _, threshold := range []struct {
+		name    string
+		value   *int
+		ceiling int
+	}{
+		{"medium", medium, ceiling.Medium},
+		{"high", high, ceiling.High},
+		{"critical", critical, ceiling.Critical},
+	} {
+		if threshold.ceiling > 0 && *threshold.value > threshold.ceiling {
+			reject("%s %s_threshold %d is above the maximum of %d", rule, threshold.name, *threshold.value, threshold.ceiling)
+			*threshold.value = threshold.ceiling
+		}
+	}
+}
+
+// severityRank orders severity names from LOW (0) to CRITICAL (3), or -1 for
+// an unknown name
+func severityRank(severity string) int {
+	return slices.Index([]string{"LOW", "MEDIUM", "HIGH", "CRITICAL"}, strings.ToUpper(severity))
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPolicyRaisesLocalSeverityFloors(t *testing.T) {
+	policy := &Policy{
+		Name:           "platform-go",
+		SeverityFloors: map[string]string{"string_concatenation": "HIGH", "nested_loops": "MEDIUM", "slice_growth": "MEDIUM"},
+	}
+	cfg := DefaultConfig()
+	cfg.Analysis.SeverityFloors = map[string]string{"string_concatenation": "LOW", "nested_loops": "CRITICAL"}
+
+	rejected := policy.Enforce(cfg)
+
+	want := map[string]string{"string_concatenation": "HIGH", "nested_loops": "CRITICAL", "slice_growth": "MEDIUM"}
+	for issueType, severity := range want {
+		if got := cfg.Analysis.SeverityFloors[issueType]; got != severity {
+			t.Errorf("severity floor of %s = %s, want %s", issueType, got, severity)
+		}
+	}
+	if len(rejected) != 1 || !strings.Contains(rejected[0], "severity_floors.string_concatenation LOW is below the policy floor HIGH") {
+		t.Errorf("rejections = %q, want one for the string_concatenation floor only", rejected)
+	}
+}
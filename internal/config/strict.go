@@ -0,0 +1,34 @@
+package config
+
+// ApplyStrictProfile tightens the configuration for release gating: every
+// category and rule group is enabled, thresholds are lowered, lower-confidence
+// findings are kept, and rarely-run code is no longer downgraded
+func (c *Config) ApplyStrictProfile() {
+	c.Analysis.EnabledCategories = []string{"performance", "complexity", "memory", "quality", "concurrency"}
+	c.Analysis.MinConfidence = 0.5
+	c.Analysis.RareCodeDowngrade = 0
+
+	c.Rules.Complexity.Enabled = true
+	c.Rules.Performance.Enabled = true
+	c.Rules.Memory.Enabled = true
+	c.Rules.Quality.Enabled = true
+	c.Rules.Concurrency.Enabled = true
+
+	cyclomatic := &c.Rules.Complexity.CyclomaticComplexity
+	cyclomatic.Enabled = true
+	cyclomatic.MediumThreshold = min(cyclomatic.MediumThreshold, 8)
+	cyclomatic.HighThreshold = min(cyclomatic.HighThreshold, 12)
+	cyclomatic.CriticalThreshold = min(cyclomatic.CriticalThreshold, 20)
+
+	typeComplexity := &c.Rules.Complexity.TypeComplexity
+	typeComplexity.Enabled = true
+	typeComplexity.MediumThreshold = min(typeComplexity.MediumThreshold, 50)
+	typeComplexity.HighThreshold = min(typeComplexity.HighThreshold, 80)
+	typeComplexity.CriticalThreshold = min(typeComplexity.CriticalThreshold, 120)
+
+	length := &c.Rules.Complexity.FunctionLength
+	length.Enabled = true
+	length.MediumThreshold = min(length.MediumThreshold, 40)
+	length.HighThreshold = min(length.HighThreshold, 80)
+	length.CriticalThreshold = min(length.CriticalThreshold, 150)
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+)
+
+// UploadConfig sends each JSON result to a central collection endpoint
+type UploadConfig struct {
+	// HTTPS endpoint the JSON result is POSTed to ("" to disable)
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+
+	// Environment variable holding the auth token (unset sends no auth header)
+	TokenEnv string `yaml:"token_env" json:"token_env"`
+
+	// Header carrying the token; "Authorization" sends it as a Bearer token,
+	// any other header (e.g. X-API-Key) sends it as is
+	AuthHeader string `yaml:"auth_header" json:"auth_header"`
+
+	// Extra headers sent with every upload
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+
+	// Attempts after the first for network errors, 429s, and 5xx responses,
+	// with exponential backoff
+	Retries int `yaml:"retries" json:"retries"`
+
+	// Timeout of each attempt, in seconds
+	TimeoutSeconds int `yaml:"timeout_seconds" json:"timeout_seconds"`
+}
+
+// DefaultUploadConfig has uploads off, ready to be enabled with a URL
+func DefaultUploadConfig() UploadConfig {
+	return UploadConfig{
+		TokenEnv:       "GOPHERCHECK_UPLOAD_TOKEN",
+		AuthHeader:     "Authorization",
+		Retries:        3,
+		TimeoutSeconds: 30,
+	}
+}
+
+func (c *Config) validateUpload() error {
+	upload := c.Output.Upload
+	if upload.Retries < 0 || upload.TimeoutSeconds < 1 {
+		return fmt.Errorf("upload.retries must not be negative and upload.timeout_seconds must be at least 1")
+	}
+	if upload.URL == "" {
+		return nil
+	}
+	endpoint, err := url.Parse(upload.URL)
+	if err != nil || endpoint.Host == "" {
+		return fmt.Errorf("invalid upload.url: %s", upload.URL)
+	}
+	// Tokens only travel over TLS; plain HTTP is allowed to a local collector
+	if endpoint.Scheme != "https" && !(endpoint.Scheme == "http" && isLoopback(endpoint.Hostname())) {
+		return fmt.Errorf("upload.url must use https: %s", upload.URL)
+	}
+	return nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
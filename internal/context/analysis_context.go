@@ -3,6 +3,7 @@ package context
 import (
 	"go/ast"
 	"go/types"
+	"path"
 )
 
 // AnalysisContext provides rich analysis context to detectors
@@ -11,6 +12,57 @@ type AnalysisContext struct {
 	CallGraph   map[string]*CallInfo
 	LoopContext map[ast.Node]*LoopInfo
 	DataSizes   map[string]*DataSizeInfo
+
+	// PackagePaths maps each analyzed file to the import path of its package,
+	// resolved from the enclosing go.mod. Files outside a module are absent.
+	PackagePaths map[string]string
+
+	// ModulePaths maps each analyzed file to the path of its module, from the
+	// go.mod module directive. Files outside a module are absent.
+	ModulePaths map[string]string
+
+	// GoVersions maps each analyzed file to the go directive version of its
+	// module, e.g. "1.21". Files outside a module or without one are absent.
+	GoVersions map[string]string
+
+	// Methods maps each type with methods, by TypeKey, to the declarations
+	// of its methods in all the analyzed files of its package
+	Methods map[string][]*ast.FuncDecl
+
+	// Imports maps each analyzed package, by PackagePath (with "_test"
+	// appended for external test packages), to the imports of all its files.
+	// It is gathered from every file before any detector runs, so the whole
+	// import graph is known even when files are analyzed a package at a time.
+	Imports map[string][]Import
+}
+
+// Import is an import declaration of an analyzed file
+type Import struct {
+	Path string // Imported package, as written
+	File string // Importing file
+	Line int
+
+	// Vendored is set when the import resolves to a copy in a vendor
+	// directory, listed in vendor/modules.txt in module mode
+	Vendored bool
+}
+
+// PackagePath returns the import path of the package of an analyzed file,
+// or its directory for files outside a module
+func (c *AnalysisContext) PackagePath(filename string) string {
+	if importPath, ok := c.PackagePaths[filename]; ok {
+		return importPath
+	}
+	dir := path.Dir(filename)
+	if dir == "." {
+		return "main"
+	}
+	return dir
+}
+
+// TypeKey identifies a type declared in a file of package pkg in dir
+func TypeKey(dir, pkg, typeName string) string {
+	return dir + "\x00" + pkg + "\x00" + typeName
 }
 
 type CallInfo struct {
@@ -26,6 +78,12 @@ type LoopInfo struct {
 	EstimatedMax int
 	IsInnerLoop  bool
 	HasEarlyExit bool
+
+	// IsBenchmarkLoop marks the timing loop (b.N or b.Loop()) of a Benchmark
+	// function in a test file, whose body is the code under measurement
+	// rather than a hot loop. Only set when
+	// tests.test_functions.allow_benchmark_loop_allocs is on.
+	IsBenchmarkLoop bool
 }
 
 type DataSizeInfo struct {
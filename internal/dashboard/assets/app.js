@@ -0,0 +1,220 @@
+// gophercheck dashboard: renders /api/result and /api/history
+
+const severities = ["LOW", "MEDIUM", "HIGH", "CRITICAL"];
+
+const state = { result: null, sortKey: "severity", sortDesc: true, file: "" };
+
+async function getJSON(url) {
+  const response = await fetch(url);
+  if (!response.ok) {
+    throw new Error(await response.text());
+  }
+  return response.json();
+}
+
+function el(tag, props = {}, ...children) {
+  const node = document.createElement(tag);
+  Object.assign(node, props);
+  node.append(...children);
+  return node;
+}
+
+// scoreColor shades from red (0) to green (100)
+function scoreColor(score) {
+  return `hsl(${Math.round(score * 1.2)}, 70%, 80%)`;
+}
+
+function renderSummary() {
+  const r = state.result;
+  const counts = severities
+    .slice()
+    .reverse()
+    .filter((s) => r.issues_by_severity[s])
+    .map((s) => `${r.issues_by_severity[s]} ${s}`)
+    .join(", ");
+  document.getElementById("summary").textContent =
+    `Score ${r.performance_score}/100 (${r.grade}) · ${r.total_issues} issues in ${r.files_analyzed.length} files` +
+    (counts ? ` · ${counts}` : "");
+}
+
+// renderTree lists files under their directories, each colored by the score
+// it would get if analyzed alone
+function renderTree() {
+  const tree = document.getElementById("tree");
+  tree.replaceChildren();
+  let dir = null;
+  for (const f of state.result.file_scores) {
+    const slash = f.file.lastIndexOf("/");
+    const fileDir = slash < 0 ? "." : f.file.slice(0, slash);
+    if (fileDir !== dir) {
+      dir = fileDir;
+      tree.append(el("li", { className: "dir", textContent: dir + "/" }));
+    }
+    const item = el("li", {
+      textContent: `${f.file.slice(slash + 1)} (${f.score})`,
+      title: `${f.file}: score ${f.score}, ${f.issues} issues`,
+      className: f.file === state.file ? "selected" : "",
+    });
+    item.style.background = scoreColor(f.score);
+    item.onclick = () => {
+      state.file = state.file === f.file ? "" : f.file;
+      renderTree();
+      renderIssues();
+    };
+    tree.append(item);
+  }
+}
+
+function compareIssues(a, b) {
+  const key = state.sortKey;
+  let order = key === "severity" ? a.severity - b.severity : String(a[key] || "").localeCompare(String(b[key] || ""));
+  if (order === 0) {
+    order = a.file.localeCompare(b.file) || a.line - b.line;
+  }
+  return state.sortDesc ? -order : order;
+}
+
+function renderIssues() {
+  const text = document.getElementById("filter").value.toLowerCase();
+  const severity = document.getElementById("severity").value;
+  const issues = state.result.issues.filter(
+    (i) =>
+      (!state.file || i.file === state.file) &&
+      (severity === "" || i.severity === Number(severity)) &&
+      (!text || [i.file, i.type, i.function, i.message].join(" ").toLowerCase().includes(text)),
+  );
+  issues.sort(compareIssues);
+
+  const rows = document.getElementById("rows");
+  rows.replaceChildren(
+    ...issues.map((issue) => {
+      const row = el(
+        "tr",
+        {},
+        el("td", { className: `sev-${issue.severity}`, textContent: severities[issue.severity] }),
+        el("td", { textContent: issue.type }),
+        el("td", { textContent: `${issue.file}:${issue.line}` }),
+        el("td", { textContent: issue.function || "" }),
+        el("td", { textContent: issue.message }),
+      );
+      row.onclick = () => showIssue(issue);
+      return row;
+    }),
+  );
+}
+
+async function showIssue(issue) {
+  document.getElementById("detail").hidden = false;
+  document.getElementById("detail-title").textContent = `${severities[issue.severity]} ${issue.type} at ${issue.file}:${issue.line}`;
+  document.getElementById("detail-message").textContent = issue.message;
+  document.getElementById("detail-suggestion").textContent = issue.suggestion;
+  const docs = document.getElementById("detail-docs");
+  docs.hidden = !issue.docs_url;
+  docs.href = issue.docs_url || "#";
+
+  const source = document.getElementById("source");
+  source.replaceChildren();
+  const file = await getJSON(`/api/source?file=${encodeURIComponent(issue.file)}`);
+  document.getElementById("detail-editor").href = `vscode://file/${file.path}:${issue.line}:${issue.column}`;
+  const lines = file.source.split("\n");
+  const from = Math.max(issue.line - 10, 1);
+  const to = Math.min(issue.line + 10, lines.length);
+  for (let n = from; n <= to; n++) {
+    const line = el("span", { textContent: `${String(n).padStart(5)}  ${lines[n - 1]}\n` });
+    if (n === issue.line) {
+      line.className = "flagged";
+    }
+    source.append(line);
+  }
+}
+
+// renderTreemap inlines the treemap SVG so its rectangles can filter the
+// issue table like the file tree does
+async function renderTreemap() {
+  const response = await fetch("/api/treemap");
+  const container = document.getElementById("treemap-image");
+  container.innerHTML = await response.text();
+  container.querySelectorAll("g").forEach((group) => {
+    const file = group.querySelector("title").textContent.split(":")[0];
+    group.onclick = () => {
+      state.file = state.file === file ? "" : file;
+      renderTree();
+      renderIssues();
+    };
+  });
+}
+
+// renderTrends draws the score over time and the issue count per rule in the
+// last few snapshots
+function renderTrends(snapshots) {
+  const chart = document.getElementById("score-chart");
+  const table = document.getElementById("rule-trends");
+  if (snapshots.length === 0) {
+    chart.replaceChildren();
+    table.replaceChildren(el("tr", {}, el("td", { textContent: "No history yet" })));
+    return;
+  }
+
+  const step = snapshots.length > 1 ? 600 / (snapshots.length - 1) : 0;
+  const points = snapshots.map((s, i) => `${i * step},${120 - s.score * 1.2}`).join(" ");
+  const line = document.createElementNS("http://www.w3.org/2000/svg", "polyline");
+  line.setAttribute("points", points);
+  chart.replaceChildren(line);
+
+  const recent = snapshots.slice(-8);
+  const rules = [...new Set(recent.flatMap((s) => Object.keys(s.issues_by_rule || {})))].sort();
+  const header = el(
+    "tr",
+    {},
+    el("th", { textContent: "Rule" }),
+    ...recent.map((s) => el("th", { textContent: s.commit || new Date(s.time).toLocaleDateString() })),
+  );
+  table.replaceChildren(
+    header,
+    ...rules.map((rule) =>
+      el(
+        "tr",
+        {},
+        el("td", { textContent: rule }),
+        ...recent.map((s) => el("td", { textContent: (s.issues_by_rule || {})[rule] || 0 })),
+      ),
+    ),
+  );
+}
+
+async function load() {
+  state.result = await getJSON("/api/result");
+  renderSummary();
+  renderTree();
+  renderIssues();
+  await renderTreemap();
+  renderTrends(await getJSON("/api/history"));
+}
+
+document.querySelectorAll("th[data-sort]").forEach((th) => {
+  th.onclick = () => {
+    state.sortDesc = state.sortKey === th.dataset.sort ? !state.sortDesc : th.dataset.sort === "severity";
+    state.sortKey = th.dataset.sort;
+    renderIssues();
+  };
+});
+document.getElementById("filter").oninput = renderIssues;
+document.getElementById("severity").onchange = renderIssues;
+document.getElementById("refresh").onclick = async (event) => {
+  event.target.disabled = true;
+  try {
+    const response = await fetch("/api/refresh", { method: "POST" });
+    if (!response.ok) {
+      throw new Error(await response.text());
+    }
+    await load();
+  } catch (err) {
+    alert(`Analysis failed: ${err.message}`);
+  } finally {
+    event.target.disabled = false;
+  }
+};
+
+load().catch((err) => {
+  document.getElementById("summary").textContent = err.message;
+});
@@ -0,0 +1,250 @@
+package dashboard
+
+import (
+	"embed"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gophercheck/internal/analyzer"
+	"gophercheck/internal/config"
+	"gophercheck/internal/history"
+	"gophercheck/internal/models"
+)
+
+//go:embed assets
+var assets embed.FS
+
+// Analysis is one analysis run as the dashboard shows it
+type Analysis struct {
+	Result  *models.AnalysisResult
+	Treemap *models.TreemapNode
+	Paths   map[string]string // File on disk by reported path, for the source view
+}
+
+// AnalyzeFunc runs a fresh analysis of the dashboard's files
+type AnalyzeFunc func() (*Analysis, error)
+
+// Server serves the dashboard UI and the JSON API behind it:
+//
+//	GET  /api/result   latest result, with a score per file
+//	GET  /api/treemap  treemap of the latest result as SVG (?format=json for JSON)
+//	GET  /api/history  snapshots from the history file, oldest first
+//	GET  /api/source   source of an analyzed file (?file=<reported path>)
+//	POST /api/refresh  analyze again and record a history snapshot
+//
+// Requests must name the served host, a localhost name, or an IP address as
+// their Host, so pages of other sites that resolve their own names to this
+// machine (DNS rebinding) can't read the source, and a refresh must come from
+// the dashboard's own page.
+type Server struct {
+	config      *config.Config
+	analyze     AnalyzeFunc
+	historyFile string // Empty to keep no history
+	host        string // Host of the address served on
+
+	mu       sync.Mutex
+	analysis *Analysis
+}
+
+// NewServer creates a dashboard server for the address addr it is served on
+func NewServer(cfg *config.Config, analyze AnalyzeFunc, historyFile, addr string) *Server {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return &Server{config: cfg, analyze: analyze, historyFile: historyFile, host: host}
+}
+
+// Refresh analyzes the code again and records the result in the history
+func (s *Server) Refresh() error {
+	analysis, err := s.analyze()
+	if err != nil {
+		return err
+	}
+	models.SortIssues(analysis.Result.Issues) // The table's initial order
+	if s.historyFile != "" {
+		if err := history.Append(s.historyFile, history.NewSnapshot(analysis.Result)); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	s.analysis = analysis
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Server) Handler() http.Handler {
+	static, _ := fs.Sub(assets, "assets")
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(static))
+	mux.HandleFunc("GET /api/result", s.handleResult)
+	mux.HandleFunc("GET /api/treemap", s.handleTreemap)
+	mux.HandleFunc("GET /api/history", s.handleHistory)
+	mux.HandleFunc("GET /api/source", s.handleSource)
+	mux.HandleFunc("POST /api/refresh", s.handleRefresh)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.allowedHost(r.Host) {
+			http.Error(w, "unexpected Host header", http.StatusForbidden)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// allowedHost reports whether a request's Host names this server in a way
+// no other site can: the host served on, a localhost name, or an IP address
+func (s *Server) allowedHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	host = strings.TrimSuffix(strings.Trim(host, "[]"), ".")
+	if host == "" {
+		return false
+	}
+	if strings.EqualFold(host, s.host) || strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return true
+	}
+	return net.ParseIP(host) != nil
+}
+
+// sameOrigin reports whether a request comes from the dashboard's own page,
+// by Sec-Fetch-Site or else Origin. Requests with neither, as from curl,
+// don't come from a browser page at all.
+func sameOrigin(r *http.Request) bool {
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" {
+		return site == "same-origin"
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	return err == nil && parsed.Host == r.Host
+}
+
+// fileScore is the score a file would get if it were analyzed alone
+type fileScore struct {
+	File   string `json:"file"`
+	Score  int    `json:"score"`
+	Issues int    `json:"issues"`
+}
+
+func (s *Server) handleResult(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	analysis := s.analysis
+	s.mu.Unlock()
+	if analysis == nil {
+		http.Error(w, "no analysis yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	writeJSON(w, struct {
+		*models.AnalysisResult
+		FileScores []fileScore `json:"file_scores"`
+	}{analysis.Result, s.fileScores(analysis.Result)})
+}
+
+func (s *Server) fileScores(result *models.AnalysisResult) []fileScore {
+	byFile := make(map[string]*models.AnalysisResult, len(result.Files))
+	for _, file := range result.Files {
+		byFile[file] = models.NewAnalysisResultWithConfig(s.config)
+		byFile[file].Files = []string{file}
+	}
+	for _, issue := range result.Issues {
+		if fileResult, ok := byFile[issue.File]; ok {
+			fileResult.AddIssue(issue)
+		}
+	}
+
+	scores := make([]fileScore, 0, len(byFile))
+	for file, fileResult := range byFile {
+		fileResult.CalculateScoreWithConfig()
+		scores = append(scores, fileScore{File: file, Score: fileResult.PerformanceScore, Issues: fileResult.TotalIssues})
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].File < scores[j].File })
+	return scores
+}
+
+func (s *Server) handleTreemap(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	analysis := s.analysis
+	s.mu.Unlock()
+	if analysis == nil || analysis.Treemap == nil {
+		http.Error(w, "no analysis yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "json" {
+		writeJSON(w, analysis.Treemap)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	io.WriteString(w, analyzer.TreemapSVG(analysis.Treemap, 1200, 600))
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	snapshots := []history.Snapshot{}
+	if s.historyFile != "" {
+		loaded, err := history.Load(s.historyFile)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		snapshots = append(snapshots, loaded...)
+	}
+	writeJSON(w, snapshots)
+}
+
+// handleSource serves only files that were analyzed, never arbitrary paths
+func (s *Server) handleSource(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	analysis := s.analysis
+	s.mu.Unlock()
+
+	reported := r.URL.Query().Get("file")
+	if analysis == nil || analysis.Paths[reported] == "" {
+		http.NotFound(w, r)
+		return
+	}
+	src, err := os.ReadFile(analysis.Paths[reported])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	abs, _ := filepath.Abs(analysis.Paths[reported])
+	writeJSON(w, struct {
+		File   string `json:"file"`
+		Path   string `json:"path"` // Absolute, for editor links
+		Source string `json:"source"`
+	}{reported, abs, string(src)})
+}
+
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin refresh", http.StatusForbidden)
+		return
+	}
+	if err := s.Refresh(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,79 @@
+package dashboard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+)
+
+func testServer(t *testing.T, addr string) *Server {
+	t.Helper()
+	source := filepath.Join(t.TempDir(), "a.go")
+	if err := os.WriteFile(source, []byte("package a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.DefaultConfig()
+	analyze := func() (*Analysis, error) {
+		result := models.NewAnalysisResultWithConfig(cfg)
+		result.Files = []string{"a.go"}
+		return &Analysis{Result: result, Paths: map[string]string{"a.go": source}}, nil
+	}
+	server := NewServer(cfg, analyze, "", addr)
+	if err := server.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	return server
+}
+
+func TestHandlerRejectsForeignHosts(t *testing.T) {
+	handler := testServer(t, "devbox:7878").Handler()
+	for host, want := range map[string]int{
+		"localhost:7878":      http.StatusOK,
+		"127.0.0.1:7878":      http.StatusOK,
+		"[::1]:7878":          http.StatusOK,
+		"192.168.1.20:7878":   http.StatusOK,
+		"devbox:7878":         http.StatusOK,
+		"evil.example:7878":   http.StatusForbidden, // Rebound to this machine
+		"localhost.evil.com":  http.StatusForbidden,
+		"devbox.evil.example": http.StatusForbidden,
+	} {
+		request := httptest.NewRequest("GET", "/api/source?file=a.go", nil)
+		request.Host = host
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != want {
+			t.Errorf("Host %s: status %d, want %d", host, recorder.Code, want)
+		}
+	}
+}
+
+func TestRefreshRequiresSameOrigin(t *testing.T) {
+	handler := testServer(t, "localhost:7878").Handler()
+	for _, tc := range []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"no browser headers", nil, http.StatusNoContent},
+		{"same-origin fetch", map[string]string{"Sec-Fetch-Site": "same-origin", "Origin": "http://localhost:7878"}, http.StatusNoContent},
+		{"cross-site fetch", map[string]string{"Sec-Fetch-Site": "cross-site", "Origin": "http://localhost:7878"}, http.StatusForbidden},
+		{"matching origin", map[string]string{"Origin": "http://localhost:7878"}, http.StatusNoContent},
+		{"foreign origin", map[string]string{"Origin": "https://evil.example"}, http.StatusForbidden},
+	} {
+		request := httptest.NewRequest("POST", "/api/refresh", nil)
+		request.Host = "localhost:7878"
+		for name, value := range tc.headers {
+			request.Header.Set(name, value)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != tc.want {
+			t.Errorf("%s: status %d, want %d", tc.name, recorder.Code, tc.want)
+		}
+	}
+}
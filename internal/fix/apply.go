@@ -0,0 +1,96 @@
+package fix
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+
+	"gophercheck/internal/models"
+)
+
+// Apply performs edits on src. Identical edits (e.g. the same import added
+// by two fixes) are applied once; any other overlap is an error.
+func Apply(src []byte, edits []models.TextEdit) ([]byte, error) {
+	edits = dedupeEdits(edits)
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].Start < edits[j].Start
+	})
+
+	var out bytes.Buffer
+	last := 0
+	for _, edit := range edits {
+		if edit.Start < last || edit.End < edit.Start || edit.End > len(src) {
+			return nil, fmt.Errorf("overlapping or out-of-range edit at offset %d", edit.Start)
+		}
+		out.Write(src[last:edit.Start])
+		out.WriteString(edit.NewText)
+		last = edit.End
+	}
+	out.Write(src[last:])
+	return out.Bytes(), nil
+}
+
+// ApplyFixes applies as many fixes as possible to src in order, skipping any
+// fix whose edits conflict with one already accepted. If src was gofmt-clean
+// the result is gofmt'd too, which also sorts added imports.
+func ApplyFixes(src []byte, fixes []*models.SuggestedFix) ([]byte, int, error) {
+	accepted := Compatible(src, fixes)
+	var edits []models.TextEdit
+	for _, fix := range accepted {
+		edits = append(edits, fix.Edits...)
+	}
+
+	out, err := Apply(src, edits)
+	if err != nil {
+		return nil, 0, err
+	}
+	return formatIfClean(src, out), len(accepted), nil
+}
+
+// Compatible returns the fixes ApplyFixes would apply to src: each fix in
+// order whose edits don't conflict with the fixes accepted before it
+func Compatible(src []byte, fixes []*models.SuggestedFix) []*models.SuggestedFix {
+	var accepted []*models.SuggestedFix
+	var edits []models.TextEdit
+	for _, fix := range fixes {
+		candidate := append(append([]models.TextEdit(nil), edits...), fix.Edits...)
+		if _, err := Apply(src, candidate); err != nil {
+			continue
+		}
+		accepted = append(accepted, fix)
+		edits = candidate
+	}
+	return accepted
+}
+
+// Preview returns src with a single fix applied, formatted like ApplyFixes
+func Preview(src []byte, fix *models.SuggestedFix) ([]byte, error) {
+	out, err := Apply(src, fix.Edits)
+	if err != nil {
+		return nil, err
+	}
+	return formatIfClean(src, out), nil
+}
+
+func formatIfClean(original, edited []byte) []byte {
+	if formatted, err := format.Source(original); err != nil || !bytes.Equal(formatted, original) {
+		return edited
+	}
+	if formatted, err := format.Source(edited); err == nil {
+		return formatted
+	}
+	return edited
+}
+
+func dedupeEdits(edits []models.TextEdit) []models.TextEdit {
+	seen := make(map[models.TextEdit]bool, len(edits))
+	unique := make([]models.TextEdit, 0, len(edits))
+	for _, edit := range edits {
+		if !seen[edit] {
+			seen[edit] = true
+			unique = append(unique, edit)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,47 @@
+package fix
+
+import (
+	"sort"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"gophercheck/internal/models"
+)
+
+// RangeEdits converts edits of src from byte offsets to positions, in the
+// order Apply performs them
+func RangeEdits(src []byte, edits []models.TextEdit) []models.RangeEdit {
+	edits = dedupeEdits(edits)
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].Start < edits[j].Start
+	})
+
+	ranged := make([]models.RangeEdit, 0, len(edits))
+	for _, edit := range edits {
+		ranged = append(ranged, models.RangeEdit{
+			Range: models.Range{
+				Start: position(src, edit.Start),
+				End:   position(src, edit.End),
+			},
+			NewText: edit.NewText,
+		})
+	}
+	return ranged
+}
+
+// position returns the line and UTF-16 character of a byte offset in src
+func position(src []byte, offset int) models.Position {
+	offset = min(offset, len(src))
+	var pos models.Position
+	for i := 0; i < offset; {
+		r, size := utf8.DecodeRune(src[i:])
+		i += size
+		if r == '\n' {
+			pos.Line++
+			pos.Character = 0
+			continue
+		}
+		pos.Character += utf16.RuneLen(r)
+	}
+	return pos
+}
@@ -0,0 +1,175 @@
+package fix
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"gophercheck/internal/models"
+)
+
+// Candidate is a suggested fix and the issue it was reported for
+type Candidate struct {
+	Path  string // File on disk the fix edits
+	Issue models.Issue
+}
+
+// Outcome is whether verification kept a candidate, and why not
+type Outcome struct {
+	Candidate
+	Kept   bool
+	Reason string // Why the fix was rolled back, empty when kept
+}
+
+// Check builds and tests the package in a directory, returning an error
+// describing the first failure
+type Check func(dir string) error
+
+// GoCheck runs go build and go test on the package in dir
+func GoCheck(dir string) error {
+	for _, args := range [][]string{{"build", "."}, {"test", "."}} {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("go %s failed: %s", args[0], firstLine(output, err))
+		}
+	}
+	return nil
+}
+
+// VerifyPackage applies the candidates for the files of the package in dir
+// and keeps them if check passes. Otherwise it rolls back and retries the
+// candidates one at a time, keeping each one the package still passes with.
+// Files are left with the kept fixes applied, or restored on error.
+func VerifyPackage(dir string, candidates []Candidate, check Check) ([]Outcome, error) {
+	originals := make(map[string][]byte)
+	for _, candidate := range candidates {
+		if _, ok := originals[candidate.Path]; ok {
+			continue
+		}
+		src, err := os.ReadFile(candidate.Path)
+		if err != nil {
+			return nil, err
+		}
+		originals[candidate.Path] = src
+	}
+	pkg := &packageFiles{originals: originals}
+
+	outcomes := make([]Outcome, len(candidates))
+	var pending []int
+	for i, candidate := range candidates {
+		outcomes[i].Candidate = candidate
+		if pkg.conflicts(candidates, i) {
+			outcomes[i].Reason = "conflicts with another fix"
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	if err := pkg.write(candidates, pending); err != nil {
+		return nil, pkg.restore(err)
+	}
+	if check(dir) == nil {
+		for _, i := range pending {
+			outcomes[i].Kept = true
+		}
+		return outcomes, nil
+	}
+
+	if err := pkg.write(candidates, nil); err != nil {
+		return nil, pkg.restore(err)
+	}
+	if err := check(dir); err != nil {
+		for _, i := range pending {
+			outcomes[i].Reason = "package fails without fixes: " + err.Error()
+		}
+		return outcomes, nil
+	}
+
+	var kept []int
+	for _, i := range pending {
+		trial := append(slices.Clone(kept), i)
+		if err := pkg.write(candidates, trial); err != nil {
+			return nil, pkg.restore(err)
+		}
+		if err := check(dir); err != nil {
+			outcomes[i].Reason = err.Error()
+			continue
+		}
+		outcomes[i].Kept = true
+		kept = trial
+	}
+	if err := pkg.write(candidates, kept); err != nil {
+		return nil, pkg.restore(err)
+	}
+	return outcomes, nil
+}
+
+// packageFiles holds the original contents of the files being fixed
+type packageFiles struct {
+	originals map[string][]byte
+}
+
+// conflicts reports whether candidate i can't be applied together with the
+// candidates for the same file before it
+func (p *packageFiles) conflicts(candidates []Candidate, i int) bool {
+	var fixes []*models.SuggestedFix
+	for _, candidate := range candidates[:i+1] {
+		if candidate.Path == candidates[i].Path {
+			fixes = append(fixes, candidate.Issue.SuggestedFix)
+		}
+	}
+	accepted := Compatible(p.originals[candidates[i].Path], fixes)
+	return len(accepted) == 0 || accepted[len(accepted)-1] != candidates[i].Issue.SuggestedFix
+}
+
+// write rewrites every file from its original with the selected candidates
+// applied
+func (p *packageFiles) write(candidates []Candidate, selected []int) error {
+	fixes := make(map[string][]*models.SuggestedFix)
+	for _, i := range selected {
+		fixes[candidates[i].Path] = append(fixes[candidates[i].Path], candidates[i].Issue.SuggestedFix)
+	}
+	for path, src := range p.originals {
+		fixed, _, err := ApplyFixes(src, fixes[path])
+		if err != nil {
+			return err
+		}
+		if err := writeKeepingMode(path, fixed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restore writes back the original files after err
+func (p *packageFiles) restore(err error) error {
+	for path, src := range p.originals {
+		if restoreErr := writeKeepingMode(path, src); restoreErr != nil {
+			return fmt.Errorf("%w (restoring %s: %v)", err, path, restoreErr)
+		}
+	}
+	return err
+}
+
+func writeKeepingMode(path string, content []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, info.Mode().Perm())
+}
+
+// firstLine returns the first non-empty line of a command's output, or the
+// command error when it printed nothing
+func firstLine(output []byte, err error) string {
+	for _, line := range strings.Split(string(bytes.TrimSpace(output)), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return err.Error()
+}
@@ -0,0 +1,116 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gophercheck/internal/models"
+)
+
+// DefaultFile is where the dashboard keeps history unless told otherwise
+const DefaultFile = ".gophercheck/history.jsonl"
+
+// Snapshot holds the headline numbers of one analysis run. The history file
+// keeps one snapshot per line (JSON Lines), oldest first.
+type Snapshot struct {
+	Time             time.Time      `json:"time"`
+	Commit           string         `json:"commit,omitempty"` // Short HEAD commit, when run in a git repository
+	Score            int            `json:"score"`
+	Grade            string         `json:"grade"`
+	Files            int            `json:"files"`
+	TotalIssues      int            `json:"total_issues"`
+	IssuesBySeverity map[string]int `json:"issues_by_severity"`
+	IssuesByRule     map[string]int `json:"issues_by_rule"`
+	IssuesByFile     map[string]int `json:"issues_by_file,omitempty"` // Files without issues are left out
+}
+
+// NewSnapshot summarizes a result, taken now
+func NewSnapshot(result *models.AnalysisResult) Snapshot {
+	byRule := make(map[string]int)
+	byFile := make(map[string]int)
+	for _, issue := range result.Issues {
+		byRule[string(issue.Type)]++
+		byFile[issue.File]++
+	}
+	return Snapshot{
+		Time:             time.Now().UTC(),
+		Commit:           headCommit(),
+		Score:            result.PerformanceScore,
+		Grade:            result.Grade,
+		Files:            len(result.Files),
+		TotalIssues:      result.TotalIssues,
+		IssuesBySeverity: result.IssuesBySeverity,
+		IssuesByRule:     byRule,
+		IssuesByFile:     byFile,
+	}
+}
+
+// Append adds a snapshot to the history file at path, creating it if needed
+func Append(path string, snapshot Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(file).Encode(snapshot); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Load reads every snapshot in the history file at path. A missing file is
+// an empty history.
+func Load(path string) ([]Snapshot, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var snapshots []Snapshot
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, scanner.Err()
+}
+
+// IssuesByFile returns the per-file issue counts of the latest snapshot that
+// has them, or nil. Older history files don't record them.
+func IssuesByFile(snapshots []Snapshot) map[string]int {
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if snapshots[i].IssuesByFile != nil {
+			return snapshots[i].IssuesByFile
+		}
+	}
+	return nil
+}
+
+func headCommit() string {
+	output, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,149 @@
+// Package mcp serves the analysis to AI coding assistants as Model Context
+// Protocol tools, over JSON-RPC 2.0 messages one per line on stdin/stdout
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"gophercheck/internal/models"
+)
+
+// ProtocolVersion is the MCP revision the server speaks
+const ProtocolVersion = "2024-11-05"
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// AnalyzeFunc analyzes the Go file at path, or src in its place when src is
+// not nil, as a run of gophercheck on that file would
+type AnalyzeFunc func(path string, src []byte) (*models.AnalysisResult, error)
+
+// Server answers MCP requests. Findings of analyze_file are kept for the
+// session, so that explain_issue and suggest_fix can refer to them by id.
+type Server struct {
+	analyze  AnalyzeFunc
+	version  string
+	docsBase string
+	levels   models.SeverityLevels
+
+	mu       sync.Mutex
+	findings map[string]models.Issue
+}
+
+// NewServer serves analyses by analyze; version is reported to clients,
+// docsBase is the output.docs_base_url for links to the rule reference, and
+// levels give findings their LSP diagnostic severity
+func NewServer(analyze AnalyzeFunc, version, docsBase string, levels models.SeverityLevels) *Server {
+	return &Server{
+		analyze:  analyze,
+		version:  version,
+		docsBase: docsBase,
+		levels:   levels,
+		findings: make(map[string]models.Issue),
+	}
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // Absent for notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// Serve reads requests from in and writes responses to out until in ends
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024) // Sources sent along can be large
+	encoder := json.NewEncoder(out)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if resp := s.handle(line); resp != nil {
+			if err := encoder.Encode(resp); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// handle answers one message, or returns nil for a notification
+func (s *Server) handle(message []byte) *response {
+	var req request
+	if err := json.Unmarshal(message, &req); err != nil {
+		return &response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{codeParseError, "parse error: " + err.Error()}}
+	}
+	if req.ID == nil {
+		return nil
+	}
+
+	resp := &response{JSONRPC: "2.0", ID: req.ID}
+	var err error
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		err = &rpcError{codeInvalidRequest, "invalid request"}
+	} else {
+		resp.Result, err = s.call(req.Method, req.Params)
+	}
+	if err != nil {
+		var rerr *rpcError
+		if !errors.As(err, &rerr) {
+			rerr = &rpcError{codeInvalidParams, err.Error()}
+		}
+		resp.Result, resp.Error = nil, rerr
+	}
+	return resp
+}
+
+func (s *Server) call(method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "gophercheck", "version": s.version},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": toolList}, nil
+	case "tools/call":
+		var call struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(params, &call); err != nil {
+			return nil, fmt.Errorf("invalid tools/call params: %w", err)
+		}
+		return s.callTool(call.Name, call.Arguments)
+	default:
+		return nil, &rpcError{codeMethodNotFound, "method not found: " + method}
+	}
+}
@@ -0,0 +1,236 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"gophercheck/internal/models"
+)
+
+// tool describes a tool for tools/list
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+func objectSchema(required []string, properties map[string]any) map[string]any {
+	return map[string]any{"type": "object", "properties": properties, "required": required}
+}
+
+var toolList = []tool{
+	{
+		Name: "analyze_file",
+		Description: "Analyze a Go file for performance and complexity issues. Returns the score and every " +
+			"finding with an id for explain_issue and suggest_fix. Pass source to analyze unsaved contents.",
+		InputSchema: objectSchema([]string{"path"}, map[string]any{
+			"path":   map[string]any{"type": "string", "description": "Path of the Go file, relative to the server's working directory"},
+			"source": map[string]any{"type": "string", "description": "Contents to analyze instead of the file on disk"},
+		}),
+	},
+	{
+		Name:        "explain_issue",
+		Description: "Explain a finding from analyze_file by its id, or a rule by its name: what it flags and why it matters.",
+		InputSchema: objectSchema(nil, map[string]any{
+			"id":   map[string]any{"type": "string", "description": "Id of a finding from analyze_file"},
+			"rule": map[string]any{"type": "string", "description": "Rule name, e.g. string_concatenation"},
+		}),
+	},
+	{
+		Name:        "suggest_fix",
+		Description: "Suggest how to fix a finding from analyze_file, with a unified diff and LSP text edits when the rewrite is mechanical.",
+		InputSchema: objectSchema([]string{"id"}, map[string]any{
+			"id": map[string]any{"type": "string", "description": "Id of a finding from analyze_file"},
+		}),
+	},
+}
+
+// toolResult is the result of tools/call. Failures of the tool itself are
+// results with IsError set, for the assistant to read, rather than
+// JSON-RPC errors.
+type toolResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func textResult(text string) toolResult {
+	return toolResult{Content: []textContent{{Type: "text", Text: text}}}
+}
+
+func jsonResult(value any) (toolResult, error) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return toolResult{}, err
+	}
+	return textResult(string(data)), nil
+}
+
+func toolError(format string, args ...any) toolResult {
+	result := textResult(fmt.Sprintf(format, args...))
+	result.IsError = true
+	return result
+}
+
+type toolArgs struct {
+	Path   string  `json:"path"`
+	Source *string `json:"source"`
+	ID     string  `json:"id"`
+	Rule   string  `json:"rule"`
+}
+
+func (s *Server) callTool(name string, arguments json.RawMessage) (any, error) {
+	var args toolArgs
+	if len(arguments) > 0 {
+		if err := json.Unmarshal(arguments, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments for %s: %w", name, err)
+		}
+	}
+	switch name {
+	case "analyze_file":
+		return s.analyzeFile(args)
+	case "explain_issue":
+		return s.explainIssue(args)
+	case "suggest_fix":
+		return s.suggestFix(args)
+	default:
+		return nil, &rpcError{codeInvalidParams, "unknown tool: " + name}
+	}
+}
+
+// finding is an issue as analyze_file lists it
+type finding struct {
+	ID       string `json:"id"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Level    string `json:"level"` // LSP diagnostic severity: error, warning, information, or hint
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Function string `json:"function,omitempty"`
+	Message  string `json:"message"`
+	Fixable  bool   `json:"fixable,omitempty"` // suggest_fix has a diff
+}
+
+// issueID identifies a finding within the session; the fingerprint alone
+// leaves out the line, so identical findings in one function would collide
+func issueID(issue *models.Issue) string {
+	return fmt.Sprintf("%s:%d", issue.Fingerprint(), issue.Line)
+}
+
+func (s *Server) analyzeFile(args toolArgs) (any, error) {
+	if args.Path == "" {
+		return toolError("path is required"), nil
+	}
+	var src []byte
+	if args.Source != nil {
+		src = []byte(*args.Source)
+	}
+	result, err := s.analyze(args.Path, src)
+	if err != nil {
+		return toolError("analysis failed: %v", err), nil
+	}
+
+	report := struct {
+		File    string               `json:"file"`
+		Score   int                  `json:"score"`
+		Grade   string               `json:"grade"`
+		Issues  []finding            `json:"issues"`
+		Skipped []models.SkippedFile `json:"skipped,omitempty"` // e.g. with the parse error
+	}{File: args.Path, Score: result.PerformanceScore, Grade: result.Grade, Issues: []finding{}, Skipped: result.SkippedFiles}
+
+	s.mu.Lock()
+	for i := range result.Issues {
+		issue := &result.Issues[i]
+		id := issueID(issue)
+		s.findings[id] = *issue
+		report.Issues = append(report.Issues, finding{
+			ID:       id,
+			Rule:     string(issue.Type),
+			Severity: issue.Severity.String(),
+			Level:    s.levels.Level(models.ConsumerLSP, issue.Severity),
+			Line:     issue.Line,
+			Column:   issue.Column,
+			Function: issue.Function,
+			Message:  issue.Message,
+			Fixable:  issue.SuggestedFix != nil && issue.SuggestedFix.Diff != "",
+		})
+	}
+	s.mu.Unlock()
+	return jsonResult(report)
+}
+
+func (s *Server) finding(id string) (models.Issue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.findings[id]
+	return issue, ok
+}
+
+func (s *Server) explainIssue(args toolArgs) (any, error) {
+	explanation := struct {
+		Rule        string `json:"rule"`
+		Description string `json:"description"`
+		DocsURL     string `json:"docs_url,omitempty"`
+		Severity    string `json:"severity,omitempty"`
+		File        string `json:"file,omitempty"`
+		Line        int    `json:"line,omitempty"`
+		Function    string `json:"function,omitempty"`
+		Message     string `json:"message,omitempty"`
+		Complexity  string `json:"complexity,omitempty"`
+		Impact      string `json:"impact,omitempty"`
+		Code        string `json:"code,omitempty"`
+	}{}
+
+	switch {
+	case args.ID != "":
+		issue, ok := s.finding(args.ID)
+		if !ok {
+			return toolError("unknown finding id %q; run analyze_file first", args.ID), nil
+		}
+		explanation.Rule = string(issue.Type)
+		explanation.Severity = issue.Severity.String()
+		explanation.File, explanation.Line, explanation.Function = issue.File, issue.Line, issue.Function
+		explanation.Message = issue.Message
+		explanation.Complexity, explanation.Impact = issue.Complexity, issue.Impact
+		explanation.Code = issue.CodeSnippet
+	case args.Rule != "":
+		if !slices.Contains(models.AllIssueTypes, models.IssueType(args.Rule)) {
+			return toolError("unknown rule %q", args.Rule), nil
+		}
+		explanation.Rule = args.Rule
+	default:
+		return toolError("pass the id of a finding or a rule name"), nil
+	}
+
+	rule := models.IssueType(explanation.Rule)
+	explanation.Description = rule.Description()
+	explanation.DocsURL = rule.DocsURL(s.docsBase)
+	return jsonResult(explanation)
+}
+
+func (s *Server) suggestFix(args toolArgs) (any, error) {
+	issue, ok := s.finding(args.ID)
+	if !ok {
+		return toolError("unknown finding id %q; run analyze_file first", args.ID), nil
+	}
+
+	suggestion := struct {
+		ID         string             `json:"id"`
+		Suggestion string             `json:"suggestion"`
+		Fix        string             `json:"fix,omitempty"`    // What the rewrite does
+		Safety     string             `json:"safety,omitempty"` // safe, likely-safe, or unsafe
+		Diff       string             `json:"diff,omitempty"`
+		Edits      []models.RangeEdit `json:"edits,omitempty"` // The diff as LSP TextEdits
+		Effort     string             `json:"effort,omitempty"`
+	}{ID: args.ID, Suggestion: issue.Suggestion, Effort: string(issue.FixEffort)}
+	if fix := issue.SuggestedFix; fix != nil {
+		suggestion.Fix, suggestion.Safety, suggestion.Diff = fix.Description, string(fix.Safety), fix.Diff
+		suggestion.Edits = fix.TextEdits
+	}
+	return jsonResult(suggestion)
+}
@@ -0,0 +1,49 @@
+package models
+
+// Issue ages relative to an earlier version of the code (see AgeClassifier)
+const (
+	AgeNew         = "new"
+	AgePreExisting = "pre-existing"
+)
+
+// AgeClassifier tells issues introduced since an earlier analysis from ones
+// that were already there. Issues are matched by fingerprint, so findings
+// that only moved to another line stay pre-existing, and each earlier issue
+// matches at most one current issue, so a second copy of an old finding
+// counts as new.
+type AgeClassifier struct {
+	remaining map[string]int // Unmatched earlier issues, by fingerprint
+}
+
+func NewAgeClassifier(previous []Issue) *AgeClassifier {
+	remaining := make(map[string]int, len(previous))
+	for _, issue := range previous {
+		remaining[issue.Fingerprint()]++
+	}
+	return &AgeClassifier{remaining: remaining}
+}
+
+// Classify sets the age of one issue
+func (c *AgeClassifier) Classify(issue *Issue) {
+	fingerprint := issue.Fingerprint()
+	if c.remaining[fingerprint] > 0 {
+		c.remaining[fingerprint]--
+		issue.Age = AgePreExisting
+		return
+	}
+	issue.Age = AgeNew
+}
+
+// ClassifyAges sets the age of every issue of the result and records what
+// they were compared against, e.g. a git ref or a baseline report
+func (r *AnalysisResult) ClassifyAges(previous []Issue, since string) {
+	classifier := NewAgeClassifier(previous)
+	r.Since = since
+	r.NewIssues = 0
+	for i := range r.Issues {
+		classifier.Classify(&r.Issues[i])
+		if r.Issues[i].Age == AgeNew {
+			r.NewIssues++
+		}
+	}
+}
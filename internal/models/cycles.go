@@ -0,0 +1,47 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CycleImport is a package in an import cycle and the import by which it
+// leads on to the next package of the cycle
+type CycleImport struct {
+	Package string `json:"package"`
+	Imports string `json:"imports"` // Next package in the cycle
+	File    string `json:"file"`    // File holding the import
+	Line    int    `json:"line"`
+}
+
+// CycleGraph renders the import cycles among issues as a Graphviz DOT graph,
+// one cluster per cycle with each import labeled by its position. Packages in
+// several cycles appear once in each.
+func CycleGraph(issues []Issue) string {
+	var b strings.Builder
+	b.WriteString("digraph import_cycles {\n\tnode [shape=box];\n")
+	count := 0
+	for _, issue := range issues {
+		if len(issue.Cycle) == 0 {
+			continue
+		}
+		count++
+		fmt.Fprintf(&b, "\tsubgraph cluster_%d {\n", count)
+		fmt.Fprintf(&b, "\t\tlabel=%q;\n", fmt.Sprintf("cycle %d (%d packages)", count, len(issue.Cycle)))
+		for _, step := range issue.Cycle {
+			fmt.Fprintf(&b, "\t\t%q [label=%q];\n", cycleNode(count, step.Package), step.Package)
+		}
+		for _, step := range issue.Cycle {
+			fmt.Fprintf(&b, "\t\t%q -> %q [label=%q];\n", cycleNode(count, step.Package), cycleNode(count, step.Imports),
+				fmt.Sprintf("%s:%d", step.File, step.Line))
+		}
+		b.WriteString("\t}\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// cycleNode is the id of a package's node in the cluster of a cycle
+func cycleNode(cycle int, pkg string) string {
+	return fmt.Sprintf("%d:%s", cycle, pkg)
+}
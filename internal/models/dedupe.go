@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// isStatementLevel reports whether an issue type points at a single statement.
+// Function-, type-, and package-level findings (complexity, length, cycles) share a line
+// with unrelated issues, so they are never merged.
+func (t IssueType) isStatementLevel() bool {
+	switch t {
+	case IssueCyclomaticComplex, IssueTypeComplexity, IssueFunctionLength, IssueImportCycle, IssueHubPackage, IssueLargeReceiver:
+		return false
+	default:
+		return true
+	}
+}
+
+// StatementSpan is the source range of the statement an issue points into, as
+// byte offsets in its file
+type StatementSpan struct {
+	Start, End int
+}
+
+type dedupeKey struct {
+	file      string
+	statement StatementSpan
+}
+
+// MergeDuplicateIssues collapses statement-level issues of different types,
+// reported by different detectors on the same statement (as statementOf
+// finds it), into a single composite finding. The most severe issue becomes
+// the primary one; the others contribute their type and suggestion so the
+// statement is only penalized once. An issue of a type already in the
+// composite only joins it from the same position, as the same finding
+// reported twice; elsewhere on the statement it stays a finding of its own,
+// as do issues on different statements of one line.
+func MergeDuplicateIssues(issues []Issue, statementOf func(Issue) StatementSpan) []Issue {
+	groups := make(map[dedupeKey][]int)
+	for i, issue := range issues {
+		if !issue.Type.isStatementLevel() {
+			continue
+		}
+		key := dedupeKey{file: issue.File, statement: statementOf(issue)}
+		groups[key] = append(groups[key], i)
+	}
+
+	merged := make([]Issue, 0, len(issues))
+	consumed := make(map[int]bool)
+	for i, issue := range issues {
+		if consumed[i] {
+			continue
+		}
+		if !issue.Type.isStatementLevel() {
+			merged = append(merged, issue)
+			continue
+		}
+
+		// The first issue of each type on the statement, starting with this
+		// one, and repeats of those at the same position
+		var group []int
+		positions := make(map[IssueType][2]int)
+		for _, idx := range groups[dedupeKey{file: issue.File, statement: statementOf(issue)}] {
+			other := issues[idx]
+			at := [2]int{other.Line, other.Column}
+			if position, seen := positions[other.Type]; consumed[idx] || seen && position != at {
+				continue
+			}
+			positions[other.Type] = at
+			consumed[idx] = true
+			group = append(group, idx)
+		}
+		if len(group) == 1 {
+			merged = append(merged, issue)
+			continue
+		}
+		merged = append(merged, compositeIssue(issues, group))
+	}
+
+	return merged
+}
+
+func compositeIssue(issues []Issue, group []int) Issue {
+	members := make([]Issue, len(group))
+	for i, idx := range group {
+		members[i] = issues[idx]
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		if members[i].Severity != members[j].Severity {
+			return members[i].Severity > members[j].Severity
+		}
+		return members[i].Confidence > members[j].Confidence
+	})
+
+	primary := members[0]
+	seen := map[IssueType]bool{primary.Type: true}
+	for _, other := range members[1:] {
+		if other.Confidence > primary.Confidence {
+			primary.Confidence = other.Confidence
+		}
+		if primary.SuggestedFix == nil {
+			primary.SuggestedFix = other.SuggestedFix
+		}
+		if seen[other.Type] {
+			continue
+		}
+		seen[other.Type] = true
+		primary.RelatedTypes = append(primary.RelatedTypes, other.Type)
+		primary.Suggestion += fmt.Sprintf("\n\nAlso flagged as %s: %s\n%s", other.Type, other.Message, other.Suggestion)
+	}
+
+	return primary
+}
@@ -0,0 +1,27 @@
+package models
+
+import "testing"
+
+// TestMergeDuplicateIssuesKeepsRepeatedTypes requires an issue type found at
+// two places of one statement to stay two findings, each merging what was
+// reported at its position
+func TestMergeDuplicateIssuesKeepsRepeatedTypes(t *testing.T) {
+	sameStatement := func(Issue) StatementSpan { return StatementSpan{Start: 10, End: 40} }
+	issues := []Issue{
+		{Type: IssueMemoryAlloc, Severity: SeverityMedium, File: "a.go", Line: 3, Column: 5},
+		{Type: IssueMemoryAlloc, Severity: SeverityMedium, File: "a.go", Line: 3, Column: 20},
+		{Type: IssueSliceGrowth, Severity: SeverityHigh, File: "a.go", Line: 3, Column: 20},
+		{Type: IssueMemoryAlloc, Severity: SeverityLow, File: "a.go", Line: 3, Column: 5}, // Reported twice
+	}
+
+	merged := MergeDuplicateIssues(issues, sameStatement)
+	if len(merged) != 2 {
+		t.Fatalf("merged into %d issues, want 2: %+v", len(merged), merged)
+	}
+	if merged[0].Type != IssueSliceGrowth || len(merged[0].RelatedTypes) != 1 || merged[0].RelatedTypes[0] != IssueMemoryAlloc {
+		t.Errorf("composite = %s related to %v, want slice_growth related to memory_allocation", merged[0].Type, merged[0].RelatedTypes)
+	}
+	if merged[1].Type != IssueMemoryAlloc || len(merged[1].RelatedTypes) != 0 {
+		t.Errorf("second = %s related to %v, want memory_allocation on its own", merged[1].Type, merged[1].RelatedTypes)
+	}
+}
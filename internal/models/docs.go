@@ -0,0 +1,97 @@
+package models
+
+// AllIssueTypes lists every issue type, in the order of the rule reference
+var AllIssueTypes = []IssueType{
+	IssueNestedLoops, IssueStringConcat, IssueInefficinetDS, IssueSplitInLoop, IssueLogInLoop,
+	IssueBuilderMisuse, IssueHandlerAlloc, IssueSingleCaseSelect, IssueBusyWait, IssueErrorfInLoop,
+	IssueInefficientSort, IssueSprintfKey, IssueSequentialIO, IssueConversionCache,
+	IssueCyclomaticComplex, IssueTypeComplexity, IssueFunctionLength, IssueIfChain,
+	IssueMemoryAlloc, IssueSliceGrowth, IssueAppendMisuse, IssueLargeReceiver, IssueSliceRetention,
+	IssueReadAll, IssueAllocFreeAPI,
+	IssueImportCycle, IssueHubPackage, IssueDependencyRule, IssueMapMutation, IssueConcurrentMap, IssueStdlibLoop,
+}
+
+// DocsURL returns the link to the documentation of an issue type in a copy of
+// the rule reference (docs/rules.md, which has an anchor for every issue
+// type) published at base, or "" without a base
+func (t IssueType) DocsURL(base string) string {
+	if base == "" {
+		return ""
+	}
+	return base + "#" + string(t)
+}
+
+// Description explains what an issue type flags and why it matters, for the
+// rule reference
+func (t IssueType) Description() string {
+	return issueDescriptions[t]
+}
+
+var issueDescriptions = map[IssueType]string{
+	IssueNestedLoops: "Loops nested inside loops over the same or related data. Each level multiplies the work, " +
+		"so code that is instant on test data becomes quadratic or cubic on production inputs.",
+	IssueStringConcat: "Strings built with += in a loop. Strings are immutable, so every iteration copies " +
+		"everything built so far, making the loop O(n²) in the length of the result.",
+	IssueInefficinetDS: "Slices searched linearly for membership or lookup, often inside loops, where a map " +
+		"or set answers the same question in constant time.",
+	IssueSplitInLoop: "strings.Split, Fields, or Join called on the same input on every iteration. Each call " +
+		"allocates a fresh slice or string that could be computed once before the loop.",
+	IssueLogInLoop: "Logging inside loops and hot functions. Formatting and writing log lines is far slower " +
+		"than the work around it, and floods logs when the loop runs often.",
+	IssueBuilderMisuse: "strings.Builder and bytes.Buffer used in ways that lose their benefit: no Grow when " +
+		"the final size is known, builders copied by value, or Fprintf where a plain write would do.",
+	IssueHandlerAlloc: "HTTP handlers redoing constant setup, such as compiling regexps or building clients " +
+		"and templates, on every request instead of once at startup.",
+	IssueSingleCaseSelect: "A select statement with a single case, which behaves like a plain channel " +
+		"operation but is harder to read and slightly slower.",
+	IssueBusyWait: "Loops that spin on a condition without blocking, burning a CPU core while waiting for " +
+		"another goroutine instead of using a channel, sync.Cond, or a timer.",
+	IssueErrorfInLoop: "Errors formatted with fmt.Errorf on every iteration although they are only used when " +
+		"the loop fails, paying for formatting that is almost always thrown away.",
+	IssueInefficientSort: "Hand-rolled O(n²) sorts and sorting inside loops, where the standard library's " +
+		"O(n log n) sorts, or sorting once, do the job.",
+	IssueSprintfKey: "Map keys built with fmt.Sprintf for every lookup. Formatting allocates and is slow; a " +
+		"struct key or string concatenation is cheaper.",
+	IssueSequentialIO: "Independent network, database, or disk calls made one after another in a loop, so " +
+		"the total latency is the sum of all calls rather than the slowest one.",
+	IssueConversionCache: "The same few values converted to strings over and over in loops, where converting " +
+		"once or keeping a lookup table avoids repeated allocation.",
+	IssueCyclomaticComplex: "Functions with many independent paths through them. High cyclomatic complexity " +
+		"makes code hard to test exhaustively and to change safely.",
+	IssueTypeComplexity: "Types whose methods together have a high cyclomatic complexity, even when each method " +
+		"is simple on its own. They are usually god objects holding several responsibilities that belong in " +
+		"separate types.",
+	IssueFunctionLength: "Functions long enough to be hard to understand at once. Long functions tend to mix " +
+		"responsibilities and hide performance problems in their middle.",
+	IssueIfChain: "Long if/else-if chains comparing one variable against constants. A switch states the " +
+		"comparison once and lists the cases side by side; when every branch just picks a value, a lookup map " +
+		"replaces the branching altogether.",
+	IssueMemoryAlloc: "Allocations in loops or hot paths that could be hoisted, reused, or avoided, adding " +
+		"garbage collector pressure on every iteration.",
+	IssueSliceGrowth: "Slices grown by append in a loop whose final length is known up front. Without a " +
+		"capacity, append reallocates and copies the slice repeatedly as it grows.",
+	IssueAppendMisuse: "append results that are dropped, assigned to a different slice than the one " +
+		"appended to, or appended to themselves, which loses data or aliases memory unexpectedly.",
+	IssueLargeReceiver: "Methods with value receivers on large structs, which copy the whole struct on " +
+		"every call; a pointer receiver passes one word instead.",
+	IssueSliceRetention: "Small subslices kept from large buffers. The subslice pins the entire backing " +
+		"array in memory for as long as it lives.",
+	IssueReadAll: "Whole inputs read into memory only to be scanned once, where streaming with a " +
+		"bufio.Scanner or decoder keeps memory use constant.",
+	IssueAllocFreeAPI: "Allocating calls in hot code that have allocation-free alternatives in the standard " +
+		"library, such as the Append variants of strconv and time formatting.",
+	IssueImportCycle: "Packages that import each other, directly or through others. Go rejects import " +
+		"cycles, and near-cycles signal tangled package boundaries.",
+	IssueHubPackage: "Packages many other packages import that also import many themselves. Every change " +
+		"to such a hub, or to anything it imports, ripples through all its importers; its instability, " +
+		"fan-out / (fan-in + fan-out), shows which way it leans.",
+	IssueDependencyRule: "Imports the project's dependency_rules forbid, such as a model package importing " +
+		"the code that uses it. Layers importing in the wrong direction end up changing together, and are " +
+		"the first step towards an import cycle.",
+	IssueMapMutation: "Maps changed while ranging over them. Entries added during iteration may or may not " +
+		"be visited, so the result depends on the runtime's iteration order.",
+	IssueConcurrentMap: "Maps written from goroutines without synchronization. Concurrent map writes are a " +
+		"data race that the runtime detects and crashes on.",
+	IssueStdlibLoop: "Loops that reimplement a function of the slices or maps packages, which states the " +
+		"intent in one call and is often faster.",
+}
@@ -1,8 +1,13 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"go/token"
 	"gophercheck/internal/config"
+	"strings"
+	"time"
 )
 
 type Severity int
@@ -29,6 +34,27 @@ func (s Severity) String() string {
 	}
 }
 
+// ParseSeverity returns the severity named LOW, MEDIUM, HIGH, or CRITICAL,
+// ignoring case
+func ParseSeverity(name string) (Severity, bool) {
+	for s := SeverityLow; s <= SeverityCritical; s++ {
+		if strings.EqualFold(s.String(), name) {
+			return s, true
+		}
+	}
+	return SeverityLow, false
+}
+
+// Downgrade lowers a severity by the given number of levels, stopping at LOW
+func (s Severity) Downgrade(levels int) Severity {
+	return max(s-Severity(levels), SeverityLow)
+}
+
+// Upgrade raises a severity by the given number of levels, stopping at CRITICAL
+func (s Severity) Upgrade(levels int) Severity {
+	return min(s+Severity(levels), SeverityCritical)
+}
+
 type IssueType string
 
 const (
@@ -36,12 +62,84 @@ const (
 	IssueStringConcat      IssueType = "string_concatenation"
 	IssueInefficinetDS     IssueType = "inefficient_data_structure"
 	IssueCyclomaticComplex IssueType = "cyclomatic_complexity"
+	IssueTypeComplexity    IssueType = "type_complexity" // Types whose methods are too complex together
+	IssueIfChain           IssueType = "if_chain"        // else-if chains comparing one value to constants
 	IssueMemoryAlloc       IssueType = "memory_allocation"
-	IssueSliceGrowth       IssueType = "slice_growth"    // New: Slice growth patterns
-	IssueFunctionLength    IssueType = "function_length" // New: Function length analysis
-	IssueImportCycle       IssueType = "import_cycle"    // New: Import cycle detection
+	IssueSliceGrowth       IssueType = "slice_growth"         // New: Slice growth patterns
+	IssueFunctionLength    IssueType = "function_length"      // New: Function length analysis
+	IssueImportCycle       IssueType = "import_cycle"         // New: Import cycle detection
+	IssueHubPackage        IssueType = "hub_package"          // Packages both widely imported and importing widely
+	IssueDependencyRule    IssueType = "dependency_violation" // Imports dependency_rules forbid
+	IssueAppendMisuse      IssueType = "append_misuse"        // Dropped, aliased, or self-appended results
+	IssueMapMutation       IssueType = "map_mutation"         // Map changed while ranging over it
+	IssueConcurrentMap     IssueType = "concurrent_map_write" // Unsynchronized map writes from goroutines
+	IssueSplitInLoop       IssueType = "split_in_loop"        // Repeated Split/Fields/Join in loops
+	IssueLogInLoop         IssueType = "log_in_loop"          // Logging in loops and hot functions
+	IssueLargeReceiver     IssueType = "large_value_receiver" // Value receivers copying large structs
+	IssueSliceRetention    IssueType = "slice_retention"      // Small subslices pinning large buffers
+	IssueBuilderMisuse     IssueType = "builder_misuse"       // Missing Grow, copied builders, Fprintf for plain strings
+	IssueReadAll           IssueType = "read_all"             // Whole inputs read into memory to be scanned once
+	IssueHandlerAlloc      IssueType = "handler_allocation"   // Constant setup redone on every HTTP request
+	IssueSingleCaseSelect  IssueType = "single_case_select"   // select with one case
+	IssueBusyWait          IssueType = "busy_wait"            // Loops spinning without blocking
+	IssueErrorfInLoop      IssueType = "errorf_in_loop"       // Errors formatted every iteration, used only on failure
+	IssueInefficientSort   IssueType = "inefficient_sort"     // Hand-rolled O(n²) sorts and sorting in loops
+	IssueStdlibLoop        IssueType = "stdlib_loop"          // Loops a slices or maps function replaces
+	IssueSprintfKey        IssueType = "sprintf_map_key"      // Map keys formatted with fmt.Sprintf per lookup
+	IssueSequentialIO      IssueType = "sequential_io"        // Independent expensive calls made one at a time in a loop
+	IssueConversionCache   IssueType = "conversion_cache"     // The same few values converted to strings in loops
+	IssueAllocFreeAPI      IssueType = "alloc_free_api"       // Allocating calls with allocation-free alternatives in hot code
 )
 
+// FixEffort is a rough estimate of how much work resolving an issue takes
+type FixEffort string
+
+const (
+	EffortTrivial FixEffort = "trivial" // One-line, mechanical change
+	EffortSmall   FixEffort = "small"   // Local refactor within a function
+	EffortLarge   FixEffort = "large"   // Redesign across functions or packages
+)
+
+// Cost returns a relative cost used to rank fixes; unknown efforts rank as small
+func (e FixEffort) Cost() int {
+	switch e {
+	case EffortTrivial:
+		return 1
+	case EffortLarge:
+		return 5
+	default:
+		return 2
+	}
+}
+
+// ParseFixEffort validates a fix effort name from config or flags
+func ParseFixEffort(s string) (FixEffort, bool) {
+	switch FixEffort(s) {
+	case EffortTrivial, EffortSmall, EffortLarge:
+		return FixEffort(s), true
+	}
+	return "", false
+}
+
+// AllCategories lists every rule category in display order
+var AllCategories = []string{"performance", "complexity", "memory", "quality", "concurrency"}
+
+// Category returns the rule category an issue type is scored under
+func (t IssueType) Category() string {
+	switch t {
+	case IssueCyclomaticComplex, IssueTypeComplexity, IssueFunctionLength, IssueIfChain:
+		return "complexity"
+	case IssueMemoryAlloc, IssueSliceGrowth, IssueAppendMisuse, IssueLargeReceiver, IssueSliceRetention, IssueReadAll, IssueAllocFreeAPI:
+		return "memory"
+	case IssueImportCycle, IssueHubPackage, IssueDependencyRule, IssueMapMutation, IssueStdlibLoop:
+		return "quality"
+	case IssueSingleCaseSelect, IssueBusyWait, IssueConcurrentMap:
+		return "concurrency"
+	default:
+		return "performance"
+	}
+}
+
 type Issue struct {
 	Type        IssueType `json:"type"`
 	Severity    Severity  `json:"severity"`
@@ -51,8 +149,153 @@ type Issue struct {
 	Function    string    `json:"function,omitempty"`
 	Message     string    `json:"message"`
 	Suggestion  string    `json:"suggestion"`
+	DocsURL     string    `json:"docs_url,omitempty"`   // Anchor of Type in the rule reference
 	Complexity  string    `json:"complexity,omitempty"` // e.g., "O(n²)", "O(n)"
 	CodeSnippet string    `json:"code_snippet,omitempty"`
+	Confidence  float64   `json:"confidence"` // 0.0-1.0, how sure the detector is
+
+	// Other detectors that flagged the same statement (see MergeDuplicateIssues)
+	RelatedTypes []IssueType `json:"related_types,omitempty"`
+
+	Impact    string    `json:"impact,omitempty"` // e.g., "O(n²)→O(n)", "1 allocation instead of n"
+	FixEffort FixEffort `json:"fix_effort,omitempty"`
+
+	Module string `json:"module,omitempty"` // Path of the Go module containing File
+
+	// Mechanical rewrite that resolves the issue, when one is known
+	SuggestedFix *SuggestedFix `json:"suggested_fix,omitempty"`
+
+	// Packages of an import cycle in order, starting with the one reported
+	// (import_cycle only)
+	Cycle []CycleImport `json:"cycle,omitempty"`
+
+	// Owning teams of File from CODEOWNERS, and the last commit to touch Line
+	// (see the ownership config)
+	Owners []string   `json:"owners,omitempty"`
+	Blame  *LineBlame `json:"blame,omitempty"`
+
+	// "new" or "pre-existing" relative to AnalysisResult.Since, when set
+	Age string `json:"age,omitempty"`
+}
+
+// LineBlame is the last commit to touch a line, from git blame
+type LineBlame struct {
+	Author string    `json:"author"`
+	Email  string    `json:"email,omitempty"`
+	Commit string    `json:"commit"`
+	Time   time.Time `json:"time"`
+}
+
+// Owner names who an issue is routed to: its CODEOWNERS owners, else the
+// author of the flagged line, else "" when neither is known
+func (i Issue) Owner() string {
+	if len(i.Owners) > 0 {
+		return strings.Join(i.Owners, " ")
+	}
+	if i.Blame != nil {
+		if i.Blame.Email != "" {
+			return i.Blame.Email
+		}
+		return i.Blame.Author
+	}
+	return ""
+}
+
+// SuggestedFix is a mechanical rewrite that resolves an issue
+type SuggestedFix struct {
+	Description string     `json:"description"`
+	Safety      FixSafety  `json:"safety"`         // How safely the fix can be applied without review
+	Diff        string     `json:"diff,omitempty"` // Unified diff of the rewrite, filled in by the analyzer
+	Edits       []TextEdit `json:"-"`
+
+	// Edits by position for editors to apply as a quick fix, filled in by
+	// the analyzer along with Diff
+	TextEdits []RangeEdit `json:"edits,omitempty"`
+}
+
+// FixSafety says whether a suggested fix can be applied without review
+type FixSafety string
+
+const (
+	FixSafe       FixSafety = "safe"        // Preserves behavior in every case the detector checked
+	FixLikelySafe FixSafety = "likely-safe" // Preserves behavior unless the code relies on something the detector can't see
+	FixUnsafe     FixSafety = "unsafe"      // Needs review: may change behavior
+)
+
+// rank orders safety levels from unsafe (0) to safe (2). Unknown levels
+// count as unsafe.
+func (s FixSafety) rank() int {
+	switch s {
+	case FixSafe:
+		return 2
+	case FixLikelySafe:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AtLeast reports whether s is as safe as minimum or safer
+func (s FixSafety) AtLeast(minimum FixSafety) bool {
+	return s.rank() >= minimum.rank()
+}
+
+// TextEdit replaces the bytes [Start, End) of a file with NewText. Start ==
+// End inserts.
+type TextEdit struct {
+	Start   int
+	End     int
+	NewText string
+}
+
+// RangeEdit is a TextEdit by position, in the shape of an LSP TextEdit:
+// lines and characters count from 0, characters in UTF-16 code units, and
+// End is exclusive. Unlike Diff, the edited code is not gofmt'd.
+type RangeEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// ImpactScore ranks issues by payoff per unit of effort so the cheapest big
+// wins sort first
+func (i *Issue) ImpactScore() float64 {
+	return float64(int(i.Severity)+1) * i.Confidence / float64(i.FixEffort.Cost())
+}
+
+// DeclaredFunction returns the declared function or method an issue's
+// Function names. Issues inside function literals name the literal after the
+// function around it, e.g. Outer.func1 or Server.Handle.func1, so this is
+// the part before the literal's.
+func (i *Issue) DeclaredFunction() string {
+	name := i.Function
+	for offset := 0; ; {
+		at := strings.Index(name[offset:], ".func")
+		if at < 0 {
+			return name
+		}
+		at += offset
+		if rest := name[at+len(".func"):]; rest != "" && rest[0] >= '0' && rest[0] <= '9' {
+			return strings.TrimSuffix(name[:at], ".") // glob..func1 is in package-level code
+		}
+		offset = at + 1
+	}
+}
+
+// Fingerprint identifies an issue across runs. It leaves out the line and
+// column so the issue keeps its fingerprint when code above it moves.
+func (i *Issue) Fingerprint() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{string(i.Type), i.File, i.Function, i.Message}, "\x00")))
+	return hex.EncodeToString(sum[:8])
 }
 
 func (i *Issue) Position() token.Pos {
@@ -60,17 +303,123 @@ func (i *Issue) Position() token.Pos {
 }
 
 type AnalysisResult struct {
+	SchemaVersion    string         `json:"schema_version"`
 	Files            []string       `json:"files_analyzed"`
 	TotalIssues      int            `json:"total_issues"`
+	TestIssues       int            `json:"test_issues"` // Subset of TotalIssues found in _test.go files
 	IssuesBySeverity map[string]int `json:"issues_by_severity"`
 	Issues           []Issue        `json:"issues"`
 	PerformanceScore int            `json:"performance_score"` // 0-100 scale
 	AnalysisDuration string         `json:"analysis_duration"`
 	Config           *config.Config `json:"-"` // Don't serialize config in JSON
+
+	// Letter grade for PerformanceScore per the configured grading scale
+	Grade string `json:"grade"`
+
+	// Sub-scores per category (performance, complexity, memory, quality)
+	CategoryScores map[string]int `json:"category_scores"`
+
+	// Issues found but left out of Issues by max_issues limits
+	OmittedIssues int `json:"omitted_issues,omitempty"`
+
+	// Analysis stopped once max_issues was reached; later files weren't analyzed
+	StoppedEarly bool `json:"stopped_early,omitempty"`
+
+	// The time budget ran out before every file was analyzed, so the issues
+	// and score only cover the files analyzed, densest in past issues first
+	Partial    bool   `json:"partial,omitempty"`
+	TimeBudget string `json:"time_budget,omitempty"` // analysis.time_budget, when set
+
+	// Per-module breakdown, sorted by module path. Empty when no analyzed
+	// file belongs to a module.
+	Modules []ModuleSummary `json:"modules,omitempty"`
+
+	// Files left out of the analysis, e.g. for exceeding max_file_size
+	SkippedFiles []SkippedFile `json:"skipped_files,omitempty"`
+
+	// How many files were analyzed and skipped, and why
+	Scope AnalysisScope `json:"scope"`
+
+	// Detectors that panicked on a file; their findings in it are missing
+	DetectorFailures []DetectorFailure `json:"detector_failures,omitempty"`
+
+	// How long each rule took, longest first, and the runs over its budget
+	RuleTimings []RuleTiming `json:"rule_timings,omitempty"`
+	SlowRules   []SlowRule   `json:"slow_rules,omitempty"`
+
+	// Local config settings the organization policy overrode
+	PolicyRejections []string `json:"policy_rejections,omitempty"`
+
+	// Part of the packages this result covers, as "i/n" (--shard); empty for
+	// a whole analysis. Shard results are combined with gophercheck merge.
+	Shard string `json:"shard,omitempty"`
+
+	// Git ref or baseline report issue ages are relative to, and how many
+	// reported issues are new since then
+	Since     string `json:"since,omitempty"`
+	NewIssues int    `json:"new_issues,omitempty"`
+
+	// The baseline report was older than output.baseline_ttl, so all its
+	// issues count as new again
+	BaselineExpired bool `json:"baseline_expired,omitempty"`
+
+	// Findings silenced by //gophercheck:ignore directives, the directives in
+	// effect, and directives past their until date, whose findings are
+	// reported again
+	SuppressedIssues    int           `json:"suppressed_issues,omitempty"`
+	ActiveSuppressions  int           `json:"active_suppressions,omitempty"`
+	ExpiredSuppressions []Suppression `json:"expired_suppressions,omitempty"`
+
+	streamedPenalties map[string]int // Per-category penalty of issues counted via RecordIssue
+}
+
+// SkippedFile is a file that was found but not analyzed
+type SkippedFile struct {
+	File   string `json:"file"`
+	Kind   string `json:"kind"` // SkipExcluded, SkipTooLarge, SkipParseError, or SkipGenerated
+	Reason string `json:"reason"`
+	Size   int64  `json:"size,omitempty"` // In bytes
+}
+
+// DetectorFailure records a detector that panicked while analyzing a file.
+// The rest of the analysis goes on without that detector's findings there.
+type DetectorFailure struct {
+	Rule  string `json:"rule"`
+	File  string `json:"file"`
+	Panic string `json:"panic"`
+}
+
+// ModuleSummary groups results for one Go module of a workspace
+type ModuleSummary struct {
+	Path             string         `json:"path"`
+	Dir              string         `json:"dir"`
+	Files            int            `json:"files"`
+	TotalIssues      int            `json:"total_issues"`
+	IssuesBySeverity map[string]int `json:"issues_by_severity"`
+}
+
+// AddModuleFile counts an analyzed file towards its module's summary
+func (ar *AnalysisResult) AddModuleFile(modulePath, dir string) {
+	ar.moduleSummary(modulePath, dir).Files++
+}
+
+func (ar *AnalysisResult) moduleSummary(modulePath, dir string) *ModuleSummary {
+	for i := range ar.Modules {
+		if ar.Modules[i].Path == modulePath {
+			return &ar.Modules[i]
+		}
+	}
+	ar.Modules = append(ar.Modules, ModuleSummary{
+		Path:             modulePath,
+		Dir:              dir,
+		IssuesBySeverity: make(map[string]int),
+	})
+	return &ar.Modules[len(ar.Modules)-1]
 }
 
 func NewAnalysisResult() *AnalysisResult {
 	return &AnalysisResult{
+		SchemaVersion:    SchemaVersion,
 		Files:            make([]string, 0),
 		Issues:           make([]Issue, 0),
 		IssuesBySeverity: make(map[string]int),
@@ -79,46 +428,39 @@ func NewAnalysisResult() *AnalysisResult {
 
 func (ar *AnalysisResult) AddIssue(issue Issue) {
 	ar.Issues = append(ar.Issues, issue)
-	ar.TotalIssues++
-	ar.IssuesBySeverity[issue.Severity.String()]++
+	ar.countIssue(issue)
 }
 
-func (ar *AnalysisResult) CalculateScore() {
-	if ar.TotalIssues == 0 {
-		ar.PerformanceScore = 100
-		return
+func (ar *AnalysisResult) countIssue(issue Issue) {
+	ar.TotalIssues++
+	ar.IssuesBySeverity[issue.Severity.String()]++
+	if issue.InTestFile() {
+		ar.TestIssues++
 	}
+	if issue.Module != "" {
+		summary := ar.moduleSummary(issue.Module, "")
+		summary.TotalIssues++
+		summary.IssuesBySeverity[issue.Severity.String()]++
+	}
+}
 
-	// Enhanced scoring algorithm with new issue types
-	penalty := 0
-	for _, issue := range ar.Issues {
-		basePenalty := 0
-		switch issue.Severity {
-		case SeverityLow:
-			basePenalty = 5
-		case SeverityMedium:
-			basePenalty = 15
-		case SeverityHigh:
-			basePenalty = 30
-		case SeverityCritical:
-			basePenalty = 50
-		}
-
-		// Apply multipliers for certain issue types
-		switch issue.Type {
-		case IssueCyclomaticComplex, IssueFunctionLength:
-			basePenalty = int(float64(basePenalty) * 1.2) // 20% more penalty for maintainability issues
-		case IssueNestedLoops, IssueMemoryAlloc:
-			basePenalty = int(float64(basePenalty) * 1.5) // 50% more penalty for performance issues
-		case IssueImportCycle:
-			basePenalty = int(float64(basePenalty) * 1.8) // 80% more penalty for architecture issues
-		}
+// InTestFile reports whether the issue was found in a _test.go file
+func (i *Issue) InTestFile() bool {
+	return strings.HasSuffix(i.File, "_test.go")
+}
 
-		penalty += basePenalty
+// RecordIssue counts an issue towards the totals and score without retaining
+// it. Streaming output uses this so huge runs don't hold every issue in memory.
+func (ar *AnalysisResult) RecordIssue(issue Issue) {
+	ar.countIssue(issue)
+	if ar.streamedPenalties == nil {
+		ar.streamedPenalties = make(map[string]int)
 	}
+	ar.streamedPenalties[issue.Type.Category()] += ar.Penalty(issue)
+}
 
-	score := max(100-penalty, 0)
-	ar.PerformanceScore = score
+func (ar *AnalysisResult) CalculateScore() {
+	ar.applyPenalties(100, false)
 }
 
 func NewAnalysisResultWithConfig(cfg *config.Config) *AnalysisResult {
@@ -132,43 +474,139 @@ func (ar *AnalysisResult) CalculateScoreWithConfig() {
 		ar.CalculateScore()
 		return
 	}
-	if ar.TotalIssues == 0 {
-		ar.PerformanceScore = ar.Config.Analysis.ScoreThresholds.Excellent
-		return
-	}
+	ar.applyPenalties(ar.Config.Analysis.ScoreThresholds.Excellent, true)
+}
 
-	penalty := 0
+// applyPenalties sets the overall score, per-category sub-scores, and grade.
+// Each sub-score starts from the same base and only counts its own issues.
+func (ar *AnalysisResult) applyPenalties(base int, useCategories bool) {
+	penalties := make(map[string]int)
+	for category, penalty := range ar.streamedPenalties {
+		penalties[category] += penalty
+	}
 	for _, issue := range ar.Issues {
-		basePenalty := 0
-		switch issue.Severity {
-		case SeverityLow:
-			basePenalty = 5
-		case SeverityMedium:
-			basePenalty = 15
-		case SeverityHigh:
-			basePenalty = 30
-		case SeverityCritical:
-			basePenalty = 50
+		penalties[issue.Type.Category()] += ar.issuePenalty(issue, useCategories)
+	}
+
+	total := 0
+	ar.CategoryScores = make(map[string]int)
+	for _, category := range ar.scoredCategories() {
+		ar.CategoryScores[category] = max(base-penalties[category], 0)
+	}
+	for _, penalty := range penalties {
+		total += penalty
+	}
+
+	ar.PerformanceScore = max(base-total, 0)
+	ar.Grade = ar.gradeFor(ar.PerformanceScore)
+	if !ar.Graded() {
+		ar.PerformanceScore, ar.Grade = 0, ""
+		clear(ar.CategoryScores)
+	}
+}
+
+// Graded reports whether the result covers any analyzed file. One that
+// doesn't, e.g. because the time budget ran out before the first file, has
+// nothing its score could describe: it gets no grade and scores of zero.
+func (ar *AnalysisResult) Graded() bool {
+	return len(ar.Files) > 0
+}
+
+// CategoryFailures describes each category whose sub-score is below its
+// minimum in analysis.min_category_scores, in display order. A partial result
+// fails any minimum: its scores leave out the files the time budget didn't
+// reach, so they can't show the codebase meets it.
+func (ar *AnalysisResult) CategoryFailures() []string {
+	if ar.Config == nil {
+		return nil
+	}
+	if ar.Partial && len(ar.Config.Analysis.MinCategoryScores) > 0 {
+		return []string{fmt.Sprintf("category scores can't be checked against their minimums: the time budget of %s ran out after %d files", ar.TimeBudget, len(ar.Files))}
+	}
+	var failures []string
+	for _, category := range AllCategories {
+		minimum, ok := ar.Config.Analysis.MinCategoryScores[category]
+		if !ok {
+			continue
 		}
+		if score := ar.CategoryScores[category]; score < minimum {
+			failures = append(failures, fmt.Sprintf("%s score %d is below the minimum of %d", category, score, minimum))
+		}
+	}
+	return failures
+}
+
+// scoredCategories lists the categories that get a sub-score
+func (ar *AnalysisResult) scoredCategories() []string {
+	if ar.Config == nil {
+		return AllCategories
+	}
+	return ar.Config.Analysis.EnabledCategories
+}
 
-		switch issue.Type {
-		case IssueCyclomaticComplex, IssueFunctionLength:
-			if ar.containsCategory("complexity") {
-				basePenalty = int(float64(basePenalty) * 1.2)
-			}
-		case IssueNestedLoops, IssueMemoryAlloc:
-			if ar.containsCategory("performance") {
-				basePenalty = int(float64(basePenalty) * 1.5)
-			}
-		case IssueImportCycle:
-			if ar.containsCategory("quality") {
-				basePenalty = int(float64(basePenalty) * 1.8)
-			}
+func (ar *AnalysisResult) gradeFor(score int) string {
+	if ar.Config == nil {
+		return LetterGrade(score)
+	}
+	return GradeFor(score, ar.Config.Analysis.GradingScale)
+}
+
+// Penalty returns the score penalty an issue costs this result
+func (ar *AnalysisResult) Penalty(issue Issue) int {
+	return ar.issuePenalty(issue, ar.Config != nil)
+}
+
+// issuePenalty returns the score penalty for one issue. When useCategories is
+// set, type multipliers only apply to categories enabled in the config.
+func (ar *AnalysisResult) issuePenalty(issue Issue, useCategories bool) int {
+	basePenalty := 0
+	switch issue.Severity {
+	case SeverityLow:
+		basePenalty = 5
+	case SeverityMedium:
+		basePenalty = 15
+	case SeverityHigh:
+		basePenalty = 30
+	case SeverityCritical:
+		basePenalty = 50
+	}
+
+	// Apply multipliers for certain issue types
+	switch issue.Type {
+	case IssueCyclomaticComplex, IssueTypeComplexity, IssueFunctionLength:
+		if !useCategories || ar.containsCategory("complexity") {
+			basePenalty = int(float64(basePenalty) * 1.2) // 20% more penalty for maintainability issues
+		}
+	case IssueNestedLoops, IssueMemoryAlloc:
+		if !useCategories || ar.containsCategory("performance") {
+			basePenalty = int(float64(basePenalty) * 1.5) // 50% more penalty for performance issues
 		}
-		penalty += basePenalty
+	case IssueImportCycle:
+		if !useCategories || ar.containsCategory("quality") {
+			basePenalty = int(float64(basePenalty) * 1.8) // 80% more penalty for architecture issues
+		}
+	}
+
+	return basePenalty
+}
+
+// LetterGrade maps a 0-100 score to a grade using the default A-F scale
+func LetterGrade(score int) string {
+	return GradeFor(score, config.DefaultGradingScale())
+}
+
+// GradeFor returns the first grade in scale whose minimum the score reaches.
+// The scale is expected in descending order of MinScore.
+func GradeFor(score int, scale []config.GradeBand) string {
+	for _, band := range scale {
+		if score >= band.MinScore {
+			return band.Grade
+		}
+	}
+	if len(scale) > 0 {
+		return scale[len(scale)-1].Grade
 	}
-	score := max(ar.Config.Analysis.ScoreThresholds.Excellent-penalty, 0)
-	ar.PerformanceScore = score
+	return ""
 }
 
 func (ar *AnalysisResult) containsCategory(category string) bool {
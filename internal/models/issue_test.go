@@ -0,0 +1,45 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"gophercheck/internal/config"
+)
+
+func TestCategoryFailuresRefusePartialResults(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Analysis.MinCategoryScores = map[string]int{"memory": 50}
+
+	complete := part(cfg, []string{"a.go"}, loopA)
+	if failures := complete.CategoryFailures(); len(failures) != 0 {
+		t.Errorf("complete result failures = %v, want none", failures)
+	}
+
+	partial := part(cfg, []string{"a.go"}, loopA)
+	partial.Partial, partial.TimeBudget = true, "1s"
+	failures := partial.CategoryFailures()
+	if len(failures) != 1 || !strings.Contains(failures[0], "time budget of 1s") {
+		t.Errorf("partial result failures = %v, want one naming the time budget", failures)
+	}
+
+	// Without minimums there is no gate to refuse
+	partial.Config = config.DefaultConfig()
+	if failures := partial.CategoryFailures(); len(failures) != 0 {
+		t.Errorf("partial result without minimums failures = %v, want none", failures)
+	}
+}
+
+func TestResultWithoutFilesIsNotGraded(t *testing.T) {
+	for _, cfg := range []*config.Config{nil, config.DefaultConfig()} {
+		empty := part(cfg, nil)
+		if empty.Graded() || empty.Grade != "" || empty.PerformanceScore != 0 || len(empty.CategoryScores) != 0 {
+			t.Errorf("no files: graded %v, score %d (%q), categories %v; want ungraded 0", empty.Graded(), empty.PerformanceScore, empty.Grade, empty.CategoryScores)
+		}
+
+		clean := part(cfg, []string{"a.go"})
+		if !clean.Graded() || clean.Grade == "" || clean.PerformanceScore == 0 {
+			t.Errorf("one clean file: graded %v, score %d (%q); want a grade", clean.Graded(), clean.PerformanceScore, clean.Grade)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"gophercheck/internal/config"
+)
+
+// MergeResults combines results of analyzing different, possibly
+// overlapping, sets of files into one, with issue counts and the score
+// computed over all of them under cfg (nil for the defaults). A file analyzed
+// by several results is listed once, and issues are deduplicated by
+// fingerprint: each is kept as often as the result reporting it most often
+// has it. The results must retain their issues, which streamed results don't.
+func MergeResults(cfg *config.Config, results ...*AnalysisResult) *AnalysisResult {
+	merged := NewAnalysisResult()
+	merged.Config = cfg
+
+	files := make(map[string]bool)
+	kept := make(map[string]int) // Copies of each fingerprint kept so far
+	for _, result := range results {
+		overlaps := slices.ContainsFunc(result.Files, func(file string) bool { return files[file] })
+		for _, file := range result.Files {
+			if !files[file] {
+				files[file] = true
+				merged.Files = append(merged.Files, file)
+			}
+		}
+		for _, module := range result.Modules {
+			summary := merged.moduleSummary(module.Path, module.Dir)
+			if overlaps {
+				// Which of the files are new is unknown per module
+				summary.Files = max(summary.Files, module.Files)
+			} else {
+				summary.Files += module.Files
+			}
+		}
+
+		seen := make(map[string]int) // Copies of each fingerprint in this result
+		for _, issue := range result.Issues {
+			fingerprint := issue.Fingerprint()
+			seen[fingerprint]++
+			if seen[fingerprint] > kept[fingerprint] {
+				kept[fingerprint]++
+				merged.Issues = append(merged.Issues, issue)
+			}
+		}
+
+		merged.mergeNotes(result)
+	}
+
+	merged.Canonicalize()
+	merged.Recompute()
+	merged.UpdateScope()
+	return merged
+}
+
+// mergeNotes adds everything besides files and issues that other reports.
+// Skipped files, detector failures, and expired suppressions that results
+// overlapping on files both report are listed once, like the files.
+func (ar *AnalysisResult) mergeNotes(other *AnalysisResult) {
+	for _, skipped := range other.SkippedFiles {
+		if !slices.Contains(ar.SkippedFiles, skipped) {
+			ar.SkippedFiles = append(ar.SkippedFiles, skipped)
+		}
+	}
+	for _, failure := range other.DetectorFailures {
+		if !slices.Contains(ar.DetectorFailures, failure) {
+			ar.DetectorFailures = append(ar.DetectorFailures, failure)
+		}
+	}
+	ar.SlowRules = append(ar.SlowRules, other.SlowRules...)
+	for _, expired := range other.ExpiredSuppressions {
+		if !slices.ContainsFunc(ar.ExpiredSuppressions, func(s Suppression) bool {
+			return s.File == expired.File && s.Line == expired.Line
+		}) {
+			ar.ExpiredSuppressions = append(ar.ExpiredSuppressions, expired)
+		}
+	}
+	ar.SuppressedIssues += other.SuppressedIssues
+	ar.ActiveSuppressions += other.ActiveSuppressions
+	for _, timing := range other.RuleTimings {
+		ar.AddRuleTiming(timing)
+	}
+	if len(ar.PolicyRejections) == 0 {
+		ar.PolicyRejections = other.PolicyRejections // Every part ran with the same config
+	}
+
+	ar.OmittedIssues += other.OmittedIssues
+	ar.StoppedEarly = ar.StoppedEarly || other.StoppedEarly
+	ar.Partial = ar.Partial || other.Partial
+	if ar.TimeBudget == "" {
+		ar.TimeBudget = other.TimeBudget
+	}
+	if ar.Since == "" {
+		ar.Since = other.Since
+	}
+	ar.BaselineExpired = ar.BaselineExpired || other.BaselineExpired
+
+	// The parts ran side by side, so the slowest one is the wall-clock time
+	if mine, err := time.ParseDuration(ar.AnalysisDuration); err != nil || longer(other.AnalysisDuration, mine) {
+		ar.AnalysisDuration = other.AnalysisDuration
+	}
+}
+
+// Recompute sorts the issues into the canonical order and recounts them and
+// the score from scratch, after Issues was changed directly
+func (ar *AnalysisResult) Recompute() {
+	SortIssues(ar.Issues)
+
+	ar.TotalIssues, ar.TestIssues, ar.NewIssues = 0, 0, 0
+	ar.IssuesBySeverity = make(map[string]int)
+	for i := range ar.Modules {
+		ar.Modules[i].TotalIssues = 0
+		ar.Modules[i].IssuesBySeverity = make(map[string]int)
+	}
+	ar.streamedPenalties = nil
+	for _, issue := range ar.Issues {
+		ar.countIssue(issue)
+		if issue.Age == AgeNew {
+			ar.NewIssues++
+		}
+	}
+
+	if ar.Config != nil {
+		ar.CalculateScoreWithConfig()
+	} else {
+		ar.CalculateScore()
+	}
+}
+
+// longer reports whether the duration string is longer than d
+func longer(duration string, d time.Duration) bool {
+	parsed, err := time.ParseDuration(duration)
+	return err == nil && parsed > d
+}
@@ -0,0 +1,202 @@
+package models
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"gophercheck/internal/config"
+)
+
+func issue(rule IssueType, severity Severity, file string, line int, function string) Issue {
+	return Issue{Type: rule, Severity: severity, File: file, Line: line, Function: function, Message: string(rule) + " in " + function}
+}
+
+var (
+	loopA   = issue(IssueNestedLoops, SeverityMedium, "a.go", 10, "A")
+	concatA = issue(IssueStringConcat, SeverityMedium, "a.go", 20, "A")
+	growthB = issue(IssueSliceGrowth, SeverityMedium, "b.go", 5, "B")
+	complxB = issue(IssueCyclomaticComplex, SeverityLow, "b.go", 1, "B")
+	cycleC  = issue(IssueImportCycle, SeverityLow, "c.go", 3, "")
+
+	// Same fingerprint as loopA: it leaves out the line
+	loopAMoved = issue(IssueNestedLoops, SeverityMedium, "a.go", 30, "A")
+)
+
+// part is a result of analyzing some files, as a shard or a watch-mode
+// update would report it
+func part(cfg *config.Config, files []string, issues ...Issue) *AnalysisResult {
+	result := NewAnalysisResultWithConfig(cfg)
+	result.Files = files
+	for _, issue := range issues {
+		result.AddIssue(issue)
+	}
+	result.CalculateScoreWithConfig()
+	return result
+}
+
+func TestMergeResultsMatchesFullRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		parts [][]Issue
+		files [][]string
+		full  []Issue // What analyzing every file at once reports
+	}{
+		{
+			name:  "disjoint shards",
+			files: [][]string{{"a.go"}, {"b.go", "c.go"}},
+			parts: [][]Issue{{loopA, concatA}, {growthB, complxB, cycleC}},
+			full:  []Issue{loopA, concatA, growthB, complxB, cycleC},
+		},
+		{
+			name:  "overlapping shards",
+			files: [][]string{{"a.go", "b.go"}, {"b.go", "c.go"}},
+			parts: [][]Issue{{loopA, concatA, growthB, complxB}, {growthB, complxB, cycleC}},
+			full:  []Issue{loopA, concatA, growthB, complxB, cycleC},
+		},
+		{
+			name:  "same shard twice",
+			files: [][]string{{"a.go", "b.go"}, {"a.go", "b.go"}},
+			parts: [][]Issue{{loopA, growthB}, {loopA, growthB}},
+			full:  []Issue{loopA, growthB},
+		},
+		{
+			name:  "repeated fingerprint in one result",
+			files: [][]string{{"a.go"}, {"a.go"}},
+			parts: [][]Issue{{loopA, loopAMoved}, {loopA}},
+			full:  []Issue{loopA, loopAMoved},
+		},
+		{
+			name:  "repeated fingerprint across results",
+			files: [][]string{{"a.go"}, {"a.go"}},
+			parts: [][]Issue{{loopA}, {loopA, loopAMoved}},
+			full:  []Issue{loopA, loopAMoved},
+		},
+		{
+			name:  "no issues",
+			files: [][]string{{"a.go"}, {"b.go"}},
+			parts: [][]Issue{{}, {}},
+			full:  nil,
+		},
+		{
+			name:  "single result",
+			files: [][]string{{"a.go", "b.go", "c.go"}},
+			parts: [][]Issue{{loopA, concatA, growthB, complxB, cycleC}},
+			full:  []Issue{loopA, concatA, growthB, complxB, cycleC},
+		},
+	}
+
+	configs := []struct {
+		name string
+		cfg  *config.Config
+	}{
+		{"nil config", nil},
+		{"default config", config.DefaultConfig()},
+		{"strict config", strictConfig()},
+	}
+
+	for _, test := range tests {
+		for _, c := range configs {
+			t.Run(test.name+"/"+c.name, func(t *testing.T) {
+				var parts []*AnalysisResult
+				var allFiles []string
+				for i, issues := range test.parts {
+					parts = append(parts, part(c.cfg, test.files[i], issues...))
+					for _, file := range test.files[i] {
+						if !slices.Contains(allFiles, file) {
+							allFiles = append(allFiles, file)
+						}
+					}
+				}
+				merged := MergeResults(c.cfg, parts...)
+				full := part(c.cfg, allFiles, test.full...)
+
+				if merged.TotalIssues != full.TotalIssues || len(merged.Issues) != len(full.Issues) {
+					t.Errorf("total issues = %d (%d listed), want %d", merged.TotalIssues, len(merged.Issues), full.TotalIssues)
+				}
+				if !maps.Equal(merged.IssuesBySeverity, full.IssuesBySeverity) {
+					t.Errorf("issues by severity = %v, want %v", merged.IssuesBySeverity, full.IssuesBySeverity)
+				}
+				if merged.PerformanceScore != full.PerformanceScore || merged.Grade != full.Grade {
+					t.Errorf("score = %d (%s), want %d (%s)", merged.PerformanceScore, merged.Grade, full.PerformanceScore, full.Grade)
+				}
+				if !maps.Equal(merged.CategoryScores, full.CategoryScores) {
+					t.Errorf("category scores = %v, want %v", merged.CategoryScores, full.CategoryScores)
+				}
+				if len(merged.Files) != len(allFiles) {
+					t.Errorf("files = %v, want %v", merged.Files, allFiles)
+				}
+			})
+		}
+	}
+}
+
+// strictConfig scores on a lower base with a category turned off
+func strictConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Analysis.ScoreThresholds.Excellent = 80
+	cfg.Analysis.EnabledCategories = []string{"performance", "complexity", "memory"}
+	return cfg
+}
+
+func TestMergeResultsConfigChangesScore(t *testing.T) {
+	issues := []Issue{complxB, concatA}
+	withDefaults := MergeResults(nil, part(nil, []string{"a.go"}, issues...))
+	strict := MergeResults(strictConfig(), part(nil, []string{"a.go"}, issues...))
+
+	// Recomputed under the merge's config, not the parts'
+	if want := part(strictConfig(), []string{"a.go"}, issues...).PerformanceScore; strict.PerformanceScore != want {
+		t.Errorf("strict score = %d, want %d", strict.PerformanceScore, want)
+	}
+	if want := part(nil, []string{"a.go"}, issues...).PerformanceScore; withDefaults.PerformanceScore != want {
+		t.Errorf("default score = %d, want %d", withDefaults.PerformanceScore, want)
+	}
+	if strict.PerformanceScore == withDefaults.PerformanceScore {
+		t.Errorf("strict and default configs both score %d", strict.PerformanceScore)
+	}
+}
+
+func TestMergeResultsDedupesNotesOfOverlappingParts(t *testing.T) {
+	notes := func() *AnalysisResult {
+		result := part(nil, []string{"a.go"}, loopA)
+		result.SkippedFiles = []SkippedFile{{File: "gen.go", Kind: SkipGenerated, Reason: "generated code"}}
+		result.DetectorFailures = []DetectorFailure{{Rule: "nested_loops", File: "a.go", Panic: "boom"}}
+		result.ExpiredSuppressions = []Suppression{{File: "a.go", Line: 9, Rules: []string{"nested_loops"}, Until: "2020-01-01", Expired: true, Matched: 1}}
+		return result
+	}
+	other := part(nil, []string{"b.go"}, growthB)
+	other.SkippedFiles = []SkippedFile{{File: "big.go", Kind: SkipTooLarge, Reason: "too large"}}
+
+	merged := MergeResults(nil, notes(), notes(), other)
+	if len(merged.SkippedFiles) != 2 {
+		t.Errorf("skipped files = %v, want gen.go and big.go once each", merged.SkippedFiles)
+	}
+	if len(merged.DetectorFailures) != 1 {
+		t.Errorf("detector failures = %v, want one", merged.DetectorFailures)
+	}
+	if len(merged.ExpiredSuppressions) != 1 {
+		t.Errorf("expired suppressions = %v, want one", merged.ExpiredSuppressions)
+	}
+	if merged.TotalIssues != 2 {
+		t.Errorf("total issues = %d, want 2", merged.TotalIssues)
+	}
+}
+
+func TestRecomputeCountsFromScratch(t *testing.T) {
+	result := part(config.DefaultConfig(), []string{"a.go", "b.go"}, loopA, concatA, growthB)
+	want := part(config.DefaultConfig(), []string{"a.go", "b.go"}, loopA, growthB)
+
+	// Dropped directly, leaving the counts stale
+	result.Issues = []Issue{growthB, loopA}
+	result.Recompute()
+
+	if result.TotalIssues != want.TotalIssues || !maps.Equal(result.IssuesBySeverity, want.IssuesBySeverity) {
+		t.Errorf("counts = %d %v, want %d %v", result.TotalIssues, result.IssuesBySeverity, want.TotalIssues, want.IssuesBySeverity)
+	}
+	if result.PerformanceScore != want.PerformanceScore || !maps.Equal(result.CategoryScores, want.CategoryScores) {
+		t.Errorf("score = %d %v, want %d %v", result.PerformanceScore, result.CategoryScores, want.PerformanceScore, want.CategoryScores)
+	}
+	if result.Issues[0].Severity < result.Issues[1].Severity {
+		t.Errorf("issues not in canonical order: %v then %v", result.Issues[0].Severity, result.Issues[1].Severity)
+	}
+}
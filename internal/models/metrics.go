@@ -0,0 +1,36 @@
+package models
+
+// FunctionMetrics are the raw size and complexity measurements of one
+// function, reported whether or not they cross any rule threshold
+type FunctionMetrics struct {
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	EndLine    int    `json:"end_line"`
+	Function   string `json:"function"`
+	Receiver   string `json:"receiver,omitempty"` // Receiver type of methods, e.g. "*Server"
+	LOC        int    `json:"loc"`                // Lines holding code
+	Cyclomatic int    `json:"cyclomatic"`
+	Cognitive  int    `json:"cognitive"`
+	Nesting    int    `json:"nesting_depth"` // Deepest nesting of control flow
+	Params     int    `json:"params"`
+	Issues     int    `json:"issues"` // Issues reported inside the function
+}
+
+// QualifiedName returns the function name, prefixed by the receiver type for
+// methods, e.g. "(*Server).Handle"
+func (m FunctionMetrics) QualifiedName() string {
+	if m.Receiver == "" {
+		return m.Function
+	}
+	return "(" + m.Receiver + ")." + m.Function
+}
+
+// PackageMetrics are the coupling measurements of one package in the import
+// graph of the analyzed packages
+type PackageMetrics struct {
+	Package     string  `json:"package"`
+	FanIn       int     `json:"fan_in"`        // Analyzed packages importing it
+	FanOut      int     `json:"fan_out"`       // Analyzed packages it imports
+	Instability float64 `json:"instability"`   // FanOut / (FanIn + FanOut): 0 depended on, 1 only depending
+	Hub         bool    `json:"hub,omitempty"` // Over both package_coupling hub thresholds
+}
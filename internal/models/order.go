@@ -0,0 +1,46 @@
+package models
+
+import (
+	"cmp"
+	"slices"
+)
+
+// CompareIssues is the canonical order of issues in every report: most
+// severe first, then by file, line, column, and rule. The message breaks any
+// remaining tie, so the order never depends on how the issues were collected
+// and two reports of the same code diff cleanly.
+func CompareIssues(a, b Issue) int {
+	return cmp.Or(
+		cmp.Compare(b.Severity, a.Severity),
+		cmp.Compare(a.File, b.File),
+		cmp.Compare(a.Line, b.Line),
+		cmp.Compare(a.Column, b.Column),
+		cmp.Compare(a.Type, b.Type),
+		cmp.Compare(a.Message, b.Message),
+	)
+}
+
+// SortIssues sorts issues in place into the canonical order
+func SortIssues(issues []Issue) {
+	slices.SortStableFunc(issues, CompareIssues)
+}
+
+// Canonicalize sorts every list of a result into a fixed order: files by
+// path, modules by module path, and issues canonically. Reports of the same
+// code are then byte-identical however the files were collected or in what
+// order they finished analyzing.
+func (ar *AnalysisResult) Canonicalize() {
+	slices.Sort(ar.Files)
+	slices.SortFunc(ar.SkippedFiles, func(a, b SkippedFile) int {
+		return cmp.Compare(a.File, b.File)
+	})
+	slices.SortFunc(ar.DetectorFailures, func(a, b DetectorFailure) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Rule, b.Rule), cmp.Compare(a.Panic, b.Panic))
+	})
+	ar.sortTimings()
+	ar.sortSuppressions()
+	slices.SortFunc(ar.Modules, func(a, b ModuleSummary) int {
+		return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Dir, b.Dir))
+	})
+	SortIssues(ar.Issues)
+}
@@ -0,0 +1,14 @@
+package models
+
+import _ "embed"
+
+// SchemaVersion is the version of the JSON result format. Within a major
+// version fields are only added, never renamed or removed; any incompatible
+// change bumps the major version.
+const SchemaVersion = "1.20.0"
+
+// ResultSchema is the JSON Schema describing AnalysisResult as emitted by
+// the json output format
+//
+//go:embed schema/result.schema.json
+var ResultSchema []byte
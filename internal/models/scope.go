@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Why a file was skipped, as SkippedFile.Kind
+const (
+	SkipExcluded   = "excluded"    // Matched files.exclude_packages
+	SkipTooLarge   = "too_large"   // Larger than files.max_file_size
+	SkipParseError = "parse_error" // Not valid Go
+	SkipGenerated  = "generated"   // Has a "Code generated ... DO NOT EDIT." header
+	SkipTimeBudget = "time_budget" // Not reached before analysis.time_budget ran out
+)
+
+// skipKinds orders the kinds in scope summaries, with a label for each
+var skipKinds = []struct{ kind, label string }{
+	{SkipExcluded, "excluded"},
+	{SkipGenerated, "generated"},
+	{SkipTooLarge, "too large"},
+	{SkipParseError, "parse errors"},
+	{SkipTimeBudget, "over time budget"},
+}
+
+// AnalysisScope summarizes what an analysis covered, so a file missing from
+// the report can be told apart from a clean one
+type AnalysisScope struct {
+	Analyzed      int            `json:"analyzed"`
+	Skipped       map[string]int `json:"skipped,omitempty"`        // Skipped files by SkippedFile.Kind
+	TestsExcluded bool           `json:"tests_excluded,omitempty"` // _test.go files were left out (include_tests off)
+}
+
+// UpdateScope recounts Scope from the analyzed and skipped files
+func (ar *AnalysisResult) UpdateScope() {
+	ar.Scope = AnalysisScope{Analyzed: len(ar.Files)}
+	if ar.Config != nil {
+		ar.Scope.TestsExcluded = !ar.Config.Files.IncludeTests
+	}
+	for _, skipped := range ar.SkippedFiles {
+		if ar.Scope.Skipped == nil {
+			ar.Scope.Skipped = make(map[string]int)
+		}
+		ar.Scope.Skipped[skipped.Kind]++
+	}
+}
+
+// String is the scope on one line, e.g. "42 files analyzed, 3 skipped (2
+// generated, 1 too large); _test.go files not included"
+func (s AnalysisScope) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d files analyzed", s.Analyzed)
+
+	skipped := 0
+	var reasons []string
+	for _, kind := range skipKinds {
+		if n := s.Skipped[kind.kind]; n > 0 {
+			skipped += n
+			reasons = append(reasons, fmt.Sprintf("%d %s", n, kind.label))
+		}
+	}
+	if skipped > 0 {
+		fmt.Fprintf(&b, ", %d skipped (%s)", skipped, strings.Join(reasons, ", "))
+	}
+	if s.TestsExcluded {
+		b.WriteString("; _test.go files not included")
+	}
+	return b.String()
+}
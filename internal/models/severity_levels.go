@@ -0,0 +1,75 @@
+package models
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Consumers of severities that name them their own way
+const (
+	ConsumerSARIF    = "sarif"    // SARIF result levels, in --format sarif
+	ConsumerSonar    = "sonar"    // SonarQube issue severities, in --format sonar
+	ConsumerLSP      = "lsp"      // LSP DiagnosticSeverity names, in MCP findings
+	ConsumerQuickfix = "quickfix" // Kinds in quickfix lines, for vim and VS Code problem matchers
+)
+
+// SeverityLevels maps severities to the levels of each consumer, indexed by
+// severity from LOW to CRITICAL
+type SeverityLevels map[string][4]string
+
+var defaultSeverityLevels = SeverityLevels{
+	ConsumerSARIF:    {"note", "warning", "error", "error"},
+	ConsumerSonar:    {"MINOR", "MAJOR", "CRITICAL", "BLOCKER"},
+	ConsumerLSP:      {"information", "warning", "error", "error"},
+	ConsumerQuickfix: {"info", "warning", "error", "error"},
+}
+
+// severityLevelChoices are the levels each consumer understands
+var severityLevelChoices = map[string][]string{
+	ConsumerSARIF:    {"none", "note", "warning", "error"},
+	ConsumerSonar:    {"INFO", "MINOR", "MAJOR", "CRITICAL", "BLOCKER"},
+	ConsumerLSP:      {"hint", "information", "warning", "error"},
+	ConsumerQuickfix: {"info", "warning", "error"},
+}
+
+// NewSeverityLevels returns the default levels with output.severity_levels
+// applied: per consumer, the level of each severity it names, e.g.
+// {"quickfix": {"MEDIUM": "info"}}
+func NewSeverityLevels(overrides map[string]map[string]string) (SeverityLevels, error) {
+	levels := maps.Clone(defaultSeverityLevels)
+	for consumer, severities := range overrides {
+		table, ok := levels[consumer]
+		if !ok {
+			return nil, fmt.Errorf("output.severity_levels: unknown consumer %s (valid: %v)", consumer, slices.Sorted(maps.Keys(levels)))
+		}
+		for name, level := range severities {
+			severity, ok := ParseSeverity(name)
+			if !ok {
+				return nil, fmt.Errorf("output.severity_levels.%s: unknown severity %s (valid: LOW, MEDIUM, HIGH, CRITICAL)", consumer, name)
+			}
+			choices := severityLevelChoices[consumer]
+			index := slices.IndexFunc(choices, func(choice string) bool { return strings.EqualFold(choice, level) })
+			if index < 0 {
+				return nil, fmt.Errorf("output.severity_levels.%s.%s: invalid level %s (valid: %v)", consumer, name, level, choices)
+			}
+			table[severity] = choices[index]
+		}
+		levels[consumer] = table
+	}
+	return levels, nil
+}
+
+// Level returns the level of a severity for a consumer, or the severity's
+// own name for an unknown consumer
+func (l SeverityLevels) Level(consumer string, s Severity) string {
+	table, ok := l[consumer]
+	if !ok {
+		table, ok = defaultSeverityLevels[consumer]
+	}
+	if !ok || s < SeverityLow || s > SeverityCritical {
+		return s.String()
+	}
+	return table[s]
+}
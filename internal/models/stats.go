@@ -0,0 +1,42 @@
+package models
+
+// CodebaseStats summarizes a codebase without listing individual issues
+type CodebaseStats struct {
+	Files     int `json:"files"`
+	Packages  int `json:"packages"`
+	Functions int `json:"functions"`
+	LOC       int `json:"loc"` // Lines holding code, across all files
+
+	FunctionLength Distribution `json:"function_length"` // Function LOC
+	Cyclomatic     Distribution `json:"cyclomatic"`
+	Cognitive      Distribution `json:"cognitive"`
+
+	Largest []FunctionMetrics `json:"largest_functions"` // Longest first
+	Rules   []RuleDensity     `json:"rules"`             // Most issues first
+
+	// Coupling of every package in the import graph, most imported first
+	Coupling []PackageMetrics `json:"package_coupling"`
+
+	TotalIssues      int            `json:"total_issues"`
+	PerformanceScore int            `json:"performance_score"`
+	Grade            string         `json:"grade"`
+	CategoryScores   map[string]int `json:"category_scores"`
+}
+
+// Distribution summarizes one measurement over all functions
+type Distribution struct {
+	Mean float64 `json:"mean"`
+	P50  int     `json:"p50"`
+	P90  int     `json:"p90"`
+	P99  int     `json:"p99"`
+	Max  int     `json:"max"`
+}
+
+// RuleDensity is how often one rule fires, relative to the code size
+type RuleDensity struct {
+	Rule      string  `json:"rule"`
+	Category  string  `json:"category"`
+	Issues    int     `json:"issues"`
+	PerKLOC   float64 `json:"per_kloc"`  // Issues per 1000 lines of code
+	Functions int     `json:"functions"` // Distinct functions with an issue
+}
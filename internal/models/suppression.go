@@ -0,0 +1,54 @@
+package models
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Suppression is a //gophercheck:ignore directive, which silences findings
+// of its rules on the line it ends or the next one, or throughout the
+// function whose doc comment it is in, until its until date has passed
+type Suppression struct {
+	File    string   `json:"file"`
+	Line    int      `json:"line"` // Of the directive
+	Rules   []string `json:"rules"`
+	Until   string   `json:"until,omitempty"` // Last day it applies, YYYY-MM-DD
+	Reason  string   `json:"reason,omitempty"`
+	Expired bool     `json:"expired,omitempty"`
+	Matched int      `json:"matched"` // Findings it silenced, or that resurfaced since it expired
+}
+
+// sortSuppressions puts expired suppressions in file and line order
+func (ar *AnalysisResult) sortSuppressions() {
+	slices.SortFunc(ar.ExpiredSuppressions, func(a, b Suppression) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line))
+	})
+}
+
+// SuppressionAudit lists the exceptions a codebase has accumulated: every
+// //gophercheck:ignore directive and every issue its baseline accepts
+type SuppressionAudit struct {
+	Suppressions []AuditedSuppression `json:"suppressions"`
+
+	Baseline        string          `json:"baseline,omitempty"`
+	BaselineExpired bool            `json:"baseline_expired,omitempty"` // Older than baseline_ttl; it accepts nothing
+	BaselineEntries []BaselineEntry `json:"baseline_entries,omitempty"` // Current issues the baseline accepts
+}
+
+// AuditedSuppression is a directive with who wrote it and when
+type AuditedSuppression struct {
+	Suppression
+	Blame   *LineBlame `json:"blame,omitempty"`    // Last commit to the directive's line
+	AgeDays int        `json:"age_days,omitempty"` // Since that commit
+}
+
+// BaselineEntry is a current issue a baseline accepts as pre-existing
+type BaselineEntry struct {
+	File     string     `json:"file"`
+	Line     int        `json:"line"`
+	Rule     string     `json:"rule"`
+	Function string     `json:"function,omitempty"`
+	Message  string     `json:"message"`
+	Blame    *LineBlame `json:"blame,omitempty"`    // Last commit to the flagged line
+	AgeDays  int        `json:"age_days,omitempty"` // Since that commit
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+)
+
+// RuleTiming is how long one rule's detector spent analyzing
+type RuleTiming struct {
+	Rule        string  `json:"rule"`
+	TotalMS     float64 `json:"total_ms"`
+	Files       int     `json:"files"`
+	SlowestFile string  `json:"slowest_file"`
+	SlowestMS   float64 `json:"slowest_ms"`
+}
+
+// SlowRule records a rule that took longer than analysis.rule_budget on a
+// file. Disabled says the rule was then turned off for the remaining files,
+// whose findings of it are missing.
+type SlowRule struct {
+	Rule       string  `json:"rule"`
+	File       string  `json:"file"`
+	DurationMS float64 `json:"duration_ms"`
+	BudgetMS   float64 `json:"budget_ms"`
+	Disabled   bool    `json:"disabled,omitempty"`
+}
+
+// Milliseconds converts a duration for RuleTiming and SlowRule, to the
+// microsecond
+func Milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Microsecond)) / 1000
+}
+
+// AddRuleTiming adds the time a rule took on some files, e.g. in another
+// shard, to its timing
+func (ar *AnalysisResult) AddRuleTiming(timing RuleTiming) {
+	i := slices.IndexFunc(ar.RuleTimings, func(t RuleTiming) bool { return t.Rule == timing.Rule })
+	if i < 0 {
+		ar.RuleTimings = append(ar.RuleTimings, timing)
+		return
+	}
+	existing := &ar.RuleTimings[i]
+	existing.TotalMS = math.Round((existing.TotalMS+timing.TotalMS)*1000) / 1000
+	existing.Files += timing.Files
+	if timing.SlowestMS > existing.SlowestMS {
+		existing.SlowestFile, existing.SlowestMS = timing.SlowestFile, timing.SlowestMS
+	}
+}
+
+// sortTimings puts the rules that took longest first
+func (ar *AnalysisResult) sortTimings() {
+	slices.SortFunc(ar.RuleTimings, func(a, b RuleTiming) int {
+		return cmp.Or(cmp.Compare(b.TotalMS, a.TotalMS), cmp.Compare(a.Rule, b.Rule))
+	})
+	slices.SortFunc(ar.SlowRules, func(a, b SlowRule) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Rule, b.Rule))
+	})
+}
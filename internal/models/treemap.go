@@ -0,0 +1,18 @@
+package models
+
+// TreemapNode is a directory or file of a treemap. A node's size is its lines
+// of code and its heat is its penalty density; directories sum their children.
+type TreemapNode struct {
+	Name     string         `json:"name"`
+	Path     string         `json:"path"`
+	LOC      int            `json:"loc"`
+	Issues   int            `json:"issues"`
+	Penalty  int            `json:"penalty"` // Score penalty of the node's issues
+	Density  float64        `json:"density"` // Penalty per 100 lines of code
+	Children []*TreemapNode `json:"children,omitempty"`
+}
+
+// IsFile reports whether the node is a file rather than a directory
+func (n *TreemapNode) IsFile() bool {
+	return n.Children == nil
+}
@@ -0,0 +1,327 @@
+// Package notify posts run summaries to Slack and Teams webhooks
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+)
+
+// State is what is kept of the last notified run
+type State struct {
+	Time     time.Time `json:"time"`
+	Score    int       `json:"score"`
+	Critical []string  `json:"critical"`         // Fingerprints of the critical issues
+	Issues   []string  `json:"issues,omitempty"` // Fingerprints of all issues
+}
+
+// NewState records a result for comparing the next run against
+func NewState(result *models.AnalysisResult) State {
+	state := State{Time: time.Now().UTC(), Score: result.PerformanceScore, Critical: []string{}}
+	for i := range result.Issues {
+		fingerprint := result.Issues[i].Fingerprint()
+		if result.Issues[i].Severity == models.SeverityCritical {
+			state.Critical = append(state.Critical, fingerprint)
+		}
+		state.Issues = append(state.Issues, fingerprint)
+	}
+	slices.Sort(state.Critical)
+	slices.Sort(state.Issues)
+	state.Issues = slices.Compact(state.Issues)
+	return state
+}
+
+// LoadState reads the state file at path; nil if there was no earlier run
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// SaveState replaces the state file at path, creating its directory if needed
+func SaveState(path string, state State) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Summary is what a notification says about a run
+type Summary struct {
+	Project     string
+	Score       int
+	Grade       string
+	Previous    *State // nil on the first run
+	TotalIssues int
+	Critical    int
+	MinSeverity models.Severity // Of the issues listed, CRITICAL unless notifications.min_severity is set
+	New         []models.Issue  // Issues at least MinSeverity the previous run didn't have (all of them on the first run)
+}
+
+// Summarize compares a result with the previous run, listing the new issues
+// at least minSeverity
+func Summarize(project string, result *models.AnalysisResult, previous *State, minSeverity models.Severity) Summary {
+	summary := Summary{
+		Project:     project,
+		Score:       result.PerformanceScore,
+		Grade:       result.Grade,
+		Previous:    previous,
+		TotalIssues: result.TotalIssues,
+		MinSeverity: minSeverity,
+	}
+	known := make(map[string]bool)
+	if previous != nil {
+		// State files from before all issues were kept only have the critical ones
+		fingerprints := previous.Issues
+		if fingerprints == nil {
+			fingerprints = previous.Critical
+		}
+		for _, fingerprint := range fingerprints {
+			known[fingerprint] = true
+		}
+	}
+	for _, issue := range result.Issues {
+		if issue.Severity == models.SeverityCritical {
+			summary.Critical++
+		}
+		if issue.Severity >= minSeverity && !known[issue.Fingerprint()] {
+			summary.New = append(summary.New, issue)
+		}
+	}
+	return summary
+}
+
+// headline is the first line of a notification, e.g.
+// "gophercheck: api scored 82 (B), down 3 since the last run"
+func (s Summary) headline() string {
+	change := "first run"
+	if s.Previous != nil {
+		switch delta := s.Score - s.Previous.Score; {
+		case delta > 0:
+			change = fmt.Sprintf("up %d since the last run", delta)
+		case delta < 0:
+			change = fmt.Sprintf("down %d since the last run", -delta)
+		default:
+			change = "unchanged since the last run"
+		}
+	}
+	return fmt.Sprintf("gophercheck: %s scored %d (%s), %s", s.Project, s.Score, s.Grade, change)
+}
+
+func (s Summary) counts() string {
+	counts := fmt.Sprintf("%d issues, %d critical", s.TotalIssues, s.Critical)
+	switch {
+	case s.Previous == nil:
+	case s.MinSeverity < models.SeverityCritical:
+		counts += fmt.Sprintf(" (%d new %s or above)", len(s.New), strings.ToLower(s.MinSeverity.String()))
+	default:
+		counts += fmt.Sprintf(" (%d new)", len(s.New))
+	}
+	return counts
+}
+
+// listTitle heads the list of new issues
+func (s Summary) listTitle() string {
+	title := "critical issues"
+	if s.MinSeverity < models.SeverityCritical {
+		title = "issues of severity " + strings.ToLower(s.MinSeverity.String()) + " or above"
+	}
+	if s.Previous == nil {
+		return strings.ToUpper(title[:1]) + title[1:] + ":"
+	}
+	return "New " + title + ":"
+}
+
+// Notifier posts summaries to the webhooks of a notifications config
+type Notifier struct {
+	config config.NotificationsConfig
+	client *http.Client
+	getenv func(string) string
+	commit string // For {commit} in links, looked up when first needed
+}
+
+func NewNotifier(cfg config.NotificationsConfig, getenv func(string) string) *Notifier {
+	return &Notifier{
+		config: cfg,
+		client: &http.Client{Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second},
+		getenv: getenv,
+	}
+}
+
+// Webhooks reports whether any webhook is configured in the environment
+func (n *Notifier) Webhooks() bool {
+	return n.webhook(n.config.SlackWebhookEnv) != "" || n.webhook(n.config.TeamsWebhookEnv) != ""
+}
+
+// MinSeverity is the least severity of the issues listed: min_severity, or
+// CRITICAL without it
+func (n *Notifier) MinSeverity() models.Severity {
+	if severity, ok := models.ParseSeverity(n.config.MinSeverity); ok {
+		return severity
+	}
+	return models.SeverityCritical
+}
+
+// Withhold returns why the summary isn't sent at now, or "" to send it:
+// during quiet_hours, within throttle_minutes of the last notified run, or
+// when min_severity is set and there is no new issue that severe
+func (n *Notifier) Withhold(summary Summary, now time.Time) string {
+	if n.config.Quiet(now) {
+		return fmt.Sprintf("quiet hours (%s)", n.config.QuietHours)
+	}
+	throttle := time.Duration(n.config.ThrottleMinutes) * time.Minute
+	if summary.Previous != nil && throttle > 0 {
+		if since := now.Sub(summary.Previous.Time); since < throttle {
+			return fmt.Sprintf("the last one was sent %s ago (throttle_minutes: %d)", since.Round(time.Second), n.config.ThrottleMinutes)
+		}
+	}
+	if n.config.MinSeverity != "" && len(summary.New) == 0 {
+		return fmt.Sprintf("no new issues of severity %s or above", strings.ToUpper(n.config.MinSeverity))
+	}
+	return ""
+}
+
+// Send posts the summary to every configured webhook, trying all of them
+// even if one fails
+func (n *Notifier) Send(summary Summary) error {
+	var errs []error
+	if endpoint := n.webhook(n.config.SlackWebhookEnv); endpoint != "" {
+		if err := n.post(endpoint, n.slackPayload(summary)); err != nil {
+			errs = append(errs, fmt.Errorf("notifying Slack: %w", err))
+		}
+	}
+	if endpoint := n.webhook(n.config.TeamsWebhookEnv); endpoint != "" {
+		if err := n.post(endpoint, n.teamsPayload(summary)); err != nil {
+			errs = append(errs, fmt.Errorf("notifying Teams: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) webhook(env string) string {
+	if env == "" {
+		return ""
+	}
+	return n.getenv(env)
+}
+
+// slackPayload formats the summary as Slack mrkdwn
+func (n *Notifier) slackPayload(s Summary) any {
+	escape := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
+	lines := n.format(s, "*%s*", func(label, target string) string {
+		return "<" + target + "|" + escape(label) + ">"
+	}, escape)
+	return map[string]any{"text": strings.Join(lines, "\n")}
+}
+
+// teamsPayload formats the summary as an Adaptive Card, which both Teams
+// workflows and incoming webhook connectors accept
+func (n *Notifier) teamsPayload(s Summary) any {
+	lines := n.format(s, "**%s**", func(label, target string) string {
+		return "[" + label + "](" + target + ")"
+	}, func(text string) string { return text })
+	text := strings.Join(lines, "\n\n") // Cards only break lines between paragraphs
+	card := map[string]any{
+		"type":    "AdaptiveCard",
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"version": "1.4",
+		"body":    []map[string]any{{"type": "TextBlock", "text": text, "wrap": true}},
+	}
+	return map[string]any{
+		"type":        "message",
+		"attachments": []map[string]any{{"contentType": "application/vnd.microsoft.card.adaptive", "content": card}},
+	}
+}
+
+// format renders the summary as lines of markdown, with bold (a format with
+// one %s), link, and escape functions for the chat's dialect
+func (n *Notifier) format(s Summary, bold string, link func(label, target string) string, escape func(string) string) []string {
+	lines := []string{fmt.Sprintf(bold, escape(s.headline())), escape(s.counts())}
+	if len(s.New) == 0 {
+		return lines
+	}
+
+	lines = append(lines, fmt.Sprintf(bold, s.listTitle()))
+	for i, issue := range s.New {
+		if i == n.config.MaxIssues {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(s.New)-i))
+			break
+		}
+		position := fmt.Sprintf("%s:%d", issue.File, issue.Line)
+		if target := n.link(issue); target != "" {
+			position = link(position, target)
+		} else {
+			position = escape(position)
+		}
+		lines = append(lines, fmt.Sprintf("• %s %s: %s", position, issue.Type, escape(issue.Message)))
+	}
+	return lines
+}
+
+// link fills in the link template for an issue, "" without a template
+func (n *Notifier) link(issue models.Issue) string {
+	template := n.config.LinkTemplate
+	if template == "" || strings.HasPrefix(issue.File, "<") {
+		return ""
+	}
+	if strings.Contains(template, "{commit}") && n.commit == "" {
+		n.commit = "HEAD"
+		if output, err := exec.Command("git", "rev-parse", "HEAD").Output(); err == nil {
+			n.commit = strings.TrimSpace(string(output))
+		}
+	}
+	return strings.NewReplacer(
+		"{file}", filepath.ToSlash(issue.File),
+		"{line}", fmt.Sprint(issue.Line),
+		"{commit}", n.commit,
+	).Replace(template)
+}
+
+func (n *Notifier) post(endpoint string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := n.client.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// Leave out the URL, which is a secret
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, bytes.TrimSpace(message))
+	}
+	return nil
+}
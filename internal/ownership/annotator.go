@@ -0,0 +1,138 @@
+package ownership
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+)
+
+// Annotator records who owns the code behind each issue: the teams listed for
+// the file in the repository's CODEOWNERS and the author and date of the last
+// commit to the flagged line. CODEOWNERS files are read once per repository;
+// blame is run once per file and redone when the file changes.
+type Annotator struct {
+	codeOwners bool
+	blame      bool
+
+	roots  map[string]string      // Repository root by directory, "" outside a repository
+	owners map[string]*codeOwners // CODEOWNERS rules by repository root
+	blames map[string]blamedFile  // By filename
+}
+
+type blamedFile struct {
+	modTime time.Time
+	lines   map[int]*models.LineBlame
+}
+
+// NewAnnotator returns an annotator for the enabled ownership sources, or nil
+// when none is enabled
+func NewAnnotator(cfg config.OwnershipConfig) *Annotator {
+	if !cfg.CodeOwners && !cfg.Blame {
+		return nil
+	}
+	return &Annotator{
+		codeOwners: cfg.CodeOwners,
+		blame:      cfg.Blame,
+		roots:      make(map[string]string),
+		owners:     make(map[string]*codeOwners),
+		blames:     make(map[string]blamedFile),
+	}
+}
+
+// Annotate fills in the owners and blame of issues found in filename
+func (a *Annotator) Annotate(filename string, issues []models.Issue) {
+	if a == nil || len(issues) == 0 {
+		return
+	}
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return
+	}
+	root := a.repositoryRoot(filepath.Dir(abs))
+	if root == "" {
+		return
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return
+	}
+	rel = filepath.ToSlash(rel)
+
+	var owners []string
+	if a.codeOwners {
+		rules, ok := a.owners[root]
+		if !ok {
+			rules = loadCodeOwners(root)
+			a.owners[root] = rules
+		}
+		owners = rules.Owners(rel)
+	}
+	var lines map[int]*models.LineBlame
+	if a.blame {
+		lines = a.blameLines(root, rel, abs)
+	}
+
+	for i := range issues {
+		issues[i].Owners = owners
+		if blame := lines[issues[i].Line]; blame != nil {
+			copied := *blame
+			issues[i].Blame = &copied
+		}
+	}
+}
+
+// Blame returns the last commit to a line of filename, or nil when it isn't
+// committed or blame is off
+func (a *Annotator) Blame(filename string, line int) *models.LineBlame {
+	if a == nil || !a.blame {
+		return nil
+	}
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil
+	}
+	root := a.repositoryRoot(filepath.Dir(abs))
+	if root == "" {
+		return nil
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return nil
+	}
+	if blame := a.blameLines(root, filepath.ToSlash(rel), abs)[line]; blame != nil {
+		copied := *blame
+		return &copied
+	}
+	return nil
+}
+
+func (a *Annotator) blameLines(root, rel, abs string) map[int]*models.LineBlame {
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil
+	}
+	if cached, ok := a.blames[abs]; ok && cached.modTime.Equal(info.ModTime()) {
+		return cached.lines
+	}
+	lines := blameFile(root, rel)
+	a.blames[abs] = blamedFile{modTime: info.ModTime(), lines: lines}
+	return lines
+}
+
+// repositoryRoot finds the closest enclosing directory holding .git
+func (a *Annotator) repositoryRoot(dir string) string {
+	if root, ok := a.roots[dir]; ok {
+		return root
+	}
+	root := ""
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		root = dir
+	} else if parent := filepath.Dir(dir); parent != dir {
+		root = a.repositoryRoot(parent)
+	}
+	a.roots[dir] = root
+	return root
+}
@@ -0,0 +1,71 @@
+package ownership
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"gophercheck/internal/models"
+)
+
+// notCommitted is the author git blame reports for uncommitted lines
+const notCommitted = "Not Committed Yet"
+
+// blameFile runs git blame on a file and returns the last commit to touch
+// each line, by line number. Uncommitted lines are left out, and so is
+// everything when the file isn't tracked or git isn't available.
+func blameFile(root, path string) map[int]*models.LineBlame {
+	cmd := exec.Command("git", "-C", root, "blame", "--line-porcelain", "--", path)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	return parseBlame(output)
+}
+
+// parseBlame reads git blame --line-porcelain output, where every line of the
+// file is a header ("<commit> <original line> <final line> ..."), commit
+// fields, and the line itself prefixed with a tab
+func parseBlame(output []byte) map[int]*models.LineBlame {
+	lines := make(map[int]*models.LineBlame)
+	var current *models.LineBlame
+	var line int
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if strings.HasPrefix(text, "\t") {
+			if current != nil && current.Author != notCommitted {
+				lines[line] = current
+			}
+			current = nil
+			continue
+		}
+
+		key, value, _ := strings.Cut(text, " ")
+		if current == nil {
+			fields := strings.Fields(value)
+			if len(fields) < 2 {
+				continue
+			}
+			line, _ = strconv.Atoi(fields[1])
+			current = &models.LineBlame{Commit: key}
+			continue
+		}
+		switch key {
+		case "author":
+			current.Author = value
+		case "author-mail":
+			current.Email = strings.Trim(value, "<>")
+		case "author-time":
+			if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+				current.Time = time.Unix(seconds, 0).UTC()
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,116 @@
+package ownership
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// codeOwnersLocations are where GitHub and GitLab look for a CODEOWNERS file,
+// relative to the repository root, in order
+var codeOwnersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// ownerRule is one CODEOWNERS line
+type ownerRule struct {
+	pattern *regexp.Regexp
+	owners  []string // Empty for a pattern that explicitly has no owner
+}
+
+// codeOwners holds the rules of a repository's CODEOWNERS file. As in Git
+// hosts, the last matching rule wins.
+type codeOwners struct {
+	rules []ownerRule
+}
+
+// loadCodeOwners reads the CODEOWNERS file of the repository at root. A
+// repository without one has no rules.
+func loadCodeOwners(root string) *codeOwners {
+	for _, location := range codeOwnersLocations {
+		file, err := os.Open(filepath.Join(root, filepath.FromSlash(location)))
+		if err != nil {
+			continue
+		}
+		defer file.Close()
+		return parseCodeOwners(bufio.NewScanner(file))
+	}
+	return &codeOwners{}
+}
+
+func parseCodeOwners(scanner *bufio.Scanner) *codeOwners {
+	owners := &codeOwners{}
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "[") {
+			continue // GitLab section headers
+		}
+		owners.rules = append(owners.rules, ownerRule{
+			pattern: patternRegexp(fields[0]),
+			owners:  fields[1:],
+		})
+	}
+	return owners
+}
+
+// Owners returns the owners of a slash-separated path relative to the
+// repository root
+func (c *codeOwners) Owners(path string) []string {
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.MatchString(path) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// patternRegexp compiles a CODEOWNERS pattern, which follows gitignore rules:
+// a pattern containing a slash is anchored at the repository root, one
+// without matches at any depth, * and ? don't cross directories while **
+// does, and a pattern naming a directory covers everything below it. As on
+// GitHub, a trailing /* only covers the files directly in the directory.
+func patternRegexp(pattern string) *regexp.Regexp {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		expr.WriteString("/.*$")
+	case strings.HasSuffix(pattern, "/*"):
+		expr.WriteString("$")
+	default:
+		expr.WriteString("(/.*)?$")
+	}
+	return regexp.MustCompile(expr.String())
+}
@@ -0,0 +1,107 @@
+package suggestions
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed catalog.yaml
+var catalogYAML []byte
+
+// Default is the built-in catalog used by the detectors
+var Default = mustLoad(catalogYAML)
+
+// Entry is one suggestion in the catalog. Text is a text/template rendered
+// with Data; entries may include each other with {{template "id" .}}.
+type Entry struct {
+	ID    string `yaml:"id"`
+	Rule  string `yaml:"rule,omitempty"` // Issue type the entry is offered for; empty for shared fragments
+	Title string `yaml:"title,omitempty"`
+	Text  string `yaml:"text"`
+}
+
+// Data carries names from the flagged code that templates refer to. Zero
+// fields are left for the template to default, e.g. {{or .Var "result"}}.
+type Data struct {
+	Var         string // Variable being built, grown, or searched
+	Source      string // Second variable involved, e.g. the slice appended to
+	Type        string // Allocation or element type, e.g. "slice"
+	Lines       int    // Lines of code in the function
+	Depth       int    // Loop nesting depth
+	Iterations  int    // Estimated loop iterations
+	CycleLength int    // Packages in an import cycle
+}
+
+// Catalog is a parsed set of suggestion entries
+type Catalog struct {
+	entries   []Entry
+	templates *template.Template
+}
+
+// Load parses a YAML list of entries and compiles their templates
+func Load(data []byte) (*Catalog, error) {
+	var entries []Entry
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse suggestion catalog: %w", err)
+	}
+
+	templates := template.New("catalog")
+	for _, entry := range entries {
+		if templates.Lookup(entry.ID) != nil {
+			return nil, fmt.Errorf("duplicate suggestion id %q", entry.ID)
+		}
+		if _, err := templates.New(entry.ID).Parse(entry.Text); err != nil {
+			return nil, fmt.Errorf("suggestion %q: %w", entry.ID, err)
+		}
+	}
+
+	return &Catalog{entries: entries, templates: templates}, nil
+}
+
+func mustLoad(data []byte) *Catalog {
+	catalog, err := Load(data)
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+// Render fills in the suggestion with the given id, or returns "" if the
+// catalog has no such entry
+func (c *Catalog) Render(id string, data Data) string {
+	tmpl := c.templates.Lookup(id)
+	if tmpl == nil {
+		return ""
+	}
+
+	var text strings.Builder
+	if err := tmpl.Execute(&text, data); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(text.String())
+}
+
+// Entries lists every entry in catalog order
+func (c *Catalog) Entries() []Entry {
+	return c.entries
+}
+
+// ForRule lists the entries offered for an issue type
+func (c *Catalog) ForRule(rule string) []Entry {
+	var matches []Entry
+	for _, entry := range c.entries {
+		if entry.Rule == rule {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
+
+// Render fills in a suggestion from the default catalog
+func Render(id string, data Data) string {
+	return Default.Render(id, data)
+}
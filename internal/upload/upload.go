@@ -0,0 +1,110 @@
+// Package upload sends results to a central collection endpoint
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gophercheck/internal/config"
+	"gophercheck/internal/models"
+)
+
+// Sink POSTs results to the endpoint of an upload config. The body is always
+// the JSON report, sent as application/json with its schema version; other
+// formats such as SARIF aren't uploaded.
+type Sink struct {
+	config  config.UploadConfig
+	client  *http.Client
+	getenv  func(string) string
+	backoff time.Duration // Wait before the first retry, doubled for each further one
+}
+
+func NewSink(cfg config.UploadConfig, getenv func(string) string) *Sink {
+	return &Sink{
+		config:  cfg,
+		client:  &http.Client{Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second},
+		getenv:  getenv,
+		backoff: time.Second,
+	}
+}
+
+// Send uploads a JSON report, retrying network errors, 429s, and 5xx
+// responses with exponential backoff (or the server's Retry-After). Canceling
+// ctx, e.g. on Ctrl-C, stops the request in flight or the wait for the next.
+func (s *Sink) Send(ctx context.Context, report []byte) error {
+	wait := s.backoff
+	var lastErr error
+	for attempt := 0; attempt <= s.config.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("uploading to %s: %w (last attempt: %v)", s.config.URL, ctx.Err(), lastErr)
+			case <-time.After(wait):
+			}
+			wait *= 2
+		}
+
+		retryAfter, err := s.post(ctx, report)
+		if ctx.Err() != nil {
+			return fmt.Errorf("uploading to %s: %w", s.config.URL, ctx.Err())
+		}
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if retryAfter < 0 {
+			break // Not worth retrying, e.g. a 401
+		}
+		wait = max(wait, retryAfter)
+	}
+	return fmt.Errorf("uploading to %s: %w", s.config.URL, lastErr)
+}
+
+func (s *Sink) token() string {
+	if s.config.TokenEnv == "" {
+		return ""
+	}
+	return s.getenv(s.config.TokenEnv)
+}
+
+// post makes one attempt. On failure it returns how long the server asked to
+// wait before retrying (0 when it didn't say), or -1 if retrying can't help.
+func (s *Sink) post(ctx context.Context, report []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.URL, bytes.NewReader(report))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gophercheck-Schema-Version", models.SchemaVersion)
+	for name, value := range s.config.Headers {
+		req.Header.Set(name, value)
+	}
+	if token := s.token(); token != "" {
+		if http.CanonicalHeaderKey(s.config.AuthHeader) == "Authorization" {
+			token = "Bearer " + token
+		}
+		req.Header.Set(s.config.AuthHeader, token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, nil
+	}
+	err = fmt.Errorf("server returned %s: %s", resp.Status, bytes.TrimSpace(body))
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return -1, err
+	}
+	seconds, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+	return time.Duration(seconds) * time.Second, err
+}
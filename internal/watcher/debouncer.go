@@ -1,7 +1,6 @@
 package watcher
 
 import (
-	"fmt"
 	"sync"
 	"time"
 )
@@ -22,7 +21,7 @@ func newDebouncer(delay time.Duration) *debouncer {
 	}
 }
 
-func (d *debouncer) add(event FileChangeEvent, handler FileChangeHandler) {
+func (d *debouncer) add(event FileChangeEvent, handler func([]string)) {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 	d.events[event.Path] = event
@@ -34,7 +33,8 @@ func (d *debouncer) add(event FileChangeEvent, handler FileChangeHandler) {
 	})
 }
 
-func (d *debouncer) flush(handler FileChangeHandler) {
+// flush passes the pending changes to handler, which reports its own errors
+func (d *debouncer) flush(handler func([]string)) {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 	if len(d.events) == 0 {
@@ -45,10 +45,7 @@ func (d *debouncer) flush(handler FileChangeHandler) {
 		changedFiles = append(changedFiles, path)
 	}
 	d.events = make(map[string]FileChangeEvent)
-	if err := handler(changedFiles); err != nil {
-		// Will add better error handling later on for now just print
-		fmt.Printf("Handler error: %v\n", err)
-	}
+	handler(changedFiles)
 }
 
 func (d *debouncer) stop() {
@@ -1,11 +1,15 @@
 package watcher
 
 import (
+	"errors"
 	"fmt"
 	"gophercheck/internal/config"
+	"gophercheck/internal/workspace"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -14,8 +18,19 @@ import (
 type FileWatcher struct {
 	watcher     *fsnotify.Watcher
 	config      *config.Config
+	roots       []string             // Paths passed to Watch
+	globs       *workspace.FileGlobs // files.include and files.exclude
 	watchedDirs map[string]bool
 	debouncer   *debouncer
+	errors      chan WatchError
+	done        chan struct{} // Closed by Close
+
+	mu            sync.Mutex // Guards watcher and the fields below
+	closed        bool
+	recreating    bool
+	resyncPending bool
+	degraded      string
+	degradedSince time.Time
 }
 
 type FileChangeEvent struct {
@@ -34,31 +49,41 @@ func NewFileWatcher(cfg *config.Config) (*FileWatcher, error) {
 	fw := &FileWatcher{
 		watcher:     watcher,
 		config:      cfg,
+		globs:       workspace.NewFileGlobs(nil, nil),
 		watchedDirs: make(map[string]bool),
 		debouncer:   newDebouncer(500 * time.Millisecond), // 500ms debounce
+		errors:      make(chan WatchError, errorBuffer),
+		done:        make(chan struct{}),
+	}
+	if cfg != nil {
+		fw.globs = workspace.NewFileGlobs(cfg.Files.Include, cfg.Files.Exclude)
 	}
 	return fw, nil
 }
 
 func (fw *FileWatcher) Watch(paths []string, handler FileChangeHandler) error {
+	fw.roots = paths
 	for _, path := range paths {
-		if err := fw.addPath(path); err != nil {
+		if err := fw.addPath(path, path); err != nil {
 			return fmt.Errorf("failed to watch path %s: %w", path, err)
 		}
 	}
-	go fw.eventLoop(handler)
+	go fw.eventLoop(fw.guard(handler))
 	return nil
 }
 
-func (fw *FileWatcher) addPath(path string) error {
-	return filepath.Walk(path, func(walkPath string, info os.FileInfo, err error) error {
+// addPath watches path and the directories below it, skipping directories
+// as shouldSkipDir does under root
+func (fw *FileWatcher) addPath(path, root string) error {
+	follow := fw.config != nil && fw.config.Files.FollowSymlinks
+	return workspace.Walk(path, follow, func(walkPath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 		if !info.IsDir() {
 			return nil
 		}
-		if fw.shouldSkipDir(walkPath) {
+		if fw.shouldSkipDir(walkPath, root) {
 			return filepath.SkipDir
 		}
 		if !fw.watchedDirs[walkPath] {
@@ -71,28 +96,81 @@ func (fw *FileWatcher) addPath(path string) error {
 	})
 }
 
-func (fw *FileWatcher) eventLoop(handler FileChangeHandler) {
+// eventLoop passes changes to the handler until the FileWatcher is closed.
+// When the file system watcher stops on its own or the loop panics, the
+// watcher is re-created and everything passed to the handler again.
+func (fw *FileWatcher) eventLoop(handler func([]string)) {
+	for {
+		werr := fw.runEvents(handler)
+		if werr == nil {
+			return
+		}
+		fw.degrade("the file watcher stopped and is being re-created; changes may have been missed")
+		fw.report(*werr)
+		if !fw.recreate() {
+			return
+		}
+		fw.resync(handler)
+	}
+}
+
+// runEvents handles events until the file system watcher stops, returning
+// the fatal error, or nil once the FileWatcher is closed
+func (fw *FileWatcher) runEvents(handler func([]string)) (werr *WatchError) {
+	defer func() {
+		if r := recover(); r != nil {
+			werr = &WatchError{
+				Kind:  ErrorPanic,
+				Err:   fmt.Errorf("event loop panicked: %v", r),
+				Fatal: true,
+				Stack: string(debug.Stack()),
+			}
+		}
+	}()
+
+	fw.mu.Lock()
+	watcher := fw.watcher
+	fw.mu.Unlock()
 	for {
 		select {
-		case event, ok := <-fw.watcher.Events:
+		case event, ok := <-watcher.Events:
 			if !ok {
-				return
+				return fw.stopped()
 			}
 			fw.handleEvent(event, handler)
-		case err, ok := <-fw.watcher.Errors:
+		case err, ok := <-watcher.Errors:
 			if !ok {
-				return
+				return fw.stopped()
+			}
+			werr := watchErrorOf(err)
+			if werr.Kind == ErrorOverflow {
+				fw.degrade("the file system dropped change events")
+				fw.resync(handler)
 			}
-			fmt.Printf("File watcher error: %v\n", err)
+			fw.report(werr)
 		}
 	}
 }
 
-func (fw *FileWatcher) handleEvent(event fsnotify.Event, handler FileChangeHandler) {
+// stopped returns the error for a file system watcher whose channels were
+// closed, unless Close did it
+func (fw *FileWatcher) stopped() *WatchError {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	if fw.closed {
+		return nil
+	}
+	return &WatchError{Kind: ErrorWatcher, Err: errors.New("file watcher stopped unexpectedly"), Fatal: true}
+}
+
+func (fw *FileWatcher) handleEvent(event fsnotify.Event, handler func([]string)) {
+	if fw.handleDirEvent(event, handler) {
+		return
+	}
 	if !fw.isGoFile(event.Name) {
 		return
 	}
-	if fw.shouldSkipFile(event.Name) {
+	if fw.shouldSkipFile(event.Name, fw.rootOf(event.Name)) {
 		return
 	}
 	changeEvent := FileChangeEvent{
@@ -103,6 +181,55 @@ func (fw *FileWatcher) handleEvent(event fsnotify.Event, handler FileChangeHandl
 	fw.debouncer.add(changeEvent, handler)
 }
 
+// handleDirEvent starts watching directories created or moved in under a
+// watched root and stops watching removed or moved-away ones. Either is
+// passed on as a change, so the files inside are picked up or dropped:
+// moving a directory sends no events for its files.
+func (fw *FileWatcher) handleDirEvent(event fsnotify.Event, handler func([]string)) bool {
+	switch {
+	case event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 && fw.watchedDirs[event.Name]:
+		for dir := range fw.watchedDirs {
+			if dir == event.Name || strings.HasPrefix(dir, event.Name+string(filepath.Separator)) {
+				fw.watcher.Remove(dir) // Already unwatched if it was deleted
+				delete(fw.watchedDirs, dir)
+			}
+		}
+	case event.Op&fsnotify.Create != 0:
+		info, err := os.Stat(event.Name)
+		if err != nil || !info.IsDir() {
+			return false
+		}
+		root := fw.rootOf(event.Name)
+		if fw.shouldSkipDir(event.Name, root) {
+			return true
+		}
+		if err := fw.addPath(event.Name, root); err != nil {
+			fw.report(WatchError{Kind: ErrorWatcher, Path: event.Name, Err: err})
+		}
+	default:
+		return false
+	}
+
+	fw.debouncer.add(FileChangeEvent{
+		Path:      event.Name,
+		Operation: fw.eventOpToString(event.Op),
+		Timestamp: time.Now(),
+	}, handler)
+	return true
+}
+
+// rootOf returns the watched root a path is under, or the path itself
+func (fw *FileWatcher) rootOf(path string) string {
+	root := path
+	for _, candidate := range fw.roots {
+		candidate = filepath.Clean(candidate)
+		if rel, err := filepath.Rel(candidate, path); err == nil && !strings.HasPrefix(rel, "..") && len(candidate) < len(root) {
+			root = candidate
+		}
+	}
+	return root
+}
+
 func (fw *FileWatcher) isGoFile(path string) bool {
 	if !strings.HasSuffix(path, ".go") {
 		return false
@@ -113,9 +240,16 @@ func (fw *FileWatcher) isGoFile(path string) bool {
 	return true
 }
 
-func (fw *FileWatcher) shouldSkipDir(path string) bool {
+// shouldSkipDir reports whether a directory under the watched root is left
+// out; vendor directories follow files.ignore_vendor, and files.exclude
+// patterns ending in /** drop whole directories, as in the file collector
+func (fw *FileWatcher) shouldSkipDir(path, root string) bool {
 	defaultExclusions := []string{
-		"vendor", ".git", "node_modules", ".vscode", ".idea", "build", "dist", "tmp", "temp",
+		".git", "node_modules", ".vscode", ".idea", "build", "dist", "tmp", "temp",
+	}
+	ignoreVendor := fw.config == nil || fw.config.Files.IgnoreVendor
+	if workspace.SkipVendorDir(path, root, ignoreVendor) {
+		return true
 	}
 	dirName := filepath.Base(path)
 	for _, excluded := range defaultExclusions {
@@ -123,18 +257,15 @@ func (fw *FileWatcher) shouldSkipDir(path string) bool {
 			return true
 		}
 	}
-	if fw.config != nil {
-		for _, pattern := range fw.config.Files.Exclude {
-			matched, _ := filepath.Match(pattern, path)
-			if matched {
-				return true
-			}
-		}
-	}
-	return false
+	return fw.globs.SkipDir(root, path)
 }
 
-func (fw *FileWatcher) shouldSkipFile(path string) bool {
+// shouldSkipFile reports whether a file under the watched root is left out:
+// editor temporaries, and files files.include and files.exclude leave out
+func (fw *FileWatcher) shouldSkipFile(path, root string) bool {
+	if !fw.globs.Keep(root, path) {
+		return true
+	}
 	filename := filepath.Base(path)
 	if strings.HasPrefix(filename, ".") {
 		return true
@@ -167,6 +298,13 @@ func (fw *FileWatcher) eventOpToString(op fsnotify.Op) string {
 
 func (fw *FileWatcher) Close() error {
 	fw.debouncer.stop()
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	if fw.closed {
+		return nil
+	}
+	fw.closed = true
+	close(fw.done)
 	return fw.watcher.Close()
 }
 
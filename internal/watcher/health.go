@@ -0,0 +1,204 @@
+package watcher
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ErrorKind says where a WatchError came from
+type ErrorKind string
+
+const (
+	ErrorWatcher  ErrorKind = "watcher"  // The file system watcher reported an error
+	ErrorOverflow ErrorKind = "overflow" // The file system dropped events
+	ErrorHandler  ErrorKind = "handler"  // Analyzing a change failed
+	ErrorPanic    ErrorKind = "panic"    // Analyzing a change or the event loop panicked
+	ErrorRecreate ErrorKind = "recreate" // Re-creating the file system watcher failed
+)
+
+// WatchError is a problem in watch mode, sent on FileWatcher.Errors. Fatal
+// errors stopped the file system watcher, which is then re-created.
+type WatchError struct {
+	Kind  ErrorKind
+	Path  string // The file or directory concerned, if any
+	Err   error
+	Fatal bool
+	Stack string // For panics
+	Time  time.Time
+}
+
+func (e WatchError) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("%s: %s: %v", e.Kind, e.Path, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Kind, e.Err)
+}
+
+func (e WatchError) Unwrap() error {
+	return e.Err
+}
+
+// errorBuffer is how many errors Errors holds; more are dropped until the
+// CLI catches up
+const errorBuffer = 32
+
+// maxRecreateDelay caps the wait between attempts to re-create the watcher
+const maxRecreateDelay = 30 * time.Second
+
+// Errors returns the channel watch mode problems are sent on
+func (fw *FileWatcher) Errors() <-chan WatchError {
+	return fw.errors
+}
+
+// Degraded returns why the watcher may have missed changes, and since when,
+// or an empty reason while it is healthy. It becomes healthy again once
+// everything has been passed to the handler anew.
+func (fw *FileWatcher) Degraded() (string, time.Time) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	return fw.degraded, fw.degradedSince
+}
+
+func (fw *FileWatcher) report(werr WatchError) {
+	if werr.Time.IsZero() {
+		werr.Time = time.Now()
+	}
+	select {
+	case fw.errors <- werr:
+	default:
+	}
+}
+
+// degrade records that changes may have been missed. The watched roots are
+// passed to the handler with the next change, or right away with resync.
+func (fw *FileWatcher) degrade(reason string) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	if fw.degraded == "" {
+		fw.degradedSince = time.Now()
+	}
+	fw.degraded = reason
+	fw.resyncPending = true
+}
+
+// resync passes every watched root to the handler, so that everything is
+// analyzed again
+func (fw *FileWatcher) resync(handler func([]string)) {
+	for _, root := range fw.roots {
+		fw.debouncer.add(FileChangeEvent{
+			Path:      root,
+			Operation: "RESYNC",
+			Timestamp: time.Now(),
+		}, handler)
+	}
+}
+
+// guard wraps the change handler: a resync owed after degrading adds the
+// watched roots to the changes, the watcher is healthy again once they are
+// handed over, and errors and panics are reported instead of ending the
+// goroutine that called it
+func (fw *FileWatcher) guard(handler FileChangeHandler) func([]string) {
+	return func(changedFiles []string) {
+		fw.mu.Lock()
+		resync := fw.resyncPending
+		if resync && !fw.recreating {
+			fw.resyncPending = false
+			fw.degraded = ""
+		}
+		fw.mu.Unlock()
+		if resync {
+			for _, root := range fw.roots {
+				if !slices.Contains(changedFiles, root) {
+					changedFiles = append(changedFiles, root)
+				}
+			}
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				fw.degrade("analyzing a change panicked; results may be stale")
+				fw.report(WatchError{
+					Kind:  ErrorPanic,
+					Err:   fmt.Errorf("analyzing %d changed files panicked: %v", len(changedFiles), r),
+					Stack: string(debug.Stack()),
+				})
+			}
+		}()
+		if err := handler(changedFiles); err != nil {
+			fw.report(WatchError{Kind: ErrorHandler, Err: err})
+		}
+	}
+}
+
+// watchErrorOf classifies an error from the file system watcher
+func watchErrorOf(err error) WatchError {
+	if errors.Is(err, fsnotify.ErrEventOverflow) {
+		return WatchError{Kind: ErrorOverflow, Err: err}
+	}
+	return WatchError{Kind: ErrorWatcher, Err: err}
+}
+
+// recreate replaces a stopped file system watcher with a new one watching
+// the same roots, retrying with a growing delay. It returns false when the
+// FileWatcher was closed meanwhile.
+func (fw *FileWatcher) recreate() bool {
+	fw.mu.Lock()
+	fw.recreating = true
+	fw.mu.Unlock()
+	defer func() {
+		fw.mu.Lock()
+		fw.recreating = false
+		fw.mu.Unlock()
+	}()
+
+	delay := time.Second
+	for {
+		err := fw.replaceWatcher()
+		if err == nil {
+			return true
+		}
+		if errors.Is(err, errClosed) {
+			return false
+		}
+		fw.report(WatchError{Kind: ErrorRecreate, Err: fmt.Errorf("%w; retrying in %s", err, delay)})
+		select {
+		case <-fw.done:
+			return false
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxRecreateDelay)
+	}
+}
+
+var errClosed = errors.New("file watcher closed")
+
+func (fw *FileWatcher) replaceWatcher() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	fw.mu.Lock()
+	if fw.closed {
+		fw.mu.Unlock()
+		watcher.Close()
+		return errClosed
+	}
+	old := fw.watcher
+	fw.watcher = watcher
+	fw.watchedDirs = make(map[string]bool)
+	fw.mu.Unlock()
+	old.Close()
+
+	for _, root := range fw.roots {
+		if err := fw.addPath(root, root); err != nil {
+			return fmt.Errorf("failed to watch path %s: %w", root, err)
+		}
+	}
+	return nil
+}
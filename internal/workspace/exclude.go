@@ -0,0 +1,114 @@
+package workspace
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PackageFilter excludes files by the import path of their package rather
+// than by where they sit on disk, e.g. "*/generated/*" or "example.com/app/pb".
+// In a pattern, * matches any run of characters including slashes and ?
+// matches one character; a trailing /* also matches the package itself, like
+// /... in go build patterns. Patterns ending in ".go" match the import path
+// joined with the file name, so "*.pb.go" drops generated protobuf files
+// while keeping hand-written files of the same package.
+type PackageFilter struct {
+	resolver *Resolver
+	packages []excludePattern
+	files    []excludePattern
+}
+
+type excludePattern struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+func NewPackageFilter(patterns []string) *PackageFilter {
+	filter := &PackageFilter{resolver: NewResolver()}
+	for _, pattern := range patterns {
+		compiled := excludePattern{pattern: pattern, re: globRegexp(pattern)}
+		if strings.HasSuffix(pattern, ".go") {
+			filter.files = append(filter.files, compiled)
+		} else {
+			filter.packages = append(filter.packages, compiled)
+		}
+	}
+	return filter
+}
+
+// globRegexp compiles a glob whose * also matches slashes
+func globRegexp(pattern string) *regexp.Regexp {
+	pattern, subpackages := strings.CutSuffix(pattern, "/*")
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	if subpackages {
+		expr.WriteString("(/.*)?")
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// PackagePatterns are import path globs with the syntax of PackageFilter
+type PackagePatterns []*regexp.Regexp
+
+func CompilePackagePatterns(patterns []string) PackagePatterns {
+	compiled := make(PackagePatterns, len(patterns))
+	for i, pattern := range patterns {
+		compiled[i] = globRegexp(pattern)
+	}
+	return compiled
+}
+
+// Match reports whether any of the patterns matches importPath
+func (p PackagePatterns) Match(importPath string) bool {
+	for _, re := range p {
+		if re.MatchString(importPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Excluded reports whether filename's package or file matches a pattern.
+// Files outside any module are matched by their slash-separated directory.
+func (f *PackageFilter) Excluded(filename string) bool {
+	_, excluded := f.ExcludedBy(filename)
+	return excluded
+}
+
+// ExcludedBy returns the first pattern matching filename's package or file
+func (f *PackageFilter) ExcludedBy(filename string) (string, bool) {
+	if len(f.packages) == 0 && len(f.files) == 0 {
+		return "", false
+	}
+
+	importPath := f.resolver.ImportPath(filename)
+	if importPath == "" {
+		importPath = filepath.ToSlash(filepath.Dir(filename))
+	}
+	for _, p := range f.packages {
+		if p.re.MatchString(importPath) {
+			return p.pattern, true
+		}
+	}
+	file := path.Join(importPath, filepath.Base(filename))
+	for _, p := range f.files {
+		if p.re.MatchString(file) {
+			return p.pattern, true
+		}
+	}
+	return "", false
+}
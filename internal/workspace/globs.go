@@ -0,0 +1,95 @@
+package workspace
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// FileGlobs selects files by their slash-separated path relative to the
+// directory they were found under, per files.include and files.exclude.
+// In a pattern, ** matches any number of whole path elements, none
+// included, and *, ?, and [...] match within one element as in path.Match:
+// "**/mocks/**" matches every file in every mocks directory.
+type FileGlobs struct {
+	include, exclude []string
+}
+
+func NewFileGlobs(include, exclude []string) *FileGlobs {
+	return &FileGlobs{include: include, exclude: exclude}
+}
+
+// Keep reports whether a file found under root is kept: it matches an
+// include pattern, or there are none, and no exclude pattern. A file that is
+// root itself, named explicitly, is always kept.
+func (g *FileGlobs) Keep(root, filename string) bool {
+	rel, ok := relativeTo(root, filename)
+	if !ok {
+		return true
+	}
+	if len(g.include) > 0 && !matchAny(g.include, rel) {
+		return false
+	}
+	return !matchAny(g.exclude, rel)
+}
+
+// SkipDir reports whether everything in a directory under root is excluded
+// by a pattern ending in /**, such as "**/mocks/**", so it needn't be walked
+// or watched at all
+func (g *FileGlobs) SkipDir(root, dir string) bool {
+	rel, ok := relativeTo(root, dir)
+	if !ok {
+		return false
+	}
+	for _, pattern := range g.exclude {
+		if prefix, found := strings.CutSuffix(pattern, "/**"); found && MatchGlob(prefix, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// relativeTo returns a path below root relative to it, slash-separated
+func relativeTo(root, p string) (string, bool) {
+	rel, err := filepath.Rel(root, p)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if MatchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchGlob reports whether a slash-separated path matches a pattern with
+// the syntax of FileGlobs. A malformed pattern matches nothing.
+func MatchGlob(pattern, name string) bool {
+	return matchElements(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchElements(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(name); i >= 0; i-- {
+				if matchElements(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], name[0]); !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,89 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// IsPackagePattern reports whether a command-line argument is a Go package
+// pattern (./..., github.com/org/repo/pkg, std, all) rather than a file or
+// directory on disk. Other paths that don't exist are taken as files, so
+// they are reported as missing instead of failing to load as packages.
+func IsPackagePattern(arg string) bool {
+	if strings.Contains(arg, "...") {
+		return true
+	}
+	if strings.HasSuffix(arg, ".go") || filepath.IsAbs(arg) || isLocalPath(filepath.ToSlash(arg)) {
+		return false
+	}
+	if _, err := os.Stat(arg); !os.IsNotExist(err) {
+		return false
+	}
+	return arg == "std" || arg == "all" || isImportPath(arg)
+}
+
+// isLocalPath reports whether a slash-separated path is relative to the
+// working directory the way go build reads it: ".", "..", "./x", or "../x"
+func isLocalPath(arg string) bool {
+	return arg == "." || arg == ".." || strings.HasPrefix(arg, "./") || strings.HasPrefix(arg, "../")
+}
+
+// isImportPath reports whether arg is shaped like an import path: its first
+// element is a domain, as in github.com/org/repo, or it is in the module of
+// the working directory
+func isImportPath(arg string) bool {
+	first, _, _ := strings.Cut(arg, "/")
+	if strings.Contains(first, ".") {
+		return true
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+	module := NewResolver().moduleForDir(cwd)
+	return module != nil && (arg == module.Path || strings.HasPrefix(arg, module.Path+"/"))
+}
+
+// LoadPatternFiles resolves package patterns the way go build/vet do and
+// returns the Go files of the matched packages. Packages that fail to load
+// are reported through the returned error but don't stop the others.
+func LoadPatternFiles(patterns []string, includeTests bool) ([]string, error) {
+	cfg := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedFiles,
+		Tests: includeTests,
+	}
+
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	var loadErrors []string
+	seen := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue // Generated test main package
+		}
+		for _, pkgErr := range pkg.Errors {
+			loadErrors = append(loadErrors, pkgErr.Error())
+		}
+		// With Tests set, test variants repeat the package's own files
+		for _, file := range pkg.GoFiles {
+			if seen[file] || (!includeTests && strings.HasSuffix(file, "_test.go")) {
+				continue
+			}
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	if len(loadErrors) > 0 {
+		return files, fmt.Errorf("%s", strings.Join(loadErrors, "; "))
+	}
+	return files, nil
+}
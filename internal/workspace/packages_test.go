@@ -0,0 +1,24 @@
+package workspace
+
+import "testing"
+
+func TestIsPackagePattern(t *testing.T) {
+	for arg, want := range map[string]bool{
+		"./...":                       true,
+		"github.com/org/repo/pkg/...": true,
+		"github.com/org/repo/pkg":     true,
+		"std":                         true,
+		"all":                         true,
+		"gophercheck/internal/models": true, // In the module of the working directory
+		".":                           false,
+		"../workspace":                false,
+		"packages.go":                 false,
+		"missing.go":                  false,
+		"missing":                     false, // A typo, reported as a missing directory
+		"internal/missing":            false,
+	} {
+		if got := IsPackagePattern(arg); got != want {
+			t.Errorf("IsPackagePattern(%q) = %v, want %v", arg, got, want)
+		}
+	}
+}
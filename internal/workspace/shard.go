@@ -0,0 +1,71 @@
+package workspace
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+)
+
+// Shard is one of Count parts of a set of packages, for splitting an analysis
+// across CI workers. Index counts from 1; the zero Shard selects everything.
+type Shard struct {
+	Index int
+	Count int
+}
+
+// ParseShard parses a shard given as "i/n", e.g. "2/4"
+func ParseShard(spec string) (Shard, error) {
+	var shard Shard
+	if _, err := fmt.Sscanf(spec, "%d/%d", &shard.Index, &shard.Count); err != nil || fmt.Sprintf("%d/%d", shard.Index, shard.Count) != spec {
+		return Shard{}, fmt.Errorf("invalid shard %q (want i/n, e.g. 2/4)", spec)
+	}
+	if shard.Count < 1 || shard.Index < 1 || shard.Index > shard.Count {
+		return Shard{}, fmt.Errorf("invalid shard %q: i must be between 1 and n", spec)
+	}
+	return shard, nil
+}
+
+func (s Shard) String() string {
+	if s.Count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
+
+// Filter returns the files of the packages in this shard. Packages are
+// identified by import path (or slash-separated directory outside a module),
+// sorted, and dealt out to the shards in turn, so every worker given the same
+// files computes the same partition wherever the checkout lives, and no
+// package is split across shards.
+func (s Shard) Filter(filenames []string) []string {
+	if s.Count <= 1 {
+		return filenames
+	}
+
+	resolver := NewResolver()
+	packageOf := make(map[string]string, len(filenames))
+	var packages []string
+	for _, filename := range filenames {
+		pkg := resolver.ImportPath(filename)
+		if pkg == "" {
+			pkg = filepath.ToSlash(filepath.Dir(filename))
+		}
+		packageOf[filename] = pkg
+		packages = append(packages, pkg)
+	}
+	slices.Sort(packages)
+	packages = slices.Compact(packages)
+
+	inShard := make(map[string]bool)
+	for i := s.Index - 1; i < len(packages); i += s.Count {
+		inShard[packages[i]] = true
+	}
+
+	kept := make([]string, 0, len(filenames)/s.Count+1)
+	for _, filename := range filenames {
+		if inShard[packageOf[filename]] {
+			kept = append(kept, filename)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,119 @@
+package workspace
+
+import (
+	"bufio"
+	"go/build"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// InVendor reports whether a path lies inside a vendor directory
+func InVendor(p string) bool {
+	for _, elem := range strings.Split(filepath.ToSlash(p), "/") {
+		if elem == "vendor" {
+			return true
+		}
+	}
+	return false
+}
+
+// SkipVendorDir reports whether a directory met while walking root is a
+// vendor directory to leave out: with ignoreVendor, unless root was inside
+// vendor already and so named it explicitly
+func SkipVendorDir(dir, root string, ignoreVendor bool) bool {
+	return ignoreVendor && filepath.Base(dir) == "vendor" && !InVendor(root)
+}
+
+// vendorImportPath strips everything up to the innermost vendor directory
+// from a slash-separated package path: code in vendor/github.com/x/y is
+// imported as github.com/x/y
+func vendorImportPath(rel string) string {
+	if i := strings.LastIndex("/"+rel, "/vendor/"); i >= 0 {
+		return rel[i+len("vendor/"):]
+	}
+	return rel
+}
+
+// readVendoredModules lists the modules in dir/vendor/modules.txt, written
+// by go mod vendor, e.g. "# github.com/pkg/errors v0.9.1"
+func readVendoredModules(dir string) []string {
+	file, err := os.Open(filepath.Join(dir, "vendor", "modules.txt"))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var modules []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "#" {
+			modules = append(modules, fields[1])
+		}
+	}
+	return modules
+}
+
+// Vendored reports whether an import of filename resolves to a vendored
+// copy: in a module, to a module listed in its vendor/modules.txt; outside
+// one (GOPATH mode), to a vendor directory beside filename or above it
+func (r *Resolver) Vendored(filename, importPath string) bool {
+	if module := r.ModuleFor(filename); module != nil {
+		for _, vendored := range module.Vendored {
+			if importPath == vendored || strings.HasPrefix(importPath, vendored+"/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return false
+	}
+	for dir := filepath.Dir(abs); ; dir = filepath.Dir(dir) {
+		if r.hasVendorDir(dir) {
+			if info, err := os.Stat(filepath.Join(dir, "vendor", filepath.FromSlash(importPath))); err == nil && info.IsDir() {
+				return true
+			}
+		}
+		if parent := filepath.Dir(dir); parent == dir || filepath.Base(parent) == "src" && r.gopathSrc(parent) {
+			return false
+		}
+	}
+}
+
+func (r *Resolver) hasVendorDir(dir string) bool {
+	has, ok := r.vendorDirs[dir]
+	if !ok {
+		info, err := os.Stat(filepath.Join(dir, "vendor"))
+		has = err == nil && info.IsDir()
+		r.vendorDirs[dir] = has
+	}
+	return has
+}
+
+// gopathSrc reports whether dir is the src directory of a GOPATH entry
+func (r *Resolver) gopathSrc(dir string) bool {
+	for _, root := range filepath.SplitList(build.Default.GOPATH) {
+		if filepath.Join(root, "src") == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// gopathImportPath returns the import path of the package in dir under a
+// GOPATH src directory, or "" outside GOPATH
+func gopathImportPath(dir string) string {
+	for _, root := range filepath.SplitList(build.Default.GOPATH) {
+		rel, err := filepath.Rel(filepath.Join(root, "src"), dir)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		return vendorImportPath(path.Clean(filepath.ToSlash(rel)))
+	}
+	return ""
+}
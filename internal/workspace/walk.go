@@ -0,0 +1,102 @@
+package workspace
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Walk walks the tree rooted at root like filepath.Walk, calling fn for every
+// file and directory in lexical order. With followSymlinks, symlinks to
+// directories are descended into and symlinks to files are reported with the
+// target's info. Each real directory and file is visited once, so links back
+// up the tree don't loop and trees reachable through several links aren't
+// analyzed twice. Without it symlinks are reported as they are, without
+// following them, as filepath.Walk does. The root is always followed.
+func Walk(root string, followSymlinks bool, fn filepath.WalkFunc) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+
+	w := &walker{follow: followSymlinks, fn: fn, visited: make(map[string]bool)}
+	err = w.walk(root, info)
+	if errors.Is(err, filepath.SkipDir) || errors.Is(err, filepath.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+type walker struct {
+	follow  bool
+	fn      filepath.WalkFunc
+	visited map[string]bool // Real paths already walked
+}
+
+// firstVisit records the real path of path and reports whether it's new
+func (w *walker) firstVisit(path string) bool {
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		real = path
+	}
+	if abs, err := filepath.Abs(real); err == nil {
+		real = abs
+	}
+	if w.visited[real] {
+		return false
+	}
+	w.visited[real] = true
+	return true
+}
+
+func (w *walker) walk(path string, info fs.FileInfo) error {
+	if !info.IsDir() {
+		if w.follow && !w.firstVisit(path) {
+			return nil
+		}
+		return w.fn(path, info, nil)
+	}
+
+	if !w.firstVisit(path) {
+		return nil // A symlink cycle, or a tree already reached another way
+	}
+	if err := w.fn(path, info, nil); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return w.fn(path, info, err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, entry := range entries {
+		child := filepath.Join(path, entry.Name())
+		childInfo, err := os.Lstat(child)
+		if err != nil {
+			if err := w.fn(child, nil, err); err != nil && !errors.Is(err, filepath.SkipDir) {
+				return err
+			}
+			continue
+		}
+		if w.follow && childInfo.Mode()&fs.ModeSymlink != 0 {
+			target, err := os.Stat(child)
+			if err != nil {
+				continue // Dangling link
+			}
+			childInfo = target
+		}
+
+		if err := w.walk(child, childInfo); err != nil {
+			if !errors.Is(err, filepath.SkipDir) {
+				return err
+			}
+			if !childInfo.IsDir() {
+				return nil // SkipDir on a file skips the rest of its directory
+			}
+		}
+	}
+	return nil
+}
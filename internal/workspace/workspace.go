@@ -0,0 +1,180 @@
+package workspace
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Module is a Go module found on disk
+type Module struct {
+	Path      string   // Module path from the go.mod "module" directive
+	Dir       string   // Absolute directory containing go.mod
+	GoVersion string   // Language version from the "go" directive, e.g. "1.21" ("" if absent)
+	Vendored  []string // Module paths vendored in vendor/modules.txt
+}
+
+// Resolver maps files to the module and workspace they belong to. Lookups
+// walk up the directory tree and are cached per directory.
+type Resolver struct {
+	modules    map[string]*Module  // directory -> nearest module (nil if none)
+	workspaces map[string]string   // directory -> directory containing go.work ("" if none)
+	uses       map[string][]string // go.work directory -> module directories it uses
+	vendorDirs map[string]bool     // directory -> whether it has a vendor directory (GOPATH mode)
+}
+
+func NewResolver() *Resolver {
+	return &Resolver{
+		modules:    make(map[string]*Module),
+		workspaces: make(map[string]string),
+		uses:       make(map[string][]string),
+		vendorDirs: make(map[string]bool),
+	}
+}
+
+// ModuleFor returns the module containing filename, or nil outside any module
+func (r *Resolver) ModuleFor(filename string) *Module {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil
+	}
+	return r.moduleForDir(filepath.Dir(abs))
+}
+
+// ImportPath returns the import path of the package containing filename,
+// e.g. "example.com/app/internal/models", and for vendored code the path it
+// is imported by. Files outside any module get their path under GOPATH/src,
+// or "" outside GOPATH too.
+func (r *Resolver) ImportPath(filename string) string {
+	abs, _ := filepath.Abs(filename)
+	module := r.ModuleFor(filename)
+	if module == nil {
+		return gopathImportPath(filepath.Dir(abs))
+	}
+	rel, err := filepath.Rel(module.Dir, filepath.Dir(abs))
+	if err != nil || rel == "." {
+		return module.Path
+	}
+	rel = filepath.ToSlash(rel)
+	if InVendor(rel) {
+		return vendorImportPath(rel)
+	}
+	return path.Join(module.Path, rel)
+}
+
+// GoVersion returns the go directive version of the module containing
+// filename, or "" when it is unknown
+func (r *Resolver) GoVersion(filename string) string {
+	module := r.ModuleFor(filename)
+	if module == nil {
+		return ""
+	}
+	return module.GoVersion
+}
+
+// Root returns the directory paths should be reported relative to: the
+// go.work directory when the file's module is used by a workspace, otherwise
+// the module directory. Files outside any module get "".
+func (r *Resolver) Root(filename string) string {
+	module := r.ModuleFor(filename)
+	if module == nil {
+		return ""
+	}
+	if work := r.workspaceForDir(module.Dir); work != "" && r.workspaceUses(work, module.Dir) {
+		return work
+	}
+	return module.Dir
+}
+
+func (r *Resolver) workspaceUses(work, moduleDir string) bool {
+	dirs, ok := r.uses[work]
+	if !ok {
+		dirs, _ = WorkspaceModules(work)
+		r.uses[work] = dirs
+	}
+	for _, dir := range dirs {
+		if dir == moduleDir {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Resolver) moduleForDir(dir string) *Module {
+	if module, ok := r.modules[dir]; ok {
+		return module
+	}
+
+	var module *Module
+	if modulePath, goVersion, ok := readGoMod(filepath.Join(dir, "go.mod")); ok {
+		module = &Module{Path: modulePath, Dir: dir, GoVersion: goVersion, Vendored: readVendoredModules(dir)}
+	} else if parent := filepath.Dir(dir); parent != dir {
+		module = r.moduleForDir(parent)
+	}
+
+	r.modules[dir] = module
+	return module
+}
+
+func (r *Resolver) workspaceForDir(dir string) string {
+	if work, ok := r.workspaces[dir]; ok {
+		return work
+	}
+
+	work := ""
+	if _, err := os.Stat(filepath.Join(dir, "go.work")); err == nil {
+		work = dir
+	} else if parent := filepath.Dir(dir); parent != dir {
+		work = r.workspaceForDir(parent)
+	}
+
+	r.workspaces[dir] = work
+	return work
+}
+
+// readGoMod extracts the module path and go directive version from a
+// go.mod file. A go.mod the go command would reject still gives its module
+// path.
+func readGoMod(goModPath string) (string, string, bool) {
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		return "", "", false
+	}
+
+	file, err := modfile.ParseLax(goModPath, data, nil)
+	if err != nil || file.Module == nil {
+		modulePath := modfile.ModulePath(data)
+		return modulePath, "", modulePath != ""
+	}
+	goVersion := ""
+	if file.Go != nil {
+		goVersion = file.Go.Version
+	}
+	return file.Module.Mod.Path, goVersion, file.Module.Mod.Path != ""
+}
+
+// WorkspaceModules lists the module directories named by use directives in
+// the go.work file in dir
+func WorkspaceModules(dir string) ([]string, error) {
+	workPath := filepath.Join(dir, "go.work")
+	data, err := os.ReadFile(workPath)
+	if err != nil {
+		return nil, err
+	}
+	file, err := modfile.ParseWork(workPath, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make([]string, 0, len(file.Use))
+	for _, use := range file.Use {
+		moduleDir := filepath.FromSlash(use.Path)
+		if !filepath.IsAbs(moduleDir) {
+			moduleDir = filepath.Join(dir, moduleDir)
+		}
+		dirs = append(dirs, filepath.Clean(moduleDir))
+	}
+	return dirs, nil
+}
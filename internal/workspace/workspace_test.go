@@ -0,0 +1,54 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestWorkspaceModules(t *testing.T) {
+	dir := t.TempDir()
+	work := `go 1.22
+
+use(
+	./api // The API
+	"./web"
+)
+
+use ./tools
+`
+	if err := os.WriteFile(filepath.Join(dir, "go.work"), []byte(work), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := WorkspaceModules(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "api"), filepath.Join(dir, "web"), filepath.Join(dir, "tools")}
+	if !slices.Equal(got, want) {
+		t.Errorf("WorkspaceModules = %v, want %v", got, want)
+	}
+}
+
+func TestReadGoMod(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		goMod           string
+		path, goVersion string
+	}{
+		{"module example.com/app // The app\n\ngo 1.23\n", "example.com/app", "1.23"},
+		{"module \"example.com/quoted\"\n", "example.com/quoted", ""},
+		{"module example.com/broken\n\nrequire (\n", "example.com/broken", ""},
+	} {
+		goModPath := filepath.Join(dir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(tc.goMod), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		path, goVersion, ok := readGoMod(goModPath)
+		if !ok || path != tc.path || goVersion != tc.goVersion {
+			t.Errorf("readGoMod(%q) = %q, %q, %v, want %q, %q, true", tc.goMod, path, goVersion, ok, tc.path, tc.goVersion)
+		}
+	}
+}
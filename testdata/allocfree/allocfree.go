@@ -0,0 +1,66 @@
+// Package allocfree checks that the allocation-free API detector flags
+// allocating calls in loops and high-frequency functions that have
+// allocation-free alternatives, and leaves calls outside hot code alone.
+package allocfree
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var wordPattern = regexp.MustCompile(`\w+`)
+
+// CountWords only needs the number of matches, not the strings
+func CountWords(lines []string) int {
+	total := 0
+	for _, line := range lines {
+		total += len(wordPattern.FindAllString(line, -1))
+	}
+	return total
+}
+
+// Stamps formats a timestamp per event
+func Stamps(events []time.Time) []string {
+	out := make([]string, 0, len(events))
+	for _, t := range events {
+		out = append(out, t.Format(time.RFC3339))
+	}
+	return out
+}
+
+// Join writes numbers through a temporary string each
+func Join(values []int) string {
+	var b strings.Builder
+	b.Grow(len(values) * 4)
+	for _, v := range values {
+		b.WriteString(strconv.Itoa(v))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// IDs formats integers with fmt
+func IDs(ids []int) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, fmt.Sprintf("%d", id))
+	}
+	return out
+}
+
+// Once formats a single timestamp, outside any loop
+func Once(t time.Time) string {
+	return t.Format(time.RFC3339) + " " + fmt.Sprintf("%d", t.Year())
+}
+
+// Labels keeps the Itoa results, so AppendInt wouldn't help
+func Labels(values []int) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		out = append(out, strconv.Itoa(v))
+	}
+	return out
+}
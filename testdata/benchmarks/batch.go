@@ -0,0 +1,18 @@
+// Package benchmarks has loops shaped like a benchmark's timing loop, in
+// production code and in benchmarks.
+package benchmarks
+
+// Batch is a unit of work; N is how many times to repeat it
+type Batch struct {
+	N    int
+	Part string
+}
+
+// Render repeats the batch b.N times: a hot loop, for all its benchmark shape
+func Render(b Batch) string {
+	output := ""
+	for i := 0; i < b.N; i++ {
+		output += b.Part
+	}
+	return output
+}
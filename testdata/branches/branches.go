@@ -0,0 +1,89 @@
+package branches
+
+type Kind int
+
+const (
+	KindA Kind = iota
+	KindB
+	KindC
+	KindD
+)
+
+// weight maps every constant to a value, which a lookup map replaces
+func weight(k Kind) float64 {
+	if k == KindA {
+		return 1
+	} else if k == KindB {
+		return 2.5
+	} else if k == KindC || k == KindD {
+		return 4
+	} else if k == 7 {
+		return 8
+	}
+	return 0
+}
+
+// describe assigns a value in every branch, with an else for the rest
+func describe(status string) string {
+	label := "unknown"
+	if status == "a" {
+		label = "alpha"
+	} else if status == "b" {
+		label = "beta"
+	} else if "c" == status {
+		label = "gamma"
+	} else if status == "d" {
+		label = "delta"
+	} else {
+		label = "other"
+	}
+	return label
+}
+
+// act does more than pick a value, so only a switch is suggested
+func act(s struct{ code int }) {
+	if s.code == 1 {
+		println("one")
+	} else if s.code == 2 {
+		println("two")
+	} else if s.code == 3 {
+		println("three")
+	} else if s.code == 4 {
+		println("four")
+		println("again")
+	} else {
+		println("many")
+	}
+}
+
+// dup compares against 1 twice, which a switch would reject
+func dup(x int) {
+	if x == 1 {
+	} else if x == 2 {
+	} else if x == 1 {
+	} else if x == 3 {
+	}
+}
+
+// short is under the default of 4 branches
+func short(x int) int {
+	if x == 1 {
+		return 10
+	} else if x == 2 {
+		return 20
+	}
+	return 0
+}
+
+// mixed compares two different variables
+func mixed(x, y int) {
+	if x == 1 {
+		println(1)
+	} else if y == 2 {
+		println(2)
+	} else if x == 3 {
+		println(3)
+	} else if x == 4 {
+		println(4)
+	}
+}
@@ -0,0 +1,62 @@
+// Package builders checks that the builder usage detector suggests the
+// builder type that copies less for how the output is used.
+package builders
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Greeting builds a string in a bytes.Buffer, whose String() copies it
+func Greeting(names []string) string {
+	var buf bytes.Buffer
+	buf.WriteString("hello")
+	for _, name := range names {
+		fmt.Fprintf(&buf, " %v", name)
+	}
+	return buf.String()
+}
+
+// Payload builds bytes in a strings.Builder and then copies them out
+func Payload(fields []string) []byte {
+	var sb strings.Builder
+	for _, field := range fields {
+		sb.WriteString(field)
+		sb.WriteByte('\n')
+	}
+	return []byte(sb.String())
+}
+
+// Dump needs the Buffer for WriteTo, so it stays a bytes.Buffer
+func Dump(lines []string) string {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.WriteString(line)
+	}
+	s := buf.String()
+	buf.WriteTo(os.Stdout)
+	return s
+}
+
+// Both uses the built output as a string and as bytes
+func Both(parts []string) (string, []byte) {
+	var sb strings.Builder
+	for _, part := range parts {
+		sb.WriteString(part)
+	}
+	return sb.String(), []byte(sb.String())
+}
+
+// Width only reads the Builder it gets a copy of, which is safe
+func Width(sb strings.Builder) int {
+	return sb.Len()
+}
+
+// Terminate writes to its copy of the Builder, which panics once it isn't
+// empty
+func Terminate(sb strings.Builder) string {
+	sb.WriteString(".")
+	return sb.String()
+}
@@ -0,0 +1,79 @@
+// Package closures checks that goroutine bodies, handler closures, and
+// package-level function literals are measured on their own and that issues
+// inside them are attributed to the literal rather than the enclosing
+// function.
+package closures
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Classify is short, but the goroutine it starts is complex
+func Classify(values []int, out chan<- string) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, v := range values {
+			switch {
+			case v < 0 && v > -10:
+				out <- "small negative"
+			case v < 0:
+				out <- "negative"
+			case v == 0:
+				out <- "zero"
+			case v < 10 || v == 42:
+				out <- "small"
+			case v < 100:
+				out <- "medium"
+			default:
+				if v%2 == 0 {
+					out <- "large even"
+				} else {
+					out <- "large odd"
+				}
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+// NewHandler returns a handler whose closure concatenates strings in a loop
+func NewHandler(prefix string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result := prefix
+		for _, value := range r.URL.Query()["v"] {
+			result += value
+		}
+		w.Write([]byte(result))
+	}
+}
+
+// Nested attributes issues to the innermost literal
+func Nested(groups [][]string) []string {
+	var joined []string
+	walk := func() {
+		each := func(group []string) {
+			text := ""
+			for _, s := range group {
+				text += s
+			}
+			joined = append(joined, text)
+		}
+		for _, group := range groups {
+			each(group)
+		}
+	}
+	walk()
+	return joined
+}
+
+var normalize = func(words []string) string {
+	output := ""
+	for _, word := range words {
+		output += strings.ToLower(word)
+	}
+	return output
+}
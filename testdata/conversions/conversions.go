@@ -0,0 +1,100 @@
+// Package conversions checks that the conversion cache detector finds the
+// same few values converted to strings over and over in loops: values the
+// loop doesn't change, values from a small set, and enum-to-string switches.
+package conversions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind is an enum named by a switch
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindFile
+	KindDir
+	KindLink
+)
+
+// String names the kind
+func (k Kind) String() string {
+	switch k {
+	case KindFile:
+		return "file"
+	case KindDir:
+		return "dir"
+	case KindLink:
+		return "link"
+	}
+	return "unknown"
+}
+
+// Label converts the same shard number for every row
+func Label(rows []string, shard int) []string {
+	labels := make([]string, 0, len(rows))
+	for _, row := range rows {
+		labels = append(labels, row+"@"+strconv.Itoa(shard))
+	}
+	return labels
+}
+
+// Buckets formats one of 200 bucket numbers per id
+func Buckets(ids []int) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, fmt.Sprintf("%d", id%200))
+	}
+	return out
+}
+
+// Digits converts the last digit, which strconv already serves without allocating
+func Digits(ids []int) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, strconv.Itoa(id%10))
+	}
+	return out
+}
+
+// Hex formats every byte of the input
+func Hex(data []byte) string {
+	var b strings.Builder
+	b.Grow(len(data) * 3)
+	for _, c := range data {
+		b.WriteString(strconv.FormatInt(int64(c), 16))
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// Describe names the kind of every entry
+func Describe(kinds []Kind) []string {
+	names := make([]string, 0, len(kinds))
+	for _, k := range kinds {
+		names = append(names, k.String())
+	}
+	return names
+}
+
+// Levels names levels with an inline switch
+func Levels(levels []int) []string {
+	names := make([]string, 0, len(levels))
+	for _, level := range levels {
+		name := "other"
+		switch level {
+		case 0:
+			name = "debug"
+		case 1:
+			name = "info"
+		case 2:
+			name = "warn"
+		case 3:
+			name = "error"
+		}
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,96 @@
+// Package datastructures checks the data structure detector's alternatives
+// to linear scans: sets for membership tests, heaps for repeated minimum
+// extraction, ring buffers for slice-shift queues, and binary-search inserts
+// for slices re-sorted after every append.
+package datastructures
+
+import (
+	"slices"
+	"sort"
+)
+
+// Common compares every element of a against b: a set of b answers it
+func Common(a, b []string) []string {
+	var common []string
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				common = append(common, x)
+				break
+			}
+		}
+	}
+	return common
+}
+
+// Allowed rescans allowed for every request
+func Allowed(requests []int, allowed []int) int {
+	count := 0
+	for _, r := range requests {
+		if slices.Contains(allowed, r) {
+			count++
+		}
+	}
+	return count
+}
+
+// Schedule picks the earliest deadline on every round
+func Schedule(deadlines []int, rounds int) []int {
+	order := make([]int, 0, rounds)
+	for range rounds {
+		best := -1
+		for i, d := range deadlines {
+			if best < 0 || d < deadlines[best] {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		order = append(order, deadlines[best])
+		deadlines = slices.Delete(deadlines, best, best+1)
+	}
+	return order
+}
+
+// Drain treats the slice as a FIFO queue
+func Drain(queue []int, visit func(int) []int) {
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		queue = append(queue, visit(next)...)
+	}
+}
+
+// Collect keeps values sorted by re-sorting after each append
+func Collect(values <-chan int) []int {
+	var sorted []int
+	for v := range values {
+		sorted = append(sorted, v)
+		sort.Ints(sorted)
+	}
+	return sorted
+}
+
+// Lookup searches by key, which a map indexed by key fits better than a set
+func Lookup(ids []int, items []struct{ ID int }) int {
+	found := 0
+	for _, id := range ids {
+		for _, item := range items {
+			if item.ID == id {
+				found++
+			}
+		}
+	}
+	return found
+}
+
+// SortedOnce appends everything and sorts once, which is fine
+func SortedOnce(values []int) []int {
+	sorted := make([]int, 0, len(values))
+	for _, v := range values {
+		sorted = append(sorted, v)
+	}
+	slices.Sort(sorted)
+	return sorted
+}
@@ -0,0 +1,101 @@
+// Package generics exercises detectors on generic code: type parameters
+// constrained to slices and maps, instantiated generic types, and methods on
+// generic receivers.
+package generics
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+type Set[T comparable] map[T]struct{}
+
+// Table has 256 bytes of fixed fields whatever T is
+type Table[T any] struct {
+	buckets [32]int64
+	extra   T
+}
+
+// Fill builds S without telling make how much room it needs
+func Fill[S ~[]E, E any](n int, value E) S {
+	out := make(S, 0)
+	for i := 0; i < n; i++ {
+		out = append(out, value)
+	}
+	return out
+}
+
+// Index allocates a map per call without a size hint
+func Index[M ~map[K]V, K comparable, V any](keys []K, value V) M {
+	index := make(M)
+	for _, key := range keys {
+		index[key] = value
+	}
+	return index
+}
+
+// Zip allocates an instantiated generic type per outer element
+func Zip[K comparable, V any](keys [][]K, values []V) [][]Pair[K, V] {
+	var out [][]Pair[K, V]
+	for _, row := range keys {
+		pairs := make([]Pair[K, V], len(row))
+		for i, key := range row {
+			pairs[i] = Pair[K, V]{Key: key, Value: values[i]}
+		}
+		out = append(out, pairs)
+	}
+	return out
+}
+
+// Union ranges over instantiated generic sets
+func Union[T comparable](sets ...Set[T]) Set[T] {
+	out := make(Set[T])
+	for _, set := range sets {
+		for key := range set {
+			out[key] = struct{}{}
+		}
+	}
+	return out
+}
+
+// Sum copies at least 256 bytes per call
+func (t Table[T]) Sum() int64 {
+	var total int64
+	for _, bucket := range t.buckets {
+		total += bucket
+	}
+	return total
+}
+
+func Totals(tables []Table[string]) []int64 {
+	totals := make([]int64, 0, len(tables))
+	for _, table := range tables {
+		totals = append(totals, table.Sum())
+	}
+	return totals
+}
+
+// Keys grows its result one key at a time from an instantiated receiver
+func (s Set[T]) Keys() []T {
+	var keys []T
+	for key := range s {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Grid has two type parameters and 256 bytes of fixed fields
+type Grid[K comparable, V any] struct {
+	cells [32]int64
+	key   K
+	value V
+}
+
+// Sum copies the grid per call
+func (g Grid[K, V]) Sum() int64 {
+	var total int64
+	for _, cell := range g.cells {
+		total += cell
+	}
+	return total
+}
@@ -0,0 +1,16 @@
+// Package model is imported by the store that persists it, and imports the
+// store back, closing an import cycle that also breaks the dependency rule
+// the registry test configures for it.
+package model
+
+import "gophercheck/testdata/layers/store"
+
+type User struct {
+	ID   string
+	Name string
+}
+
+// Save reaches into the storage layer from the model
+func (u User) Save() error {
+	return store.Put(u.ID, u.Name)
+}
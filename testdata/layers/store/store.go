@@ -0,0 +1,11 @@
+// Package store persists models, importing the package that imports it.
+package store
+
+import "gophercheck/testdata/layers/model"
+
+var users = make(map[string]model.User)
+
+func Put(id, name string) error {
+	users[id] = model.User{ID: id, Name: name}
+	return nil
+}
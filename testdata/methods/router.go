@@ -0,0 +1,114 @@
+package methods
+
+import "strings"
+
+// Router grows a method for every new kind of request until it handles
+// everything. No single method is complex, but together they are.
+type Router struct {
+	routes  map[string]string
+	limits  map[string]int
+	aliases map[string]string
+}
+
+// Cache has a method named like one of Router's, to check methods are told
+// apart by their receiver
+type Cache struct {
+	entries map[string]string
+}
+
+func (c *Cache) Resolve(key string) string {
+	return c.entries[key]
+}
+
+// Describe builds its description in a closure, which is named after the
+// method around it
+func (c *Cache) Describe() string {
+	describe := func() string {
+		result := ""
+		for key, value := range c.entries {
+			result += key + "=" + value + ";"
+		}
+		return result
+	}
+	return strings.TrimSuffix(describe(), ";")
+}
+
+func (r *Router) Limit(key string) int {
+	switch key {
+	case "a0":
+		return 0
+	case "b1":
+		return 1
+	case "c2":
+		return 2
+	case "d3":
+		return 3
+	case "e4":
+		return 4
+	case "f5":
+		return 5
+	case "g6":
+		return 6
+	}
+	return len(r.routes)
+}
+
+func (r *Router) Alias(key string) int {
+	switch key {
+	case "a0":
+		return 0
+	case "b1":
+		return 1
+	case "c2":
+		return 2
+	case "d3":
+		return 3
+	case "e4":
+		return 4
+	case "f5":
+		return 5
+	case "g6":
+		return 6
+	}
+	return len(r.routes)
+}
+
+func (r *Router) Method(key string) int {
+	switch key {
+	case "a0":
+		return 0
+	case "b1":
+		return 1
+	case "c2":
+		return 2
+	case "d3":
+		return 3
+	case "e4":
+		return 4
+	case "f5":
+		return 5
+	case "g6":
+		return 6
+	}
+	return len(r.routes)
+}
+
+func (r *Router) Route(key string) int {
+	switch key {
+	case "a0":
+		return 0
+	case "b1":
+		return 1
+	case "c2":
+		return 2
+	case "d3":
+		return 3
+	case "e4":
+		return 4
+	case "f5":
+		return 5
+	case "g6":
+		return 6
+	}
+	return len(r.routes)
+}
@@ -0,0 +1,83 @@
+package methods
+
+// More of Router's methods, declared away from the type
+
+func (r *Router) Scheme(key string) int {
+	switch key {
+	case "a0":
+		return 0
+	case "b1":
+		return 1
+	case "c2":
+		return 2
+	case "d3":
+		return 3
+	case "e4":
+		return 4
+	case "f5":
+		return 5
+	case "g6":
+		return 6
+	}
+	return len(r.routes)
+}
+
+func (r *Router) Priority(key string) int {
+	switch key {
+	case "a0":
+		return 0
+	case "b1":
+		return 1
+	case "c2":
+		return 2
+	case "d3":
+		return 3
+	case "e4":
+		return 4
+	case "f5":
+		return 5
+	case "g6":
+		return 6
+	}
+	return len(r.routes)
+}
+
+func (r *Router) Timeout(key string) int {
+	switch key {
+	case "a0":
+		return 0
+	case "b1":
+		return 1
+	case "c2":
+		return 2
+	case "d3":
+		return 3
+	case "e4":
+		return 4
+	case "f5":
+		return 5
+	case "g6":
+		return 6
+	}
+	return len(r.routes)
+}
+
+func (r *Router) Retries(key string) int {
+	switch key {
+	case "a0":
+		return 0
+	case "b1":
+		return 1
+	case "c2":
+		return 2
+	case "d3":
+		return 3
+	case "e4":
+		return 4
+	case "f5":
+		return 5
+	case "g6":
+		return 6
+	}
+	return len(r.routes)
+}
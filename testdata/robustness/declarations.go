@@ -0,0 +1,14 @@
+package robustness
+
+import _ "unsafe"
+
+// Functions without a body are implemented in assembly or linked in; detectors
+// reading fn.Body must skip them.
+
+//go:noescape
+func assembly(dst, src []byte) int
+
+//go:linkname nanotime runtime.nanotime
+func nanotime() int64
+
+func (Empty) bodyless()
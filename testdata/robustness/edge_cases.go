@@ -0,0 +1,184 @@
+// Package robustness holds unusual but valid Go that every detector must
+// handle without panicking. Findings here are incidental.
+package robustness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	Number interface{ ~int | ~int64 | ~float64 }
+	Pair[K comparable, V any] struct {
+		Key   K
+		Value V
+	}
+	List[T any] []T
+	Empty   struct{}
+	Handler func(http.ResponseWriter, *http.Request)
+	Alias   = map[string][]int
+)
+
+func emptyBody() {}
+
+func (Empty) unnamedReceiver() {}
+
+func (_ *Empty) blankReceiver() {}
+
+func (l List[T]) Len() int { return len(l) }
+
+func (l *List[T]) Push(values ...T) {
+	for _, v := range values {
+		*l = append(*l, v)
+	}
+}
+
+func Sum[T Number](values []T) (total T) {
+	for _, v := range values {
+		total += v
+	}
+	return
+}
+
+func Map[S ~[]E, E, R any](s S, f func(E) R) []R {
+	out := make([]R, 0)
+	for _, e := range s {
+		out = append(out, f(e))
+	}
+	return out
+}
+
+func Keys[K comparable, V any](m map[K]V) []K {
+	var keys []K
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func instantiations() {
+	sum := Sum[int]
+	mapper := Map[[]int, int, string]
+	_ = sum([]int{1, 2})
+	_ = mapper([]int{1}, func(i int) string { return fmt.Sprint(i) })
+	var pairs []Pair[string, List[int]]
+	pairs = append(pairs, Pair[string, List[int]]{Key: "a"})
+	_ = pairs
+}
+
+func methodValues(e Empty, l List[string]) {
+	f := e.unnamedReceiver
+	g := (*Empty).blankReceiver
+	h := l.Len
+	f()
+	g(&e)
+	_ = h()
+	_ = strings.ToUpper
+	_ = List[int].Len
+}
+
+func closures() Handler {
+	var mu sync.Mutex
+	counts := map[string]int{}
+	return func(w http.ResponseWriter, r *http.Request) {
+		go func() {
+			mu.Lock()
+			defer mu.Unlock()
+			for i := 0; i < 10; i++ {
+				counts[r.URL.Path] += i
+			}
+		}()
+		func() {}()
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				fmt.Fprintln(w, recovered)
+			}
+		}()
+	}
+}
+
+func controlFlow(ctx context.Context, ch chan int, items []string) (err error) {
+outer:
+	for i := range 10 {
+		switch {
+		case i == 3:
+			continue outer
+		case i > 8:
+			break outer
+		default:
+			fallthrough
+		case i < 0:
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case v, ok := <-ch:
+			if !ok {
+				goto done
+			}
+			_ = v
+		}
+	}
+done:
+
+	var x interface{} = items
+	switch v := x.(type) {
+	case nil:
+	case []string, []int:
+		_ = v
+	}
+
+	for range items {
+	}
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+	for ; ; time.Sleep(time.Millisecond) {
+		break
+	}
+	select {}
+}
+
+func rangeOverFunc(seq iter.Seq2[int, string]) {
+	for i, s := range seq {
+		_, _ = i, s
+	}
+	for range seq {
+	}
+}
+
+func anonymousTypes() {
+	var point struct{ X, Y int }
+	points := []struct{ X, Y int }{{1, 2}, {3, 4}}
+	sort.Slice(points, func(i, j int) bool { return points[i].X < points[j].X })
+	_ = point
+	var _ Alias
+	var _ [0]func()
+	var _ = [...]string{2: "c", 0: "a"}
+}
+
+func errorsOnly(values []int) error {
+	var errs []error
+	for _, v := range values {
+		if v < 0 {
+			errs = append(errs, errors.New("negative"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func init() {}
+
+func init() {
+	_ = strings.Repeat("x", 0)
+}
@@ -0,0 +1,79 @@
+// Package rules gives each detector without findings elsewhere in testdata
+// a case it reports.
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+var ErrInvalid = errors.New("invalid")
+
+type Item struct {
+	ID    string
+	Valid bool
+}
+
+// Triples compares every triple of values, three loops deep
+func Triples(a, b, c []int) int {
+	count := 0
+	for _, x := range a {
+		for _, y := range b {
+			for _, z := range c {
+				if x+y == z {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+// Columns splits the same header for every row
+func Columns(header string, rows []string) int {
+	total := 0
+	for range rows {
+		fields := strings.Split(header, ",")
+		total += len(fields)
+	}
+	return total
+}
+
+// Validate formats an error per item, used only when one is invalid
+func Validate(items []Item) error {
+	for _, item := range items {
+		err := fmt.Errorf("item %s: %w", item.ID, ErrInvalid)
+		if !item.Valid {
+			return err
+		}
+	}
+	return nil
+}
+
+// Wait spins until another goroutine sets done
+func Wait(done *atomic.Bool) {
+	for !done.Load() {
+	}
+}
+
+// Drain polls the channel without ever blocking
+func Drain(ch chan int, handle func(int)) {
+	for {
+		select {
+		case msg := <-ch:
+			handle(msg)
+		default:
+		}
+	}
+}
+
+// Lookup formats the map key on every iteration
+func Lookup(m map[string]int, ids []int, kind string) int {
+	total := 0
+	for _, id := range ids {
+		total += m[fmt.Sprintf("%s-%d", kind, id)]
+	}
+	return total
+}
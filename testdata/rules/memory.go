@@ -0,0 +1,40 @@
+package rules
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// Prepend copies the whole result for every item
+func Prepend(items []string) []string {
+	var result []string
+	for _, item := range items {
+		result = append([]string{item}, result...)
+	}
+	return result
+}
+
+// Header keeps a few bytes of a large buffer alive
+func Header(r io.Reader) ([]byte, error) {
+	buf := make([]byte, 1<<20)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf[:8], nil
+}
+
+// CountLines reads the whole file to scan it once
+func CountLines(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,46 @@
+package rules
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// Expand adds entries to the map it ranges over
+func Expand(m map[string]int) {
+	for key, value := range m {
+		m[key+"-copy"] = value
+	}
+}
+
+// Record writes the shared map from every goroutine
+func Record(items []Item) map[string]bool {
+	seen := make(map[string]bool)
+	for _, item := range items {
+		go func() {
+			seen[item.ID] = item.Valid
+		}()
+	}
+	return seen
+}
+
+// Match compiles the same pattern on every request
+func Match(w http.ResponseWriter, r *http.Request) {
+	pattern := regexp.MustCompile(`^/items/[0-9]+$`)
+	if !pattern.MatchString(r.URL.Path) {
+		http.NotFound(w, r)
+	}
+}
+
+// Fetch requests every URL one after another
+func Fetch(urls []string) ([]int, error) {
+	statuses := make([]int, len(urls))
+	for i, url := range urls {
+		resp, err := http.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body.Close()
+		statuses[i] = resp.StatusCode
+	}
+	return statuses, nil
+}
@@ -0,0 +1,77 @@
+// Command rulesdoc generates the rule reference, docs/rules.md, from the
+// issue type descriptions and the suggestion catalog. Every issue type gets a
+// section whose anchor is its id, which issues link to through docs_url.
+//
+//	go run ./tools/rulesdoc          # Rewrite docs/rules.md
+//	go run ./tools/rulesdoc -check   # Exit 1 if docs/rules.md is out of date
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gophercheck/internal/models"
+	"gophercheck/internal/suggestions"
+)
+
+func main() {
+	check := flag.Bool("check", false, "Only report whether the reference is up to date")
+	output := flag.String("o", "docs/rules.md", "File to write the reference to")
+	flag.Parse()
+
+	reference, err := generate()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *check {
+		current, err := os.ReadFile(*output)
+		if err != nil || !bytes.Equal(current, reference) {
+			fmt.Printf("%s is out of date (run go run ./tools/rulesdoc)\n", *output)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := os.WriteFile(*output, reference, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("wrote %s (%d rules)\n", *output, len(models.AllIssueTypes))
+}
+
+// generate renders the reference, grouped by category. An issue type without
+// a description is an error, so new rules can't go undocumented.
+func generate() ([]byte, error) {
+	var doc bytes.Buffer
+	doc.WriteString("<!-- Generated by go run ./tools/rulesdoc from internal/models/docs.go and internal/suggestions/catalog.yaml. Do not edit. -->\n\n")
+	doc.WriteString("# Rule Reference\n\n")
+	doc.WriteString("Every rule gophercheck reports, by category. Each section's anchor is the rule id\n")
+	doc.WriteString("shown in reports, which issues link to through `docs_url`.\n")
+
+	for _, category := range models.AllCategories {
+		fmt.Fprintf(&doc, "\n## %s%s\n", strings.ToUpper(category[:1]), category[1:])
+		for _, issueType := range models.AllIssueTypes {
+			if issueType.Category() != category {
+				continue
+			}
+			description := issueType.Description()
+			if description == "" {
+				return nil, fmt.Errorf("issue type %s has no description in internal/models/docs.go", issueType)
+			}
+
+			fmt.Fprintf(&doc, "\n### %s\n\n%s\n", issueType, description)
+			for _, entry := range suggestions.Default.ForRule(string(issueType)) {
+				title := entry.Title
+				if title == "" {
+					title = "Suggestion"
+				}
+				fmt.Fprintf(&doc, "\n**%s**\n\n```text\n%s\n```\n", title, suggestions.Render(entry.ID, suggestions.Data{}))
+			}
+		}
+	}
+	return doc.Bytes(), nil
+}